@@ -55,6 +55,7 @@ var (
 		executablePath("abigen"),
 		executablePath("chaintool"),
 		executablePath("bootnode"),
+		executablePath("pbftsigner"),
 		executablePath("phoenixchain"),
 		executablePath("rlpdump"),
 		executablePath("wnode"),
@@ -74,6 +75,10 @@ var (
 			BinaryName:  "bootnode",
 			Description: "PhoenixChain bootnode.",
 		},
+		{
+			BinaryName:  "pbftsigner",
+			Description: "PhoenixChain remote consensus signer.",
+		},
 		{
 			BinaryName:  "phoenixchain",
 			Description: "PhoenixChain CLI client.",
@@ -18,6 +18,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/restricting"
 )
 
@@ -80,6 +81,34 @@ type Dpos_1005 struct {
 	Amount          *big.Int
 }
 
+// redelegate
+type Dpos_1006 struct {
+	StakingBlockNum uint64
+	NodeId          discover.NodeID
+	TargetNodeId    discover.NodeID
+	Amount          *big.Int
+}
+
+// setAutoCompound
+type Dpos_1007 struct {
+	StakingBlockNum uint64
+	NodeId          discover.NodeID
+	AutoCompound    bool
+}
+
+// rotateBlsKey
+type Dpos_1008 struct {
+	NodeId    discover.NodeID
+	BlsPubKey bls.PublicKeyHex
+	BlsProof  bls.SchnorrProofHex
+}
+
+// setOperator
+type Dpos_1009 struct {
+	NodeId   discover.NodeID
+	Operator common.Address
+}
+
 // getRelatedListByDelAddr
 type Dpos_1103 struct {
 	Addr common.Address
@@ -128,6 +157,28 @@ type Dpos_2005 struct {
 	TobeCanceled    common.Hash
 }
 
+// submitParamBatch
+type Dpos_2006 struct {
+	Verifier discover.NodeID
+	PIPID    string
+	Changes  []gov.ParamChange
+}
+
+// submitTreasury
+type Dpos_2007 struct {
+	Verifier  discover.NodeID
+	PIPID     string
+	Recipient common.Address
+	Amount    *big.Int
+	Plans     []restricting.RestrictingPlan
+}
+
+// voteByDelegator
+type Dpos_2008 struct {
+	ProposalID common.Hash
+	Option     uint8
+}
+
 // vote
 type Dpos_2003 struct {
 	Verifier       discover.NodeID
@@ -198,6 +249,17 @@ type Dpos_4000 struct {
 	Plans   []restricting.RestrictingPlan
 }
 
+// createVestingPlan
+type Dpos_4001 struct {
+	Account common.Address
+	Plans   []restricting.VestingPlan
+}
+
+// revokeRestrictingPlan
+type Dpos_4002 struct {
+	Account common.Address
+}
+
 // GetRestrictingInfo
 type Dpos_4100 struct {
 	Account common.Address
@@ -219,6 +281,10 @@ type decDataConfig struct {
 	P1003 Dpos_1003
 	P1004 Dpos_1004
 	P1005 Dpos_1005
+	P1006 Dpos_1006
+	P1007 Dpos_1007
+	P1008 Dpos_1008
+	P1009 Dpos_1009
 	P1103 Dpos_1103
 	P1104 Dpos_1104
 	P1105 Dpos_1105
@@ -226,6 +292,9 @@ type decDataConfig struct {
 	P2001 Dpos_2001
 	P2002 Dpos_2002
 	P2005 Dpos_2005
+	P2006 Dpos_2006
+	P2007 Dpos_2007
+	P2008 Dpos_2008
 	P2003 Dpos_2003
 	P2004 Dpos_2004
 	P2100 Dpos_2100
@@ -238,6 +307,8 @@ type decDataConfig struct {
 	P3000 Dpos_3000
 	P3001 Dpos_3001
 	P4000 Dpos_4000
+	P4001 Dpos_4001
+	P4002 Dpos_4002
 	P4100 Dpos_4100
 	P5100 Dpos_5100
 }
@@ -354,6 +425,46 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, nodeId)
 			params = append(params, amount)
 		}
+	case 1006:
+		{
+			stakingBlockNum, _ := rlp.EncodeToBytes(cfg.P1006.StakingBlockNum)
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1006.NodeId)
+			targetNodeId, _ := rlp.EncodeToBytes(cfg.P1006.TargetNodeId)
+			amount, _ := rlp.EncodeToBytes(cfg.P1006.Amount)
+
+			params = append(params, stakingBlockNum)
+			params = append(params, nodeId)
+			params = append(params, targetNodeId)
+			params = append(params, amount)
+		}
+	case 1007:
+		{
+			stakingBlockNum, _ := rlp.EncodeToBytes(cfg.P1007.StakingBlockNum)
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1007.NodeId)
+			autoCompound, _ := rlp.EncodeToBytes(cfg.P1007.AutoCompound)
+
+			params = append(params, stakingBlockNum)
+			params = append(params, nodeId)
+			params = append(params, autoCompound)
+		}
+	case 1008:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1008.NodeId)
+			blsPubKey, _ := rlp.EncodeToBytes(cfg.P1008.BlsPubKey)
+			blsProof, _ := rlp.EncodeToBytes(cfg.P1008.BlsProof)
+
+			params = append(params, nodeId)
+			params = append(params, blsPubKey)
+			params = append(params, blsProof)
+		}
+	case 1009:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1009.NodeId)
+			operator, _ := rlp.EncodeToBytes(cfg.P1009.Operator.Bytes())
+
+			params = append(params, nodeId)
+			params = append(params, operator)
+		}
 	case 1100:
 	case 1101:
 	case 1102:
@@ -424,6 +535,35 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, endVotingRounds)
 			params = append(params, tobeCanceled)
 		}
+	case 2006:
+		{
+			verifier, _ := rlp.EncodeToBytes(cfg.P2006.Verifier)
+			pipID, _ := rlp.EncodeToBytes(cfg.P2006.PIPID)
+			changes, _ := rlp.EncodeToBytes(cfg.P2006.Changes)
+			params = append(params, verifier)
+			params = append(params, pipID)
+			params = append(params, changes)
+		}
+	case 2007:
+		{
+			verifier, _ := rlp.EncodeToBytes(cfg.P2007.Verifier)
+			pipID, _ := rlp.EncodeToBytes(cfg.P2007.PIPID)
+			recipient, _ := rlp.EncodeToBytes(cfg.P2007.Recipient.Bytes())
+			amount, _ := rlp.EncodeToBytes(cfg.P2007.Amount)
+			plans, _ := rlp.EncodeToBytes(cfg.P2007.Plans)
+			params = append(params, verifier)
+			params = append(params, pipID)
+			params = append(params, recipient)
+			params = append(params, amount)
+			params = append(params, plans)
+		}
+	case 2008:
+		{
+			proposalID, _ := rlp.EncodeToBytes(cfg.P2008.ProposalID.Bytes())
+			option, _ := rlp.EncodeToBytes(cfg.P2008.Option)
+			params = append(params, proposalID)
+			params = append(params, option)
+		}
 	case 2003:
 		{
 			verifier, _ := rlp.EncodeToBytes(cfg.P2003.Verifier)
@@ -500,6 +640,18 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, account)
 			params = append(params, plans)
 		}
+	case 4001:
+		{
+			account, _ := rlp.EncodeToBytes(cfg.P4001.Account.Bytes())
+			plans, _ := rlp.EncodeToBytes(cfg.P4001.Plans)
+			params = append(params, account)
+			params = append(params, plans)
+		}
+	case 4002:
+		{
+			account, _ := rlp.EncodeToBytes(cfg.P4002.Account.Bytes())
+			params = append(params, account)
+		}
 	case 4100:
 		{
 			account, _ := rlp.EncodeToBytes(cfg.P4100.Account.Bytes())
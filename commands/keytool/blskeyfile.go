@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+)
+
+const (
+	defaultBlsKeyfileName = "blskeyfile.json"
+)
+
+type outputGenerateBls struct {
+	PublicKey string
+}
+
+type outputInspectBls struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+var commandGenerateBls = cli.Command{
+	Name:      "generatebls",
+	Usage:     "generate new encrypted bls keyfile",
+	ArgsUsage: "[ <keyfile> ]",
+	Description: `
+Generate a new encrypted bls keyfile, to be loaded by the node with --pbft.blskey
+and --pbft.blskey.password.
+
+If you want to encrypt an existing bls private key, such as the plaintext blskey
+file of a data directory, it can be specified by setting --privatekey with the
+location of the file containing the private key.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		cli.StringFlag{
+			Name:  "privatekey",
+			Usage: "file containing a raw bls private key to encrypt",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		if err := bls.Init(int(bls.BLS12_381)); err != nil {
+			return err
+		}
+
+		// Check if keyfile path given and make sure it doesn't already exist.
+		keyfilepath := ctx.Args().First()
+		if keyfilepath == "" {
+			keyfilepath = defaultBlsKeyfileName
+		}
+		if _, err := os.Stat(keyfilepath); err == nil {
+			utils.Fatalf("Keyfile already exists at %s.", keyfilepath)
+		} else if !os.IsNotExist(err) {
+			utils.Fatalf("Error checking if keyfile exists: %v", err)
+		}
+
+		var privateKey *bls.SecretKey
+		if file := ctx.String("privatekey"); file != "" {
+			// Load private key from file.
+			var err error
+			privateKey, err = bls.LoadBLS(file)
+			if err != nil {
+				utils.Fatalf("Can't load private key: %v", err)
+			}
+		} else {
+			// If not loaded, generate random.
+			privateKey = bls.GenerateKey()
+		}
+
+		// Encrypt key with passphrase.
+		passphrase := getPassphrase(ctx, true)
+		keyjson, err := keystore.EncryptBlsKey(privateKey, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			utils.Fatalf("Error encrypting key: %v", err)
+		}
+
+		// Store the file to disk.
+		if err := os.MkdirAll(filepath.Dir(keyfilepath), 0700); err != nil {
+			utils.Fatalf("Could not create directory %s", filepath.Dir(keyfilepath))
+		}
+		if err := ioutil.WriteFile(keyfilepath, keyjson, 0600); err != nil {
+			utils.Fatalf("Failed to write keyfile to %s: %v", keyfilepath, err)
+		}
+
+		// Output some information.
+		out := outputGenerateBls{
+			PublicKey: hex.EncodeToString(privateKey.GetPublicKey().Serialize()),
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("PublicKey : ", out.PublicKey)
+		}
+		return nil
+	},
+}
+
+var commandInspectBls = cli.Command{
+	Name:      "inspectbls",
+	Usage:     "inspect an encrypted bls keyfile",
+	ArgsUsage: "<keyfile>",
+	Description: `
+Print the public key of the bls keyfile.
+
+Private key information can be printed by using the --private flag;
+make sure to use this feature with great caution!`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		cli.BoolFlag{
+			Name:  "private",
+			Usage: "include the private key in the output",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		if err := bls.Init(int(bls.BLS12_381)); err != nil {
+			return err
+		}
+		keyfilepath := ctx.Args().First()
+
+		// Read key from file.
+		keyjson, err := ioutil.ReadFile(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+		}
+
+		// Decrypt key with passphrase.
+		passphrase := getPassphrase(ctx, false)
+		key, err := keystore.DecryptBlsKey(keyjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting key: %v", err)
+		}
+
+		// Output all relevant information we can retrieve.
+		showPrivate := ctx.Bool("private")
+		out := outputInspectBls{
+			PublicKey: hex.EncodeToString(key.GetPublicKey().Serialize()),
+		}
+		if showPrivate {
+			out.PrivateKey = hex.EncodeToString(key.GetLittleEndian())
+		}
+
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Public key:    ", out.PublicKey)
+			if showPrivate {
+				fmt.Println("Private key:   ", out.PrivateKey)
+			}
+		}
+		return nil
+	},
+}
+
+var commandChangeBlsPassphrase = cli.Command{
+	Name:      "changeblspassphrase",
+	Usage:     "change the passphrase on a bls keyfile",
+	ArgsUsage: "<keyfile>",
+	Description: `
+Change the passphrase of a bls keyfile.`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		newPassphraseFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		if err := bls.Init(int(bls.BLS12_381)); err != nil {
+			return err
+		}
+		keyfilepath := ctx.Args().First()
+
+		// Read key from file.
+		keyjson, err := ioutil.ReadFile(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+		}
+
+		// Decrypt key with passphrase.
+		passphrase := getPassphrase(ctx, false)
+		key, err := keystore.DecryptBlsKey(keyjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting key: %v", err)
+		}
+
+		// Get a new passphrase.
+		fmt.Println("Please provide a new passphrase")
+		var newPhrase string
+		if passFile := ctx.String(newPassphraseFlag.Name); passFile != "" {
+			content, err := ioutil.ReadFile(passFile)
+			if err != nil {
+				utils.Fatalf("Failed to read new passphrase file '%s': %v", passFile, err)
+			}
+			newPhrase = strings.TrimRight(string(content), "\r\n")
+		} else {
+			newPhrase = promptPassphrase(true)
+		}
+
+		// Encrypt the key with the new passphrase.
+		newJson, err := keystore.EncryptBlsKey(key, newPhrase, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			utils.Fatalf("Error encrypting with new passphrase: %v", err)
+		}
+
+		// Then write the new keyfile in place of the old one.
+		if err := ioutil.WriteFile(keyfilepath, newJson, 0600); err != nil {
+			utils.Fatalf("Error writing new keyfile to disk: %v", err)
+		}
+		return nil
+	},
+}
@@ -28,6 +28,9 @@ func init() {
 		commandVerifyMessage,
 		commandGenkeypair,
 		commandGenblskeypair,
+		commandGenerateBls,
+		commandInspectBls,
+		commandChangeBlsPassphrase,
 		//commandAddressHexToBech32,
 	}
 }
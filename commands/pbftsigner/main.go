@@ -0,0 +1,74 @@
+// pbftsigner is a reference implementation of the remote consensus signer. It
+// holds the BLS key of a validator and signs the consensus messages on behalf
+// of a phoenixchain node started with --pbft.signer. The node key isn't held by
+// the signer, it stays with the node as its p2p identity.
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/signer"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+)
+
+func main() {
+	var (
+		endpoint   = flag.String("endpoint", "", "listen endpoint (unix:///path/to/socket or tcp://host:port)")
+		blsKeyFile = flag.String("blskey", "", "bls private key filename")
+		certFile   = flag.String("tls.cert", "", "TLS certificate of the signer")
+		keyFile    = flag.String("tls.key", "", "TLS private key of the signer certificate")
+		caFile     = flag.String("tls.ca", "", "CA certificate that signed the node certificates")
+		verbosity  = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-9)")
+	)
+	flag.Parse()
+
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(*verbosity))
+	log.Root().SetHandler(glogger)
+
+	switch {
+	case *endpoint == "":
+		utils.Fatalf("Use -endpoint to specify the listen endpoint")
+	case *blsKeyFile == "":
+		utils.Fatalf("Use -blskey to specify the validator BLS key")
+	case *certFile == "" || *keyFile == "" || *caFile == "":
+		utils.Fatalf("Use -tls.cert, -tls.key and -tls.ca to specify the TLS certificates")
+	}
+
+	if err := bls.Init(bls.BLS12_381); err != nil {
+		utils.Fatalf("Failed to initialize bls: %v", err)
+	}
+	blsKey, err := bls.LoadBLS(*blsKeyFile)
+	if err != nil {
+		utils.Fatalf("-blskey: %v", err)
+	}
+	local := signer.NewLocalSigner(blsKey)
+
+	config, err := signer.NewTLSConfig(*certFile, *keyFile, *caFile)
+	if err != nil {
+		utils.Fatalf("Failed to load TLS certificates: %v", err)
+	}
+	srv, err := signer.NewServer(local)
+	if err != nil {
+		utils.Fatalf("Failed to create signer service: %v", err)
+	}
+	l, err := signer.Listen(*endpoint, config)
+	if err != nil {
+		utils.Fatalf("Failed to listen on %s: %v", *endpoint, err)
+	}
+	log.Info("Consensus signer started", "endpoint", *endpoint, "blsPublicKey", hexutil.Encode(local.BlsPublicKey().Serialize()))
+	go srv.ServeListener(l)
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Consensus signer stopping")
+	l.Close()
+	srv.Stop()
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/econsim"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+)
+
+var (
+	econModelFlag = cli.StringFlag{
+		Name:  "model",
+		Usage: "EconomicModel JSON file, the fields in it override the main net model",
+	}
+	econDistFlag = cli.StringFlag{
+		Name:  "dist",
+		Usage: "JSON file of the synthetic candidates and delegators",
+	}
+	econYearsFlag = cli.IntFlag{
+		Name:  "years",
+		Usage: "Number of chain years to simulate, overrides the distribution file",
+	}
+	econFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the yearly projection: csv or json",
+		Value: "csv",
+	}
+	econOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "File to write the yearly projection to, stdout by default",
+	}
+	econNodesFlag = cli.StringFlag{
+		Name:  "nodes",
+		Usage: "File to write the projection of every candidate to, as CSV",
+	}
+
+	econCommand = cli.Command{
+		Name:     "econ",
+		Usage:    "Project the economic model offline",
+		Category: "MISCELLANEOUS COMMANDS",
+		Description: `
+
+Tools to reason about the economic model before changing its parameters.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "simulate",
+				Usage:  "Simulate the issuance, the rewards and the election year by year",
+				Action: utils.MigrateFlags(econSimulate),
+				Flags: []cli.Flag{
+					econModelFlag,
+					econDistFlag,
+					econYearsFlag,
+					econFormatFlag,
+					econOutputFlag,
+					econNodesFlag,
+				},
+				Description: `
+    phoenixchain econ simulate --model model.json --dist dist.json --years 5
+
+Run a synthetic chain through the staking, restricting and reward plugins on a
+temporary snapshotdb and an in-memory state, and project the issuance, the
+foundation balances, the validator and delegator APYs and the election year
+by year. The amounts are in von.
+
+The model file has the layout of the EconomicModel of the genesis file. The
+distribution file describes the candidate and delegator groups:
+
+    {
+      "years": 3,
+      "seed": 1,
+      "candidates": [{
+        "count": 150, "joinEpoch": 0,
+        "stake": {"distribution": "pareto", "alpha": 1.2,
+          "min": 100000000000000000000000, "max": 10000000000000000000000000},
+        "rewardPer": {"min": 1000, "max": 8000}
+      }],
+      "delegators": [{
+        "count": 500, "joinEpoch": 0, "autoCompound": 0.5, "target": "stake",
+        "amount": {"distribution": "uniform",
+          "min": 100000000000000000000, "max": 1000000000000000000000000}
+      }]
+    }
+
+The amounts of the file are integers in von. The blocks of an epoch are produced
+by its verifiers in turn, nobody is slashed, unstakes or votes. The run time
+grows with the delegators, their rewards are settled every epoch as on chain.`,
+			},
+		},
+	}
+)
+
+func econSimulate(ctx *cli.Context) error {
+	// the plugins log every epoch
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlWarn, log.Root().GetHandler()))
+
+	format := ctx.String(econFormatFlag.Name)
+	if format != "csv" && format != "json" {
+		utils.Fatalf("Unknown output format %q", format)
+	}
+
+	// the main net model, as the default genesis block sets it
+	model := xcom.GetEc(xcom.DefaultMainNet)
+	xcom.SetNodeBlockTimeWindow(configs.MainnetChainConfig.Pbft.Period / 1000)
+	xcom.SetPerRoundBlocks(uint64(configs.MainnetChainConfig.Pbft.Amount))
+	if file := ctx.String(econModelFlag.Name); file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read the economic model: %v", err)
+		}
+		if err := json.Unmarshal(data, model); err != nil {
+			utils.Fatalf("Invalid economic model %s: %v", file, err)
+		}
+	}
+
+	cfg := econsim.DefaultConfig()
+	if file := ctx.String(econDistFlag.Name); file != "" {
+		var err error
+		if cfg, err = econsim.LoadConfig(file); err != nil {
+			utils.Fatalf("Failed to load the distribution: %v", err)
+		}
+	}
+	if ctx.IsSet(econYearsFlag.Name) {
+		cfg.Years = uint32(ctx.Int(econYearsFlag.Name))
+	}
+
+	report, err := econsim.Simulate(cfg, func(year *econsim.YearReport) {
+		fmt.Fprintf(os.Stderr, "Year %d: %d epochs, cumulative issue %v, validator APY %.2f%%, delegator APY %.2f%%\n",
+			year.Year, year.Epochs, year.CumulativeIssue, year.ValidatorAPY*100, year.DelegatorAPY*100)
+	})
+	if err != nil {
+		utils.Fatalf("Failed to simulate: %v", err)
+	}
+
+	writeEconOutput(ctx.String(econOutputFlag.Name), func(w io.Writer) error {
+		if format == "json" {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+		return report.WriteYearsCSV(w)
+	})
+	if file := ctx.String(econNodesFlag.Name); file != "" {
+		writeEconOutput(file, report.WriteNodesCSV)
+	}
+	return nil
+}
+
+func writeEconOutput(file string, write func(w io.Writer) error) {
+	if file == "" {
+		if err := write(os.Stdout); err != nil {
+			utils.Fatalf("Failed to write the projection: %v", err)
+		}
+		return
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		utils.Fatalf("Failed to create %s: %v", file, err)
+	}
+	defer f.Close()
+	if err := write(f); err != nil {
+		utils.Fatalf("Failed to write %s: %v", file, err)
+	}
+}
@@ -80,6 +80,8 @@ var (
 		//utils.MinerGasLimitFlag,
 		utils.MinerGasPriceFlag,
 		//	utils.MinerExtraDataFlag,
+		utils.ReporterAccountFlag,
+		utils.ReporterGasPriceFlag,
 		//utils.MinerLegacyExtraDataFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
@@ -147,8 +149,14 @@ var (
 	pbftFlags = []cli.Flag{
 		utils.PbftPeerMsgQueueSize,
 		utils.PbftWalDisabledFlag,
+		utils.PbftRecordFileFlag,
 		utils.PbftMaxPingLatency,
 		utils.PbftBlsPriKeyFileFlag,
+		utils.PbftBlsPasswordFileFlag,
+		utils.PbftSignerFlag,
+		utils.PbftSignerCertFlag,
+		utils.PbftSignerKeyFlag,
+		utils.PbftSignerCAFlag,
 		utils.PbftBlacklistDeadlineFlag,
 	}
 
@@ -158,11 +166,13 @@ var (
 		utils.DBGCTimeoutFlag,
 		utils.DBGCMptFlag,
 		utils.DBGCBlockFlag,
+		utils.DBArchiveFlag,
 	}
 
 	vmFlags = []cli.Flag{
 		utils.VMWasmType,
 		utils.VmTimeoutDuration,
+		utils.VMWasmModuleStore,
 	}
 )
 
@@ -182,6 +192,12 @@ func init() {
 		removedbCommand,
 		dumpCommand,
 		inspectCommand,
+		// See walcmd.go:
+		walCommand,
+		// See protectioncmd.go:
+		protectionCommand,
+		// See econcmd.go:
+		econCommand,
 		// See accountcmd.go:
 		accountCommand,
 		// See consolecmd.go:
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protection"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+)
+
+var (
+	protectionCommand = cli.Command{
+		Name:     "protection",
+		Usage:    "Manage the double-sign protection database",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+
+The double-sign protection database records the highest consensus messages
+signed by the validator. Export it from the old node and import it into the
+new one before starting it when a validator is moved to new hardware.
+
+The database is stored under <DATADIR>/phoenixchain/protection.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the signed records to a file",
+				Action:    utils.MigrateFlags(protectionExport),
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    phoenixchain protection export <file>
+
+Write the highest signed messages of the node to <file> in the JSON interchange
+format. The node must be stopped.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import the signed records from a file",
+				Action:    utils.MigrateFlags(protectionImport),
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    phoenixchain protection import <file>
+
+Merge the records of <file> into the database, keeping the highest signed
+message of each type. The records must have been exported by the same node
+key. The node must be stopped.`,
+			},
+		},
+	}
+)
+
+func openProtectionDatabase(ctx *cli.Context) (protection.Database, discover.NodeID) {
+	stack, cfg := makeConfigNode(ctx)
+	defer stack.Close()
+
+	path := stack.ResolvePath(cfg.Eth.PbftConfig.ProtectionDir)
+	if path == "" {
+		utils.Fatalf("The double-sign protection database requires a data directory")
+	}
+	db, err := protection.NewBaseDatabase(path)
+	if err != nil {
+		utils.Fatalf("Failed to open double-sign protection database: %v", err)
+	}
+	return db, discover.PubkeyID(&stack.Config().NodeKey().PublicKey)
+}
+
+func protectionExport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	db, nodeID := openProtectionDatabase(ctx)
+	defer db.Close()
+
+	f, err := os.OpenFile(ctx.Args().First(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		utils.Fatalf("Failed to create export file: %v", err)
+	}
+	defer f.Close()
+
+	if err := protection.Export(db, nodeID, f); err != nil {
+		utils.Fatalf("Failed to export signed records: %v", err)
+	}
+	log.Info("Exported signed records", "file", ctx.Args().First())
+	return nil
+}
+
+func protectionImport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	db, nodeID := openProtectionDatabase(ctx)
+	defer db.Close()
+
+	f, err := os.Open(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to open import file: %v", err)
+	}
+	defer f.Close()
+
+	if err := protection.Import(db, nodeID, f); err != nil {
+		utils.Fatalf("Failed to import signed records: %v", err)
+	}
+	log.Info("Imported signed records", "file", ctx.Args().First())
+	return nil
+}
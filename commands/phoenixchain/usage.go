@@ -165,6 +165,13 @@ var AppHelpFlagGroups = []flagGroup{
 			//	utils.MinerExtraDataFlag,
 		},
 	},
+	{
+		Name: "EVIDENCE REPORTER",
+		Flags: []cli.Flag{
+			utils.ReporterAccountFlag,
+			utils.ReporterGasPriceFlag,
+		},
+	},
 	{
 		Name: "GAS PRICE ORACLE",
 		Flags: []cli.Flag{
@@ -207,8 +214,14 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.PbftPeerMsgQueueSize,
 			utils.PbftWalDisabledFlag,
+			utils.PbftRecordFileFlag,
 			utils.PbftMaxPingLatency,
 			utils.PbftBlsPriKeyFileFlag,
+			utils.PbftBlsPasswordFileFlag,
+			utils.PbftSignerFlag,
+			utils.PbftSignerCertFlag,
+			utils.PbftSignerKeyFlag,
+			utils.PbftSignerCAFlag,
 			utils.PbftBlacklistDeadlineFlag,
 		},
 	},
@@ -220,6 +233,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.DBGCTimeoutFlag,
 			utils.DBGCMptFlag,
 			utils.DBGCBlockFlag,
+			utils.DBArchiveFlag,
 		},
 	},
 	{
@@ -227,6 +241,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.VMWasmType,
 			utils.VmTimeoutDuration,
+			utils.VMWasmModuleStore,
 		},
 	},
 	{
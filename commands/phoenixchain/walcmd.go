@@ -0,0 +1,336 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/wal"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/web"
+)
+
+var (
+	walCommand = cli.Command{
+		Name:     "wal",
+		Usage:    "Inspect and repair the consensus write-ahead log",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+
+Read the pbft write-ahead log of a stopped node: the chainState, the viewChange
+meta, the stored viewChangeQCs and the journaled consensus messages.
+
+The wal is stored under <DATADIR>/phoenixchain/wal.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "inspect",
+				Usage:  "Print a summary of the wal",
+				Action: utils.MigrateFlags(walInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+Print the chainState, the viewChange meta, the number of stored viewChangeQCs
+and the journal files with the number of messages and views they contain.`,
+			},
+			{
+				Name:   "dump",
+				Usage:  "Dump the content of the wal as JSON",
+				Action: utils.MigrateFlags(walDump),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+Dump the chainState, the viewChange meta, the stored viewChangeQCs and every
+journaled consensus message as JSON. Blocks are shown by their header.`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Check that the wal can be loaded",
+				Action: utils.MigrateFlags(walVerify),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+Decode every record of the wal and report the ones that can not be loaded,
+such as a journal entry with a bad checksum.`,
+			},
+			{
+				Name:      "truncate",
+				Usage:     "Truncate the wal to the given epoch and view",
+				Action:    utils.MigrateFlags(walTruncate),
+				ArgsUsage: "<epoch> <viewNumber>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    phoenixchain wal truncate <epoch> <viewNumber>
+
+Drop the journaled messages and the viewChangeQCs of views later than the given
+one, together with any corrupted tail of the journal. Use it to recover a node
+whose wal can not be loaded anymore. This is a destructive action.`,
+			},
+		},
+	}
+)
+
+// errStopWalJournal stops reading the journal once the inspected file is done.
+var errStopWalJournal = errors.New("stop reading journal")
+
+func openWalInspector(ctx *cli.Context) *wal.Inspector {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	in, err := wal.NewInspector(wal.NodeWalDir(stack))
+	if err != nil {
+		utils.Fatalf("Failed to open wal: %v", err)
+	}
+	return in
+}
+
+func walInspect(ctx *cli.Context) error {
+	in := openWalInspector(ctx)
+	defer in.Close()
+
+	cs, err := in.ChainState()
+	if err != nil {
+		utils.Fatalf("Failed to read chainState: %v", err)
+	}
+	if cs.ValidChainState() {
+		fmt.Printf("ChainState:      commit %d (%s), lock %d, qc %d\n",
+			cs.Commit.Block.NumberU64(), cs.Commit.Block.Hash().TerminalString(), cs.Lock.Block.NumberU64(), cs.QC[0].Block.NumberU64())
+	} else {
+		fmt.Println("ChainState:      none")
+	}
+	vc, err := in.ViewChange()
+	if err != nil {
+		utils.Fatalf("Failed to read viewChange meta: %v", err)
+	}
+	if vc != nil {
+		fmt.Printf("ViewChange:      epoch %d, view %d, block %d, journal wal.%d seq %d\n", vc.Epoch, vc.ViewNumber, vc.BlockNumber, vc.FileID, vc.Seq)
+	} else {
+		fmt.Println("ViewChange:      none")
+	}
+	qcs, err := in.ViewChangeQCs()
+	if err != nil {
+		utils.Fatalf("Failed to read viewChangeQCs: %v", err)
+	}
+	fmt.Printf("ViewChangeQCs:   %d\n", len(qcs))
+
+	files, err := in.JournalFiles()
+	if err != nil {
+		utils.Fatalf("Failed to list journal files: %v", err)
+	}
+	for _, file := range files {
+		var (
+			count      int
+			first, end *wal.JournalEntry
+		)
+		err := in.Journal(file.FileID, 0, func(entry *wal.JournalEntry) error {
+			if entry.FileID != file.FileID {
+				return errStopWalJournal
+			}
+			if first == nil {
+				first = entry
+			}
+			end = entry
+			count++
+			return nil
+		})
+		if err != nil && err != errStopWalJournal {
+			fmt.Printf("Journal wal.%d:  %d bytes, %d messages, %v\n", file.FileID, file.Size, count, err)
+			continue
+		}
+		if count == 0 {
+			fmt.Printf("Journal wal.%d:  %d bytes, no messages\n", file.FileID, file.Size)
+			continue
+		}
+		fmt.Printf("Journal wal.%d:  %d bytes, %d messages, from epoch %d view %d to epoch %d view %d\n",
+			file.FileID, file.Size, count, first.Epoch(), first.ViewNumber(), end.Epoch(), end.ViewNumber())
+	}
+	return nil
+}
+
+func walDump(ctx *cli.Context) error {
+	in := openWalInspector(ctx)
+	defer in.Close()
+
+	cs, err := in.ChainState()
+	if err != nil {
+		utils.Fatalf("Failed to read chainState: %v", err)
+	}
+	vc, err := in.ViewChange()
+	if err != nil {
+		utils.Fatalf("Failed to read viewChange meta: %v", err)
+	}
+	qcs, err := in.ViewChangeQCs()
+	if err != nil {
+		utils.Fatalf("Failed to read viewChangeQCs: %v", err)
+	}
+	type dumpEntry struct {
+		FileID    uint32      `json:"fileID"`
+		Seq       uint64      `json:"seq"`
+		Timestamp uint64      `json:"timestamp"`
+		MsgType   uint16      `json:"msgType"`
+		Msg       interface{} `json:"msg"`
+	}
+	entries := make([]*dumpEntry, 0)
+	journalErr := in.Journal(0, 0, func(entry *wal.JournalEntry) error {
+		entries = append(entries, &dumpEntry{
+			FileID:    entry.FileID,
+			Seq:       entry.Seq,
+			Timestamp: entry.Timestamp,
+			MsgType:   entry.MsgType,
+			Msg:       walMessageJSON(entry.Msg),
+		})
+		return nil
+	})
+
+	dump := struct {
+		ChainState    interface{}               `json:"chainState"`
+		ViewChange    *wal.ViewChangeMessage    `json:"viewChange"`
+		ViewChangeQCs []*wal.StoredViewChangeQC `json:"viewChangeQCs"`
+		Journal       []*dumpEntry              `json:"journal"`
+		JournalError  string                    `json:"journalError,omitempty"`
+	}{
+		ChainState:    walChainStateJSON(cs),
+		ViewChange:    vc,
+		ViewChangeQCs: qcs,
+		Journal:       entries,
+	}
+	if journalErr != nil {
+		dump.JournalError = journalErr.Error()
+	}
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode wal: %v", err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func walVerify(ctx *cli.Context) error {
+	in := openWalInspector(ctx)
+	defer in.Close()
+
+	errs := in.Verify()
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		utils.Fatalf("Wal verification failed, %d problems found", len(errs))
+	}
+	fmt.Println("Wal verified successfully")
+	return nil
+}
+
+func walTruncate(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments: <epoch> <viewNumber>")
+	}
+	epoch, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid epoch: %v", err)
+	}
+	viewNumber, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid view number: %v", err)
+	}
+
+	in := openWalInspector(ctx)
+	defer in.Close()
+
+	confirm, err := web.Stdin.PromptConfirm(fmt.Sprintf("Truncate wal to epoch %d view %d?", epoch, viewNumber))
+	switch {
+	case err != nil:
+		utils.Fatalf("%v", err)
+	case !confirm:
+		log.Info("Wal truncation skipped")
+	default:
+		if err := in.Truncate(epoch, viewNumber); err != nil {
+			utils.Fatalf("Failed to truncate wal: %v", err)
+		}
+		log.Info("Wal successfully truncated", "epoch", epoch, "viewNumber", viewNumber)
+	}
+	return nil
+}
+
+// walChainStateJSON replaces the blocks of the chainState by their headers.
+func walChainStateJSON(cs *protocols.ChainState) interface{} {
+	if cs == nil {
+		return nil
+	}
+	type state struct {
+		Block      *types.Header `json:"block"`
+		QuorumCert interface{}   `json:"quorumCert"`
+	}
+	convert := func(s *protocols.State) *state {
+		if s == nil {
+			return nil
+		}
+		return &state{Block: walBlockJSON(s.Block), QuorumCert: s.QuorumCert}
+	}
+	qcs := make([]*state, 0, len(cs.QC))
+	for _, s := range cs.QC {
+		qcs = append(qcs, convert(s))
+	}
+	return map[string]interface{}{
+		"commit": convert(cs.Commit),
+		"lock":   convert(cs.Lock),
+		"qc":     qcs,
+	}
+}
+
+// walMessageJSON replaces the blocks of a journaled message by their headers.
+func walMessageJSON(msg interface{}) interface{} {
+	switch m := msg.(type) {
+	case *protocols.ConfirmedViewChange:
+		return map[string]interface{}{
+			"epoch":        m.Epoch,
+			"viewNumber":   m.ViewNumber,
+			"block":        walBlockJSON(m.Block),
+			"qc":           m.QC,
+			"viewChangeQC": m.ViewChangeQC,
+		}
+	case *protocols.SendViewChange:
+		return map[string]interface{}{
+			"viewChange": m.ViewChange,
+		}
+	case *protocols.SendPrepareBlock:
+		return map[string]interface{}{
+			"epoch":         m.Prepare.Epoch,
+			"viewNumber":    m.Prepare.ViewNumber,
+			"block":         walBlockJSON(m.Prepare.Block),
+			"blockIndex":    m.Prepare.BlockIndex,
+			"proposalIndex": m.Prepare.ProposalIndex,
+			"prepareQC":     m.Prepare.PrepareQC,
+			"viewChangeQC":  m.Prepare.ViewChangeQC,
+			"signature":     m.Prepare.Signature,
+		}
+	case *protocols.SendPrepareVote:
+		return map[string]interface{}{
+			"block": walBlockJSON(m.Block),
+			"vote":  m.Vote,
+		}
+	case *protocols.SendPreCommit:
+		return map[string]interface{}{
+			"block": walBlockJSON(m.Block),
+			"vote":  m.Vote,
+		}
+	}
+	return msg
+}
+
+func walBlockJSON(block *types.Block) *types.Header {
+	if block == nil {
+		return nil
+	}
+	return block.Header()
+}
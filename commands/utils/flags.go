@@ -24,9 +24,11 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/signer"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/reporter"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/les"
@@ -38,7 +40,6 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/fdlimit"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
-	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/ethdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/metrics"
@@ -259,6 +260,16 @@ var (
 		Usage: "Minimum gas price for mining a transaction",
 		Value: eth2.DefaultConfig.Miner.GasPrice,
 	}
+	// Evidence reporter settings
+	ReporterAccountFlag = cli.StringFlag{
+		Name:  "reporter.account",
+		Usage: "Account reporting the duplicate sign evidences to the slashing contract, must be unlocked (disabled if empty)",
+	}
+	ReporterGasPriceFlag = BigFlag{
+		Name:  "reporter.gasprice",
+		Usage: "Gas price of the evidence report transactions",
+		Value: eth2.DefaultConfig.Miner.GasPrice,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -539,6 +550,11 @@ var (
 		Usage: "Disable the Wal server",
 	}
 
+	PbftRecordFileFlag = cli.StringFlag{
+		Name:  "pbft.record",
+		Usage: "Record the received consensus messages to the given capture file",
+	}
+
 	PbftMaxPingLatency = cli.Int64Flag{
 		Name:  "pbft.max_ping_latency",
 		Usage: "Maximum latency of ping",
@@ -550,6 +566,31 @@ var (
 		Usage: "BLS key file",
 	}
 
+	PbftBlsPasswordFileFlag = cli.StringFlag{
+		Name:  "pbft.blskey.password",
+		Usage: "Password file to unlock the encrypted BLS key file",
+	}
+
+	PbftSignerFlag = cli.StringFlag{
+		Name:  "pbft.signer",
+		Usage: "Endpoint of the remote signer holding the BLS key (unix:///path/to/socket or tcp://host:port), the node key stays local",
+	}
+
+	PbftSignerCertFlag = cli.StringFlag{
+		Name:  "pbft.signer.cert",
+		Usage: "TLS certificate presented to the remote consensus signer",
+	}
+
+	PbftSignerKeyFlag = cli.StringFlag{
+		Name:  "pbft.signer.key",
+		Usage: "TLS private key of the certificate presented to the remote consensus signer",
+	}
+
+	PbftSignerCAFlag = cli.StringFlag{
+		Name:  "pbft.signer.ca",
+		Usage: "CA certificate that signed the certificate of the remote consensus signer",
+	}
+
 	PbftBlacklistDeadlineFlag = cli.StringFlag{
 		Name:  "pbft.blacklist_deadline",
 		Usage: "Blacklist effective time. uint:minute",
@@ -579,6 +620,10 @@ var (
 		Usage: "Number of cache block states, default 10",
 		Value: eth2.DefaultConfig.DBGCBlock,
 	}
+	DBArchiveFlag = cli.BoolFlag{
+		Name:  "db.archive",
+		Usage: "Keeps the history of PPOS state, so the PPOS queries can be made at any block",
+	}
 
 	VMWasmType = cli.StringFlag{
 		Name:   "vm.wasm_type",
@@ -593,6 +638,13 @@ var (
 		EnvVar: "",
 		Value:  eth2.DefaultConfig.VmTimeoutDuration,
 	}
+
+	VMWasmModuleStore = cli.IntFlag{
+		Name:   "vm.wasm_module_store",
+		Usage:  "Megabytes of the compiled wasm modules kept in the database (0 = disabled)",
+		EnvVar: "",
+		Value:  eth2.DefaultConfig.VMWasmModuleStore,
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -1051,14 +1103,29 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	}
 }
 
+func setReporter(ctx *cli.Context, ks *keystore.KeyStore, cfg *reporter.Config) {
+	if ctx.GlobalIsSet(ReporterAccountFlag.Name) {
+		account, err := MakeAddress(ks, ctx.GlobalString(ReporterAccountFlag.Name))
+		if err != nil {
+			Fatalf("Invalid evidence reporter account: %v", err)
+		}
+		cfg.Account = account.Address
+	}
+	if ctx.GlobalIsSet(ReporterGasPriceFlag.Name) {
+		cfg.GasPrice = GlobalBig(ctx, ReporterGasPriceFlag.Name)
+	}
+}
+
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth2.Config) {
 	// Avoid conflicting network flags
 	CheckExclusive(ctx, TestnetFlag)
 	CheckExclusive(ctx, LightServFlag, SyncModeFlag, "light")
 
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
+	setReporter(ctx, ks, &cfg.Reporter)
 	// for mpc compute
 	//setMpcPool(ctx, &cfg.MPCPool)
 	//setVcPool(ctx, &cfg.VCPool)
@@ -1132,6 +1199,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth2.Config) {
 			cfg.DBGCBlock = b
 		}
 	}
+	if ctx.GlobalIsSet(DBArchiveFlag.Name) {
+		cfg.DBArchive = ctx.GlobalBool(DBArchiveFlag.Name)
+	}
 
 	// vm options
 	if ctx.GlobalIsSet(VMWasmType.Name) {
@@ -1140,30 +1210,61 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth2.Config) {
 	if ctx.GlobalIsSet(VmTimeoutDuration.Name) {
 		cfg.VmTimeoutDuration = ctx.GlobalUint64(VmTimeoutDuration.Name)
 	}
+	if ctx.GlobalIsSet(VMWasmModuleStore.Name) {
+		cfg.VMWasmModuleStore = ctx.GlobalInt(VMWasmModuleStore.Name)
+	}
 
 }
 
+// makeRemoteSigner connects to the remote consensus signer configured by the flags.
+func makeRemoteSigner(ctx *cli.Context) signer.Signer {
+	for _, flag := range []cli.StringFlag{PbftSignerCertFlag, PbftSignerKeyFlag, PbftSignerCAFlag} {
+		if !ctx.GlobalIsSet(flag.Name) {
+			Fatalf("Flag --%s is required by --%s", flag.Name, PbftSignerFlag.Name)
+		}
+	}
+	config, err := signer.NewTLSConfig(ctx.GlobalString(PbftSignerCertFlag.Name), ctx.GlobalString(PbftSignerKeyFlag.Name), ctx.GlobalString(PbftSignerCAFlag.Name))
+	if err != nil {
+		Fatalf("Failed to load remote signer certificates: %v", err)
+	}
+	s, err := signer.NewRemoteSigner(ctx.GlobalString(PbftSignerFlag.Name), config)
+	if err != nil {
+		Fatalf("Failed to connect to remote signer: %v", err)
+	}
+	return s
+}
+
 func SetPbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
 	if nodeCfg.P2P.PrivateKey != nil {
 		cfg.NodePriKey = nodeCfg.P2P.PrivateKey
 		cfg.NodeID = discover.PubkeyID(&cfg.NodePriKey.PublicKey)
 	}
 
-	if ctx.GlobalIsSet(PbftBlsPriKeyFileFlag.Name) {
-		priKey, err := bls.LoadBLS(ctx.GlobalString(PbftBlsPriKeyFileFlag.Name))
-		if err != nil {
-			Fatalf("Failed to load bls key from file: %v", err)
-		}
-		cfg.BlsPriKey = priKey
+	CheckExclusive(ctx, PbftSignerFlag, PbftBlsPriKeyFileFlag)
+	if ctx.GlobalIsSet(PbftSignerFlag.Name) {
+		cfg.Signer = makeRemoteSigner(ctx)
+		nodeCfg.P2P.BlsPublicKey = *(cfg.Signer.BlsPublicKey())
 	} else {
-		cfg.BlsPriKey = nodeCfg.BlsKey()
+		if ctx.GlobalIsSet(PbftBlsPriKeyFileFlag.Name) {
+			priKey, err := keystore.LoadBlsKey(ctx.GlobalString(PbftBlsPriKeyFileFlag.Name), makeBlsKeyPassword(ctx))
+			if err != nil {
+				Fatalf("Failed to load bls key from file: %v", err)
+			}
+			cfg.BlsPriKey = priKey
+		} else {
+			cfg.BlsPriKey = nodeCfg.BlsKey(makeBlsKeyPassword(ctx))
+		}
+		nodeCfg.P2P.BlsPublicKey = *(cfg.BlsPriKey.GetPublicKey())
 	}
-	nodeCfg.P2P.BlsPublicKey = *(cfg.BlsPriKey.GetPublicKey())
 
 	if ctx.GlobalIsSet(PbftWalDisabledFlag.Name) {
 		cfg.WalMode = !ctx.GlobalBool(PbftWalDisabledFlag.Name)
 	}
 
+	if ctx.GlobalIsSet(PbftRecordFileFlag.Name) {
+		cfg.RecordFile = ctx.GlobalString(PbftRecordFileFlag.Name)
+	}
+
 	if ctx.GlobalIsSet(PbftPeerMsgQueueSize.Name) {
 		cfg.PeerMsgQueueSize = ctx.GlobalUint64(PbftPeerMsgQueueSize.Name)
 	}
@@ -1177,6 +1278,20 @@ func SetPbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
 
 }
 
+// makeBlsKeyPassword reads the password of the BLS key file from the file
+// given by --pbft.blskey.password, only its first line is used.
+func makeBlsKeyPassword(ctx *cli.Context) string {
+	path := ctx.GlobalString(PbftBlsPasswordFileFlag.Name)
+	if path == "" {
+		return ""
+	}
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		Fatalf("Failed to read bls key password file: %v", err)
+	}
+	return strings.TrimRight(strings.SplitN(string(text), "\n", 2)[0], "\r")
+}
+
 // RegisterEthService adds an Ethereum client to the stack.
 func RegisterEthService(stack *node.Node, cfg *eth2.Config) {
 	var err error
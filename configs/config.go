@@ -112,9 +112,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, GenesisVersion}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, GenesisVersion}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1),  "", big.NewInt(0), big.NewInt(0), nil, new(PbftConfig), GenesisVersion}
+	TestChainConfig = &ChainConfig{big.NewInt(1),  "", big.NewInt(0), big.NewInt(0), nil, nil, nil, new(PbftConfig), GenesisVersion}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -140,6 +140,10 @@ type ChainConfig struct {
 	EmptyBlock  string   `json:"emptyBlock"`
 	EIP155Block *big.Int `json:"eip155Block,omitempty"` // EIP155 HF block
 	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
+
+	ValidatorsCommitBlock  *big.Int `json:"validatorsCommitBlock,omitempty"`  // Epoch switch blocks commit to the next validators (nil = no fork, 0 = already activated)
+	VoteParticipationBlock *big.Int `json:"voteParticipationBlock,omitempty"` // Blocks carry the QC of the committed head for the vote participation (nil = no fork, 0 = already activated)
+
 	// Various consensus engines
 	Clique *CliqueConfig `json:"clique,omitempty"`
 	Pbft   *PbftConfig   `json:"pbft,omitempty"`
@@ -209,6 +213,16 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsValidatorsCommit returns whether num represents a block number after the validators commitment fork
+func (c *ChainConfig) IsValidatorsCommit(num *big.Int) bool {
+	return isForked(c.ValidatorsCommitBlock, num)
+}
+
+// IsVoteParticipation returns whether num represents a block number after the vote participation fork
+func (c *ChainConfig) IsVoteParticipation(num *big.Int) bool {
+	return isForked(c.VoteParticipationBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -241,6 +255,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.ValidatorsCommitBlock, newcfg.ValidatorsCommitBlock, head) {
+		return newCompatError("validators commit fork block", c.ValidatorsCommitBlock, newcfg.ValidatorsCommitBlock)
+	}
+	if isForkIncompatible(c.VoteParticipationBlock, newcfg.VoteParticipationBlock, head) {
+		return newCompatError("vote participation fork block", c.VoteParticipationBlock, newcfg.VoteParticipationBlock)
+	}
 	return nil
 }
 
@@ -124,6 +124,10 @@ const (
 	WithdrewStakeGas      uint64 = 20000 // Gas needed for withdrewStaking
 	DelegateGas           uint64 = 16000 // Gas needed for delegate
 	WithdrewDelegationGas uint64 = 8000  // Gas needed for withdrewDelegate
+	RedelegateGas         uint64 = 20000 // Gas needed for redelegate
+	SetAutoCompoundGas    uint64 = 8000  // Gas needed for setAutoCompound
+	RotateBlsKeyGas       uint64 = 20000 // Gas needed for rotateBlsKey
+	SetOperatorGas        uint64 = 8000  // Gas needed for setOperator
 
 	GovGas                   uint64 = 9000   // Gas needed for precompiled contract: govContract
 	SubmitTextProposalGas    uint64 = 320000 // Gas needed for submitText
@@ -131,6 +135,7 @@ const (
 	SubmitCancelProposalGas  uint64 = 500000 // Gas needed for submitCancel
 	SubmitParamProposalGas   uint64 = 500000 // Gas needed for submitParam
 	VoteGas                  uint64 = 2000   // Gas needed for vote
+	DelegatorVoteGas         uint64 = 1000   // Gas needed for voteByDelegator, every delegation of the delegator
 	DeclareVersionGas        uint64 = 3000   // Gas needed for declareVersion
 
 	SlashingGas              uint64 = 21000 // Gas needed for precompiled contract: slashingContract
@@ -139,6 +144,7 @@ const (
 	RestrictingPlanGas       uint64 = 18000 // Gas needed for precompiled contract: restrictingPlanContract
 	CreateRestrictingPlanGas uint64 = 8000  // Gas needed for createRestrictingPlan
 	ReleasePlanGas           uint64 = 21000 // Gas consumed every time the von of the restrictPlan is released
+	RevokeRestrictingPlanGas uint64 = 8000  // Gas needed for revokeRestrictingPlan
 
 	DelegateRewardGas         uint64 = 3000 // Gas needed for  delegate reward
 	WithdrawDelegateRewardGas uint64 = 8000 // Gas needed for withdraw  delegate reward
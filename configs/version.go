@@ -7,8 +7,8 @@ import (
 const (
 	//These versions are meaning the current code version.
 	VersionMajor = 1          // Major version component of the current release
-	VersionMinor = 1          // Minor version component of the current release
-	VersionPatch = 1          // Patch version component of the current release
+	VersionMinor = 2          // Minor version component of the current release
+	VersionPatch = 0          // Patch version component of the current release
 	VersionMeta  = "unstable" // Version metadata to append to the version string
 
 	//CAUTION: DO NOT MODIFY THIS ONCE THE CHAIN HAS BEEN INITIALIZED!!!
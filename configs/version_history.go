@@ -3,4 +3,5 @@ package configs
 const (
 	FORKVERSION_0_11_0 = uint32(0<<16 | 11<<8 | 0)
 	FORKVERSION_1_1_0  = uint32(1<<16 | 1<<8 | 0)
+	FORKVERSION_1_2_0  = uint32(1<<16 | 2<<8 | 0)
 )
@@ -1,11 +1,16 @@
 package pbft
 
 import (
+	"context"
 	"encoding/json"
+	"reflect"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/finality"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/state"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rpc"
 )
 
 type Status struct {
@@ -19,7 +24,17 @@ type API interface {
 	Status() []byte
 	Evidences() string
 	GetPrepareQC(number uint64) *types.QuorumCert
+	GetFinalityProof(number uint64) (*finality.Proof, error)
+	GetValidatorTransitions(from uint64) ([]*finality.Transition, error)
 	GetSchnorrNIZKProve() (*bls.SchnorrProof, error)
+	SubscribeConsensusEvents() *event.TypeMuxSubscription
+}
+
+// ConsensusEvent is the notification sent to the consensus event subscribers,
+// Type is the name of the event type, e.g. ViewChangeEvent.
+type ConsensusEvent struct {
+	Type  string      `json:"type"`
+	Event interface{} `json:"event"`
 }
 
 // PublicDebugConsensusAPI provides an API to access the PhoenixChain blockchain.
@@ -67,6 +82,68 @@ func (s *PublicPhoenixchainConsensusAPI) Evidences() string {
 	return s.engine.Evidences()
 }
 
+// PublicPbftConsensusAPI provides an API to access the PhoenixChain blockchain.
+// It offers only methods that operate on public data that
+// is freely available to anyone.
+type PublicPbftConsensusAPI struct {
+	engine API
+}
+
+// NewPublicPbftConsensusAPI creates a new PhoenixChain blockchain API.
+func NewPublicPbftConsensusAPI(engine API) *PublicPbftConsensusAPI {
+	return &PublicPbftConsensusAPI{engine: engine}
+}
+
+// GetFinalityProof returns the header, the precommit QC and the validator set
+// of the committed block of the specified height, the result can be verified
+// offline with the finality package.
+func (s *PublicPbftConsensusAPI) GetFinalityProof(number uint64) (*finality.Proof, error) {
+	return s.engine.GetFinalityProof(number)
+}
+
+// GetValidatorTransitions returns the validator set transitions of the epoch
+// switch blocks starting with the epoch of the specified block, use 0 to start
+// from genesis or the number of a trusted checkpoint.
+func (s *PublicPbftConsensusAPI) GetValidatorTransitions(from uint64) ([]*finality.Transition, error) {
+	return s.engine.GetValidatorTransitions(from)
+}
+
+// ConsensusEvents creates a subscription streaming the view changes, quorum
+// certificates, proposer changes, block commits and validator switches of the
+// local consensus engine. Subscribe with pbft_subscribe("consensusEvents").
+func (s *PublicPbftConsensusAPI) ConsensusEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		sub := s.engine.SubscribeConsensusEvents()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-sub.Chan():
+				if ev == nil {
+					return
+				}
+				notifier.Notify(rpcSub.ID, &ConsensusEvent{
+					Type:  reflect.TypeOf(ev.Data).Name(),
+					Event: ev.Data,
+				})
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PublicAdminConsensusAPI provides an API to access the PhoenixChain blockchain.
 // It offers only methods that operate on public data that
 // is freely available to anyone.
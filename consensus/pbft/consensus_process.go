@@ -278,6 +278,7 @@ func (pbft *Pbft) insertQCBlock(block *types.Block, qc *ctypes.QuorumCert) {
 			"CommitHash", pbft.state.HighestCommitBlock().Hash())
 		return
 	}
+	pbft.postQCEvent(pbfttypes.QCPhasePreCommit, qc)
 	if pbft.state.Epoch() == qc.Epoch{
 		if pbft.state.ViewBlockByIndex(qc.BlockNumber) == nil {
 			pbft.state.AddQCBlock(block, qc)
@@ -640,6 +641,7 @@ func (pbft *Pbft) findPreVoteQC(blockNumber uint64) {
 			//pbft.trySendPrepareVote()
 			pbft.state.SetPrepareVoteQC(qc)
 			pbft.state.AddQC(qc)
+			pbft.postQCEvent(pbfttypes.QCPhasePrepare, qc)
 			pbft.genPreCommit(qc)
 		}
 	}
@@ -697,10 +699,12 @@ func (pbft *Pbft) tryAddStateBlockNumber(qc *ctypes.QuorumCert) {
 	}
 	if qc.BlockNumber==0{
 		pbft.state.AddBlockNumber(qc)
+		pbft.checkProposerChange()
 		return
 	}
 	if qc.BlockNumber==pbft.state.BlockNumber(){
 		pbft.state.AddBlockNumber(qc)
+		pbft.checkProposerChange()
 	}
 	return
 }
@@ -789,6 +793,7 @@ func (pbft *Pbft) tryChangeView() {
 	if shouldSwitch {
 		if err := pbft.validatorPool.Update(block.NumberU64(), pbft.state.Epoch()+1, pbft.eventMux); err == nil {
 			pbft.log.Info("Update validator success", "number", block.NumberU64())
+			pbft.postValidatorSwitchEvent(pbft.state.Epoch() + 1)
 		}
 	}
 
@@ -973,6 +978,8 @@ func (pbft *Pbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	pbft.SyncPrepareBlock("", epoch, block.NumberU64(), 0,pbft.state.ViewNumber())
 	pbft.log = log.New("epoch", pbft.state.Epoch(), "view", pbft.state.ViewNumber(),"blockNumber",pbft.state.BlockNumber())
 	pbft.log.Info("Success to change view, current view deadline", "deadline", pbft.state.Deadline())
+	pbft.postViewChangeEvent(preEpoch, preViewNumber, viewChangeQC)
+	pbft.checkProposerChange()
 }
 
 // Clean up invalid blocks in the previous view
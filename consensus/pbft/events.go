@@ -0,0 +1,135 @@
+package pbft
+
+import (
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
+
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+)
+
+// Size of the queue of the consensus events waiting to be posted
+const consensusEventQueueSize = 256
+
+// SubscribeConsensusEvents subscribes to the events describing the progress of
+// the consensus: view changes, quorum certificates, proposer changes, block
+// commits and validator switches.
+func (pbft *Pbft) SubscribeConsensusEvents() *event.TypeMuxSubscription {
+	return pbft.eventMux.Subscribe(
+		pbfttypes.ViewChangeEvent{},
+		pbfttypes.QCEvent{},
+		pbfttypes.ProposerChangeEvent{},
+		pbfttypes.BlockCommitEvent{},
+		pbfttypes.ValidatorSwitchEvent{},
+	)
+}
+
+// postConsensusEvent queues an event for the event mux. The consensus never
+// waits for the subscribers, the event is dropped if the queue is full.
+func (pbft *Pbft) postConsensusEvent(ev interface{}) {
+	if pbft.consensusEventCh == nil || pbft.isLoading() {
+		return
+	}
+	select {
+	case pbft.consensusEventCh <- ev:
+	default:
+		pbft.log.Debug("Consensus event queue is full, drop event", "event", ev)
+	}
+}
+
+// consensusEventLoop posts the queued consensus events to the event mux.
+func (pbft *Pbft) consensusEventLoop() {
+	for {
+		select {
+		case ev := <-pbft.consensusEventCh:
+			pbft.eventMux.Post(ev)
+		case <-pbft.exitCh:
+			return
+		}
+	}
+}
+
+func (pbft *Pbft) consensusView() pbfttypes.ConsensusView {
+	return pbfttypes.ConsensusView{
+		Epoch:       pbft.state.Epoch(),
+		ViewNumber:  pbft.state.ViewNumber(),
+		BlockNumber: pbft.state.BlockNumber(),
+	}
+}
+
+func qcView(qc *ctypes.QuorumCert) pbfttypes.ConsensusView {
+	return pbfttypes.ConsensusView{
+		Epoch:       qc.Epoch,
+		ViewNumber:  qc.ViewNumber,
+		BlockNumber: qc.BlockNumber,
+	}
+}
+
+func (pbft *Pbft) postViewChangeEvent(preEpoch, preViewNumber uint64, viewChangeQC *ctypes.ViewChangeQC) {
+	reason := pbfttypes.ViewChangeReasonBlocksProduced
+	if viewChangeQC != nil {
+		reason = pbfttypes.ViewChangeReasonTimeout
+	} else if pbft.state.Epoch() > preEpoch {
+		reason = pbfttypes.ViewChangeReasonEpochSwitch
+	}
+	var timeout uint64
+	if d := time.Until(pbft.state.Deadline()); d > 0 {
+		timeout = uint64(d / time.Millisecond)
+	}
+	pbft.postConsensusEvent(pbfttypes.ViewChangeEvent{
+		ConsensusView:  pbft.consensusView(),
+		PrevEpoch:      preEpoch,
+		PrevViewNumber: preViewNumber,
+		Reason:         reason,
+		Timeout:        timeout,
+	})
+}
+
+func (pbft *Pbft) postQCEvent(phase string, qc *ctypes.QuorumCert) {
+	pbft.postConsensusEvent(pbfttypes.QCEvent{
+		ConsensusView: qcView(qc),
+		Phase:         phase,
+		BlockHash:     qc.BlockHash,
+	})
+}
+
+func (pbft *Pbft) postBlockCommitEvent(block *types.Block, qc *ctypes.QuorumCert) {
+	pbft.postConsensusEvent(pbfttypes.BlockCommitEvent{
+		ConsensusView: qcView(qc),
+		BlockHash:     block.Hash(),
+	})
+}
+
+// checkProposerChange posts a ProposerChangeEvent if the proposer of the
+// current view differs from the last one seen.
+func (pbft *Pbft) checkProposerChange() {
+	if pbft.isLoading() || pbft.validatorPool == nil || pbft.validatorPool.Len(pbft.state.Epoch()) == 0 {
+		return
+	}
+	proposer := pbft.currentProposer()
+	if proposer == nil || (pbft.lastProposer != nil && pbft.lastProposer.NodeID == proposer.NodeID) {
+		return
+	}
+	pbft.lastProposer = proposer
+	pbft.postConsensusEvent(pbfttypes.ProposerChangeEvent{
+		ConsensusView: pbft.consensusView(),
+		Index:         proposer.Index,
+		NodeID:        proposer.NodeID,
+	})
+}
+
+// postValidatorSwitchEvent posts the validators of the epoch loaded by the
+// validator pool.
+func (pbft *Pbft) postValidatorSwitchEvent(epoch uint64) {
+	validators := pbft.validatorPool.Validators(epoch)
+	if validators == nil {
+		return
+	}
+	pbft.postConsensusEvent(pbfttypes.ValidatorSwitchEvent{
+		ConsensusView:    pbft.consensusView(),
+		ValidBlockNumber: validators.ValidBlockNumber,
+		Validators:       validators.NodeList(),
+	})
+}
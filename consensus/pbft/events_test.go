@@ -0,0 +1,58 @@
+package pbft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
+)
+
+func waitConsensusEvent(t *testing.T, sub *event.TypeMuxSubscription) interface{} {
+	select {
+	case ev := <-sub.Chan():
+		return ev.Data
+	case <-time.After(time.Second):
+		t.Fatal("consensus event timeout")
+	}
+	return nil
+}
+
+func TestConsensusEvents(t *testing.T) {
+	pk, sk, pbftnodes := GeneratePbftNode(4)
+	node := MockNode(pk[0], sk[0], pbftnodes, 10000, 10)
+	assert.Nil(t, node.Start())
+	defer node.engine.Close()
+
+	sub := node.engine.SubscribeConsensusEvents()
+	defer sub.Unsubscribe()
+
+	engine := node.engine
+	genesis := node.chain.Genesis()
+	epoch, viewNumber := engine.state.Epoch(), engine.state.ViewNumber()
+	engine.changeView(epoch, viewNumber+1, genesis, nil, nil)
+
+	ev, ok := waitConsensusEvent(t, sub).(pbfttypes.ViewChangeEvent)
+	assert.True(t, ok)
+	assert.Equal(t, epoch, ev.Epoch)
+	assert.Equal(t, viewNumber+1, ev.ViewNumber)
+	assert.Equal(t, viewNumber, ev.PrevViewNumber)
+	assert.Equal(t, pbfttypes.ViewChangeReasonBlocksProduced, ev.Reason)
+	assert.True(t, ev.Timeout > 0)
+
+	proposer, ok := waitConsensusEvent(t, sub).(pbfttypes.ProposerChangeEvent)
+	assert.True(t, ok)
+	assert.Equal(t, engine.currentProposer().NodeID, proposer.NodeID)
+
+	// The proposer is only posted again when it changes.
+	engine.checkProposerChange()
+	qc := &ctypes.QuorumCert{Epoch: epoch, ViewNumber: viewNumber + 1, BlockHash: genesis.Hash()}
+	engine.postQCEvent(pbfttypes.QCPhasePrepare, qc)
+	qcEvent, ok := waitConsensusEvent(t, sub).(pbfttypes.QCEvent)
+	assert.True(t, ok)
+	assert.Equal(t, pbfttypes.QCPhasePrepare, qcEvent.Phase)
+	assert.Equal(t, genesis.Hash(), qcEvent.BlockHash)
+}
@@ -0,0 +1,253 @@
+// Package finality implements self-contained finality proofs for blocks
+// committed by the PBFT consensus. A proof carries the block header, the
+// precommit QC of the block and the validator set of the epoch in which
+// the QC was formed, so that it can be checked without trusting the node
+// that produced it. Transitions chain such proofs across epoch switch
+// blocks, letting a client that trusts one validator set follow the
+// validator sets of every later epoch.
+package finality
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrMissingHeader       = errors.New("finality proof: missing header")
+	ErrMissingQuorumCert   = errors.New("finality proof: missing quorum cert")
+	ErrMissingValidatorSet = errors.New("finality proof: missing validator set")
+	ErrNotPreCommit        = errors.New("finality proof: quorum cert is not a precommit qc")
+	ErrBlockMismatch       = errors.New("finality proof: quorum cert does not match the header")
+	ErrValidatorSetSize    = errors.New("finality proof: validator bitmap does not match the validator set")
+	ErrBelowThreshold      = errors.New("finality proof: not enough signatures")
+	ErrInvalidSignature    = errors.New("finality proof: invalid aggregated signature")
+
+	ErrMissingNextValidators  = errors.New("validator transition: missing next validator set")
+	ErrMissingValidatorsHash  = errors.New("validator transition: header carries no validators hash")
+	ErrValidatorsHashMismatch = errors.New("validator transition: validators hash does not match the next validator set")
+	ErrUntrustedValidatorSet  = errors.New("validator transition: validator set does not match the trusted set")
+)
+
+// Validator is the consensus identity of a single validator.
+type Validator struct {
+	Index     uint32           `json:"index"`
+	NodeID    discover.NodeID  `json:"nodeID"`
+	BlsPubKey bls.PublicKeyHex `json:"blsPubKey"`
+}
+
+// ValidatorSet is the set of validators that was active for an epoch,
+// ordered by validator index.
+type ValidatorSet struct {
+	ValidBlockNumber uint64       `json:"validBlockNumber"`
+	Validators       []*Validator `json:"validators"`
+}
+
+// NewValidatorSet converts the consensus validators into a ValidatorSet.
+func NewValidatorSet(vds *pbfttypes.Validators) *ValidatorSet {
+	nodes := make(pbfttypes.SortedValidatorNode, 0, vds.Len())
+	for _, node := range vds.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Sort(nodes)
+
+	set := &ValidatorSet{
+		ValidBlockNumber: vds.ValidBlockNumber,
+		Validators:       make([]*Validator, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		v := &Validator{
+			Index:  node.Index,
+			NodeID: node.NodeID,
+		}
+		if node.BlsPubKey != nil {
+			copy(v.BlsPubKey[:], node.BlsPubKey.Serialize())
+		}
+		set.Validators = append(set.Validators, v)
+	}
+	return set
+}
+
+// Len returns the number of validators in the set.
+func (vs *ValidatorSet) Len() int {
+	return len(vs.Validators)
+}
+
+// Hash returns the commitment to the validator set that epoch switch blocks
+// carry in their header.
+func (vs *ValidatorSet) Hash() common.Hash {
+	return rlpHash(vs)
+}
+
+// Threshold returns the minimum number of signatures (2f+1) a QC needs.
+func (vs *ValidatorSet) Threshold() int {
+	return Threshold(vs.Len())
+}
+
+// Threshold returns the 2f+1 threshold of a validator set of size num.
+func Threshold(num int) int {
+	return num - (num-1)/3
+}
+
+// VerifyQuorumCert checks that the QC is signed by at least 2f+1 validators
+// of the set and that the aggregated BLS signature is valid.
+func (vs *ValidatorSet) VerifyQuorumCert(qc *ctypes.QuorumCert) error {
+	if qc == nil || qc.ValidatorSet == nil {
+		return ErrMissingQuorumCert
+	}
+	if int(qc.ValidatorSet.Size()) != vs.Len() {
+		return ErrValidatorSetSize
+	}
+	if signs, threshold := qc.Len(), vs.Threshold(); signs < threshold {
+		return fmt.Errorf("%w: total:%d, threshold:%d", ErrBelowThreshold, signs, threshold)
+	}
+
+	var (
+		pub     bls.PublicKey
+		aggInit bool
+	)
+	for i, v := range vs.Validators {
+		if !qc.ValidatorSet.GetIndex(uint32(i)) {
+			continue
+		}
+		pk, err := v.BlsPubKey.ParseBlsPubKey()
+		if err != nil {
+			return fmt.Errorf("finality proof: invalid bls public key of validator %d: %v", v.Index, err)
+		}
+		if !aggInit {
+			pub = *pk
+			aggInit = true
+		} else {
+			pub.Add(pk)
+		}
+	}
+
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(qc.Signature.Bytes()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Proof is a finality proof of a committed block.
+type Proof struct {
+	Header       *types.Header      `json:"header"`
+	QuorumCert   *ctypes.QuorumCert `json:"quorumCert"`
+	ValidatorSet *ValidatorSet      `json:"validatorSet"`
+}
+
+// NewProof assembles a proof from the committed header, its QC and the
+// validators that were active when the QC was formed.
+func NewProof(header *types.Header, qc *ctypes.QuorumCert, vds *pbfttypes.Validators) *Proof {
+	return &Proof{
+		Header:       header,
+		QuorumCert:   qc,
+		ValidatorSet: NewValidatorSet(vds),
+	}
+}
+
+// Verify checks that the proof's QC is a precommit QC for the proof's
+// header and that it is signed by 2f+1 validators of the proof's set.
+//
+// The validator set itself is taken from the proof, callers are expected
+// to check it against a set they already trust.
+func (p *Proof) Verify() error {
+	if p.Header == nil {
+		return ErrMissingHeader
+	}
+	if p.QuorumCert == nil {
+		return ErrMissingQuorumCert
+	}
+	if p.ValidatorSet == nil || p.ValidatorSet.Len() == 0 {
+		return ErrMissingValidatorSet
+	}
+	if p.QuorumCert.Step != ctypes.RoundStepPreCommit {
+		return ErrNotPreCommit
+	}
+	if p.Header.Number == nil || p.Header.Number.Uint64() != p.QuorumCert.BlockNumber || p.Header.Hash() != p.QuorumCert.BlockHash {
+		return ErrBlockMismatch
+	}
+	return p.ValidatorSet.VerifyQuorumCert(p.QuorumCert)
+}
+
+// Transition proves the handover from the validators of an epoch to the
+// validators of the next one. The epoch switch block commits to the next
+// validator set in its header, and the block is committed by a QC of the
+// outgoing validators.
+type Transition struct {
+	Proof          *Proof        `json:"proof"`
+	NextValidators *ValidatorSet `json:"nextValidators"`
+}
+
+// NewTransition assembles a transition from the finality proof of an epoch
+// switch block and the validators that take over after it.
+func NewTransition(proof *Proof, next *pbfttypes.Validators) *Transition {
+	return &Transition{
+		Proof:          proof,
+		NextValidators: NewValidatorSet(next),
+	}
+}
+
+// Verify checks that the switch block is committed by the trusted validator
+// set and that it commits to the transition's next validator set.
+func (t *Transition) Verify(trusted *ValidatorSet) error {
+	if t.Proof == nil {
+		return ErrMissingHeader
+	}
+	if t.NextValidators == nil || t.NextValidators.Len() == 0 {
+		return ErrMissingNextValidators
+	}
+	if trusted == nil || t.Proof.ValidatorSet == nil || t.Proof.ValidatorSet.Hash() != trusted.Hash() {
+		return ErrUntrustedValidatorSet
+	}
+	if err := t.Proof.Verify(); err != nil {
+		return err
+	}
+	hash, ok := t.Proof.Header.ValidatorsHash()
+	if !ok {
+		return ErrMissingValidatorsHash
+	}
+	if hash != t.NextValidators.Hash() {
+		return ErrValidatorsHashMismatch
+	}
+	return nil
+}
+
+// VerifyTransitions walks the transitions in order starting from the trusted
+// validator set, and returns the validator set that is trusted after the last
+// one.
+func VerifyTransitions(trusted *ValidatorSet, transitions []*Transition) (*ValidatorSet, error) {
+	for _, t := range transitions {
+		if err := t.Verify(trusted); err != nil {
+			if t.Proof != nil && t.Proof.Header != nil && t.Proof.Header.Number != nil {
+				return nil, fmt.Errorf("transition at block %d: %w", t.Proof.Header.Number.Uint64(), err)
+			}
+			return nil, err
+		}
+		trusted = t.NextValidators
+	}
+	return trusted, nil
+}
+
+func rlpHash(x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
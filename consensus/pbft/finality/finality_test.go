@@ -0,0 +1,173 @@
+package finality
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+)
+
+func newTestValidators(t *testing.T, num int) (*pbfttypes.Validators, []*bls.SecretKey) {
+	vds := &pbfttypes.Validators{
+		Nodes:            make(pbfttypes.ValidateNodeMap, num),
+		ValidBlockNumber: 1,
+	}
+	secs := make([]*bls.SecretKey, 0, num)
+	for i := 0; i < num; i++ {
+		key, err := crypto.GenerateKey()
+		assert.Nil(t, err)
+		var sec bls.SecretKey
+		sec.SetByCSPRNG()
+		nodeID := discover.PubkeyID(&key.PublicKey)
+		vds.Nodes[nodeID] = &pbfttypes.ValidateNode{
+			Index:     uint32(i),
+			PubKey:    &key.PublicKey,
+			NodeID:    nodeID,
+			BlsPubKey: sec.GetPublicKey(),
+		}
+		secs = append(secs, &sec)
+	}
+	return vds, secs
+}
+
+func newTestProof(t *testing.T, signers int) *Proof {
+	vds, secs := newTestValidators(t, 4)
+	return signTestProof(t, &types.Header{Number: big.NewInt(10)}, vds, secs, signers)
+}
+
+func signTestProof(t *testing.T, header *types.Header, vds *pbfttypes.Validators, secs []*bls.SecretKey, signers int) *Proof {
+	qc := &ctypes.QuorumCert{
+		Epoch:        1,
+		ViewNumber:   2,
+		BlockHash:    header.Hash(),
+		BlockNumber:  header.Number.Uint64(),
+		Step:         ctypes.RoundStepPreCommit,
+		ValidatorSet: utils.NewBitArray(uint32(len(secs))),
+	}
+	msg, err := qc.CannibalizeBytes()
+	assert.Nil(t, err)
+
+	var aggSig bls.Sign
+	for i := 0; i < signers; i++ {
+		sig := secs[i].Sign(string(msg))
+		if i == 0 {
+			aggSig = *sig
+		} else {
+			aggSig.Add(sig)
+		}
+		qc.ValidatorSet.SetIndex(uint32(i), true)
+	}
+	qc.Signature.SetBytes(aggSig.Serialize())
+	return NewProof(header, qc, vds)
+}
+
+func TestProofVerify(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	proof := newTestProof(t, 3)
+	assert.Nil(t, proof.Verify())
+
+	// Round trip through JSON as a remote client would receive it.
+	b, err := json.Marshal(proof)
+	assert.Nil(t, err)
+	var decoded Proof
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Nil(t, decoded.Verify())
+}
+
+func TestProofVerifyFailed(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	proof := newTestProof(t, 2)
+	assert.True(t, errors.Is(proof.Verify(), ErrBelowThreshold))
+
+	proof = newTestProof(t, 3)
+	proof.Header = &types.Header{Number: big.NewInt(11)}
+	assert.Equal(t, ErrBlockMismatch, proof.Verify())
+
+	proof = newTestProof(t, 3)
+	proof.QuorumCert.Step = ctypes.RoundStepPrepareVote
+	assert.Equal(t, ErrNotPreCommit, proof.Verify())
+
+	proof = newTestProof(t, 3)
+	proof.QuorumCert.ViewNumber++
+	assert.Equal(t, ErrInvalidSignature, proof.Verify())
+
+	proof = newTestProof(t, 3)
+	proof.ValidatorSet.Validators[0], proof.ValidatorSet.Validators[3] = proof.ValidatorSet.Validators[3], proof.ValidatorSet.Validators[0]
+	assert.Equal(t, ErrInvalidSignature, proof.Verify())
+
+	proof = newTestProof(t, 3)
+	proof.ValidatorSet.Validators = proof.ValidatorSet.Validators[:3]
+	assert.Equal(t, ErrValidatorSetSize, proof.Verify())
+}
+
+func newTestTransitions(t *testing.T, num int) (*ValidatorSet, []*Transition) {
+	vds, secs := newTestValidators(t, 4)
+	trusted := NewValidatorSet(vds)
+
+	transitions := make([]*Transition, 0, num)
+	for i := 0; i < num; i++ {
+		next, nextSecs := newTestValidators(t, 4)
+		next.ValidBlockNumber = uint64(i+1)*10 + 1
+
+		header := &types.Header{
+			Number: big.NewInt(int64(i+1) * 10),
+			Extra:  append(make([]byte, types.ExtraMaxSize), NewValidatorSet(next).Hash().Bytes()...),
+		}
+		proof := signTestProof(t, header, vds, secs, 3)
+		transitions = append(transitions, NewTransition(proof, next))
+		vds, secs = next, nextSecs
+	}
+	return trusted, transitions
+}
+
+func TestVerifyTransitions(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	trusted, transitions := newTestTransitions(t, 3)
+	last, err := VerifyTransitions(trusted, transitions)
+	assert.Nil(t, err)
+	assert.Equal(t, transitions[2].NextValidators.Hash(), last.Hash())
+
+	b, err := json.Marshal(transitions)
+	assert.Nil(t, err)
+	var decoded []*Transition
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	last, err = VerifyTransitions(trusted, decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, transitions[2].NextValidators.Hash(), last.Hash())
+}
+
+func TestVerifyTransitionsFailed(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	trusted, transitions := newTestTransitions(t, 3)
+	_, err := VerifyTransitions(transitions[0].NextValidators, transitions)
+	assert.True(t, errors.Is(err, ErrUntrustedValidatorSet))
+
+	trusted, transitions = newTestTransitions(t, 3)
+	_, err = VerifyTransitions(trusted, []*Transition{transitions[0], transitions[2]})
+	assert.True(t, errors.Is(err, ErrUntrustedValidatorSet))
+
+	trusted, transitions = newTestTransitions(t, 3)
+	transitions[1].NextValidators.ValidBlockNumber++
+	_, err = VerifyTransitions(trusted, transitions)
+	assert.True(t, errors.Is(err, ErrValidatorsHashMismatch))
+
+	trusted, transitions = newTestTransitions(t, 1)
+	header := transitions[0].Proof.Header
+	header.Extra = header.Extra[:types.ExtraMaxSize]
+	transitions[0].Proof.QuorumCert.BlockHash = header.Hash()
+	assert.Equal(t, ErrInvalidSignature, transitions[0].Verify(trusted))
+}
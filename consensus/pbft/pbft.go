@@ -27,9 +27,13 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/evidence"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/executor"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/fetcher"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/finality"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/network"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protection"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/recorder"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/rules"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/signer"
 	cstate "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/state"
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/utils"
@@ -41,9 +45,11 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mclock"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rpc"
 )
 
@@ -53,6 +59,8 @@ const (
 	maxStatQueuesSize      = 200
 	syncCacheTimeout       = 200 * time.Millisecond
 	checkBlockSyncInterval = 100 * time.Millisecond
+
+	maxValidatorTransitions = 64
 )
 
 var (
@@ -105,6 +113,7 @@ type Pbft struct {
 	peerMsgCh        chan *ctypes.MsgInfo
 	syncMsgCh        chan *ctypes.MsgInfo
 	evPool           evidence.EvidencePool
+	protectionDB     protection.Database
 	log              log.Logger
 	network          *network.EngineManager
 
@@ -147,6 +156,16 @@ type Pbft struct {
 	wal                wal.Wal
 	bridge             Bridge
 
+	// Capture file of the received consensus messages, nil if disabled
+	recorder *recorder.Recorder
+	// Clock driving the view timer, the system clock is used if nil
+	clock mclock.Clock
+
+	// Consensus events waiting to be posted to the eventMux
+	consensusEventCh chan interface{}
+	// Proposer of the last ProposerChangeEvent
+	lastProposer *pbfttypes.ValidateNode
+
 	loading                   int32
 	updateChainStateHook      pbfttypes.UpdateChainStateFn
 	updateChainStateDelayHook func(qcState, lockState, commitState *protocols.State)
@@ -192,6 +211,7 @@ func New(sysConfig *configs.PbftConfig, optConfig *ctypes.OptionsConfig, eventMu
 		statQueues:         make(map[common.Hash]map[string]int),
 		messageHashCache:   mapset.NewSet(),
 		netLatencyMap:      make(map[string]*list.List),
+		consensusEventCh:   make(chan interface{}, consensusEventQueueSize),
 	}
 
 	if evPool, err := evidence.NewEvidencePool(ctx, optConfig.EvidenceDir); err == nil {
@@ -200,6 +220,13 @@ func New(sysConfig *configs.PbftConfig, optConfig *ctypes.OptionsConfig, eventMu
 		return nil
 	}
 
+	if protectionDB, err := protection.NewDatabase(ctx, optConfig.ProtectionDir); err == nil {
+		pbft.protectionDB = protectionDB
+	} else {
+		pbft.log.Error("Open double-sign protection database failed", "err", err)
+		return nil
+	}
+
 	return pbft
 }
 
@@ -211,6 +238,11 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	pbft.blockCacheWriter = blockCacheWriter
 	pbft.asyncExecutor = executor.NewAsyncExecutor(blockCacheWriter.Execute)
 
+	if err := pbft.openRecorder(); err != nil {
+		pbft.log.Error("Open consensus message recorder failed", "err", err)
+		return err
+	}
+
 	//Initialize block tree
 	block := chain.GetBlock(chain.CurrentHeader().Hash(), chain.CurrentHeader().Number.Uint64())
 	//block := chain.CurrentBlock()
@@ -236,6 +268,9 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 
 	//Initialize view state
 	pbft.state = cstate.NewViewState(pbft.config.Sys.Period, pbft.blockTree)
+	if pbft.clock != nil {
+		pbft.state.SetClock(pbft.clock)
+	}
 	pbft.state.SetHighestQCBlock(block)
 	pbft.state.SetHighestLockBlock(block)
 	pbft.state.SetHighestPreCommitQCBlock(block)
@@ -290,6 +325,7 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	utils.SetFalse(&pbft.loading)
 
 	go pbft.receiveLoop()
+	go pbft.consensusEventLoop()
 
 	pbft.fetcher.Start()
 
@@ -302,6 +338,8 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 // The message sent from the peer node is sent to the PBFT message queue and
 // there is a loop that will distribute the incoming message.
 func (pbft *Pbft) ReceiveMessage(msg *ctypes.MsgInfo) error {
+	pbft.captureMessage(msg, false)
+
 	if !pbft.running() {
 		//pbft.log.Trace("Pbft not running, stop process message", "fecthing", utils.True(&pbft.fetching), "syncing", utils.True(&pbft.syncing))
 		return nil
@@ -424,6 +462,8 @@ func (pbft *Pbft) statMessage(msg *ctypes.MsgInfo) error {
 // Possible message types are:
 //  PrepareBlockVotesMsg/GetLatestStatusMsg/LatestStatusMsg/
 func (pbft *Pbft) ReceiveSyncMsg(msg *ctypes.MsgInfo) error {
+	pbft.captureMessage(msg, true)
+
 	// If the node is synchronizing the block, discard sync msg directly and do not count the msg
 	// When the syncMsgCh channel is congested, it is easy to cause a message backlog
 	if utils.True(&pbft.syncing) {
@@ -452,6 +492,36 @@ func (pbft *Pbft) ReceiveSyncMsg(msg *ctypes.MsgInfo) error {
 	return nil
 }
 
+// openRecorder creates the capture file of the received consensus messages
+// if recording is enabled.
+func (pbft *Pbft) openRecorder() error {
+	path := pbft.config.Option.RecordFile
+	if path == "" {
+		return nil
+	}
+	if pbft.nodeServiceContext != nil {
+		path = pbft.nodeServiceContext.ResolvePath(path)
+	}
+	r, err := recorder.NewRecorder(path)
+	if err != nil {
+		return err
+	}
+	pbft.recorder = r
+	pbft.log.Info("Recording consensus messages", "path", path)
+	return nil
+}
+
+// captureMessage writes the received message to the capture file, sync
+// reports whether the message was received through ReceiveSyncMsg.
+func (pbft *Pbft) captureMessage(msg *ctypes.MsgInfo, sync bool) {
+	if pbft.recorder == nil {
+		return
+	}
+	if err := pbft.recorder.Record(msg, sync); err != nil {
+		pbft.log.Warn("Record consensus message failed", "peer", msg.PeerID, "type", fmt.Sprintf("%T", msg.Msg), "err", err)
+	}
+}
+
 // LoadWal tries to recover consensus state and view msg from the wal.
 func (pbft *Pbft) LoadWal() (err error) {
 	// init wal and load wal state
@@ -667,6 +737,26 @@ func (pbft *Pbft) VerifyHeader(chain consensus.ChainReader, header *types.Header
 		return fmt.Errorf("verify header fail, Extra field is too long, number:%d, hash:%s", header.Number.Uint64(), header.CacheHash().String())
 	}
 
+	if pbft.blockChain.Config().IsValidatorsCommit(header.Number) {
+		if err := pbft.validatorPool.VerifyValidatorsHash(header); err != nil {
+			pbft.log.Error("Verify header fail, invalid validators hash", "number", header.Number, "hash", header.Hash(), "err", err)
+			return fmt.Errorf("verify header fail, number:%d, hash:%s, err:%s", header.Number.Uint64(), header.Hash().String(), err.Error())
+		}
+	} else if _, ok := header.ValidatorsHash(); ok {
+		pbft.log.Error("Verify header fail, Extra field is too long", "number", header.Number, "hash", header.CacheHash())
+		return fmt.Errorf("verify header fail, Extra field is too long, number:%d, hash:%s", header.Number.Uint64(), header.CacheHash().String())
+	}
+
+	if pbft.blockChain.Config().IsVoteParticipation(header.Number) {
+		if err := pbft.verifyVoteQuorumCert(header); err != nil {
+			pbft.log.Error("Verify header fail, invalid vote quorum cert", "number", header.Number, "hash", header.Hash(), "err", err)
+			return fmt.Errorf("verify header fail, number:%d, hash:%s, err:%s", header.Number.Uint64(), header.Hash().String(), err.Error())
+		}
+	} else if _, ok := header.VoteQuorumCert(); ok {
+		pbft.log.Error("Verify header fail, Extra field is too long", "number", header.Number, "hash", header.CacheHash())
+		return fmt.Errorf("verify header fail, Extra field is too long, number:%d, hash:%s", header.Number.Uint64(), header.CacheHash().String())
+	}
+
 	if err := pbft.validatorPool.VerifyHeader(header); err != nil {
 		pbft.log.Error("Verify header fail", "number", header.Number, "hash", header.Hash(), "err", err)
 		return fmt.Errorf("verify header fail, number:%d, hash:%s, err:%s", header.Number.Uint64(), header.Hash().String(), err.Error())
@@ -674,6 +764,52 @@ func (pbft *Pbft) VerifyHeader(chain consensus.ChainReader, header *types.Header
 	return nil
 }
 
+// verifyVoteQuorumCert verifies the quorum certificate of the parent block carried
+// by the header, the signers of it are counted for the vote participation.
+// Only the certificate of the parent is accepted, so that the proposer can't
+// pick the certificate of another block to leave out a validator.
+func (pbft *Pbft) verifyVoteQuorumCert(header *types.Header) error {
+	data, ok := header.VoteQuorumCert()
+	if !ok {
+		return nil
+	}
+	var qc ctypes.QuorumCert
+	if err := rlp.DecodeBytes(data, &qc); err != nil {
+		return err
+	}
+	if qc.BlockHash != header.ParentHash || qc.BlockNumber+1 != header.Number.Uint64() {
+		return fmt.Errorf("unexpected vote quorum cert, qcNumber:%d, qcHash:%s", qc.BlockNumber, qc.BlockHash.String())
+	}
+	validators, err := pbft.validatorPool.ValidatorsByBlockNumber(qc.BlockNumber)
+	if err != nil {
+		return err
+	}
+	return finality.NewValidatorSet(validators).VerifyQuorumCert(&qc)
+}
+
+// parentQuorumCert returns the quorum certificate of the parent block of the header,
+// from the block tree or from the extra of the committed block, nil if there is none.
+func (pbft *Pbft) parentQuorumCert(header *types.Header) *ctypes.QuorumCert {
+	if header.Number.Uint64() <= 1 {
+		return nil
+	}
+	parentNumber := header.Number.Uint64() - 1
+	result := make(chan *ctypes.QuorumCert, 1)
+	pbft.asyncCallCh <- func() {
+		_, qc := pbft.blockTree.FindBlockAndQC(header.ParentHash, parentNumber)
+		result <- qc
+	}
+	if qc := <-result; qc != nil {
+		return qc
+	}
+	if parent := pbft.blockChain.GetBlock(header.ParentHash, parentNumber); parent != nil {
+		if _, qc, err := ctypes.DecodeExtra(parent.ExtraData()); err == nil && qc.BlockHash == header.ParentHash {
+			return qc
+		}
+	}
+	return nil
+}
+
 // VerifyHeaders is used to verify the validity of block headers in batch.
 func (pbft *Pbft) VerifyHeaders(chain consensus.ChainReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
 	//pbft.log.Trace("Verify headers", "total", len(headers))
@@ -721,6 +857,29 @@ func (pbft *Pbft) Prepare(chain consensus.ChainReader, header *types.Header) err
 	//init header.Extra[32: 32+65]
 	header.Extra = append(header.Extra, make([]byte, consensus.ExtraSeal)...)
 	pbft.log.Debug("Prepare, add header-extra ExtraSeal bytes(0x00)", "extraLength", len(header.Extra))
+
+	//header.Extra[97:] to store the quorum cert of the parent block for the vote participation.
+	if pbft.blockChain.Config().IsVoteParticipation(header.Number) {
+		if qc := pbft.parentQuorumCert(header); qc != nil {
+			data, err := rlp.EncodeToBytes(qc)
+			if err != nil {
+				return err
+			}
+			header.Extra = append(header.Extra, data...)
+			pbft.log.Debug("Prepare, add header-extra vote quorum cert", "number", header.Number, "qcNumber", qc.BlockNumber, "extraLength", len(header.Extra))
+		}
+	}
+
+	//then the hash of the next validators on epoch switch blocks.
+	if pbft.blockChain.Config().IsValidatorsCommit(header.Number) && pbft.validatorPool.IsEpochSwitchBlock(header.Number.Uint64()) {
+		nds, err := pbft.validatorPool.NextValidators(header.Number.Uint64())
+		if err != nil {
+			pbft.log.Error("Prepare, get next validators fail", "number", header.Number, "err", err)
+			return err
+		}
+		header.Extra = append(header.Extra, finality.NewValidatorSet(nds).Hash().Bytes()...)
+		pbft.log.Debug("Prepare, add header-extra validators hash", "number", header.Number, "extraLength", len(header.Extra))
+	}
 	return nil
 }
 
@@ -747,7 +906,7 @@ func (pbft *Pbft) Seal(chain consensus.ChainReader, block *types.Block, results
 		return err
 	}
 
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 
 	sealBlock := block.WithSeal(header)
 
@@ -771,7 +930,7 @@ func (pbft *Pbft) DealBlock(block *types.Block)(b *types.Block,e error) {
 		return
 	}
 
-	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
+	copy(header.Extra[32:32+consensus.ExtraSeal], sign[:])
 
 	b = block.WithSeal(header)
 	return
@@ -898,6 +1057,12 @@ func (pbft *Pbft) APIs(chain consensus.ChainReader) []rpc.API {
 			Service:   NewPublicAdminConsensusAPI(pbft),
 			Public:    true,
 		},
+		{
+			Namespace: "pbft",
+			Version:   "1.0",
+			Service:   NewPublicPbftConsensusAPI(pbft),
+			Public:    true,
+		},
 	}
 }
 
@@ -1022,6 +1187,54 @@ func (pbft *Pbft) GetPrepareQC(number uint64) *ctypes.QuorumCert {
 	return &ctypes.QuorumCert{}
 }
 
+// GetFinalityProof returns the finality proof of the committed block of the specified height.
+func (pbft *Pbft) GetFinalityProof(number uint64) (*finality.Proof, error) {
+	header := pbft.blockChain.GetHeaderByNumber(number)
+	if header == nil {
+		return nil, ErrorUnKnowBlock
+	}
+	block := pbft.blockChain.GetBlock(header.Hash(), number)
+	if block == nil {
+		return nil, ErrorUnKnowBlock
+	}
+	_, qc, err := ctypes.DecodeExtra(block.ExtraData())
+	if err != nil {
+		return nil, fmt.Errorf("decode block extra data failed, number:%d, err:%v", number, err)
+	}
+	validators, err := pbft.validatorPool.ValidatorsByBlockNumber(number)
+	if err != nil {
+		return nil, fmt.Errorf("get validators failed, number:%d, err:%v", number, err)
+	}
+	return finality.NewProof(header, qc, validators), nil
+}
+
+// GetValidatorTransitions returns the transitions of the epoch switch blocks
+// committed after the specified block, starting with the epoch the block
+// belongs to. At most maxValidatorTransitions are returned, callers continue
+// from the block following the last returned switch block.
+func (pbft *Pbft) GetValidatorTransitions(from uint64) ([]*finality.Transition, error) {
+	current := pbft.blockChain.CurrentHeader().Number.Uint64()
+	transitions := make([]*finality.Transition, 0)
+	for number := pbft.validatorPool.LastNumber(from); number > 0 && number <= current && len(transitions) < maxValidatorTransitions; {
+		proof, err := pbft.GetFinalityProof(number)
+		if err != nil {
+			return nil, err
+		}
+		nds, err := pbft.validatorPool.NextValidators(number)
+		if err != nil {
+			return nil, fmt.Errorf("get next validators failed, number:%d, err:%v", number, err)
+		}
+		transitions = append(transitions, finality.NewTransition(proof, nds))
+
+		next := pbft.validatorPool.LastNumber(validator.NextRound(number))
+		if next <= number {
+			break
+		}
+		number = next
+	}
+	return transitions, nil
+}
+
 // GetBlockByHash get the specified block by hash.
 func (pbft *Pbft) GetBlockByHash(hash common.Hash) *types.Block {
 	result := make(chan *types.Block, 1)
@@ -1125,6 +1338,17 @@ func (pbft *Pbft) Close() error {
 		pbft.asyncExecutor.Stop()
 	}
 	pbft.bridge.Close()
+	if pbft.protectionDB != nil {
+		pbft.protectionDB.Close()
+	}
+	if pbft.config.Option.Signer != nil {
+		pbft.config.Option.Signer.Close()
+	}
+	if pbft.recorder != nil {
+		if err := pbft.recorder.Close(); err != nil {
+			pbft.log.Error("Close consensus message recorder failed", "err", err)
+		}
+	}
 	return nil
 }
 
@@ -1378,6 +1602,7 @@ func (pbft *Pbft) commitBlock(commitBlock *types.Block, commitQC *ctypes.QuorumC
 		pbft.updateChainStateHook(qcState, lockState, commitState)
 	}
 	pbft.log.Info("CommitBlock, send consensus result to worker", "number", commitBlock.Number(), "hash", commitBlock.Hash())
+	pbft.postBlockCommitEvent(commitBlock, commitQC)
 	cpy := types.NewBlockWithHeader(commitBlock.Header()).WithBody(commitBlock.Transactions(), commitBlock.ExtraData())
 	pbft.eventMux.Post(pbfttypes.PbftResult{
 		Block:              cpy,
@@ -1387,6 +1612,11 @@ func (pbft *Pbft) commitBlock(commitBlock *types.Block, commitQC *ctypes.QuorumC
 	})
 }
 
+// EvidencePool returns the pool recording the duplicate signatures.
+func (pbft *Pbft) EvidencePool() evidence.EvidencePool {
+	return pbft.evPool
+}
+
 // Evidences implements functions in API.
 func (pbft *Pbft) Evidences() string {
 	evs := pbft.evPool.Evidences()
@@ -1419,12 +1649,26 @@ func (pbft *Pbft) signFn(m []byte) ([]byte, error) {
 
 // signFn use bls private key to sign byte slice.
 func (pbft *Pbft) signFnByBls(m []byte) ([]byte, error) {
-	sign := pbft.config.Option.BlsPriKey.Sign(string(m))
-	return sign.Serialize(), nil
+	return pbft.signer().SignBls(m)
+}
+
+// signer returns the configured remote BLS signer, or a signer using the local BLS key.
+func (pbft *Pbft) signer() signer.Signer {
+	if pbft.config.Option.Signer != nil {
+		return pbft.config.Option.Signer
+	}
+	return signer.NewLocalSigner(pbft.config.Option.BlsPriKey)
 }
 
 // signMsg use bls private key to sign msg.
 func (pbft *Pbft) signMsgByBls(msg ctypes.ConsensusMsg) error {
+	// Refuse to sign anything that could be slashed as a duplicate signature.
+	if pbft.protectionDB != nil {
+		if err := pbft.protectionDB.CheckAndUpdate(msg); err != nil {
+			pbft.log.Error("Double-sign protection refused to sign", "err", err)
+			return err
+		}
+	}
 	buf, err := msg.CannibalizeBytes()
 	if err != nil {
 		return err
@@ -1997,7 +2241,7 @@ func (pbft *Pbft) avgRTT() time.Duration {
 }
 
 func (pbft *Pbft) GetSchnorrNIZKProve() (*bls.SchnorrProof, error) {
-	return pbft.config.Option.BlsPriKey.MakeSchnorrNIZKP()
+	return pbft.signer().SchnorrNIZKProof()
 }
 
 func (pbft *Pbft) DecodeExtra(extra []byte) (common.Hash, uint64, error) {
@@ -0,0 +1,100 @@
+package protection
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+)
+
+// InterchangeVersion is the version of the interchange format.
+const InterchangeVersion = 1
+
+// Interchange is the format used to move the database between nodes, e.g. when
+// a validator is migrated to new hardware. It is encoded as JSON:
+//
+//	{
+//	  "version": 1,
+//	  "nodeID": "<hex encoded node id of the validator>",
+//	  "records": [
+//	    {
+//	      "type": "prepareVote",
+//	      "epoch": 1,
+//	      "viewNumber": 12,
+//	      "blockNumber": 131,
+//	      "blockHash": "0x..."
+//	    }
+//	  ]
+//	}
+//
+// The type is one of prepareBlock, prepareVote, preCommit and viewChange, with
+// at most one record per type. A zero blockHash forbids signing any message at
+// the position of the record.
+type Interchange struct {
+	Version uint64               `json:"version"`
+	NodeID  discover.NodeID      `json:"nodeID"`
+	Records []*InterchangeRecord `json:"records"`
+}
+
+// InterchangeRecord is a Record with its message type.
+type InterchangeRecord struct {
+	Type string `json:"type"`
+	Record
+}
+
+// Export writes the records of db signed by the node nodeID to w.
+func Export(db Database, nodeID discover.NodeID, w io.Writer) error {
+	records, err := db.Records()
+	if err != nil {
+		return err
+	}
+	ic := &Interchange{Version: InterchangeVersion, NodeID: nodeID, Records: make([]*InterchangeRecord, 0, len(records))}
+	for typ, r := range records {
+		ic.Records = append(ic.Records, &InterchangeRecord{Type: typ.String(), Record: *r})
+	}
+	sort.Slice(ic.Records, func(i, j int) bool {
+		return ic.Records[i].Type < ic.Records[j].Type
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ic)
+}
+
+// Import merges the records read from r into db, the records must have been
+// exported by the node nodeID.
+func Import(db Database, nodeID discover.NodeID, r io.Reader) error {
+	var ic Interchange
+	if err := json.NewDecoder(r).Decode(&ic); err != nil {
+		return err
+	}
+	if ic.Version != InterchangeVersion {
+		return fmt.Errorf("unsupported interchange version %d", ic.Version)
+	}
+	if ic.NodeID != nodeID {
+		return fmt.Errorf("records of node %s can not be imported by node %s", ic.NodeID.TerminalString(), nodeID.TerminalString())
+	}
+	records := make(map[MsgType]*Record)
+	for _, ir := range ic.Records {
+		typ, ok := parseMsgType(ir.Type)
+		if !ok {
+			return fmt.Errorf("unknown record type %q", ir.Type)
+		}
+		if _, ok := records[typ]; ok {
+			return fmt.Errorf("duplicate record type %q", ir.Type)
+		}
+		record := ir.Record
+		records[typ] = &record
+	}
+	return db.Merge(records)
+}
+
+func parseMsgType(name string) (MsgType, bool) {
+	for typ, n := range msgTypeNames {
+		if n == name {
+			return typ, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,282 @@
+// Package protection implements a persistent double-sign protection database
+// for pbft validators.
+//
+// Before a consensus message is signed, the highest (epoch, viewNumber,
+// blockNumber) signed so far for the message type is checked and updated, so
+// that the node never signs two messages that could form a duplicate evidence,
+// even across restarts with a stale wal.
+package protection
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+)
+
+// MsgType identifies the type of a signed consensus message.
+type MsgType byte
+
+const (
+	PrepareBlockType MsgType = 0x1
+	PrepareVoteType  MsgType = 0x2
+	PreCommitType    MsgType = 0x3
+	ViewChangeType   MsgType = 0x4
+)
+
+var msgTypeNames = map[MsgType]string{
+	PrepareBlockType: "prepareBlock",
+	PrepareVoteType:  "prepareVote",
+	PreCommitType:    "preCommit",
+	ViewChangeType:   "viewChange",
+}
+
+func (t MsgType) String() string {
+	if name, ok := msgTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", byte(t))
+}
+
+// Record is the highest message signed for a message type. A zero BlockHash
+// blocks the position for any block, it is used when two conflicting records
+// are merged by an import.
+type Record struct {
+	Epoch       uint64      `json:"epoch"`
+	ViewNumber  uint64      `json:"viewNumber"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+}
+
+func (r *Record) String() string {
+	return fmt.Sprintf("{Epoch:%d,ViewNumber:%d,BlockNumber:%d,BlockHash:%s}", r.Epoch, r.ViewNumber, r.BlockNumber, r.BlockHash.TerminalString())
+}
+
+// compare returns the order of the positions of r and o. The position of a
+// viewChange is its (epoch, viewNumber), a node must sign a single viewChange
+// per view whatever the block it is based on.
+func (r *Record) compare(o *Record, typ MsgType) int {
+	cmp := func(a, b uint64) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	if c := cmp(r.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	if c := cmp(r.ViewNumber, o.ViewNumber); c != 0 {
+		return c
+	}
+	if typ == ViewChangeType {
+		return 0
+	}
+	return cmp(r.BlockNumber, o.BlockNumber)
+}
+
+// sameBlock reports whether r and o are signatures of the same block.
+func (r *Record) sameBlock(o *Record) bool {
+	return r.BlockHash != common.Hash{} && r.BlockHash == o.BlockHash && r.BlockNumber == o.BlockNumber
+}
+
+// DoubleSignError is returned when signing a message could form a duplicate
+// evidence together with a message signed before.
+type DoubleSignError struct {
+	Type   MsgType
+	Signed *Record
+	Next   *Record
+}
+
+func (e *DoubleSignError) Error() string {
+	return fmt.Sprintf("refuse to sign %s, signed:%s, next:%s", e.Type, e.Signed, e.Next)
+}
+
+// Database records the highest signed consensus messages of the local node.
+type Database interface {
+	// CheckAndUpdate fails if signing msg could form a duplicate evidence,
+	// otherwise msg is recorded as signed before returning.
+	CheckAndUpdate(msg types.ConsensusMsg) error
+	// Records returns the highest signed message of every message type.
+	Records() (map[MsgType]*Record, error)
+	// Merge keeps the highest of the current and the given records.
+	Merge(records map[MsgType]*Record) error
+	Close()
+}
+
+// NewDatabase opens the database in dir resolved by the service context,
+// an empty implementation is returned if there is no data directory.
+func NewDatabase(ctx *node.ServiceContext, dir string) (Database, error) {
+	path := ""
+	if ctx != nil {
+		path = ctx.ResolvePath(dir)
+	}
+	if len(path) == 0 {
+		return &emptyDatabase{}, nil
+	}
+	return NewBaseDatabase(path)
+}
+
+// emptyDatabase is a empty implementation for Database
+type emptyDatabase struct {
+}
+
+func (db *emptyDatabase) CheckAndUpdate(msg types.ConsensusMsg) error {
+	return nil
+}
+
+func (db *emptyDatabase) Records() (map[MsgType]*Record, error) {
+	return make(map[MsgType]*Record), nil
+}
+
+func (db *emptyDatabase) Merge(records map[MsgType]*Record) error {
+	return nil
+}
+
+func (db *emptyDatabase) Close() {
+}
+
+// baseDatabase is a default implementation for Database
+type baseDatabase struct {
+	db *leveldb.DB
+	mu sync.Mutex
+}
+
+// NewBaseDatabase opens or creates the database at path.
+func NewBaseDatabase(path string) (*baseDatabase, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &baseDatabase{db: db}, nil
+}
+
+func (db *baseDatabase) CheckAndUpdate(msg types.ConsensusMsg) error {
+	typ, next, err := newRecord(msg)
+	if err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	signed, err := db.get(typ)
+	if err != nil {
+		return err
+	}
+	if signed != nil {
+		switch c := signed.compare(next, typ); {
+		case c > 0:
+			return &DoubleSignError{Type: typ, Signed: signed, Next: next}
+		case c == 0:
+			if !signed.sameBlock(next) {
+				return &DoubleSignError{Type: typ, Signed: signed, Next: next}
+			}
+			// Signing the same message again, e.g. when reloading the wal.
+			return nil
+		}
+	}
+	return db.put(typ, next)
+}
+
+func (db *baseDatabase) Records() (map[MsgType]*Record, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	records := make(map[MsgType]*Record)
+	for typ := range msgTypeNames {
+		r, err := db.get(typ)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			records[typ] = r
+		}
+	}
+	return records, nil
+}
+
+func (db *baseDatabase) Merge(records map[MsgType]*Record) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for typ, r := range records {
+		if _, ok := msgTypeNames[typ]; !ok {
+			return fmt.Errorf("unknown message type %d", typ)
+		}
+		signed, err := db.get(typ)
+		if err != nil {
+			return err
+		}
+		merged := *r
+		if signed != nil {
+			switch c := signed.compare(r, typ); {
+			case c > 0:
+				continue
+			case c == 0:
+				if signed.sameBlock(r) {
+					continue
+				}
+				// Both blocks may have been signed, block the position.
+				merged.BlockHash = common.Hash{}
+			}
+		}
+		if err := db.put(typ, &merged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *baseDatabase) Close() {
+	db.db.Close()
+}
+
+func (db *baseDatabase) get(typ MsgType) (*Record, error) {
+	data, err := db.db.Get([]byte{byte(typ)}, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Record
+	if err := rlp.DecodeBytes(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// put writes the record synchronously, the record must be on disk before
+// the signature is released.
+func (db *baseDatabase) put(typ MsgType, r *Record) error {
+	data, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return db.db.Put([]byte{byte(typ)}, data, &opt.WriteOptions{Sync: true})
+}
+
+// newRecord returns the message type and the record of a consensus message.
+func newRecord(msg types.ConsensusMsg) (MsgType, *Record, error) {
+	switch m := msg.(type) {
+	case *protocols.PrepareBlock:
+		return PrepareBlockType, &Record{Epoch: m.Epoch, ViewNumber: m.ViewNumber, BlockNumber: m.Block.NumberU64(), BlockHash: m.Block.Hash()}, nil
+	case *protocols.PrepareVote:
+		return PrepareVoteType, &Record{Epoch: m.Epoch, ViewNumber: m.ViewNumber, BlockNumber: m.BlockNumber, BlockHash: m.BlockHash}, nil
+	case *protocols.PreCommit:
+		return PreCommitType, &Record{Epoch: m.Epoch, ViewNumber: m.ViewNumber, BlockNumber: m.BlockNumber, BlockHash: m.BlockHash}, nil
+	case *protocols.ViewChange:
+		return ViewChangeType, &Record{Epoch: m.Epoch, ViewNumber: m.ViewNumber, BlockNumber: m.BlockNumber, BlockHash: m.BlockHash}, nil
+	}
+	return 0, nil, fmt.Errorf("unsupported message type %T", msg)
+}
@@ -0,0 +1,94 @@
+package protection
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+func newTestVote(epoch, viewNumber, blockNumber uint64, hash string) *protocols.PrepareVote {
+	return &protocols.PrepareVote{Epoch: epoch, ViewNumber: viewNumber, BlockNumber: blockNumber, BlockHash: common.BytesToHash([]byte(hash))}
+}
+
+func newTestViewChange(epoch, viewNumber, blockNumber uint64, hash string) *protocols.ViewChange {
+	return &protocols.ViewChange{Epoch: epoch, ViewNumber: viewNumber, BlockNumber: blockNumber, BlockHash: common.BytesToHash([]byte(hash))}
+}
+
+func TestCheckAndUpdate(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "protection")
+	defer os.RemoveAll(tempDir)
+
+	db, err := NewBaseDatabase(tempDir)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.CheckAndUpdate(newTestVote(1, 1, 10, "a")))
+	// Signing the same vote again is allowed.
+	assert.Nil(t, db.CheckAndUpdate(newTestVote(1, 1, 10, "a")))
+	assert.IsType(t, &DoubleSignError{}, db.CheckAndUpdate(newTestVote(1, 1, 10, "b")))
+	assert.IsType(t, &DoubleSignError{}, db.CheckAndUpdate(newTestVote(1, 1, 9, "c")))
+	assert.IsType(t, &DoubleSignError{}, db.CheckAndUpdate(newTestVote(1, 0, 11, "c")))
+	assert.Nil(t, db.CheckAndUpdate(newTestVote(1, 1, 11, "c")))
+	assert.Nil(t, db.CheckAndUpdate(newTestVote(1, 2, 11, "d")))
+
+	// A single viewChange per view, whatever the block.
+	assert.Nil(t, db.CheckAndUpdate(newTestViewChange(1, 1, 10, "a")))
+	assert.IsType(t, &DoubleSignError{}, db.CheckAndUpdate(newTestViewChange(1, 1, 11, "b")))
+	assert.Nil(t, db.CheckAndUpdate(newTestViewChange(1, 2, 9, "c")))
+
+	// Messages of other types are recorded apart.
+	assert.Nil(t, db.CheckAndUpdate(&protocols.PreCommit{Epoch: 1, ViewNumber: 1, BlockNumber: 10, BlockHash: common.BytesToHash([]byte("a"))}))
+
+	// The records survive a restart.
+	db.Close()
+	db, err = NewBaseDatabase(tempDir)
+	assert.Nil(t, err)
+	defer db.Close()
+	assert.IsType(t, &DoubleSignError{}, db.CheckAndUpdate(newTestVote(1, 2, 11, "e")))
+
+	records, err := db.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, uint64(2), records[ViewChangeType].ViewNumber)
+}
+
+func TestInterchange(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "protection")
+	defer os.RemoveAll(tempDir)
+
+	src, err := NewBaseDatabase(tempDir + "/src")
+	assert.Nil(t, err)
+	defer src.Close()
+	dst, err := NewBaseDatabase(tempDir + "/dst")
+	assert.Nil(t, err)
+	defer dst.Close()
+
+	nodeID := discover.NodeID{0x1}
+	assert.Nil(t, src.CheckAndUpdate(newTestVote(1, 3, 20, "a")))
+	assert.Nil(t, src.CheckAndUpdate(newTestViewChange(1, 2, 19, "b")))
+	assert.Nil(t, dst.CheckAndUpdate(newTestVote(1, 3, 20, "c")))
+	assert.Nil(t, dst.CheckAndUpdate(newTestViewChange(1, 4, 19, "d")))
+
+	var buf bytes.Buffer
+	assert.Nil(t, Export(src, nodeID, &buf))
+	assert.NotNil(t, Import(dst, discover.NodeID{0x2}, bytes.NewReader(buf.Bytes())))
+	assert.Nil(t, Import(dst, nodeID, bytes.NewReader(buf.Bytes())))
+
+	// Both votes may have been signed, nothing can be signed at their position.
+	assert.IsType(t, &DoubleSignError{}, dst.CheckAndUpdate(newTestVote(1, 3, 20, "a")))
+	assert.IsType(t, &DoubleSignError{}, dst.CheckAndUpdate(newTestVote(1, 3, 20, "c")))
+	assert.Nil(t, dst.CheckAndUpdate(newTestVote(1, 3, 21, "e")))
+
+	// The highest viewChange is kept.
+	records, err := dst.Records()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), records[ViewChangeType].ViewNumber)
+
+	assert.NotNil(t, Import(dst, nodeID, bytes.NewReader([]byte(`{"version":1,"records":[{"type":"unknown"}]}`))))
+}
@@ -0,0 +1,223 @@
+// Package recorder captures the consensus messages received by the pbft engine
+// so that they can be replayed offline against a fresh engine.
+package recorder
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+)
+
+const (
+	// The version of the capture file format
+	captureVersion = 1
+
+	// A new Reader whose buffer has at least the specified size
+	readBufferLimitSize = 16 * 1024
+)
+
+var (
+	errUnsupportedVersion = errors.New("unsupported capture version")
+	errUnknownMessageCode = errors.New("unknown message code")
+)
+
+// captureHeader is the first item of a capture file.
+type captureHeader struct {
+	Version uint64
+	Start   uint64 // Unix time in nanoseconds when the capture started
+}
+
+// captureRecord is a received consensus message as stored in a capture file.
+type captureRecord struct {
+	Offset uint64 // Monotonic nanoseconds elapsed since the capture started
+	PeerID string
+	Inner  bool
+	Sync   bool
+	Code   uint64
+	Data   rlp.RawValue
+}
+
+// Recorder appends the received consensus messages to a capture file.
+type Recorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	start time.Time
+}
+
+// NewRecorder creates the capture file at path, truncating any previous capture.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	if err := rlp.Encode(file, &captureHeader{Version: captureVersion, Start: uint64(start.UnixNano())}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &Recorder{file: file, start: start}, nil
+}
+
+// Record appends msg to the capture file, sync reports whether the message
+// was received through ReceiveSyncMsg. Messages recorded after Close are dropped.
+func (r *Recorder) Record(msg *ctypes.MsgInfo, sync bool) error {
+	offset := time.Since(r.start)
+	data, err := rlp.EncodeToBytes(msg.Msg)
+	if err != nil {
+		return err
+	}
+	rec := &captureRecord{
+		Offset: uint64(offset),
+		PeerID: msg.PeerID,
+		Inner:  msg.Inner,
+		Sync:   sync,
+		Code:   protocols.MessageType(msg.Msg),
+		Data:   data,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return rlp.Encode(r.file, rec)
+}
+
+// Close syncs and closes the capture file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Sync()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	r.file = nil
+	return err
+}
+
+// Entry is a message read back from a capture file.
+type Entry struct {
+	Offset time.Duration // Elapsed time since the capture started
+	Sync   bool          // Whether the message was received through ReceiveSyncMsg
+	Msg    *ctypes.MsgInfo
+}
+
+// Reader reads the messages of a capture file in the order they were received.
+type Reader struct {
+	file   *os.File
+	stream *rlp.Stream
+	start  time.Time
+}
+
+// NewReader opens the capture file at path.
+func NewReader(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	stream := rlp.NewStream(bufio.NewReaderSize(file, readBufferLimitSize), 0)
+	var header captureHeader
+	if err := stream.Decode(&header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("invalid capture header: %v", err)
+	}
+	if header.Version != captureVersion {
+		file.Close()
+		return nil, errUnsupportedVersion
+	}
+	return &Reader{file: file, stream: stream, start: time.Unix(0, int64(header.Start))}, nil
+}
+
+// Start returns the wall clock time at which the capture started.
+func (r *Reader) Start() time.Time {
+	return r.start
+}
+
+// Next returns the next message of the capture, io.EOF is returned once
+// all the messages have been read.
+func (r *Reader) Next() (*Entry, error) {
+	var rec captureRecord
+	if err := r.stream.Decode(&rec); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("invalid capture record: %v", err)
+	}
+	msg, err := newMessage(rec.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := rlp.DecodeBytes(rec.Data, msg); err != nil {
+		return nil, fmt.Errorf("invalid message, code: %d, err: %v", rec.Code, err)
+	}
+	return &Entry{
+		Offset: time.Duration(rec.Offset),
+		Sync:   rec.Sync,
+		Msg:    &ctypes.MsgInfo{Msg: msg, PeerID: rec.PeerID, Inner: rec.Inner},
+	}, nil
+}
+
+// Close closes the capture file.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+// newMessage returns an empty message for the given protocol message code.
+func newMessage(code uint64) (ctypes.Message, error) {
+	switch code {
+	case protocols.PrepareBlockMsg:
+		return &protocols.PrepareBlock{}, nil
+	case protocols.PrepareVoteMsg:
+		return &protocols.PrepareVote{}, nil
+	case protocols.PreCommitMsg:
+		return &protocols.PreCommit{}, nil
+	case protocols.ViewChangeMsg:
+		return &protocols.ViewChange{}, nil
+	case protocols.GetPrepareBlockMsg:
+		return &protocols.GetPrepareBlock{}, nil
+	case protocols.GetBlockQuorumCertMsg:
+		return &protocols.GetBlockQuorumCert{}, nil
+	case protocols.BlockQuorumCertMsg:
+		return &protocols.BlockQuorumCert{}, nil
+	case protocols.GetBlockPreCommitQuorumCertMsg:
+		return &protocols.GetBlockPreCommitQuorumCert{}, nil
+	case protocols.BlockPreCommitQuorumCertMsg:
+		return &protocols.BlockPreCommitQuorumCert{}, nil
+	case protocols.GetQCBlockListMsg:
+		return &protocols.GetQCBlockList{}, nil
+	case protocols.QCBlockListMsg:
+		return &protocols.QCBlockList{}, nil
+	case protocols.GetPrepareVoteMsg:
+		return &protocols.GetPrepareVote{}, nil
+	case protocols.PrepareVotesMsg:
+		return &protocols.PrepareVotes{}, nil
+	case protocols.GetPreCommitMsg:
+		return &protocols.GetPreCommit{}, nil
+	case protocols.PreCommitsMsg:
+		return &protocols.PreCommits{}, nil
+	case protocols.PrepareBlockHashMsg:
+		return &protocols.PrepareBlockHash{}, nil
+	case protocols.GetLatestStatusMsg:
+		return &protocols.GetLatestStatus{}, nil
+	case protocols.LatestStatusMsg:
+		return &protocols.LatestStatus{}, nil
+	case protocols.GetViewChangeMsg:
+		return &protocols.GetViewChange{}, nil
+	case protocols.ViewChangeQuorumCertMsg:
+		return &protocols.ViewChangeQuorumCert{}, nil
+	case protocols.ViewChangesMsg:
+		return &protocols.ViewChanges{}, nil
+	}
+	return nil, errUnknownMessageCode
+}
@@ -0,0 +1,69 @@
+package recorder
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+func TestRecorder(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "recorder")
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "capture")
+
+	r, err := NewRecorder(path)
+	assert.Nil(t, err)
+
+	vote := &protocols.PrepareVote{Epoch: 1, ViewNumber: 2, BlockHash: common.BytesToHash([]byte("block")), BlockNumber: 3, ValidatorIndex: 1}
+	viewChange := &protocols.ViewChange{Epoch: 1, ViewNumber: 3, BlockNumber: 3, ValidatorIndex: 2}
+	status := &protocols.GetLatestStatus{BlockNumber: 3, LogicType: 1}
+	assert.Nil(t, r.Record(ctypes.NewMsgInfo(vote, "peer1"), false))
+	assert.Nil(t, r.Record(ctypes.NewMsgInfo(viewChange, "peer2"), false))
+	assert.Nil(t, r.Record(ctypes.NewMsgInfo(status, "peer1"), true))
+	assert.Nil(t, r.Close())
+	assert.Nil(t, r.Record(ctypes.NewMsgInfo(vote, "peer1"), false))
+
+	reader, err := NewReader(path)
+	assert.Nil(t, err)
+	defer reader.Close()
+
+	var entries []*Entry
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, vote.MsgHash(), entries[0].Msg.Msg.MsgHash())
+	assert.Equal(t, "peer1", entries[0].Msg.PeerID)
+	assert.False(t, entries[0].Sync)
+	assert.IsType(t, &protocols.ViewChange{}, entries[1].Msg.Msg)
+	assert.Equal(t, viewChange.ViewNumber, entries[1].Msg.Msg.(*protocols.ViewChange).ViewNumber)
+	assert.True(t, entries[2].Sync)
+	assert.Equal(t, status.LogicType, entries[2].Msg.Msg.(*protocols.GetLatestStatus).LogicType)
+	assert.True(t, entries[0].Offset <= entries[1].Offset && entries[1].Offset <= entries[2].Offset)
+}
+
+func TestReaderInvalidCapture(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "recorder")
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "capture")
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte{0x01, 0x02}, 0644))
+	_, err := NewReader(path)
+	assert.NotNil(t, err)
+
+	_, err = newMessage(protocols.PingMsg)
+	assert.Equal(t, errUnknownMessageCode, err)
+}
@@ -0,0 +1,89 @@
+package pbft
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/recorder"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mclock"
+)
+
+const (
+	// Polling interval while waiting for the engine to consume replayed messages
+	replayDrainInterval = time.Millisecond
+)
+
+// Replayer feeds the consensus messages captured by the recorder into an engine
+// whose view timer is driven by a simulated clock, so that the recorded timing
+// of the messages relative to the view timeouts is reproduced.
+//
+// The engine is expected to be started on a copy of the chain the capture was
+// recorded on, with the identity of the recording node.
+type Replayer struct {
+	engine *Pbft
+	clock  *mclock.Simulated
+	now    time.Duration
+}
+
+// NewReplayer creates a replayer for engine. It must be called before the
+// engine is started.
+func NewReplayer(engine *Pbft) *Replayer {
+	clock := new(mclock.Simulated)
+	engine.clock = clock
+	return &Replayer{engine: engine, clock: clock}
+}
+
+// Clock returns the simulated clock driving the view timer of the engine.
+func (r *Replayer) Clock() *mclock.Simulated {
+	return r.clock
+}
+
+// Replay feeds the messages of the capture file at path in the order they were
+// received, advancing the clock by the recorded time elapsed between them.
+// It returns the number of messages replayed.
+func (r *Replayer) Replay(path string) (int, error) {
+	reader, err := recorder.NewReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	count := 0
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("replay message %d failed: %v", count, err)
+		}
+		if entry.Offset > r.now {
+			r.clock.Run(entry.Offset - r.now)
+			r.now = entry.Offset
+		}
+		if entry.Sync {
+			err = r.engine.ReceiveSyncMsg(entry.Msg)
+		} else {
+			err = r.engine.ReceiveMessage(entry.Msg)
+		}
+		// Rejected messages are part of the recorded behaviour, keep replaying.
+		if err != nil {
+			r.engine.log.Debug("Replayed message rejected", "peer", entry.Msg.PeerID, "type", fmt.Sprintf("%T", entry.Msg.Msg), "err", err)
+		}
+		r.drain()
+		count++
+	}
+}
+
+// drain waits until the engine has consumed the queued messages, so that the
+// replayed messages are not discarded because of full queues.
+func (r *Replayer) drain() {
+	for len(r.engine.peerMsgCh) > 0 || len(r.engine.syncMsgCh) > 0 {
+		select {
+		case <-r.engine.exitCh:
+			return
+		case <-time.After(replayDrainInterval):
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package pbft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/recorder"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+)
+
+func TestReplay(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "replay")
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "capture")
+
+	pk, sk, pbftnodes := GeneratePbftNode(4)
+	nodes := make([]*TestPBFT, 0)
+	for i := 0; i < 4; i++ {
+		nodes = append(nodes, MockNode(pk[i], sk[i], pbftnodes, 10000, 10))
+	}
+	replayer := NewReplayer(nodes[0].engine)
+	assert.Nil(t, nodes[0].Start())
+	defer nodes[0].engine.Close()
+
+	// Capture the view changes of two validators, not enough for a quorum.
+	genesis := nodes[0].chain.Genesis()
+	r, err := recorder.NewRecorder(path)
+	assert.Nil(t, err)
+	for i := 1; i < 3; i++ {
+		vc := mockViewChange(sk[i], nodes[0].engine.state.Epoch(), nodes[0].engine.state.ViewNumber(), genesis.Hash(), genesis.NumberU64(), uint32(i), nil)
+		assert.Nil(t, r.Record(ctypes.NewMsgInfo(vc, pbftnodes[i].Node.ID.TerminalString()), false))
+	}
+	assert.Nil(t, r.Close())
+
+	count, err := replayer.Replay(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, nodes[0].engine.state.ViewChangeLen())
+	assert.Equal(t, uint64(0), nodes[0].engine.state.ViewNumber())
+
+	// The local view change sent on timeout completes the quorum.
+	replayer.Clock().Run(time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(1), nodes[0].engine.state.ViewNumber())
+}
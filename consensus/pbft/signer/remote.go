@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rpc"
+)
+
+const (
+	// Timeout of the connection to the remote signer
+	remoteDialTimeout = 5 * time.Second
+
+	// Timeout of a signing request, consensus messages must be signed
+	// well within a view.
+	remoteCallTimeout = 2 * time.Second
+)
+
+// RemoteSigner delegates the BLS signatures to a signer process reached over a
+// unix socket or TCP, both sides authenticate with TLS certificates.
+type RemoteSigner struct {
+	endpoint string
+	config   *tls.Config
+
+	mu     sync.Mutex
+	conn   net.Conn
+	client *rpc.Client
+
+	blsPub *bls.PublicKey
+}
+
+// NewRemoteSigner connects to the signer at endpoint and retrieves its BLS public key.
+func NewRemoteSigner(endpoint string, config *tls.Config) (*RemoteSigner, error) {
+	network, address, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	config = config.Clone()
+	if config.ServerName == "" {
+		// The certificate of the signer must be valid for the host it is
+		// reached on, or for localhost when using a unix socket.
+		config.ServerName = "localhost"
+		if network == "tcp" {
+			if host, _, err := net.SplitHostPort(address); err == nil {
+				config.ServerName = host
+			}
+		}
+	}
+	s := &RemoteSigner{endpoint: endpoint, config: config}
+
+	var id Identity
+	if err := s.call(&id, "identity"); err != nil {
+		s.Close()
+		return nil, err
+	}
+	var pub bls.PublicKey
+	if err := pub.Deserialize(id.BlsPublicKey); err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.blsPub = &pub
+	log.Info("Connected to remote signer", "endpoint", endpoint, "blsPublicKey", hexutil.Encode(id.BlsPublicKey))
+	return s, nil
+}
+
+func (s *RemoteSigner) BlsPublicKey() *bls.PublicKey {
+	return s.blsPub
+}
+
+func (s *RemoteSigner) SignBls(msg []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.call(&sig, "signBls", hexutil.Bytes(msg)); err != nil {
+		return nil, err
+	}
+	// A signature that doesn't match the key would be rejected by the peers.
+	var sign bls.Sign
+	if err := sign.Deserialize(sig); err != nil || !sign.Verify(s.blsPub, string(msg)) {
+		log.Error("Remote signer returned an invalid bls signature", "endpoint", s.endpoint, "signature", sig)
+		return nil, errInvalidSignature
+	}
+	return sig, nil
+}
+
+func (s *RemoteSigner) SchnorrNIZKProof() (*bls.SchnorrProof, error) {
+	var proof bls.SchnorrProof
+	if err := s.call(&proof, "schnorrNIZKProof"); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// Close closes the connection to the signer.
+func (s *RemoteSigner) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnect()
+	return nil
+}
+
+// call invokes method of the signer service. The connection is reestablished
+// once if it turns out to be broken, signing the same payload twice is harmless.
+func (s *RemoteSigner) call(result interface{}, method string, args ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if s.client == nil {
+			if err := s.connect(); err != nil {
+				return err
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+		err := s.client.CallContext(ctx, result, Namespace+"_"+method, args...)
+		cancel()
+		if _, ok := err.(rpc.Error); err == nil || ok || attempt > 0 {
+			return err
+		}
+		log.Warn("Remote signer request failed, reconnecting", "endpoint", s.endpoint, "method", method, "err", err)
+		s.disconnect()
+	}
+}
+
+func (s *RemoteSigner) connect() error {
+	network, address, err := ParseEndpoint(s.endpoint)
+	if err != nil {
+		return err
+	}
+	raw, err := net.DialTimeout(network, address, remoteDialTimeout)
+	if err != nil {
+		return err
+	}
+	conn := tls.Client(raw, s.config)
+	conn.SetDeadline(time.Now().Add(remoteDialTimeout))
+	if err := conn.Handshake(); err != nil {
+		raw.Close()
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+
+	client, err := rpc.DialIO(context.Background(), conn, conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	s.conn, s.client = conn, client
+	return nil
+}
+
+// disconnect closes the connection before the client, the codec created by
+// rpc.DialIO doesn't close it and the client waits for its reader to fail.
+func (s *RemoteSigner) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
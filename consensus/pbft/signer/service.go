@@ -0,0 +1,130 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rpc"
+)
+
+// Namespace is the JSON-RPC namespace of the signer service.
+const Namespace = "signer"
+
+// Identity describes the key held by a signer.
+type Identity struct {
+	BlsPublicKey hexutil.Bytes `json:"blsPublicKey"`
+}
+
+// Service exposes a Signer over JSON-RPC.
+type Service struct {
+	signer Signer
+}
+
+// NewService creates a service signing with s.
+func NewService(s Signer) *Service {
+	return &Service{signer: s}
+}
+
+// Identity returns the public key of the signer.
+func (s *Service) Identity() (*Identity, error) {
+	pub := s.signer.BlsPublicKey()
+	if pub == nil {
+		return nil, errMissingBlsKey
+	}
+	return &Identity{BlsPublicKey: pub.Serialize()}, nil
+}
+
+// SignBls signs msg with the BLS key.
+func (s *Service) SignBls(msg hexutil.Bytes) (hexutil.Bytes, error) {
+	return s.signer.SignBls(msg)
+}
+
+// SchnorrNIZKProof returns the proof of possession of the BLS key.
+func (s *Service) SchnorrNIZKProof() (*bls.SchnorrProof, error) {
+	return s.signer.SchnorrNIZKProof()
+}
+
+// NewServer creates a JSON-RPC server exposing s.
+func NewServer(s Signer) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(Namespace, NewService(s)); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// Listen listens on the endpoint, the accepted connections must authenticate
+// with a client certificate signed by the CA of config.
+func Listen(endpoint string, config *tls.Config) (net.Listener, error) {
+	network, address, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		// Remove any previous leftover socket
+		os.Remove(address)
+	}
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		os.Chmod(address, 0600)
+	}
+	return tls.NewListener(l, config), nil
+}
+
+// ParseEndpoint splits an endpoint of the form unix:///path/to/socket or
+// tcp://host:port into a network and an address.
+func ParseEndpoint(endpoint string) (string, string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("missing socket path in endpoint %q", endpoint)
+		}
+		return "unix", u.Path, nil
+	case "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("missing address in endpoint %q", endpoint)
+		}
+		return "tcp", u.Host, nil
+	}
+	return "", "", fmt.Errorf("unsupported endpoint %q, expected unix:// or tcp://", endpoint)
+}
+
+// NewTLSConfig loads the certificate of the local side and the CA that signed
+// the certificate of the other side. Both the node and the signer must present
+// a certificate signed by the CA.
+func NewTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("no certificate found in CA file")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
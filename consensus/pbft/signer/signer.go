@@ -0,0 +1,65 @@
+// Package signer provides the BLS key used by the pbft engine to sign the
+// consensus messages, either loaded in-process or held by a remote signer.
+//
+// Only the BLS key can be held remotely. The ECDSA node key is the p2p
+// identity of the node and is also used for the RLPx handshake, so it stays
+// in-process and the blocks are still sealed with it.
+package signer
+
+import (
+	"errors"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+)
+
+var (
+	errMissingBlsKey    = errors.New("missing bls key")
+	errInvalidSignature = errors.New("invalid bls signature from the remote signer")
+)
+
+// Signer signs the consensus messages with the BLS key of a validator.
+type Signer interface {
+	// BlsPublicKey returns the public key of the BLS key.
+	BlsPublicKey() *bls.PublicKey
+	// SignBls signs msg with the BLS key and returns the serialized signature.
+	SignBls(msg []byte) ([]byte, error)
+	// SchnorrNIZKProof returns the proof of possession of the BLS key.
+	SchnorrNIZKProof() (*bls.SchnorrProof, error)
+	// Close releases the resources held by the signer.
+	Close() error
+}
+
+// LocalSigner signs with a BLS key loaded in-process.
+type LocalSigner struct {
+	blsKey *bls.SecretKey
+}
+
+// NewLocalSigner creates a signer from the given key.
+func NewLocalSigner(blsKey *bls.SecretKey) *LocalSigner {
+	return &LocalSigner{blsKey: blsKey}
+}
+
+func (s *LocalSigner) BlsPublicKey() *bls.PublicKey {
+	if s.blsKey == nil {
+		return nil
+	}
+	return s.blsKey.GetPublicKey()
+}
+
+func (s *LocalSigner) SignBls(msg []byte) ([]byte, error) {
+	if s.blsKey == nil {
+		return nil, errMissingBlsKey
+	}
+	return s.blsKey.Sign(string(msg)).Serialize(), nil
+}
+
+func (s *LocalSigner) SchnorrNIZKProof() (*bls.SchnorrProof, error) {
+	if s.blsKey == nil {
+		return nil, errMissingBlsKey
+	}
+	return s.blsKey.MakeSchnorrNIZKP()
+}
+
+func (s *LocalSigner) Close() error {
+	return nil
+}
@@ -0,0 +1,130 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+)
+
+// writeTestCert writes a certificate for localhost signed by the parent, or a
+// self-signed CA certificate if parent is nil.
+func writeTestCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return cert, key
+}
+
+func newTestTLSConfig(t *testing.T, dir, name, ca string) *tls.Config {
+	config, err := NewTLSConfig(filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key"), filepath.Join(dir, ca+".crt"))
+	assert.Nil(t, err)
+	return config
+}
+
+func TestRemoteSigner(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	tempDir, _ := ioutil.TempDir("", "signer")
+	defer os.RemoveAll(tempDir)
+
+	ca, caKey := writeTestCert(t, tempDir, "ca", nil, nil)
+	writeTestCert(t, tempDir, "signer", ca, caKey)
+	writeTestCert(t, tempDir, "node", ca, caKey)
+	other, otherKey := writeTestCert(t, tempDir, "other-ca", nil, nil)
+	writeTestCert(t, tempDir, "intruder", other, otherKey)
+
+	local := NewLocalSigner(bls.GenerateKey())
+	srv, err := NewServer(local)
+	assert.Nil(t, err)
+	defer srv.Stop()
+
+	endpoint := "unix://" + filepath.Join(tempDir, "signer.sock")
+	l, err := Listen(endpoint, newTestTLSConfig(t, tempDir, "signer", "ca"))
+	assert.Nil(t, err)
+	defer l.Close()
+	go srv.ServeListener(l)
+
+	remote, err := NewRemoteSigner(endpoint, newTestTLSConfig(t, tempDir, "node", "ca"))
+	assert.Nil(t, err)
+	defer remote.Close()
+
+	assert.True(t, local.BlsPublicKey().IsEqual(remote.BlsPublicKey()))
+
+	msg := []byte("vote")
+	blsSig, err := remote.SignBls(msg)
+	assert.Nil(t, err)
+	var sign bls.Sign
+	assert.Nil(t, sign.Deserialize(blsSig))
+	assert.True(t, sign.Verify(remote.BlsPublicKey(), string(msg)))
+
+	proof, err := remote.SchnorrNIZKProof()
+	assert.Nil(t, err)
+	assert.Nil(t, proof.VerifySchnorrNIZK(*remote.BlsPublicKey()))
+
+	// A signature that doesn't match the public key is refused.
+	pub := remote.blsPub
+	remote.blsPub = bls.GenerateKey().GetPublicKey()
+	_, err = remote.SignBls(msg)
+	assert.Equal(t, errInvalidSignature, err)
+	remote.blsPub = pub
+
+	// A broken connection is reestablished.
+	remote.mu.Lock()
+	remote.conn.Close()
+	remote.mu.Unlock()
+	_, err = remote.SignBls(msg)
+	assert.Nil(t, err)
+
+	// The signer refuses clients without a certificate signed by its CA.
+	_, err = NewRemoteSigner(endpoint, newTestTLSConfig(t, tempDir, "intruder", "ca"))
+	assert.NotNil(t, err)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	network, address, err := ParseEndpoint("unix:///var/run/signer.sock")
+	assert.Nil(t, err)
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/var/run/signer.sock", address)
+
+	network, address, err = ParseEndpoint("tcp://10.0.0.1:7890")
+	assert.Nil(t, err)
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "10.0.0.1:7890", address)
+
+	_, _, err = ParseEndpoint("http://10.0.0.1:7890")
+	assert.NotNil(t, err)
+}
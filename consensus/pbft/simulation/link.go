@@ -0,0 +1,155 @@
+package simulation
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+)
+
+// Behavior describes how a Byzantine validator deviates from the protocol on
+// the messages it sends.
+type Behavior struct {
+	// Equivocate signs a prepare vote and a precommit for another block hash
+	// and sends them, instead of the genuine ones, to the peers of odd index.
+	Equivocate bool
+	// Withhold lists the codes of the messages that are never sent,
+	// e.g. protocols.PrepareVoteMsg.
+	Withhold []uint64
+	// ViewChangeDelay is added to the latency of the view change messages.
+	ViewChangeDelay time.Duration
+}
+
+func (b *Behavior) withholds(code uint64) bool {
+	for _, c := range b.Withhold {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// link is the end of a simulated connection owned by the from validator, the
+// messages it writes are subject to the faults of the network.
+type link struct {
+	net      *Network
+	from, to *Node
+	rw       p2p.MsgReadWriter
+}
+
+func (l *link) ReadMsg() (p2p.Msg, error) {
+	return l.rw.ReadMsg()
+}
+
+// WriteMsg returns as soon as the message is scheduled, the messages are
+// delivered asynchronously and may be reordered by the latency.
+func (l *link) WriteMsg(msg p2p.Msg) error {
+	payload, err := ioutil.ReadAll(msg.Payload)
+	if err != nil {
+		return err
+	}
+	// The handshake is never disturbed, the peers would disconnect.
+	if msg.Code == protocols.PBFTStatusMsg {
+		return l.deliver(msg.Code, payload)
+	}
+
+	delay, drop := l.net.fault(l.from.Index, l.to.Index, msg.Code)
+	if drop {
+		return nil
+	}
+	if behavior := l.net.behavior(l.from.Index); behavior != nil && behavior.Equivocate && l.to.Index%2 == 1 {
+		if conflicting, err := l.equivocate(msg.Code, payload); err != nil {
+			log.Error("Simulated equivocation failed", "node", l.from.Index, "code", msg.Code, "err", err)
+		} else if conflicting != nil {
+			payload = conflicting
+		}
+	}
+	go func() {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-l.net.quit:
+				return
+			}
+		}
+		l.deliver(msg.Code, payload)
+	}()
+	return nil
+}
+
+func (l *link) deliver(code uint64, payload []byte) error {
+	return l.rw.WriteMsg(p2p.Msg{Code: code, Size: uint32(len(payload)), Payload: bytes.NewReader(payload)})
+}
+
+// equivocate returns the payload of a message conflicting with the vote or
+// precommit in payload, nil if the message can not be equivocated.
+func (l *link) equivocate(code uint64, payload []byte) ([]byte, error) {
+	var msg ctypes.ConsensusMsg
+	switch code {
+	case protocols.PrepareVoteMsg:
+		var vote protocols.PrepareVote
+		if err := rlp.DecodeBytes(payload, &vote); err != nil {
+			return nil, err
+		}
+		vote.BlockHash = crypto.Keccak256Hash(vote.BlockHash.Bytes())
+		msg = &vote
+	case protocols.PreCommitMsg:
+		var preCommit protocols.PreCommit
+		if err := rlp.DecodeBytes(payload, &preCommit); err != nil {
+			return nil, err
+		}
+		preCommit.BlockHash = crypto.Keccak256Hash(preCommit.BlockHash.Bytes())
+		msg = &preCommit
+	default:
+		return nil, nil
+	}
+	// Only equivocate the own messages, not the forwarded ones. The static
+	// agency indexes the validators in the order of the network.
+	if msg.NodeIndex() != uint32(l.from.Index) {
+		return nil, nil
+	}
+	buf, err := msg.CannibalizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	msg.SetSign(l.from.blsKey.Sign(string(buf)).Serialize())
+	return rlp.EncodeToBytes(msg)
+}
+
+// fault returns the delay of a message, or whether it is dropped.
+func (n *Network) fault(from, to int, code uint64) (time.Duration, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.groups[from] != n.groups[to] {
+		return 0, true
+	}
+	if n.loss > 0 && n.rand.Float64() < n.loss {
+		return 0, true
+	}
+	delay := n.latency
+	if n.jitter > 0 {
+		delay += time.Duration(n.rand.Int63n(int64(n.jitter)))
+	}
+	if behavior, ok := n.byzantine[from]; ok {
+		if behavior.withholds(code) {
+			return 0, true
+		}
+		if code == protocols.ViewChangeMsg {
+			delay += behavior.ViewChangeDelay
+		}
+	}
+	return delay, false
+}
+
+func (n *Network) behavior(index int) *Behavior {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.byzantine[index]
+}
@@ -0,0 +1,500 @@
+// Package simulation runs a network of PBFT validators in a single process.
+//
+// The validators are complete Pbft engines connected through the PBFT
+// protocol handler over simulated links, which can inject latency, message
+// loss, partitions and Byzantine behaviors. Scenarios script the faults and
+// assert the safety and the liveness of the consensus.
+package simulation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+)
+
+const (
+	// Interval between two attempts of a validator to seal a block
+	sealInterval = 20 * time.Millisecond
+
+	// Upper bound of the view timeout relative to the period, the view timer
+	// grows up to 1.5^2 times the period after consecutive timeouts.
+	maxViewTimeoutFactor = 3
+)
+
+var errNoNodes = errors.New("simulation needs at least one validator")
+
+// Config is the configuration of a simulated network.
+type Config struct {
+	Nodes  int    // Number of validators
+	Period uint64 // Duration of a view in milliseconds
+	Amount uint32 // Number of blocks produced in a view
+	Seed   int64  // Seed of the random faults, a fixed seed replays the same faults
+}
+
+// DefaultConfig is a four validators network.
+var DefaultConfig = Config{
+	Nodes:  4,
+	Period: 3000,
+	Amount: 10,
+	Seed:   1,
+}
+
+// Node is a validator of the simulated network.
+type Node struct {
+	Index  int
+	ID     discover.NodeID
+	Engine *pbft.Pbft
+
+	nodeKey *ecdsa.PrivateKey
+	blsKey  *bls.SecretKey
+	mux     *event.TypeMux
+	chain   *core.BlockChain
+	cache   *core.BlockChainCache
+
+	mu          sync.Mutex
+	commits     map[uint64]common.Hash // committed blocks by number
+	highest     uint64                 // highest committed block number
+	viewChanges int                    // number of view changes seen
+	view        pbfttypes.ConsensusView
+}
+
+// Commits returns the hashes of the blocks committed by the node by number.
+func (n *Node) Commits() map[uint64]common.Hash {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	commits := make(map[uint64]common.Hash, len(n.commits))
+	for number, hash := range n.commits {
+		commits[number] = hash
+	}
+	return commits
+}
+
+// HighestCommit returns the number of the highest block committed by the node.
+func (n *Node) HighestCommit() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.highest
+}
+
+func (n *Node) progress() (uint64, int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.highest, n.viewChanges
+}
+
+// Network is a set of validators connected by simulated links.
+type Network struct {
+	config Config
+	nodes  []*Node
+
+	mu        sync.Mutex
+	rand      *rand.Rand
+	latency   time.Duration
+	jitter    time.Duration
+	loss      float64
+	groups    map[int]int // partition group by node index, unlisted nodes are in group 0
+	byzantine map[int]*Behavior
+
+	pipes []*p2p.MsgPipeRW
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New starts a network of validators, they are connected to each other and
+// start producing blocks immediately.
+func New(config Config) (*Network, error) {
+	if config.Nodes < 1 {
+		return nil, errNoNodes
+	}
+	n := &Network{
+		config:    config,
+		rand:      rand.New(rand.NewSource(config.Seed)),
+		groups:    make(map[int]int),
+		byzantine: make(map[int]*Behavior),
+		quit:      make(chan struct{}),
+	}
+	nodeKeys, blsKeys, pbftNodes := pbft.GeneratePbftNode(config.Nodes)
+	for i := 0; i < config.Nodes; i++ {
+		nd, err := n.newNode(i, nodeKeys[i], blsKeys[i], pbftNodes)
+		if err != nil {
+			n.Stop()
+			return nil, err
+		}
+		n.nodes = append(n.nodes, nd)
+	}
+	for i := 0; i < len(n.nodes); i++ {
+		for j := i + 1; j < len(n.nodes); j++ {
+			n.connect(n.nodes[i], n.nodes[j])
+		}
+	}
+	for _, nd := range n.nodes {
+		n.wg.Add(2)
+		go n.sealLoop(nd)
+		go n.resultLoop(nd)
+	}
+	return n, nil
+}
+
+func (n *Network) newNode(index int, nodeKey *ecdsa.PrivateKey, blsKey *bls.SecretKey, pbftNodes []configs.PbftNode) (*Node, error) {
+	sysConfig := &configs.PbftConfig{
+		Period:       n.config.Period,
+		Amount:       n.config.Amount,
+		InitialNodes: pbftNodes,
+	}
+	optConfig := &ctypes.OptionsConfig{
+		NodePriKey:        nodeKey,
+		NodeID:            discover.PubkeyID(&nodeKey.PublicKey),
+		BlsPriKey:         blsKey,
+		PeerMsgQueueSize:  1024,
+		MaxQueuesLimit:    1000,
+		BlacklistDeadline: 1,
+	}
+	mux := new(event.TypeMux)
+	ctx := node.NewServiceContext(&node.Config{DataDir: ""}, nil, mux, nil)
+	engine := pbft.New(sysConfig, optConfig, mux, ctx)
+	if engine == nil {
+		return nil, fmt.Errorf("create validator %d failed", index)
+	}
+	chain, cache, txpool, agency := pbft.CreateBackend(engine, pbftNodes)
+
+	nd := &Node{
+		Index:   index,
+		ID:      optConfig.NodeID,
+		Engine:  engine,
+		nodeKey: nodeKey,
+		blsKey:  blsKey,
+		mux:     mux,
+		chain:   chain,
+		cache:   cache,
+		commits: make(map[uint64]common.Hash),
+	}
+	// Subscribe before starting to observe the first view.
+	sub := engine.SubscribeConsensusEvents()
+	if err := engine.Start(chain, cache, txpool, agency); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+	n.wg.Add(1)
+	go n.eventLoop(nd, sub)
+	return nd, nil
+}
+
+// connect links two validators through their PBFT protocol handlers.
+func (n *Network) connect(a, b *Node) {
+	rwA, rwB := p2p.MsgPipe()
+	n.mu.Lock()
+	n.pipes = append(n.pipes, rwA, rwB)
+	n.mu.Unlock()
+
+	run := func(local, remote *Node, rw p2p.MsgReadWriter) {
+		defer n.wg.Done()
+		peer := p2p.NewPeer(remote.ID, remote.ID.TerminalString(), nil)
+		if err := local.Engine.Protocols()[0].Run(peer, rw); err != nil {
+			log.Debug("Simulated link closed", "local", local.Index, "remote", remote.Index, "err", err)
+		}
+	}
+	n.wg.Add(2)
+	go run(a, b, &link{net: n, from: a, to: b, rw: rwA})
+	go run(b, a, &link{net: n, from: b, to: a, rw: rwB})
+}
+
+// eventLoop records the commits and the view changes of a validator.
+func (n *Network) eventLoop(nd *Node, sub *event.TypeMuxSubscription) {
+	defer n.wg.Done()
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-sub.Chan():
+			if ev == nil {
+				return
+			}
+			nd.mu.Lock()
+			switch data := ev.Data.(type) {
+			case pbfttypes.BlockCommitEvent:
+				nd.commits[data.BlockNumber] = data.BlockHash
+				if data.BlockNumber > nd.highest {
+					nd.highest = data.BlockNumber
+				}
+			case pbfttypes.ViewChangeEvent:
+				nd.viewChanges++
+				nd.view = data.ConsensusView
+			}
+			nd.mu.Unlock()
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// sealLoop plays the miner of a validator, it proposes an empty block on top
+// of the highest precommit QC block whenever the engine allows to seal.
+func (n *Network) sealLoop(nd *Node) {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(sealInterval)
+	defer ticker.Stop()
+
+	type proposal struct {
+		parent            common.Hash
+		epoch, viewNumber uint64
+	}
+	var last proposal
+	for {
+		select {
+		case <-ticker.C:
+		case <-n.quit:
+			return
+		}
+		if ok, _ := nd.Engine.ShouldSeal(time.Now()); !ok {
+			continue
+		}
+		parent := nd.Engine.NextBaseBlock()
+		nd.mu.Lock()
+		next := proposal{parent: parent.Hash(), epoch: nd.view.Epoch, viewNumber: nd.view.ViewNumber}
+		nd.mu.Unlock()
+		if next == last {
+			continue
+		}
+		if err := n.seal(nd, parent); err != nil {
+			log.Debug("Simulated seal failed", "node", nd.Index, "parent", parent.NumberU64(), "err", err)
+			continue
+		}
+		last = next
+	}
+}
+
+func (n *Network) seal(nd *Node, parent *types.Block) error {
+	header := &types.Header{
+		ParentHash:  parent.Hash(),
+		Number:      new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:    parent.GasLimit(),
+		Time:        uint64(time.Now().UnixNano() / 1e6),
+		Root:        parent.Root(), // blocks are empty
+		ReceiptHash: types.EmptyRootHash,
+	}
+	if err := nd.Engine.Prepare(nd.chain, header); err != nil {
+		return err
+	}
+	block := types.NewBlockWithHeader(header)
+	if err := nd.cache.Execute(block, parent); err != nil {
+		return err
+	}
+
+	results := make(chan *types.Block, 1)
+	stop := make(chan struct{})
+	complete := make(chan struct{}, 1)
+	if err := nd.Engine.Seal(nd.chain, block, results, stop, complete); err != nil {
+		return err
+	}
+	select {
+	case <-complete:
+	case <-n.quit:
+	}
+	return nil
+}
+
+// resultLoop writes the blocks committed by the consensus to the chain, as
+// the miner of a real node does.
+func (n *Network) resultLoop(nd *Node) {
+	defer n.wg.Done()
+
+	sub := nd.mux.Subscribe(pbfttypes.PbftResult{})
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-sub.Chan():
+			if ev == nil {
+				return
+			}
+			result := ev.Data.(pbfttypes.PbftResult)
+			block := result.Block
+			if block == nil || nd.chain.HasBlock(block.Hash(), block.NumberU64()) {
+				continue
+			}
+			block.SetExtraData(result.ExtraData)
+			result.ChainStateUpdateCB()
+			if err := nd.cache.WriteBlock(block); err != nil {
+				log.Error("Simulated write block failed", "node", nd.Index, "number", block.NumberU64(), "err", err)
+				if result.SyncState != nil {
+					result.SyncState <- err
+				}
+			}
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// Nodes returns the validators of the network.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Node returns the validator at index.
+func (n *Network) Node(index int) *Node {
+	return n.nodes[index]
+}
+
+// Stop stops all the validators and closes the links.
+func (n *Network) Stop() {
+	select {
+	case <-n.quit:
+		return
+	default:
+	}
+	close(n.quit)
+	n.mu.Lock()
+	for _, pipe := range n.pipes {
+		pipe.Close()
+	}
+	n.mu.Unlock()
+	for _, nd := range n.nodes {
+		nd.Engine.Close()
+	}
+	n.wg.Wait()
+	for _, nd := range n.nodes {
+		nd.chain.Stop()
+	}
+}
+
+// SetLatency sets the delay of the messages, each message is delayed by
+// latency plus a random duration up to jitter.
+func (n *Network) SetLatency(latency, jitter time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.latency, n.jitter = latency, jitter
+}
+
+// SetLoss sets the probability for a message to be dropped.
+func (n *Network) SetLoss(rate float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.loss = rate
+}
+
+// Partition splits the validators into the groups, messages are only delivered
+// within a group. The validators not listed form an additional group.
+func (n *Network) Partition(groups ...[]int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.groups = make(map[int]int)
+	for i, group := range groups {
+		for _, index := range group {
+			n.groups[index] = i + 1
+		}
+	}
+}
+
+// Heal removes the partitions.
+func (n *Network) Heal() {
+	n.Partition()
+}
+
+// SetByzantine makes the validator at index misbehave, a nil behavior makes
+// it honest again.
+func (n *Network) SetByzantine(index int, behavior *Behavior) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if behavior == nil {
+		delete(n.byzantine, index)
+		return
+	}
+	n.byzantine[index] = behavior
+}
+
+// Honest returns the validators without Byzantine behavior.
+func (n *Network) Honest() []*Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var nodes []*Node
+	for _, nd := range n.nodes {
+		if _, ok := n.byzantine[nd.Index]; !ok {
+			nodes = append(nodes, nd)
+		}
+	}
+	return nodes
+}
+
+// maxViewTimeout is an upper bound of the duration of a view.
+func (n *Network) maxViewTimeout() time.Duration {
+	return time.Duration(n.config.Period*maxViewTimeoutFactor) * time.Millisecond
+}
+
+// CheckSafety verifies that no two validators committed different blocks at
+// the same height.
+func (n *Network) CheckSafety() error {
+	committed := make(map[uint64]common.Hash)
+	committer := make(map[uint64]int)
+	for _, nd := range n.nodes {
+		for number, hash := range nd.Commits() {
+			if prev, ok := committed[number]; ok && prev != hash {
+				return fmt.Errorf("conflicting commits at block %d: node %d committed %s, node %d committed %s",
+					number, committer[number], prev.TerminalString(), nd.Index, hash.TerminalString())
+			}
+			committed[number] = hash
+			committer[number] = nd.Index
+		}
+	}
+	return nil
+}
+
+// CheckLiveness waits until every honest validator commits a new block, it
+// fails if a validator goes through more than views view changes first.
+func (n *Network) CheckLiveness(views int) error {
+	type start struct {
+		highest     uint64
+		viewChanges int
+	}
+	nodes := n.Honest()
+	starts := make(map[int]start)
+	for _, nd := range nodes {
+		highest, viewChanges := nd.progress()
+		starts[nd.Index] = start{highest, viewChanges}
+	}
+
+	deadline := time.After(time.Duration(views+1) * n.maxViewTimeout())
+	ticker := time.NewTicker(sealInterval)
+	defer ticker.Stop()
+	for {
+		pending := 0
+		for _, nd := range nodes {
+			highest, viewChanges := nd.progress()
+			s := starts[nd.Index]
+			if highest > s.highest {
+				continue
+			}
+			if viewChanges-s.viewChanges > views {
+				return fmt.Errorf("node %d committed nothing within %d views, highest commit %d", nd.Index, views, highest)
+			}
+			pending++
+		}
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return fmt.Errorf("%d honest nodes committed nothing within %d views", pending, views)
+		case <-n.quit:
+			return errors.New("network stopped")
+		}
+	}
+}
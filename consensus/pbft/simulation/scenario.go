@@ -0,0 +1,120 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// Step is an action of a scenario, a step returning an error fails the scenario.
+type Step struct {
+	Name string
+	Run  func(n *Network) error
+}
+
+// Scenario is a scripted simulation, the steps run in order on a new network.
+type Scenario struct {
+	Config Config
+	Steps  []Step
+}
+
+// Run starts the network, runs the steps and checks the safety at the end.
+func (s *Scenario) Run() error {
+	n, err := New(s.Config)
+	if err != nil {
+		return err
+	}
+	defer n.Stop()
+
+	for i, step := range s.Steps {
+		if err := step.Run(n); err != nil {
+			return fmt.Errorf("step %d (%s): %v", i, step.Name, err)
+		}
+	}
+	return n.CheckSafety()
+}
+
+// Wait lets the network run for d.
+func Wait(d time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("wait %v", d),
+		Run: func(n *Network) error {
+			time.Sleep(d)
+			return nil
+		},
+	}
+}
+
+// Latency sets the latency of the links.
+func Latency(latency, jitter time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("latency %v+%v", latency, jitter),
+		Run: func(n *Network) error {
+			n.SetLatency(latency, jitter)
+			return nil
+		},
+	}
+}
+
+// Loss sets the message loss rate of the links.
+func Loss(rate float64) Step {
+	return Step{
+		Name: fmt.Sprintf("loss %.2f", rate),
+		Run: func(n *Network) error {
+			n.SetLoss(rate)
+			return nil
+		},
+	}
+}
+
+// Partition splits the network into groups.
+func Partition(groups ...[]int) Step {
+	return Step{
+		Name: fmt.Sprintf("partition %v", groups),
+		Run: func(n *Network) error {
+			n.Partition(groups...)
+			return nil
+		},
+	}
+}
+
+// Heal removes the partitions.
+func Heal() Step {
+	return Step{
+		Name: "heal",
+		Run: func(n *Network) error {
+			n.Heal()
+			return nil
+		},
+	}
+}
+
+// Byzantine makes the validator at index misbehave.
+func Byzantine(index int, behavior *Behavior) Step {
+	return Step{
+		Name: fmt.Sprintf("byzantine %d", index),
+		Run: func(n *Network) error {
+			n.SetByzantine(index, behavior)
+			return nil
+		},
+	}
+}
+
+// Safety checks that no conflicting blocks were committed.
+func Safety() Step {
+	return Step{
+		Name: "safety",
+		Run: func(n *Network) error {
+			return n.CheckSafety()
+		},
+	}
+}
+
+// Liveness checks that every honest validator commits a block within views.
+func Liveness(views int) Step {
+	return Step{
+		Name: fmt.Sprintf("liveness %d views", views),
+		Run: func(n *Network) error {
+			return n.CheckLiveness(views)
+		},
+	}
+}
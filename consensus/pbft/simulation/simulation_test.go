@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+var testConfig = Config{
+	Nodes:  4,
+	Period: 2000,
+	Amount: 2,
+	Seed:   1,
+}
+
+func TestScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	tests := []struct {
+		name  string
+		steps []Step
+	}{
+		{
+			name: "lossy network",
+			steps: []Step{
+				Liveness(2),
+				Latency(20*time.Millisecond, 30*time.Millisecond),
+				Loss(0.05),
+				Liveness(4),
+			},
+		},
+		{
+			name: "minority partition",
+			steps: []Step{
+				Liveness(2),
+				Partition([]int{3}),
+				Liveness(4),
+				Heal(),
+				Liveness(4),
+			},
+		},
+		{
+			name: "byzantine validator",
+			steps: []Step{
+				Liveness(2),
+				Byzantine(1, &Behavior{
+					Equivocate:      true,
+					Withhold:        []uint64{protocols.PreCommitMsg},
+					ViewChangeDelay: time.Second,
+				}),
+				Liveness(6),
+				Safety(),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Scenario{Config: testConfig, Steps: test.steps}
+			assert.Nil(t, s.Run())
+		})
+	}
+}
+
+func TestCheckSafety(t *testing.T) {
+	n := &Network{nodes: []*Node{
+		{Index: 0, commits: map[uint64]common.Hash{1: {1}, 2: {2}}},
+		{Index: 1, commits: map[uint64]common.Hash{1: {1}}},
+	}}
+	assert.Nil(t, n.CheckSafety())
+
+	n.nodes = append(n.nodes, &Node{Index: 2, commits: map[uint64]common.Hash{2: {3}}})
+	assert.NotNil(t, n.CheckSafety())
+}
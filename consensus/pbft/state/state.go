@@ -10,6 +10,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mclock"
 
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
@@ -906,7 +907,7 @@ func (vs *ViewState) ViewString() string {
 }
 
 func (vs *ViewState) Deadline() time.Time {
-	return vs.viewTimer.deadline
+	return vs.viewTimer.deadline()
 }
 
 func (vs *ViewState) NextViewBlockNumber() uint64 {
@@ -1380,10 +1381,16 @@ func (vs *ViewState) IsDeadline() bool {
 	return vs.viewTimer.isDeadline()
 }
 
-func (vs *ViewState) ViewTimeout() <-chan time.Time {
+func (vs *ViewState) ViewTimeout() <-chan mclock.AbsTime {
 	return vs.viewTimer.timerChan()
 }
 
+// SetClock replaces the clock driving the view timer, used to replay
+// recorded consensus messages against a simulated clock.
+func (vs *ViewState) SetClock(clock mclock.Clock) {
+	vs.viewTimer.setClock(clock)
+}
+
 func (vs *ViewState) SetViewTimer(viewInterval uint64) {
 	vs.viewTimer.setupTimer(viewInterval)
 }
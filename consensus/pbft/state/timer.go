@@ -3,6 +3,8 @@ package state
 import (
 	"math"
 	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mclock"
 )
 
 const (
@@ -12,8 +14,13 @@ const (
 
 type viewTimer struct {
 	//Timer last timeout
-	deadline time.Time
-	timer    *time.Timer
+	expire mclock.AbsTime
+	clock  mclock.Clock
+	timer  mclock.ChanTimer
+
+	// Wall time of the clock reading origin, used to report the deadline
+	origin    time.Time
+	originAbs mclock.AbsTime
 
 	//Time window length calculation module
 	timeInterval    viewTimeInterval
@@ -21,12 +28,23 @@ type viewTimer struct {
 }
 
 func newViewTimer(period uint64) *viewTimer {
-	timer := time.NewTimer(0)
-	timer.Stop()
-	return &viewTimer{timer: timer,
+	t := &viewTimer{
 		timeInterval:    viewTimeInterval{baseMs: period * uint64(time.Millisecond), exponentBase: exponentBase, maxExponent: maxExponent},
 		preViewInterval: 1,
 	}
+	t.setClock(mclock.System{})
+	return t
+}
+
+// setClock replaces the clock driving the timer, the timer is stopped.
+func (t *viewTimer) setClock(clock mclock.Clock) {
+	if t.timer != nil {
+		t.stopTimer()
+	}
+	t.clock = clock
+	t.origin, t.originAbs = time.Now(), clock.Now()
+	t.timer = clock.NewTimer(time.Hour)
+	t.timer.Stop()
 }
 
 // Ensure that the timeout period is adjusted smoothly.
@@ -44,7 +62,7 @@ func (t *viewTimer) calViewInterval(viewInterval uint64) uint64 {
 func (t *viewTimer) setupTimer(viewInterval uint64) {
 	viewInterval = t.calViewInterval(viewInterval)
 	duration := t.timeInterval.getViewTimeInterval(viewInterval)
-	t.deadline = time.Now().Add(duration)
+	t.expire = t.clock.Now().Add(duration)
 	t.stopTimer()
 	t.timer.Reset(duration)
 }
@@ -52,17 +70,22 @@ func (t *viewTimer) setupTimer(viewInterval uint64) {
 func (t *viewTimer) stopTimer() {
 	if !t.timer.Stop() {
 		select {
-		case <-t.timer.C:
+		case <-t.timer.C():
 		default:
 		}
 	}
 }
-func (t *viewTimer) timerChan() <-chan time.Time {
-	return t.timer.C
+func (t *viewTimer) timerChan() <-chan mclock.AbsTime {
+	return t.timer.C()
 }
 
 func (t viewTimer) isDeadline() bool {
-	return t.deadline.Before(time.Now())
+	return t.expire < t.clock.Now()
+}
+
+// deadline returns the wall time at which the timer expires.
+func (t viewTimer) deadline() time.Time {
+	return t.origin.Add(time.Duration(t.expire - t.originAbs))
 }
 
 // Calculate the time window of each view，time=b*e^m
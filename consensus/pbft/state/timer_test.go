@@ -1,16 +1,15 @@
 package state
 
 import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mclock"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 )
 
 func TestTimer(t *testing.T) {
-	timer := time.NewTimer(0)
-	timer.Stop()
-
-	viewTimer := &viewTimer{timer: timer, timeInterval: viewTimeInterval{baseMs: uint64(1 * time.Second), exponentBase: exponentBase, maxExponent: maxExponent}}
+	viewTimer := &viewTimer{timeInterval: viewTimeInterval{baseMs: uint64(1 * time.Second), exponentBase: exponentBase, maxExponent: maxExponent}}
+	viewTimer.setClock(mclock.System{})
 	viewTimer.setupTimer(1)
 	assert.False(t, viewTimer.isDeadline())
 	select {
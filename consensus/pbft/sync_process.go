@@ -13,6 +13,7 @@ import (
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/utils"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types/pbfttypes"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 )
 
@@ -302,6 +303,7 @@ func (pbft *Pbft) OnBlockQuorumCert(id string, msg *protocols.BlockQuorumCert) e
 	//pbft.trySendPrepareVote()
 	pbft.state.SetPrepareVoteQC(msg.BlockQC)
 	pbft.state.AddQC(msg.BlockQC)
+	pbft.postQCEvent(pbfttypes.QCPhasePrepare, msg.BlockQC)
 
 	pbft.genPreCommit(msg.BlockQC)
 
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/signer"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
 )
@@ -12,13 +13,16 @@ type OptionsConfig struct {
 	NodePriKey *ecdsa.PrivateKey `json:"-"`
 	NodeID     discover.NodeID   `json:"nodeID"`
 	BlsPriKey  *bls.SecretKey    `json:"-"`
+	Signer     signer.Signer     `json:"-"` // Remote signer used instead of the local BLS key, nil if disabled.
 	WalMode    bool              `json:"walMode"`
 
 	PeerMsgQueueSize  uint64 `json:"peerMsgQueueSize"`
 	EvidenceDir       string `json:"evidenceDir"`
+	ProtectionDir     string `json:"protectionDir"` // Double-sign protection database directory.
 	MaxPingLatency    int64  `json:"maxPingLatency"`    // maxPingLatency is the time in milliseconds between Ping and Pong
 	MaxQueuesLimit    int64  `json:"maxQueuesLimit"`    // The maximum value that a single node can send a message.
 	BlacklistDeadline int64  `json:"blacklistDeadline"` // Blacklist expiration time. unit: minute.
+	RecordFile        string `json:"recordFile"`        // Capture file of the received consensus messages, empty to disable.
 
 	Period uint64 `json:"period"`
 	Amount uint32 `json:"amount"`
@@ -9,6 +9,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/state"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/finality"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/utils"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
@@ -551,6 +552,63 @@ func (vp *ValidatorPool) Validators(epoch uint64) *pbfttypes.Validators {
 	return vp.currentValidators
 }
 
+// ValidatorsByBlockNumber returns the validators that were responsible for
+// the specified block. Only the previous and current validators are kept in
+// the pool, validators of earlier epochs are queried from the agency.
+func (vp *ValidatorPool) ValidatorsByBlockNumber(blockNumber uint64) (*pbfttypes.Validators, error) {
+	vp.lock.RLock()
+	defer vp.lock.RUnlock()
+
+	if blockNumber > vp.switchPoint {
+		return vp.currentValidators, nil
+	}
+	if vp.prevValidators != nil && blockNumber >= vp.prevValidators.ValidBlockNumber {
+		return vp.prevValidators, nil
+	}
+	return vp.agency.GetValidator(blockNumber)
+}
+
+// IsEpochSwitchBlock returns whether the block is the last block of its
+// epoch, which hands over to the next validators.
+func (vp *ValidatorPool) IsEpochSwitchBlock(blockNumber uint64) bool {
+	return blockNumber != 0 && vp.agency.GetLastNumber(blockNumber) == blockNumber
+}
+
+// LastNumber returns the number of the epoch switch block of the epoch
+// which the specified block belongs to.
+func (vp *ValidatorPool) LastNumber(blockNumber uint64) uint64 {
+	return vp.agency.GetLastNumber(blockNumber)
+}
+
+// NextValidators returns the validators that take over after the specified
+// epoch switch block.
+func (vp *ValidatorPool) NextValidators(blockNumber uint64) (*pbfttypes.Validators, error) {
+	return vp.agency.GetValidator(NextRound(blockNumber))
+}
+
+// VerifyValidatorsHash checks the next validators commitment of the header,
+// only epoch switch blocks carry one.
+func (vp *ValidatorPool) VerifyValidatorsHash(header *types.Header) error {
+	hash, ok := header.ValidatorsHash()
+	if !vp.IsEpochSwitchBlock(header.Number.Uint64()) {
+		if ok {
+			return errors.New("unexpected validators hash")
+		}
+		return nil
+	}
+	if !ok {
+		return errors.New("missing validators hash")
+	}
+	nds, err := vp.NextValidators(header.Number.Uint64())
+	if err != nil {
+		return err
+	}
+	if expect := finality.NewValidatorSet(nds).Hash(); hash != expect {
+		return fmt.Errorf("validators hash mismatch, expect:%s, actual:%s", expect.TerminalString(), hash.TerminalString())
+	}
+	return nil
+}
+
 // VerifyHeader verify block's header.
 func (vp *ValidatorPool) VerifyHeader(header *types.Header) error {
 	_, err := crypto.Ecrecover(header.SealHash().Bytes(), header.Signature())
@@ -0,0 +1,403 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// The size of the header of a journal entry: crc (4 byte) + length (4 byte) + msgType (2 byte)
+const journalHeaderSize = 10
+
+var (
+	errWalDirNotExist = errors.New("wal directory does not exist")
+)
+
+// NodeWalDir returns the wal directory of the specified node.
+func NodeWalDir(stack *node.Node) string {
+	return stack.ResolvePath(walDir)
+}
+
+// JournalCorruptedError reports the position of the first entry of a journal
+// file that can not be read.
+type JournalCorruptedError struct {
+	FileID uint32
+	Seq    uint64
+	Reason string
+}
+
+func (e *JournalCorruptedError) Error() string {
+	return fmt.Sprintf("journal wal.%d corrupted at seq %d: %s", e.FileID, e.Seq, e.Reason)
+}
+
+// JournalFile describes a journal file in the wal directory.
+type JournalFile struct {
+	FileID uint32 `json:"fileID"`
+	Size   uint64 `json:"size"`
+}
+
+// JournalEntry is a consensus message read from a journal file.
+type JournalEntry struct {
+	FileID    uint32      `json:"fileID"`
+	Seq       uint64      `json:"seq"`
+	Timestamp uint64      `json:"timestamp"`
+	MsgType   uint16      `json:"msgType"`
+	Msg       interface{} `json:"msg"`
+}
+
+// Epoch returns the epoch of the journaled message.
+func (e *JournalEntry) Epoch() uint64 {
+	epoch, _ := messageView(e.Msg)
+	return epoch
+}
+
+// ViewNumber returns the view number of the journaled message.
+func (e *JournalEntry) ViewNumber() uint64 {
+	_, viewNumber := messageView(e.Msg)
+	return viewNumber
+}
+
+// StoredViewChangeQC is a viewChangeQC stored in the wal database.
+type StoredViewChangeQC struct {
+	Epoch        uint64               `json:"epoch"`
+	BlockNumber  uint64               `json:"blockNumber"`
+	ViewNumber   uint64               `json:"viewNumber"`
+	ViewChangeQC *ctypes.ViewChangeQC `json:"viewChangeQC"`
+}
+
+// Inspector gives offline access to the wal of a stopped node. Unlike
+// NewWal it never creates the wal directory or a new journal file.
+type Inspector struct {
+	path   string
+	metaDB IWALDatabase
+}
+
+// NewInspector opens the wal in the specified directory for inspection.
+func NewInspector(path string) (*Inspector, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, errWalDirNotExist
+	}
+	metaDB, err := createWalDB(filepath.Join(path, metaDBName))
+	if err != nil {
+		return nil, err
+	}
+	return &Inspector{
+		path:   path,
+		metaDB: metaDB,
+	}, nil
+}
+
+// Close closes the wal database.
+func (in *Inspector) Close() {
+	in.metaDB.Close()
+}
+
+// ChainState returns the stored chainState, nil if there is none.
+func (in *Inspector) ChainState() (*protocols.ChainState, error) {
+	data, err := in.metaDB.Get(chainStateKey)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var cs protocols.ChainState
+	if err := rlp.DecodeBytes(data, &cs); err != nil {
+		return nil, errGetChainState
+	}
+	return &cs, nil
+}
+
+// ViewChange returns the stored viewChange meta, nil if there is none.
+func (in *Inspector) ViewChange() (*ViewChangeMessage, error) {
+	data, err := in.metaDB.Get(viewChangeKey)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var vc ViewChangeMessage
+	if err := rlp.DecodeBytes(data, &vc); err != nil {
+		return nil, errGetViewChangeMeta
+	}
+	return &vc, nil
+}
+
+// ViewChangeQCs returns all the stored viewChangeQCs ordered by epoch, block number and view number.
+func (in *Inspector) ViewChangeQCs() ([]*StoredViewChangeQC, error) {
+	it := in.metaDB.NewIterator(viewChangeQCPrefix, nil)
+	defer it.Release()
+
+	qcs := make([]*StoredViewChangeQC, 0)
+	for it.Next() {
+		epoch, blockNumber, viewNumber, err := parseViewChangeQCKey(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		var qc ctypes.ViewChangeQC
+		if err := rlp.DecodeBytes(it.Value(), &qc); err != nil {
+			return nil, fmt.Errorf("%v, epoch:%d, blockNumber:%d, viewNumber:%d", errGetViewChangeQC, epoch, blockNumber, viewNumber)
+		}
+		qcs = append(qcs, &StoredViewChangeQC{
+			Epoch:        epoch,
+			BlockNumber:  blockNumber,
+			ViewNumber:   viewNumber,
+			ViewChangeQC: &qc,
+		})
+	}
+	return qcs, it.Error()
+}
+
+// JournalFiles returns the journal files in ascending order.
+func (in *Inspector) JournalFiles() ([]*JournalFile, error) {
+	files := make([]*JournalFile, 0)
+	for _, file := range listJournalFiles(in.path) {
+		info, err := os.Stat(filepath.Join(in.path, file.name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &JournalFile{FileID: file.num, Size: uint64(info.Size())})
+	}
+	return files, nil
+}
+
+// Journal reads the journaled consensus messages starting from the specified
+// file and seq, and passes them to fn in order. Reading stops with a
+// *JournalCorruptedError at the first entry that can not be decoded.
+func (in *Inspector) Journal(fromFileID uint32, fromSeq uint64, fn func(entry *JournalEntry) error) error {
+	for _, file := range listJournalFiles(in.path) {
+		var err error
+		if file.num == fromFileID {
+			err = in.readJournalFile(file.num, fromSeq, fn)
+		} else if file.num > fromFileID {
+			err = in.readJournalFile(file.num, 0, fn)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *Inspector) readJournalFile(fileID uint32, seq uint64, fn func(entry *JournalEntry) error) error {
+	file, err := os.Open(filepath.Join(in.path, fmt.Sprintf("wal.%d", fileID)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := uint64(info.Size())
+	if seq > size {
+		return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("seq exceeds file size %d", size)}
+	}
+
+	bufReader := bufio.NewReaderSize(file, readBufferLimitSize)
+	if _, err := bufReader.Discard(int(seq)); err != nil {
+		return err
+	}
+
+	header := make([]byte, journalHeaderSize)
+	for seq < size {
+		if _, err := io.ReadFull(bufReader, header); err != nil {
+			return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: "incomplete entry header"}
+		}
+		crc := binary.BigEndian.Uint32(header[0:4])      // 4 byte
+		length := binary.BigEndian.Uint32(header[4:8])   // 4 byte
+		msgType := binary.BigEndian.Uint16(header[8:10]) // 2 byte
+
+		if seq+journalHeaderSize+uint64(length) > size {
+			return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: "incomplete entry"}
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(bufReader, data); err != nil {
+			return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: err.Error()}
+		}
+		if _crc := crc32.Checksum(data, crc32c); crc != _crc {
+			return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("crc mismatch, crc:%d, _crc:%d", crc, _crc)}
+		}
+		entry, err := decodeJournalEntry(data, msgType)
+		if err != nil {
+			return &JournalCorruptedError{FileID: fileID, Seq: seq, Reason: err.Error()}
+		}
+		entry.FileID, entry.Seq = fileID, seq
+		if err := fn(entry); err != nil {
+			return err
+		}
+		seq += journalHeaderSize + uint64(length)
+	}
+	return nil
+}
+
+// Verify checks that every record of the wal can be decoded and that the
+// viewChange meta points into an existing journal file. It returns all the
+// problems found, an empty result means the wal is loadable.
+func (in *Inspector) Verify() []error {
+	var errs []error
+	if cs, err := in.ChainState(); err != nil {
+		errs = append(errs, err)
+	} else if cs != nil && !cs.ValidChainState() {
+		errs = append(errs, errors.New("invalid chainState"))
+	}
+	if _, err := in.ViewChangeQCs(); err != nil {
+		errs = append(errs, err)
+	}
+
+	vc, err := in.ViewChange()
+	if err != nil {
+		return append(errs, err)
+	}
+	var (
+		fromFileID uint32
+		fromSeq    uint64
+	)
+	if vc != nil {
+		fromFileID, fromSeq = vc.FileID, vc.Seq
+		if _, err := os.Stat(filepath.Join(in.path, fmt.Sprintf("wal.%d", vc.FileID))); err != nil {
+			errs = append(errs, fmt.Errorf("journal wal.%d of the viewChange meta is missing", vc.FileID))
+		}
+	}
+	if err := in.Journal(fromFileID, fromSeq, func(*JournalEntry) error { return nil }); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Truncate drops everything the wal recorded after the specified epoch and
+// view: the journaled messages of later views, together with any corrupted
+// tail of the journal, and the viewChangeQCs of later views. The viewChange
+// meta is moved back if it points past the truncated journal. The chainState
+// is left untouched, it only refers to blocks which are already committed.
+func (in *Inspector) Truncate(epoch, viewNumber uint64) error {
+	var (
+		cut      *JournalEntry
+		errFound = errors.New("found")
+	)
+	err := in.Journal(0, 0, func(entry *JournalEntry) error {
+		if isLaterView(entry.Epoch(), entry.ViewNumber(), epoch, viewNumber) {
+			cut = entry
+			return errFound
+		}
+		return nil
+	})
+	switch e := err.(type) {
+	case nil:
+	case *JournalCorruptedError:
+		cut = &JournalEntry{FileID: e.FileID, Seq: e.Seq}
+	default:
+		if err != errFound {
+			return err
+		}
+	}
+
+	if cut != nil {
+		for _, file := range listJournalFiles(in.path) {
+			path := filepath.Join(in.path, file.name)
+			if file.num == cut.FileID {
+				if err := os.Truncate(path, int64(cut.Seq)); err != nil {
+					return err
+				}
+			} else if file.num > cut.FileID {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	qcs, err := in.ViewChangeQCs()
+	if err != nil {
+		return err
+	}
+	for _, qc := range qcs {
+		if isLaterView(qc.Epoch, qc.ViewNumber, epoch, viewNumber) {
+			if err := in.metaDB.Delete(viewChangeQCKey(qc.Epoch, qc.BlockNumber, qc.ViewNumber)); err != nil {
+				return err
+			}
+		}
+	}
+
+	vc, err := in.ViewChange()
+	if err != nil {
+		return err
+	}
+	if vc != nil && cut != nil && (vc.FileID > cut.FileID || (vc.FileID == cut.FileID && vc.Seq > cut.Seq)) {
+		vc.FileID, vc.Seq = cut.FileID, cut.Seq
+		data, err := rlp.EncodeToBytes(vc)
+		if err != nil {
+			return err
+		}
+		return in.metaDB.Put(viewChangeKey, data, nil)
+	}
+	return nil
+}
+
+// decodeJournalEntry decodes a journal message, keeping its timestamp.
+func decodeJournalEntry(data []byte, msgType uint16) (*JournalEntry, error) {
+	switch msgType {
+	case protocols.ConfirmedViewChangeMsg, protocols.SendViewChangeMsg, protocols.SendPrepareBlockMsg,
+		protocols.SendPrepareVoteMsg, protocols.SendPreCommitMsg:
+	default:
+		return nil, fmt.Errorf("invalid msg type %d", msgType)
+	}
+	var raw struct {
+		Timestamp uint64
+		Data      rlp.RawValue
+	}
+	if err := rlp.DecodeBytes(data, &raw); err != nil {
+		return nil, err
+	}
+	msg, err := WALDecode(data, msgType)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalEntry{
+		Timestamp: raw.Timestamp,
+		MsgType:   msgType,
+		Msg:       msg,
+	}, nil
+}
+
+// parseViewChangeQCKey is the inverse of viewChangeQCKey.
+func parseViewChangeQCKey(key []byte) (uint64, uint64, uint64, error) {
+	split := len(viewChangeQCSplit)
+	if len(key) != len(viewChangeQCPrefix)+8+split+8+split+8 {
+		return 0, 0, 0, fmt.Errorf("invalid viewChangeQC key %x", key)
+	}
+	key = key[len(viewChangeQCPrefix):]
+	epoch := binary.BigEndian.Uint64(key[0:8])
+	blockNumber := binary.BigEndian.Uint64(key[8+split : 16+split])
+	viewNumber := binary.BigEndian.Uint64(key[16+2*split:])
+	return epoch, blockNumber, viewNumber, nil
+}
+
+// messageView returns the epoch and view number of a journaled message.
+func messageView(msg interface{}) (uint64, uint64) {
+	switch m := msg.(type) {
+	case *protocols.ConfirmedViewChange:
+		return m.Epoch, m.ViewNumber
+	case interface {
+		Epoch() uint64
+		ViewNumber() uint64
+	}:
+		return m.Epoch(), m.ViewNumber()
+	}
+	return 0, 0
+}
+
+func isLaterView(epoch, viewNumber, targetEpoch, targetViewNumber uint64) bool {
+	return epoch > targetEpoch || (epoch == targetEpoch && viewNumber > targetViewNumber)
+}
@@ -0,0 +1,133 @@
+package wal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/protocols"
+)
+
+// buildValidState returns a state whose QC certifies its block.
+func buildValidState() *protocols.State {
+	block := newBlock()
+	qc := buildQuorumCert()
+	qc.BlockHash = block.Hash()
+	return &protocols.State{Block: block, QuorumCert: qc}
+}
+
+func writeTestViews(t *testing.T, path string, views int) {
+	wal, err := NewWal(nil, path)
+	assert.Nil(t, err)
+	defer wal.Close()
+
+	assert.Nil(t, wal.UpdateViewChange(&ViewChangeMessage{Epoch: epoch, ViewNumber: 0}))
+	assert.Nil(t, wal.UpdateChainState(&protocols.ChainState{
+		Commit: buildValidState(),
+		Lock:   buildValidState(),
+		QC:     []*protocols.State{buildValidState()},
+	}))
+	for v := uint64(0); v < uint64(views); v++ {
+		vc := buildConfirmedViewChange()
+		vc.ViewNumber = v
+		assert.Nil(t, wal.WriteSync(vc))
+
+		pb := buildSendPrepareBlock()
+		pb.Prepare.ViewNumber = v
+		assert.Nil(t, wal.WriteSync(pb))
+
+		assert.Nil(t, wal.UpdateViewChangeQC(epoch, blockNumber, v, buildViewChangeQC()))
+	}
+}
+
+func countJournal(t *testing.T, in *Inspector) (int, uint64) {
+	var (
+		count   int
+		maxView uint64
+	)
+	assert.Nil(t, in.Journal(0, 0, func(entry *JournalEntry) error {
+		count++
+		if entry.ViewNumber() > maxView {
+			maxView = entry.ViewNumber()
+		}
+		return nil
+	}))
+	return count, maxView
+}
+
+func TestInspector(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	_, err := NewInspector(filepath.Join(tempDir, "missing"))
+	assert.Equal(t, errWalDirNotExist, err)
+
+	writeTestViews(t, tempDir, 3)
+
+	in, err := NewInspector(tempDir)
+	assert.Nil(t, err)
+	defer in.Close()
+
+	cs, err := in.ChainState()
+	assert.Nil(t, err)
+	assert.True(t, cs.ValidChainState())
+
+	vc, err := in.ViewChange()
+	assert.Nil(t, err)
+	assert.Equal(t, epoch, vc.Epoch)
+
+	qcs, err := in.ViewChangeQCs()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(qcs))
+	assert.Equal(t, blockNumber, qcs[2].BlockNumber)
+	assert.Equal(t, uint64(2), qcs[2].ViewNumber)
+
+	var msgs []*JournalEntry
+	assert.Nil(t, in.Journal(0, 0, func(entry *JournalEntry) error {
+		msgs = append(msgs, entry)
+		return nil
+	}))
+	assert.Equal(t, 6, len(msgs))
+	assert.IsType(t, &protocols.ConfirmedViewChange{}, msgs[0].Msg)
+	assert.IsType(t, &protocols.SendPrepareBlock{}, msgs[1].Msg)
+	assert.Equal(t, uint64(2), msgs[5].ViewNumber())
+	assert.NotZero(t, msgs[5].Timestamp)
+	assert.Empty(t, in.Verify())
+}
+
+func TestInspectorTruncate(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	writeTestViews(t, tempDir, 3)
+
+	// Append a torn entry to the journal.
+	files := listJournalFiles(tempDir)
+	f, err := os.OpenFile(filepath.Join(tempDir, files[len(files)-1].name), os.O_WRONLY|os.O_APPEND, 0755)
+	assert.Nil(t, err)
+	_, err = f.Write([]byte{0x01, 0x02, 0x03})
+	assert.Nil(t, err)
+	f.Close()
+
+	in, err := NewInspector(tempDir)
+	assert.Nil(t, err)
+	defer in.Close()
+
+	errs := in.Verify()
+	assert.Equal(t, 1, len(errs))
+	assert.IsType(t, &JournalCorruptedError{}, errs[0])
+
+	assert.Nil(t, in.Truncate(epoch, 1))
+	assert.Empty(t, in.Verify())
+
+	count, maxView := countJournal(t, in)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, uint64(1), maxView)
+
+	qcs, err := in.ViewChangeQCs()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(qcs))
+}
@@ -504,7 +504,8 @@ func (pbft *Pbft) recoveryMsg(msg interface{}) error {
 				pbft.state.SetExecuting(m.Prepare.BlockIndex, true)
 				pbft.state.SetMaxExecutedBlockNumber(m.Prepare.BlockNum())
 			}
-			pbft.signMsgByBls(m.Prepare)
+			// The journaled prepareBlock is already signed, signing it again
+			// would be refused by the double-sign protection.
 			pbft.state.AddPrepareBlock(m.Prepare)
 		}
 
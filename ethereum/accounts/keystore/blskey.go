@@ -0,0 +1,82 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/pborman/uuid"
+)
+
+// encryptedBlsKeyJSONV3 is the on-disk format of an encrypted BLS consensus
+// key, it mirrors encryptedKeyJSONV3 with the public key in place of the address.
+type encryptedBlsKeyJSONV3 struct {
+	PublicKey string     `json:"publicKey"`
+	Crypto    cryptoJSON `json:"crypto"`
+	Id        string     `json:"id"`
+	Version   int        `json:"version"`
+}
+
+// EncryptBlsKey encrypts a BLS secret key using the specified scrypt parameters
+// into a json blob that can be decrypted later on.
+func EncryptBlsKey(key *bls.SecretKey, auth string, scryptN, scryptP int) ([]byte, error) {
+	cryptoStruct, err := encryptDataV3(key.GetLittleEndian(), []byte(auth), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKeyJSON := encryptedBlsKeyJSONV3{
+		hex.EncodeToString(key.GetPublicKey().Serialize()),
+		cryptoStruct,
+		uuid.NewRandom().String(),
+		version,
+	}
+	return json.Marshal(encryptedKeyJSON)
+}
+
+// DecryptBlsKey decrypts a BLS secret key from a json blob.
+func DecryptBlsKey(keyjson []byte, auth string) (*bls.SecretKey, error) {
+	k := new(encryptedBlsKeyJSONV3)
+	if err := json.Unmarshal(keyjson, k); err != nil {
+		return nil, err
+	}
+	if k.Version != version {
+		return nil, fmt.Errorf("Version not supported: %v", k.Version)
+	}
+	keyBytes, err := decryptDataV3(k.Crypto, auth)
+	if err != nil {
+		return nil, err
+	}
+	var key bls.SecretKey
+	if err := key.SetLittleEndian(keyBytes); err != nil {
+		return nil, err
+	}
+	// Make sure the key matches the advertised public key.
+	if pub := hex.EncodeToString(key.GetPublicKey().Serialize()); pub != k.PublicKey {
+		return nil, fmt.Errorf("key content mismatch: have public key %s, want %s", pub, k.PublicKey)
+	}
+	return &key, nil
+}
+
+// IsEncryptedBlsKey reports whether the content of a BLS key file is in the
+// encrypted json format rather than the legacy plaintext hex format.
+func IsEncryptedBlsKey(content []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(content), []byte("{"))
+}
+
+// LoadBlsKey loads a BLS secret key from file, decrypting it with auth. Files
+// in the legacy plaintext format are still accepted, with a warning.
+func LoadBlsKey(file, auth string) (*bls.SecretKey, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if IsEncryptedBlsKey(content) {
+		return DecryptBlsKey(content, auth)
+	}
+	log.Warn("Loading unencrypted BLS key, encrypt it with 'keytool generatebls --privatekey'", "file", file)
+	return bls.LoadBLS(file)
+}
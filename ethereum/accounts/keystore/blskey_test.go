@@ -0,0 +1,82 @@
+package keystore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+)
+
+// Tests that a bls key can be encrypted and decrypted in multiple rounds.
+func TestBlsKeyEncryptDecrypt(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	key := bls.GenerateKey()
+	password := ""
+	keyjson, err := EncryptBlsKey(key, password, veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		// Try a bad password first
+		if _, err := DecryptBlsKey(keyjson, password+"bad"); err != ErrDecrypt {
+			t.Errorf("test %d: json key decrypted with bad password: %v", i, err)
+		}
+		// Decrypt with the correct password
+		decrypted, err := DecryptBlsKey(keyjson, password)
+		if err != nil {
+			t.Fatalf("test %d: json key failed to decrypt: %v", i, err)
+		}
+		if !decrypted.GetPublicKey().IsEqual(key.GetPublicKey()) {
+			t.Errorf("test %d: key mismatch", i)
+		}
+		// Recrypt with a new password and start over
+		password += "new data appended"
+		if keyjson, err = EncryptBlsKey(decrypted, password, veryLightScryptN, veryLightScryptP); err != nil {
+			t.Errorf("test %d: failed to recrypt key %v", i, err)
+		}
+	}
+}
+
+// Tests that both the encrypted and the legacy plaintext bls key files load.
+func TestLoadBlsKey(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	dir, err := ioutil.TempDir("", "blskey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := bls.GenerateKey()
+	legacy := filepath.Join(dir, "blskey")
+	if err := bls.SaveBLS(legacy, key); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBlsKey(legacy, "")
+	if err != nil {
+		t.Fatalf("failed to load plaintext key: %v", err)
+	}
+	if !loaded.GetPublicKey().IsEqual(key.GetPublicKey()) {
+		t.Errorf("plaintext key mismatch")
+	}
+
+	keyjson, err := EncryptBlsKey(key, "foo", veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := filepath.Join(dir, "blskey.json")
+	if err := ioutil.WriteFile(encrypted, keyjson, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBlsKey(encrypted, "bar"); err != ErrDecrypt {
+		t.Errorf("encrypted key loaded with bad password: %v", err)
+	}
+	loaded, err = LoadBlsKey(encrypted, "foo")
+	if err != nil {
+		t.Fatalf("failed to load encrypted key: %v", err)
+	}
+	if !loaded.GetPublicKey().IsEqual(key.GetPublicKey()) {
+		t.Errorf("encrypted key mismatch")
+	}
+}
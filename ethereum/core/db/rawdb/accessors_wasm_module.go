@@ -0,0 +1,48 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+)
+
+// ReadWasmModule retrieves the compiled WASM module of the code hash compiled
+// by the compiler version.
+func ReadWasmModule(db ethdb.KeyValueReader, version uint32, hash common.Hash) []byte {
+	data, _ := db.Get(wasmModuleKey(version, hash))
+	return data
+}
+
+// WriteWasmModule stores the compiled WASM module of the code hash compiled
+// by the compiler version.
+func WriteWasmModule(db ethdb.KeyValueWriter, version uint32, hash common.Hash, module []byte) {
+	if err := db.Put(wasmModuleKey(version, hash), module); err != nil {
+		log.Crit("Failed to store compiled wasm module", "err", err)
+	}
+}
+
+// DeleteWasmModule removes the compiled WASM module of the code hash compiled
+// by the compiler version.
+func DeleteWasmModule(db ethdb.KeyValueWriter, version uint32, hash common.Hash) {
+	if err := db.Delete(wasmModuleKey(version, hash)); err != nil {
+		log.Crit("Failed to delete compiled wasm module", "err", err)
+	}
+}
+
+// IterateWasmModules calls fn with the compiler version, the code hash and the
+// size of every compiled WASM module stored, in the order of the keys.
+func IterateWasmModules(db ethdb.Iteratee, fn func(version uint32, hash common.Hash, size int)) {
+	it := db.NewIteratorWithPrefix(wasmModulePrefix)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(wasmModulePrefix)+4+common.HashLength {
+			continue
+		}
+		version := binary.BigEndian.Uint32(key[len(wasmModulePrefix):])
+		fn(version, common.BytesToHash(key[len(key)-common.HashLength:]), len(it.Value()))
+	}
+}
@@ -42,6 +42,7 @@ var (
 	preimagePrefix      = []byte("secure-key-")        // preimagePrefix + hash -> preimage
 	configPrefix        = []byte("ethereum-config-")   // config prefix for the db
 	economicModelPrefix = []byte("economicModel-key-") // economicModel prefix for the db
+	wasmModulePrefix    = []byte("wasm-module-")       // wasmModulePrefix + compiler version (uint32 big endian) + code hash -> compiled module
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
@@ -147,3 +148,10 @@ func configKey(hash common.Hash) []byte {
 func economicModelKey(hash common.Hash) []byte {
 	return append(economicModelPrefix, hash.Bytes()...)
 }
+
+// wasmModuleKey = wasmModulePrefix + compiler version (uint32 big endian) + code hash
+func wasmModuleKey(version uint32, hash common.Hash) []byte {
+	key := append(append(wasmModulePrefix, make([]byte, 4)...), hash.Bytes()...)
+	binary.BigEndian.PutUint32(key[len(wasmModulePrefix):], version)
+	return key
+}
@@ -0,0 +1,344 @@
+package snapshotdb
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+const (
+	//DBArchivePath path of archivedb
+	DBArchivePath = "archive"
+
+	// the key of the lowest block number that kept in archivedb
+	archiveFromKey = "snapshotdbArchiveFrom"
+
+	// the prefix of the index of the keys kept in archivedb, the index is `prefix` + `key`
+	archiveIndexPrefix = "snapshotdbArchiveIndex:"
+)
+
+var (
+	archiveMode bool
+
+	//ErrNotArchived when the block is lower than the archivedb kept
+	ErrNotArchived = errors.New("snapshotDB: the block state is not archived")
+)
+
+// SetDBArchive enables the archive mode, every version of the keys
+// written to baseDB is kept, so the snapshot of any committed block can be read.
+func SetDBArchive(archive bool) {
+	archiveMode = archive
+	logger.Info("set archive", "archive", archive)
+}
+
+func getArchiveDBPath(dbpath string) string {
+	return path.Join(dbpath, DBArchivePath)
+}
+
+// The archive key is `key` + `blockNumber` + `len(key)`,
+// so the versions of a key are sorted by blockNumber
+// and the key can be decoded although the keys have different length.
+func archiveKey(key []byte, number uint64) []byte {
+	k := make([]byte, 0, len(key)+12)
+	k = append(k, key...)
+	k = append(k, common.Uint64ToBytes(number)...)
+	return append(k, common.Uint32ToBytes(uint32(len(key)))...)
+}
+
+func archiveIndexKey(key []byte) []byte {
+	return append([]byte(archiveIndexPrefix), key...)
+}
+
+func decodeArchiveKey(k []byte) ([]byte, uint64, bool) {
+	if len(k) < 12 {
+		return nil, 0, false
+	}
+	keyLen := int(common.BytesToUint32(k[len(k)-4:]))
+	if keyLen+12 != len(k) {
+		return nil, 0, false
+	}
+	return k[:keyLen], common.BytesToUint64(k[keyLen : keyLen+8]), true
+}
+
+func openArchiveDB(snapshotDBPath string, cache int, handles int) (*leveldb.DB, error) {
+	archivePath := getArchiveDBPath(snapshotDBPath)
+	archiveDB, err := leveldb.OpenFile(archivePath, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 4 * opt.MiB,
+		WriteBuffer:            cache / 8 * opt.MiB,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[SnapshotDB.archive]open archiveDB fail:%v", err)
+	}
+	return archiveDB, nil
+}
+
+func (s *snapshotDB) archiveFrom() (uint64, error) {
+	v, err := s.archiveDB.Get([]byte(archiveFromKey), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, ErrNotArchived
+	} else if err != nil {
+		return 0, err
+	}
+	return common.BytesToUint64(v), nil
+}
+
+// resetArchive copies the whole baseDB as the version of base block,
+// it is called when the archive mode is enabled first time or the baseDB is rewritten.
+func (s *snapshotDB) resetArchive(base uint64) error {
+	logger.Info("begin reset archive", "base", base)
+	batch := new(leveldb.Batch)
+	itr := s.baseDB.NewIterator(nil, nil)
+	for itr.Next() {
+		batch.Put(archiveKey(itr.Key(), base), common.CopyBytes(itr.Value()))
+		batch.Put(archiveIndexKey(itr.Key()), nil)
+		if batch.Len() >= kvLIMIT {
+			if err := s.archiveDB.Write(batch, nil); err != nil {
+				itr.Release()
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	batch.Put([]byte(archiveFromKey), common.Uint64ToBytes(base))
+	return s.archiveDB.Write(batch, nil)
+}
+
+// writeToArchive keeps the kvs of the committed blocks which will be written to baseDB,
+// it must be called before the baseDB is written, so the archivedb always covers the baseDB.
+func (s *snapshotDB) writeToArchive(commitNum int) error {
+	batch := new(leveldb.Batch)
+	for i := 0; i < commitNum; i++ {
+		number := s.committed[i].Number.Uint64()
+		itr := s.committed[i].data.NewIterator(nil)
+		for itr.Next() {
+			batch.Put(archiveKey(itr.Key(), number), common.CopyBytes(itr.Value()))
+			batch.Put(archiveIndexKey(itr.Key()), nil)
+		}
+		itr.Release()
+	}
+	if err := s.archiveDB.Write(batch, nil); err != nil {
+		logger.Error("write to archiveDB fail", "err", err)
+		return errors.New("[SnapshotDB]write to archiveDB fail:" + err.Error())
+	}
+	return nil
+}
+
+type history struct {
+	number uint64
+	refs   int
+}
+
+// OpenHistory opens the history of the hash on the db instance,
+// it does nothing if the db instance is not opened.
+func OpenHistory(hash common.Hash) (release func()) {
+	instance.Lock()
+	db := dbInstance
+	instance.Unlock()
+	if db == nil || db.closed {
+		return func() {}
+	}
+	return db.OpenHistory(hash)
+}
+
+// OpenHistory makes Get and Ranking on the hash read the snapshot of the block from the archive,
+// until release is called. It does nothing if the archive mode is disabled,
+// or the hash is not a canonical block lower than the highest committed block.
+func (s *snapshotDB) OpenHistory(hash common.Hash) (release func()) {
+	number, ok := s.historyNumber(hash)
+	if !ok {
+		return func() {}
+	}
+	s.historyLock.Lock()
+	defer s.historyLock.Unlock()
+	if s.histories == nil {
+		s.histories = make(map[common.Hash]*history)
+	}
+	h, ok := s.histories[hash]
+	if !ok {
+		h = &history{number: number}
+		s.histories[hash] = h
+	}
+	h.refs++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.historyLock.Lock()
+			defer s.historyLock.Unlock()
+			if h.refs--; h.refs == 0 {
+				delete(s.histories, hash)
+			}
+		})
+	}
+}
+
+func (s *snapshotDB) openedHistory(hash common.Hash) (uint64, bool) {
+	s.historyLock.RLock()
+	defer s.historyLock.RUnlock()
+	if h, ok := s.histories[hash]; ok {
+		return h.number, true
+	}
+	return 0, false
+}
+
+// historyNumber returns the number of the block if the snapshot of the hash
+// must be read from the archive, that is a canonical block lower than the highest committed block.
+func (s *snapshotDB) historyNumber(hash common.Hash) (uint64, bool) {
+	if s.archiveDB == nil || hash == common.ZeroHash || blockchain == nil {
+		return 0, false
+	}
+	s.unCommit.RLock()
+	_, ok := s.unCommit.blocks[hash]
+	s.unCommit.RUnlock()
+	if ok {
+		return 0, false
+	}
+	header := blockchain.GetHeaderByHash(hash)
+	if header == nil {
+		return 0, false
+	}
+	number := header.Number.Uint64()
+	if number >= s.current.GetHighest(false).Num.Uint64() {
+		return 0, false
+	}
+	if canonical := blockchain.GetHeaderByNumber(number); canonical == nil || canonical.Hash() != hash {
+		return 0, false
+	}
+	return number, true
+}
+
+// getHistory get the value of the key in the snapshot of the block number,
+// committed blocks which not higher than the number > archiveDB
+func (s *snapshotDB) getHistory(key []byte, number uint64) ([]byte, error) {
+	s.commitLock.RLock()
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		if s.committed[i].Number.Uint64() > number {
+			continue
+		}
+		v, err := s.committed[i].data.Get(key)
+		if err == memdb.ErrNotFound {
+			continue
+		}
+		s.commitLock.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		if len(v) == 0 {
+			return nil, ErrNotFound
+		}
+		return v, nil
+	}
+	s.commitLock.RUnlock()
+	return s.getFromArchive(key, number)
+}
+
+func (s *snapshotDB) getFromArchive(key []byte, number uint64) ([]byte, error) {
+	from, err := s.archiveFrom()
+	if err != nil {
+		return nil, err
+	}
+	if number < from {
+		return nil, ErrNotArchived
+	}
+
+	itr := s.archiveDB.NewIterator(util.BytesPrefix(key), nil)
+	defer itr.Release()
+	v, ok := seekArchive(itr, key, number)
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	if !ok || len(v) == 0 {
+		return nil, ErrNotFound
+	}
+	return common.CopyBytes(v), nil
+}
+
+// seekArchive finds the latest version of the key not higher than the number,
+// the versions of the key lower than number+1 are before the seek position,
+// the keys prefixed with the key may be interleaved, skip them
+func seekArchive(itr iterator.Iterator, key []byte, number uint64) ([]byte, bool) {
+	var ok bool
+	if itr.Seek(append(common.CopyBytes(key), common.Uint64ToBytes(number+1)...)) {
+		ok = itr.Prev()
+	} else {
+		ok = itr.Last()
+	}
+	for ; ok && bytes.HasPrefix(itr.Key(), key); ok = itr.Prev() {
+		if k, _, valid := decodeArchiveKey(itr.Key()); valid && bytes.Equal(k, key) {
+			return itr.Value(), true
+		}
+	}
+	return nil, false
+}
+
+// rankingHistory same as Ranking, but on the snapshot of the block number
+func (s *snapshotDB) rankingHistory(number uint64, key []byte, rangeNumber int) iterator.Iterator {
+	from, err := s.archiveFrom()
+	if err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+	if number < from {
+		return iterator.NewEmptyIterator(ErrNotArchived)
+	}
+
+	prefix := util.BytesPrefix(key)
+	var itrs []iterator.Iterator
+	s.commitLock.RLock()
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		if s.committed[i].Number.Uint64() <= number {
+			itrs = append(itrs, s.committed[i].data.NewIterator(prefix))
+		}
+	}
+	s.commitLock.RUnlock()
+
+	// seek the latest version not higher than the number of every key in the index
+	archived := memdb.New(DefaultComparer, 0)
+	indexItr := s.archiveDB.NewIterator(util.BytesPrefix(archiveIndexKey(key)), nil)
+	defer indexItr.Release()
+	archiveItr := s.archiveDB.NewIterator(prefix, nil)
+	defer archiveItr.Release()
+	for indexItr.Next() {
+		k := indexItr.Key()[len(archiveIndexPrefix):]
+		if v, ok := seekArchive(archiveItr, k, number); ok && len(v) > 0 {
+			if err := archived.Put(k, v); err != nil {
+				return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+			}
+		}
+	}
+	if err := indexItr.Error(); err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+	if err := archiveItr.Error(); err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+
+	rankingHeap := newRankingHeap(rangeNumber)
+	for i := 0; i < len(itrs); i++ {
+		rankingHeap.itr2Heap(itrs[i], false, false)
+	}
+	rankingHeap.itr2Heap(archived.NewIterator(nil), true, false)
+	mdb := memdb.New(DefaultComparer, rangeNumber)
+	for rankingHeap.heap.Len() > 0 {
+		kv := heap.Pop(&rankingHeap.heap).(kv)
+		if err := mdb.Put(kv.key, kv.value); err != nil {
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+	}
+	return mdb.NewIterator(nil)
+}
@@ -0,0 +1,137 @@
+package snapshotdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+func TestSnapshotDB_Archive(t *testing.T) {
+	SetDBArchive(true)
+	defer SetDBArchive(false)
+
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	var (
+		key       = []byte("archive")
+		longerKey = []byte("archiveLonger")
+		hashes    []common.Hash
+	)
+
+	writeBlock := func(compaction bool, f func(hash common.Hash) error) {
+		ch.addBlock()
+		head := ch.CurrentHeader()
+		hash := head.Hash()
+		if err := ch.db.NewBlock(head.Number, head.ParentHash, hash); err != nil {
+			t.Fatal(err)
+		}
+		if err := f(hash); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Commit(hash); err != nil {
+			t.Fatal(err)
+		}
+		if compaction {
+			ch.db.walSync.Wait()
+			if err := ch.db.Compaction(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		hashes = append(hashes, hash)
+	}
+
+	// block 1: put v1
+	writeBlock(true, func(hash common.Hash) error {
+		return ch.db.Put(hash, key, []byte("v1"))
+	})
+	// block 2: put v2, and a key prefixed with the key
+	writeBlock(true, func(hash common.Hash) error {
+		if err := ch.db.Put(hash, key, []byte("v2")); err != nil {
+			return err
+		}
+		return ch.db.Put(hash, longerKey, []byte("l2"))
+	})
+	// block 3: del
+	writeBlock(true, func(hash common.Hash) error {
+		return ch.db.Del(hash, key)
+	})
+	// block 4: put v4, only committed
+	writeBlock(false, func(hash common.Hash) error {
+		return ch.db.Put(hash, key, []byte("v4"))
+	})
+	// block 5: the highest
+	writeBlock(false, func(hash common.Hash) error {
+		return ch.db.Put(hash, longerKey, []byte("l5"))
+	})
+
+	expects := []struct {
+		value  []byte
+		longer []byte
+	}{
+		{[]byte("v1"), nil},
+		{[]byte("v2"), []byte("l2")},
+		{nil, []byte("l2")},
+		{[]byte("v4"), []byte("l2")},
+		{[]byte("v4"), []byte("l5")},
+	}
+
+	// without OpenHistory, Get on an old block reads the current state as before
+	current, err := ch.db.Get(common.ZeroHash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ch.db.Get(hashes[0], key); err != nil || !bytes.Equal(v, current) {
+		t.Errorf("block 1 without history: expect %s, got %s, %v", current, v, err)
+	}
+
+	for i, expect := range expects {
+		release := ch.db.OpenHistory(hashes[i])
+		for _, item := range []struct {
+			key   []byte
+			value []byte
+		}{{key, expect.value}, {longerKey, expect.longer}} {
+			v, err := ch.db.Get(hashes[i], item.key)
+			if item.value == nil {
+				if err != ErrNotFound {
+					t.Errorf("block %d key %s: expect not found, got %s, %v", i+1, item.key, v, err)
+				}
+				continue
+			}
+			if err != nil || !bytes.Equal(v, item.value) {
+				t.Errorf("block %d key %s: expect %s, got %s, %v", i+1, item.key, item.value, v, err)
+			}
+		}
+		release()
+	}
+
+	// ranking on the snapshot of block 2
+	release := ch.db.OpenHistory(hashes[1])
+	itr := ch.db.Ranking(hashes[1], key, 0)
+	var values [][]byte
+	for itr.Next() {
+		values = append(values, common.CopyBytes(itr.Value()))
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || !bytes.Equal(values[0], []byte("v2")) || !bytes.Equal(values[1], []byte("l2")) {
+		t.Errorf("ranking on block 2: got %s", values)
+	}
+	release()
+	if _, ok := ch.db.openedHistory(hashes[1]); ok {
+		t.Error("the history of block 2 is not released")
+	}
+
+	// the blocks before the archive are not answered
+	if err := ch.db.archiveDB.Put([]byte(archiveFromKey), common.Uint64ToBytes(2), nil); err != nil {
+		t.Fatal(err)
+	}
+	release = ch.db.OpenHistory(hashes[0])
+	defer release()
+	if _, err := ch.db.Get(hashes[0], key); err != ErrNotArchived {
+		t.Errorf("expect ErrNotArchived, got %v", err)
+	}
+}
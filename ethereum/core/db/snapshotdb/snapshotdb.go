@@ -85,6 +85,10 @@ type DB interface {
 	//ues to Revert failed tx
 	RevertToSnapshot(hash common.Hash, revid int)
 	Snapshot(hash common.Hash) int
+
+	// OpenHistory makes Get and Ranking on the hash read the snapshot of the block in the archive mode,
+	// until the returned release is called
+	OpenHistory(hash common.Hash) (release func())
 }
 
 type BaseDB interface {
@@ -127,6 +131,13 @@ type snapshotDB struct {
 
 	baseDB *leveldb.DB
 
+	// only opened in the archive mode
+	archiveDB *leveldb.DB
+
+	// the blocks opened by OpenHistory, Get and Ranking on them read the archived snapshot
+	histories   map[common.Hash]*history
+	historyLock sync.RWMutex
+
 	unCommit *unCommitBlocks
 
 	committed  []*blockData
@@ -228,6 +239,13 @@ func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, erro
 	if baseOnly {
 		return db, nil
 	}
+	if archiveMode {
+		archiveDB, err := openArchiveDB(path, cache, handles)
+		if err != nil {
+			return nil, err
+		}
+		db.archiveDB = archiveDB
+	}
 
 	_, getCurrentError := baseDB.Get([]byte(CurrentSet), nil)
 	if getCurrentError == nil {
@@ -248,6 +266,15 @@ func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, erro
 	} else {
 		return nil, getCurrentError
 	}
+	if db.archiveDB != nil {
+		if _, err := db.archiveFrom(); err == ErrNotArchived {
+			if err := db.resetArchive(db.current.GetBase(false).Num.Uint64()); err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+	}
 	return db, nil
 }
 
@@ -268,6 +295,7 @@ func copyDB(from, to *snapshotDB) {
 	to.path = from.path
 	to.current = from.current
 	to.baseDB = from.baseDB
+	to.archiveDB = from.archiveDB
 	to.unCommit = from.unCommit
 	to.committed = from.committed
 	to.corn = from.corn
@@ -331,6 +359,10 @@ func (s *snapshotDB) SetCurrent(highestHash common.Hash, base, height big.Int) e
 	}
 	s.current = current
 	logger.Debug("SetCurrent", "base", s.current.base, "height", s.current.highest)
+	// the baseDB is rewritten by fast sync, the archive starts from the new base
+	if s.archiveDB != nil {
+		return s.resetArchive(base.Uint64())
+	}
 	return nil
 }
 
@@ -410,6 +442,11 @@ func (s *snapshotDB) Compaction() error {
 	if commitNum == 0 {
 		return nil
 	}
+	if s.archiveDB != nil {
+		if err := s.writeToArchive(commitNum); err != nil {
+			return err
+		}
+	}
 	if err := s.writeToBasedb(commitNum); err != nil {
 		return err
 	}
@@ -612,7 +649,11 @@ func (s *snapshotDB) getFromCommit(key []byte) ([]byte, error) {
 // Get get key,val from  snapshotDB
 // if hash is nil, unRecognizedBlockData > RecognizedBlockData > CommittedBlockData > baseDB
 // if hash is not nil,it will find from the chain, RecognizedBlockData > CommittedBlockData > baseDB
+// if hash is opened by OpenHistory, it will find from the snapshot of the block
 func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
+	if number, ok := s.openedHistory(hash); ok {
+		return s.getHistory(key, number)
+	}
 	v, err := s.getFromUnCommit(hash, key)
 	if err != nil && err != ErrNotFound {
 		return nil, err
@@ -802,6 +843,9 @@ func (s *snapshotDB) Clear() error {
 // The iterator must be released after use, by calling Release method.t
 // Also read Iterator documentation of the leveldb/iterator package.
 func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iterator.Iterator {
+	if number, ok := s.openedHistory(hash); ok {
+		return s.rankingHistory(number, key, rangeNumber)
+	}
 	prefix := util.BytesPrefix(key)
 	var itrs []iterator.Iterator
 	var parentHash common.Hash
@@ -865,6 +909,11 @@ func (s *snapshotDB) Close() error {
 			return fmt.Errorf("[snapshotdb]close base db fail:%v", err)
 		}
 	}
+	if s.archiveDB != nil {
+		if err := s.archiveDB.Close(); err != nil {
+			return fmt.Errorf("[snapshotdb]close archive db fail:%v", err)
+		}
+	}
 
 	s.current = nil
 	s.unCommit = nil
@@ -29,6 +29,10 @@ var (
 	EmptyRootHash = DeriveSha(Transactions{})
 	// Extra field in the block header, maximum length
 	ExtraMaxSize = 97
+	// Length of the next validators commitment appended to the extra field of epoch switch blocks
+	ExtraValidatorsHashSize = common.HashLength
+	// Maximum length of the quorum certificate carried in the extra field for the vote participation
+	ExtraVoteQuorumCertMaxSize = 512
 )
 
 // BlockNonce is an 81-byte vrf proof containing random numbers
@@ -213,9 +217,58 @@ func (h *Header) ExtraData() []byte {
 	return h.Extra[:32]
 }
 
+// ValidatorsHash returns the commitment to the next validators carried by
+// an epoch switch block, the boolean is false if the header has none.
+func (h *Header) ValidatorsHash() (common.Hash, bool) {
+	_, hash, ok := h.splitExtra()
+	if !ok || hash == nil {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(hash), true
+}
+
+// VoteQuorumCert returns the RLP encoded quorum certificate of the committed
+// head carried for the vote participation, the boolean is false if the header has none.
+func (h *Header) VoteQuorumCert() ([]byte, bool) {
+	qc, _, ok := h.splitExtra()
+	if !ok || qc == nil {
+		return nil, false
+	}
+	return qc, true
+}
+
+// splitExtra splits the fields following the signature of the extra,
+// Extra[97:] is the optional quorum certificate followed by the optional
+// validators hash. The boolean is false if the fields are malformed.
+func (h *Header) splitExtra() (qc []byte, hash []byte, ok bool) {
+	if len(h.Extra) <= ExtraMaxSize {
+		return nil, nil, true
+	}
+	rest := h.Extra[ExtraMaxSize:]
+	if len(rest) == ExtraValidatorsHashSize {
+		return nil, rest, true
+	}
+	kind, _, tail, err := rlp.Split(rest)
+	if err != nil || kind != rlp.List {
+		return nil, nil, false
+	}
+	qc = rest[:len(rest)-len(tail)]
+	if len(qc) > ExtraVoteQuorumCertMaxSize {
+		return nil, nil, false
+	}
+	switch len(tail) {
+	case 0:
+		return qc, nil, true
+	case ExtraValidatorsHashSize:
+		return qc, tail, true
+	}
+	return nil, nil, false
+}
+
 // Check whether the Extra field exceeds the limit size
 func (h *Header) IsInvalid() bool {
-	return len(h.Extra) > ExtraMaxSize
+	_, _, ok := h.splitExtra()
+	return !ok
 }
 
 // hasherPool holds Keccak hashers.
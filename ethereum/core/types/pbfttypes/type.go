@@ -80,6 +80,63 @@ type RemoveValidatorEvent struct {
 
 type UpdateValidatorEvent struct{}
 
+// Reasons of a view change.
+const (
+	ViewChangeReasonTimeout        = "timeout"        // the validators agreed on a viewChangeQC
+	ViewChangeReasonEpochSwitch    = "epochSwitch"    // the switch point of the validators was reached
+	ViewChangeReasonBlocksProduced = "blocksProduced" // the view produced enough blocks or was synchronized
+)
+
+// Phases of a quorum certificate.
+const (
+	QCPhasePrepare   = "prepare"
+	QCPhasePreCommit = "precommit"
+)
+
+// ConsensusView identifies the view and the block a consensus event refers to.
+type ConsensusView struct {
+	Epoch       uint64 `json:"epoch"`
+	ViewNumber  uint64 `json:"viewNumber"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+// ViewChangeEvent is posted when the consensus engine enters a new view.
+type ViewChangeEvent struct {
+	ConsensusView
+	PrevEpoch      uint64 `json:"prevEpoch"`
+	PrevViewNumber uint64 `json:"prevViewNumber"`
+	Reason         string `json:"reason"`
+	Timeout        uint64 `json:"timeout"` // milliseconds until the new view times out
+}
+
+// QCEvent is posted when a prepare or precommit quorum certificate is formed
+// locally or received from the network.
+type QCEvent struct {
+	ConsensusView
+	Phase     string      `json:"phase"`
+	BlockHash common.Hash `json:"blockHash"`
+}
+
+// ProposerChangeEvent is posted when the proposer of the current view changes.
+type ProposerChangeEvent struct {
+	ConsensusView
+	Index  uint32          `json:"index"`
+	NodeID discover.NodeID `json:"nodeID"`
+}
+
+// BlockCommitEvent is posted when a block is committed by the consensus.
+type BlockCommitEvent struct {
+	ConsensusView
+	BlockHash common.Hash `json:"blockHash"`
+}
+
+// ValidatorSwitchEvent is posted when the validators of the next epoch are loaded.
+type ValidatorSwitchEvent struct {
+	ConsensusView
+	ValidBlockNumber uint64            `json:"validBlockNumber"` // first block produced by the new validators
+	Validators       []discover.NodeID `json:"validators"`
+}
+
 type ValidateNode struct {
 	Index     uint32             `json:"index"`
 	Address   common.NodeAddress `json:"address"`
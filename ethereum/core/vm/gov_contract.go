@@ -12,6 +12,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/restricting"
 )
 
 const (
@@ -21,6 +22,9 @@ const (
 	Vote                  = uint16(2003)
 	Declare               = uint16(2004)
 	SubmitCancel          = uint16(2005)
+	SubmitParamBatch      = uint16(2006)
+	SubmitTreasury        = uint16(2007)
+	VoteByDelegator       = uint16(2008)
 	GetProposal           = uint16(2100)
 	GetResult             = uint16(2101)
 	ListProposal          = uint16(2102)
@@ -51,18 +55,22 @@ func (gc *GovContract) Run(input []byte) ([]byte, error) {
 	if checkInputEmpty(input) {
 		return nil, nil
 	}
-	return execPhoenixchainContract(input, gc.FnSigns())
+	return execPhoenixchainContract(input, activeFnSigns(gc.Evm.StateDB, gc.FnSigns(),
+		SubmitParamBatch, SubmitTreasury, VoteByDelegator))
 }
 
 func (gc *GovContract) FnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
-		SubmitText:    gc.submitText,
-		SubmitVersion: gc.submitVersion,
-		Vote:          gc.vote,
-		Declare:       gc.declareVersion,
-		SubmitCancel:  gc.submitCancel,
-		SubmitParam:   gc.submitParam,
+		SubmitText:       gc.submitText,
+		SubmitVersion:    gc.submitVersion,
+		Vote:             gc.vote,
+		Declare:          gc.declareVersion,
+		SubmitCancel:     gc.submitCancel,
+		SubmitParam:      gc.submitParam,
+		SubmitParamBatch: gc.submitParamBatch,
+		SubmitTreasury:   gc.submitTreasury,
+		VoteByDelegator:  gc.voteByDelegator,
 
 		// Get
 		GetProposal:           gc.getProposal,
@@ -89,7 +97,7 @@ func (gc *GovContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
 		if gasPrice.Cmp(configs.SubmitCancelProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
 		}
-	case SubmitParam:
+	case SubmitParam, SubmitParamBatch, SubmitTreasury:
 		if gasPrice.Cmp(configs.SubmitParamProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
 		}
@@ -258,6 +266,86 @@ func (gc *GovContract) submitParam(verifier discover.NodeID, pipID string, modul
 	return gc.nonCallHandler("submitParam", SubmitParam, err)
 }
 
+func (gc *GovContract) submitParamBatch(verifier discover.NodeID, pipID string, changes []gov.ParamChange) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call submitParamBatch of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"PIPID", pipID,
+		"verifierID", verifier.TerminalString(),
+		"changes", changes)
+
+	if !gc.Contract.UseGas(configs.SubmitParamProposalGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if gc.Evm.GasPrice.Cmp(configs.SubmitParamProposalGasPrice) < 0 {
+		return nil, ErrUnderPrice
+	}
+
+	p := &gov.ParamBatchProposal{
+		PIPID:        pipID,
+		ProposalType: gov.ParamBatch,
+		SubmitBlock:  blockNumber,
+		ProposalID:   txHash,
+		Proposer:     verifier,
+		Changes:      changes,
+	}
+	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB, gc.Evm.chainConfig.ChainID)
+	return gc.nonCallHandler("submitParamBatch", SubmitParamBatch, err)
+}
+
+func (gc *GovContract) submitTreasury(verifier discover.NodeID, pipID string, recipient common.Address, amount *big.Int, plans []restricting.RestrictingPlan) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call submitTreasury of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"PIPID", pipID,
+		"verifierID", verifier.TerminalString(),
+		"recipient", recipient,
+		"amount", amount,
+		"plans", plans)
+
+	if !gc.Contract.UseGas(configs.SubmitParamProposalGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if gc.Evm.GasPrice.Cmp(configs.SubmitParamProposalGasPrice) < 0 {
+		return nil, ErrUnderPrice
+	}
+
+	p := &gov.TreasuryProposal{
+		PIPID:        pipID,
+		ProposalType: gov.Treasury,
+		SubmitBlock:  blockNumber,
+		ProposalID:   txHash,
+		Proposer:     verifier,
+		Recipient:    recipient,
+		Amount:       amount,
+		Plans:        plans,
+	}
+	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB, gc.Evm.chainConfig.ChainID)
+	return gc.nonCallHandler("submitTreasury", SubmitTreasury, err)
+}
+
 func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op uint8, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
 	from := gc.Contract.CallerAddress
 	blockNumber := gc.Evm.BlockNumber.Uint64()
@@ -294,6 +382,49 @@ func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op
 	return gc.nonCallHandler("vote", Vote, err)
 }
 
+func (gc *GovContract) voteByDelegator(proposalID common.Hash, op uint8) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call voteByDelegator of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"proposalID", proposalID,
+		"option", op)
+
+	if !gc.Contract.UseGas(configs.VoteGas) {
+		return nil, ErrOutOfGas
+	}
+
+	list, err := plugin.StakingInstance().GetDelegatesInfo(blockHash, from)
+	if err != nil {
+		return gc.nonCallHandler("voteByDelegator", VoteByDelegator, common.InternalError.Wrap(err.Error()))
+	}
+	if !gc.Contract.UseGas(configs.DelegatorVoteGas * uint64(len(list))) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	delegations := make([]gov.DelegationWeight, 0, len(list))
+	for _, info := range list {
+		amount := new(big.Int).Add(info.Delegation.Released, info.Delegation.ReleasedHes)
+		amount.Add(amount, info.Delegation.RestrictingPlan)
+		amount.Add(amount, info.Delegation.RestrictingPlanHes)
+		if amount.Sign() > 0 {
+			delegations = append(delegations, gov.DelegationWeight{NodeID: info.NodeID, StakingBlockNum: info.StakeBlockNumber, Amount: amount})
+		}
+	}
+	err = gov.VoteByDelegator(from, proposalID, gov.ParseVoteOption(op), delegations, blockHash, blockNumber, gc.Evm.StateDB)
+
+	return gc.nonCallHandler("voteByDelegator", VoteByDelegator, err)
+}
+
 func (gc *GovContract) declareVersion(activeNode discover.NodeID, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
 	from := gc.Contract.CallerAddress
 	blockNumber := gc.Evm.BlockNumber.Uint64()
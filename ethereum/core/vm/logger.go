@@ -38,6 +38,7 @@ type LogConfig struct {
 	DisableStack      bool // disable stack capture
 	DisableStorage    bool // disable storage capture
 	DisableReturnData bool // disable return data capture
+	DisableWasmSteps  bool // disable wasm instruction capture
 	Debug             bool // print output during capture end
 	Limit             int  // maximum length of output, but zero means unlimited
 }
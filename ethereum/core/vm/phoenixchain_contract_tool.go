@@ -6,6 +6,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/plugin"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
 )
@@ -32,6 +33,17 @@ func execPhoenixchainContract(input []byte, command map[uint16]interface{}) (ret
 	return result[0].Bytes(), nil
 }
 
+// activeFnSigns removes the functions introduced by the 1.2.0 version from the
+// methods of the contract until the version is active, they are unknown functions before.
+func activeFnSigns(state xcom.StateDB, fnSigns map[uint16]interface{}, fns120 ...uint16) map[uint16]interface{} {
+	if !gov.Gte120VersionState(state) {
+		for _, fn := range fns120 {
+			delete(fnSigns, fn)
+		}
+	}
+	return fnSigns
+}
+
 func txResultHandler(contractAddr common.Address, evm *EVM, title, reason string, fncode int, errCode *common.BizError) ([]byte, error) {
 	event := strconv.Itoa(fncode)
 	receipt := strconv.Itoa(int(errCode.Code))
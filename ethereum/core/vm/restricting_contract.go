@@ -14,6 +14,8 @@ import (
 
 const (
 	TxCreateRestrictingPlan = 4000
+	TxCreateVestingPlan     = 4001
+	TxRevokeRestrictingPlan = 4002
 	QueryRestrictingInfo    = 4100
 )
 
@@ -34,13 +36,16 @@ func (rc *RestrictingContract) Run(input []byte) ([]byte, error) {
 	if checkInputEmpty(input) {
 		return nil, nil
 	}
-	return execPhoenixchainContract(input, rc.FnSigns())
+	return execPhoenixchainContract(input, activeFnSigns(rc.Evm.StateDB, rc.FnSigns(),
+		TxCreateVestingPlan, TxRevokeRestrictingPlan))
 }
 
 func (rc *RestrictingContract) FnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
 		TxCreateRestrictingPlan: rc.createRestrictingPlan,
+		TxCreateVestingPlan:     rc.createVestingPlan,
+		TxRevokeRestrictingPlan: rc.revokeRestrictingPlan,
 
 		// Get
 		QueryRestrictingInfo: rc.getRestrictingInfo,
@@ -87,6 +92,79 @@ func (rc *RestrictingContract) createRestrictingPlan(account common.Address, pla
 	}
 }
 
+// createVestingPlan is a PhoenixChain precompiled contract function, used for create the restricting plans
+// from the cliff and linear vesting plans
+func (rc *RestrictingContract) createVestingPlan(account common.Address, plans []restricting.VestingPlan) ([]byte, error) {
+
+	from := rc.Contract.CallerAddress
+	txHash := rc.Evm.StateDB.TxHash()
+	blockNum := rc.Evm.BlockNumber
+	blockHash := rc.Evm.BlockHash
+	state := rc.Evm.StateDB
+
+	log.Debug("Call createVestingPlan of RestrictingContract", "blockNumber", blockNum.Uint64(),
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from.String(), "account", account.String())
+
+	if !rc.Contract.UseGas(configs.CreateRestrictingPlanGas) {
+		return nil, ErrOutOfGas
+	}
+	// only the next release of a vesting plan is stored, so it costs the same as the plan of createRestrictingPlan
+	if !rc.Contract.UseGas(configs.ReleasePlanGas * uint64(len(plans))) {
+		return nil, ErrOutOfGas
+	}
+
+	err := rc.Plugin.AddVestingRecord(from, account, blockNum.Uint64(), blockHash, plans, state, txHash)
+	switch err.(type) {
+	case nil:
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "",
+			"", TxCreateVestingPlan, common.NoErr)
+	case *common.BizError:
+		bizErr := err.(*common.BizError)
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "createVestingPlan",
+			bizErr.Error(), TxCreateVestingPlan, bizErr)
+	default:
+		log.Error("Failed to cal AddVestingRecord on createVestingPlan", "blockNumber", blockNum.Uint64(),
+			"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "error", err)
+		return nil, err
+	}
+}
+
+// revokeRestrictingPlan is a PhoenixChain precompiled contract function, used for the revoker
+// to reclaim the unreleased amount of the revocable plans of the account
+func (rc *RestrictingContract) revokeRestrictingPlan(account common.Address) ([]byte, error) {
+
+	from := rc.Contract.CallerAddress
+	txHash := rc.Evm.StateDB.TxHash()
+	blockNum := rc.Evm.BlockNumber
+	blockHash := rc.Evm.BlockHash
+	state := rc.Evm.StateDB
+
+	log.Debug("Call revokeRestrictingPlan of RestrictingContract", "blockNumber", blockNum.Uint64(),
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from.String(), "account", account.String())
+
+	if !rc.Contract.UseGas(configs.RevokeRestrictingPlanGas) {
+		return nil, ErrOutOfGas
+	}
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	revoked, err := rc.Plugin.RevokeRestrictingPlan(from, account, state)
+	switch err.(type) {
+	case nil:
+		return txResultHandlerWithRes(vm.RestrictingContractAddr, rc.Evm, "",
+			"", TxRevokeRestrictingPlan, int(common.NoErr.Code), revoked), nil
+	case *common.BizError:
+		bizErr := err.(*common.BizError)
+		return txResultHandler(vm.RestrictingContractAddr, rc.Evm, "revokeRestrictingPlan",
+			bizErr.Error(), TxRevokeRestrictingPlan, bizErr)
+	default:
+		log.Error("Failed to cal RevokeRestrictingPlan on revokeRestrictingPlan", "blockNumber", blockNum.Uint64(),
+			"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "error", err)
+		return nil, err
+	}
+}
+
 // createRestrictingPlan is a PhoenixChain precompiled contract function, used for getting restricting info.
 // first output param is a slice of byte of restricting info;
 // the secend output param is the result what plugin executed GetRestrictingInfo returns.
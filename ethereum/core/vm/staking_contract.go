@@ -36,6 +36,10 @@ const (
 	TxWithdrewCandidate  = 1003
 	TxDelegate           = 1004
 	TxWithdrewDelegation = 1005
+	TxRedelegate         = 1006
+	TxSetAutoCompound    = 1007
+	TxRotateBlsKey       = 1008
+	TxSetOperator        = 1009
 	QueryVerifierList    = 1100
 	QueryValidatorList   = 1101
 	QueryCandidateList   = 1102
@@ -69,7 +73,8 @@ func (stkc *StakingContract) Run(input []byte) ([]byte, error) {
 	if checkInputEmpty(input) {
 		return nil, nil
 	}
-	return execPhoenixchainContract(input, stkc.FnSigns())
+	return execPhoenixchainContract(input, activeFnSigns(stkc.Evm.StateDB, stkc.FnSigns(),
+		TxRedelegate, TxSetAutoCompound, TxRotateBlsKey, TxSetOperator))
 }
 
 func (stkc *StakingContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
@@ -85,6 +90,10 @@ func (stkc *StakingContract) FnSigns() map[uint16]interface{} {
 		TxWithdrewCandidate:  stkc.withdrewStaking,
 		TxDelegate:           stkc.delegate,
 		TxWithdrewDelegation: stkc.withdrewDelegation,
+		TxRedelegate:         stkc.redelegate,
+		TxSetAutoCompound:    stkc.setAutoCompound,
+		TxRotateBlsKey:       stkc.rotateBlsKey,
+		TxSetOperator:        stkc.setOperator,
 
 		// Get
 		QueryVerifierList:  stkc.getVerifierList,
@@ -364,9 +373,24 @@ func (stkc *StakingContract) editCandidate(benefitAddress *common.Address, nodeI
 	}
 
 	if from != canOld.StakingAddress {
-		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
-			fmt.Sprintf("contract sender: %s, can stake addr: %s", from, canOld.StakingAddress),
-			TxEditorCandidate, staking.ErrNoSameStakingAddr)
+		operator, err := stkc.Plugin.GetOperator(blockHash, canOld.NodeId, canOld.StakingBlockNum)
+		if nil != err {
+			log.Error("Failed to editCandidate by GetOperator", "txHash", txHash,
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+			return nil, err
+		}
+		if operator == common.ZeroAddr || from != operator {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
+				fmt.Sprintf("contract sender: %s, can stake addr: %s", from, canOld.StakingAddress),
+				TxEditorCandidate, staking.ErrNoSameStakingAddr)
+		}
+		// the operator only edits the description
+		if (benefitAddress != nil && *benefitAddress != canOld.BenefitAddress) ||
+			(rewardPer != nil && *rewardPer != canOld.NextRewardPer) {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
+				fmt.Sprintf("contract sender: %s, can operator: %s", from, operator),
+				TxEditorCandidate, staking.ErrOperatorNotAllowed)
+		}
 	}
 
 	if benefitAddress != nil && canOld.BenefitAddress != vm.RewardManagerPoolAddr {
@@ -798,6 +822,380 @@ func (stkc *StakingContract) withdrewDelegation(stakingBlockNum uint64, nodeId d
 		"", TxWithdrewDelegation, int(common.NoErr.Code), issueIncome), nil
 }
 
+func (stkc *StakingContract) redelegate(stakingBlockNum uint64, nodeId, targetNodeId discover.NodeID, amount *big.Int) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+	state := stkc.Evm.StateDB
+
+	log.Debug("Call redelegate of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "delAddr", from, "nodeId", nodeId.String(),
+		"stakingNum", stakingBlockNum, "targetNodeId", targetNodeId.String(), "amount", amount)
+
+	if !stkc.Contract.UseGas(configs.RedelegateGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if nodeId == targetNodeId {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("the target is the same candidate: %s", nodeId.String()),
+			TxRedelegate, staking.ErrRedelegateSameCandidate)
+	}
+
+	del, err := stkc.Plugin.GetDelegateInfo(blockHash, from, nodeId, stakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetDelegateInfo",
+			"txHash", txHash.Hex(), "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if del.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"del is nil", TxRedelegate, staking.ErrDelegateNoExist)
+	}
+
+	targetAddr, err := xutil.NodeId2Addr(targetNodeId)
+	if nil != err {
+		log.Error("Failed to redelegate by parse nodeId", "txHash", txHash, "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "targetNodeId", targetNodeId.String(), "err", err)
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("nodeid %s to address fail: %s",
+				targetNodeId.String(), err.Error()),
+			TxRedelegate, staking.ErrNodeID2Addr)
+	}
+
+	canMutable, err := stkc.Plugin.GetCanMutable(blockHash, targetAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetCanMutable", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if canMutable.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"target can is nil", TxRedelegate, staking.ErrCanNoExist)
+	}
+
+	if canMutable.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("target can status is: %d", canMutable.Status),
+			TxRedelegate, staking.ErrCanStatusInvalid)
+	}
+
+	canBase, err := stkc.Plugin.GetCanBase(blockHash, targetAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetCanBase", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if canBase.StakingBlockNum == blockNumber.Uint64() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("redelegate fail,can't not delgate in the staking block:%d", blockNumber.Uint64()),
+			TxRedelegate, staking.ErrCanNoExist)
+	}
+
+	// If the candidate’s benefitaAddress is the RewardManagerPoolAddr, no delegation is allowed
+	if canBase.BenefitAddress == vm.RewardManagerPoolAddr {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			"the target can benefitAddr is reward addr",
+			TxRedelegate, staking.ErrCanNoAllowDelegate)
+	}
+
+	currentEpoch := xutil.CalculateEpoch(blockNumber.Uint64())
+
+	delegateRewardPerList, err := plugin.RewardMgrInstance().GetDelegateRewardPerList(blockHash, nodeId, stakingBlockNum, uint64(del.DelegateEpoch), currentEpoch-1)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetDelegateRewardPerList", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	result, err := stkc.calcRewardPerUseGas(delegateRewardPerList, del)
+	if nil != err {
+		return result, err
+	}
+
+	targetDel, err := stkc.Plugin.GetDelegateInfo(blockHash, from, targetNodeId, canBase.StakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to redelegate by GetDelegateInfo of target",
+			"txHash", txHash.Hex(), "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+	if targetDel.IsEmpty() {
+		// build delegate
+		targetDel = new(staking.Delegation)
+		// Prevent null pointer initialization
+		targetDel.Released = new(big.Int).SetInt64(0)
+		targetDel.RestrictingPlan = new(big.Int).SetInt64(0)
+		targetDel.ReleasedHes = new(big.Int).SetInt64(0)
+		targetDel.RestrictingPlanHes = new(big.Int).SetInt64(0)
+		targetDel.CumulativeIncome = new(big.Int).SetInt64(0)
+	}
+	var targetRewardPerList []*reward.DelegateRewardPer
+	if targetDel.DelegateEpoch > 0 {
+		targetRewardPerList, err = plugin.RewardMgrInstance().GetDelegateRewardPerList(blockHash, targetNodeId, canBase.StakingBlockNum, uint64(targetDel.DelegateEpoch), currentEpoch-1)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to redelegate by GetDelegateRewardPerList of target", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+		result, err := stkc.calcRewardPerUseGas(targetRewardPerList, targetDel)
+		if nil != err {
+			return result, err
+		}
+	}
+
+	if ok, threshold := plugin.CheckOperatingThreshold(blockNumber.Uint64(), blockHash, amount); !ok {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("redelegate threshold: %d, deposit: %d", threshold, amount),
+			TxRedelegate, staking.ErrDelegateVonTooLow)
+	}
+
+	// check account
+	hasStake, err := stkc.Plugin.HasStake(blockHash, from)
+	if nil != err {
+		return nil, err
+	}
+
+	if hasStake {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+			fmt.Sprintf("'%s' has staking, so don't allow to delegate", from),
+			TxRedelegate, staking.ErrAccountNoAllowToDelegate)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	target := &staking.Candidate{}
+	target.CandidateBase = canBase
+	target.CandidateMutable = canMutable
+
+	issueIncome, err := stkc.Plugin.Redelegate(state, blockHash, blockNumber, amount, from, nodeId, stakingBlockNum, del,
+		delegateRewardPerList, targetAddr, target, targetDel, targetRewardPerList)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "redelegate",
+				bizErr.Error(), TxRedelegate, bizErr)
+		} else {
+			log.Error("Failed to redelegate by Redelegate", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandlerWithRes(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxRedelegate, int(common.NoErr.Code), issueIncome), nil
+}
+
+func (stkc *StakingContract) setAutoCompound(stakingBlockNum uint64, nodeId discover.NodeID, autoCompound bool) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call setAutoCompound of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "delAddr", from, "nodeId", nodeId.String(),
+		"stakingNum", stakingBlockNum, "autoCompound", autoCompound)
+
+	if !stkc.Contract.UseGas(configs.SetAutoCompoundGas) {
+		return nil, ErrOutOfGas
+	}
+
+	del, err := stkc.Plugin.GetDelegateInfo(blockHash, from, nodeId, stakingBlockNum)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to setAutoCompound by GetDelegateInfo",
+			"txHash", txHash.Hex(), "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	if del.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setAutoCompound",
+			"del is nil", TxSetAutoCompound, staking.ErrDelegateNoExist)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if err := stkc.Plugin.SetAutoCompound(blockHash, from, nodeId, stakingBlockNum, autoCompound); nil != err {
+		log.Error("Failed to setAutoCompound by SetAutoCompound", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxSetAutoCompound, common.NoErr)
+}
+
+// The new BLS key takes effect at the next epoch boundary,
+// the node must switch to the new BLS private key from then on.
+func (stkc *StakingContract) rotateBlsKey(nodeId discover.NodeID, blsPubKey bls.PublicKeyHex, blsProof bls.SchnorrProofHex) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call rotateBlsKey of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "from", from,
+		"blsPubKey", blsPubKey, "blsProof", blsProof)
+
+	if !stkc.Contract.UseGas(configs.RotateBlsKeyGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if len(blsPubKey) != BLSPUBKEYLEN {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("got blsKey length: %d, must be: %d", len(blsPubKey), BLSPUBKEYLEN),
+			TxRotateBlsKey, staking.ErrWrongBlsPubKey)
+	}
+
+	if len(blsProof) != BLSPROOFLEN {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("got blsProof length: %d, must be: %d", len(blsProof), BLSPROOFLEN),
+			TxRotateBlsKey, staking.ErrWrongBlsPubKeyProof)
+	}
+
+	// parse bls publickey
+	blsPk, err := blsPubKey.ParseBlsPubKey()
+	if nil != err {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("failed to parse blspubkey: %s", err.Error()),
+			TxRotateBlsKey, staking.ErrWrongBlsPubKey)
+	}
+
+	// verify bls proof
+	if err := verifyBlsProof(blsProof, blsPk); nil != err {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("failed to verify bls proof: %s", err.Error()),
+			TxRotateBlsKey, staking.ErrWrongBlsPubKeyProof)
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to rotateBlsKey by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("nodeid %s to address fail: %s",
+				nodeId.String(), err.Error()),
+			TxRotateBlsKey, staking.ErrNodeID2Addr)
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to rotateBlsKey by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			"can is nil", TxRotateBlsKey, staking.ErrCanNoExist)
+	}
+
+	if canOld.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("can status is: %d", canOld.Status),
+			TxRotateBlsKey, staking.ErrCanStatusInvalid)
+	}
+
+	if from != canOld.StakingAddress {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("contract sender: %s, can stake addr: %s", from, canOld.StakingAddress),
+			TxRotateBlsKey, staking.ErrNoSameStakingAddr)
+	}
+
+	if canOld.BlsPubKey == blsPubKey {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			"", TxRotateBlsKey, staking.ErrSameBlsPubKey)
+	}
+
+	used, err := stkc.Plugin.IsBlsKeyUsed(blockHash, blockNumber.Uint64(), canAddr, blsPubKey)
+	if nil != err {
+		log.Error("Failed to rotateBlsKey by IsBlsKeyUsed", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return nil, err
+	}
+	if used {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateBlsKey",
+			fmt.Sprintf("blsPubKey: %s", blsPubKey), TxRotateBlsKey, staking.ErrBlsPubKeyUsed)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if err := stkc.Plugin.RotateBlsKey(blockHash, blockNumber.Uint64(), canAddr, blsPubKey); nil != err {
+		log.Error("Failed to rotateBlsKey by RotateBlsKey", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxRotateBlsKey, common.NoErr)
+}
+
+// The operator is allowed to edit the description, vote on proposals and declare versions
+// for the candidate, the zero address revokes it.
+func (stkc *StakingContract) setOperator(nodeId discover.NodeID, operator common.Address) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.BlockNumber
+	blockHash := stkc.Evm.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call setOperator of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "nodeId", nodeId.String(), "from", from, "operator", operator)
+
+	if !stkc.Contract.UseGas(configs.SetOperatorGas) {
+		return nil, ErrOutOfGas
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to setOperator by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setOperator",
+			fmt.Sprintf("nodeid %s to address fail: %s",
+				nodeId.String(), err.Error()),
+			TxSetOperator, staking.ErrNodeID2Addr)
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to setOperator by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setOperator",
+			"can is nil", TxSetOperator, staking.ErrCanNoExist)
+	}
+
+	if canOld.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setOperator",
+			fmt.Sprintf("can status is: %d", canOld.Status),
+			TxSetOperator, staking.ErrCanStatusInvalid)
+	}
+
+	if from != canOld.StakingAddress {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "setOperator",
+			fmt.Sprintf("contract sender: %s, can stake addr: %s", from, canOld.StakingAddress),
+			TxSetOperator, staking.ErrNoSameStakingAddr)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if err := stkc.Plugin.SetOperator(blockHash, canOld.NodeId, canOld.StakingBlockNum, operator); nil != err {
+		log.Error("Failed to setOperator by SetOperator", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxSetOperator, common.NoErr)
+}
+
 func (stkc *StakingContract) calcRewardPerUseGas(delegateRewardPerList []*reward.DelegateRewardPer, del *staking.Delegation) ([]byte, error) {
 	unCalcEpoch := len(delegateRewardPerList)
 	if unCalcEpoch > 0 {
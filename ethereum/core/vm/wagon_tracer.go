@@ -0,0 +1,330 @@
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+)
+
+// wagonTracer reports the execution of the wagon vm of a contract to the WasmTracer
+type wagonTracer struct {
+	tracer WasmTracer
+	engine *wagonEngine
+	module *wasm.Module
+
+	names map[int64]string
+	calls []*wagonCall // the calls in progress, unwound by a fault of the vm
+}
+
+type wagonCall struct {
+	*WasmCall
+	args []uint64 // the raw args of a host call, decoded when it returns
+}
+
+func newWagonTracer(tracer WasmTracer, engine *wagonEngine, module *wasm.Module) *wagonTracer {
+	return &wagonTracer{
+		tracer: tracer,
+		engine: engine,
+		module: module,
+		names:  make(map[int64]string),
+	}
+}
+
+func (t *wagonTracer) CaptureStep(fnIndex int64, pc int64, op byte) {
+	var cost uint64
+	if int(op) < len(WasmGasCostTable) {
+		cost = WasmGasCostTable[op]
+	}
+	contract := t.engine.Contract()
+	t.tracer.CaptureWasmState(t.engine.EVM(), t.funcName(fnIndex), uint64(pc), exec.OpName(op), contract.Gas, cost,
+		len(t.engine.vm.Memory()), contract, t.engine.EVM().depth)
+}
+
+func (t *wagonTracer) CaptureEnter(fnIndex int64, args []uint64) {
+	call := &wagonCall{
+		WasmCall: &WasmCall{
+			Func: t.funcName(fnIndex),
+			Gas:  t.engine.Contract().Gas,
+		},
+	}
+	if fn := t.module.GetFunction(int(fnIndex)); fn != nil && fn.IsHost() {
+		call.Host = true
+		call.args = append([]uint64(nil), args...)
+	}
+	call.Args = rawWasmArgs(args)
+	t.calls = append(t.calls, call)
+	t.tracer.CaptureWasmEnter(t.engine.EVM(), call.WasmCall, t.engine.Contract(), t.engine.EVM().depth)
+}
+
+func (t *wagonTracer) CaptureExit(fnIndex int64, returns []uint64) {
+	if len(t.calls) == 0 {
+		return
+	}
+	call := t.calls[len(t.calls)-1]
+	t.calls = t.calls[:len(t.calls)-1]
+	if len(returns) > 0 {
+		call.Returns = append([]uint64(nil), returns...)
+	}
+	t.exit(call)
+}
+
+func (t *wagonTracer) CaptureGrowMemory(pages int32, oldSize, newSize int) {
+	contract := t.engine.Contract()
+	t.tracer.CaptureWasmMemory(t.engine.EVM(), pages, oldSize, newSize, contract.Gas, contract, t.engine.EVM().depth)
+}
+
+// fault unwinds the calls in progress with the error of the vm
+func (t *wagonTracer) fault(err error) {
+	for i := len(t.calls) - 1; i >= 0; i-- {
+		t.calls[i].Err = err
+		t.exit(t.calls[i])
+	}
+	t.calls = nil
+}
+
+func (t *wagonTracer) exit(call *wagonCall) {
+	if gas := t.engine.Contract().Gas; gas < call.Gas {
+		call.GasCost = call.Gas - gas
+	}
+	if call.Host {
+		if decode, ok := hostArgDecoders[call.Func]; ok {
+			call.Args = decode(&hostArgs{
+				mem:     t.engine.vm.Memory(),
+				args:    call.args,
+				returns: call.Returns,
+			})
+		}
+	}
+	t.tracer.CaptureWasmExit(t.engine.EVM(), call.WasmCall, t.engine.Contract(), t.engine.EVM().depth)
+}
+
+// funcName returns the name of the function, the imported name of a host function,
+// the name of the name section or the exported name of a contract function.
+func (t *wagonTracer) funcName(index int64) string {
+	if name, ok := t.names[index]; ok {
+		return name
+	}
+	name := t.lookupFuncName(index)
+	t.names[index] = name
+	return name
+}
+
+func (t *wagonTracer) lookupFuncName(index int64) string {
+	if t.module.Import != nil {
+		var imported int64
+		for _, entry := range t.module.Import.Entries {
+			if _, ok := entry.Type.(wasm.FuncImport); !ok {
+				continue
+			}
+			if imported == index {
+				return entry.FieldName
+			}
+			imported++
+		}
+	}
+	if fn := t.module.GetFunction(int(index)); fn != nil && fn.Name != "" {
+		return fn.Name
+	}
+	if t.module.Export != nil {
+		for name, entry := range t.module.Export.Entries {
+			if entry.Kind == wasm.ExternalFunction && int64(entry.Index) == index {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("$f%d", index)
+}
+
+func rawWasmArgs(args []uint64) []WasmArg {
+	if len(args) == 0 {
+		return nil
+	}
+	raws := make([]WasmArg, len(args))
+	for i, arg := range args {
+		raws[i] = WasmArg{Name: "$" + strconv.Itoa(i), Value: strconv.FormatUint(arg, 10)}
+	}
+	return raws
+}
+
+// hostArgs decodes the arguments of a host call from the memory of the contract
+type hostArgs struct {
+	mem     []byte
+	args    []uint64
+	returns []uint64
+}
+
+func (h *hostArgs) slice(ptr, length uint64) ([]byte, bool) {
+	if ptr+length < ptr || ptr+length > uint64(len(h.mem)) {
+		return nil, false
+	}
+	return h.mem[ptr : ptr+length], true
+}
+
+func (h *hostArgs) arg(i int) uint64 {
+	if i < len(h.args) {
+		return h.args[i]
+	}
+	return 0
+}
+
+// ret returns the value returned by the host function as an int32, if it returned
+func (h *hostArgs) ret() (int32, bool) {
+	if len(h.returns) == 0 {
+		return 0, false
+	}
+	return int32(h.returns[0]), true
+}
+
+func (h *hostArgs) number(name string, i int) WasmArg {
+	return WasmArg{Name: name, Value: strconv.FormatUint(h.arg(i), 10)}
+}
+
+func (h *hostArgs) bytes(name string, ptr int, length uint64) WasmArg {
+	b, ok := h.slice(h.arg(ptr), length)
+	if !ok {
+		return WasmArg{Name: name, Value: "out of bounds"}
+	}
+	return WasmArg{Name: name, Value: hexutil.Encode(b)}
+}
+
+func (h *hostArgs) string(name string, ptr, length int) WasmArg {
+	b, ok := h.slice(h.arg(ptr), h.arg(length))
+	if !ok {
+		return WasmArg{Name: name, Value: "out of bounds"}
+	}
+	return WasmArg{Name: name, Value: string(b)}
+}
+
+func (h *hostArgs) address(name string, ptr int) WasmArg {
+	b, ok := h.slice(h.arg(ptr), common.AddressLength)
+	if !ok {
+		return WasmArg{Name: name, Value: "out of bounds"}
+	}
+	return WasmArg{Name: name, Value: common.BytesToAddress(b).String()}
+}
+
+func (h *hostArgs) amount(name string, ptr int, length uint64) WasmArg {
+	b, ok := h.slice(h.arg(ptr), length)
+	if !ok {
+		return WasmArg{Name: name, Value: "out of bounds"}
+	}
+	return WasmArg{Name: name, Value: new(big.Int).SetBytes(b).String()}
+}
+
+// the args of the calls to the other contracts, from the ptr arg
+func (h *hostArgs) call(ptr int, value bool) []WasmArg {
+	args := []WasmArg{h.bytes("input", ptr, h.arg(ptr+1))}
+	if value {
+		args = append(args, h.amount("value", ptr+2, h.arg(ptr+3)))
+		ptr += 2
+	}
+	return append(args, h.amount("gas", ptr+2, h.arg(ptr+3)))
+}
+
+// hostArgDecoders decodes the arguments of the host functions from the memory,
+// the other host functions are traced with their raw arguments
+var hostArgDecoders = map[string]func(h *hostArgs) []WasmArg{
+	"phoenixchain_gas_price": func(h *hostArgs) []WasmArg {
+		if n, ok := h.ret(); ok {
+			return []WasmArg{h.amount("gasPrice", 0, uint64(n))}
+		}
+		return nil
+	},
+	"phoenixchain_block_hash": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.number("number", 0), h.bytes("hash", 1, common.HashLength)}
+	},
+	"phoenixchain_coinbase": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("coinbase", 0)}
+	},
+	"phoenixchain_balance": func(h *hostArgs) []WasmArg {
+		args := []WasmArg{h.address("address", 0)}
+		if n, ok := h.ret(); ok {
+			args = append(args, h.amount("balance", 1, uint64(n)))
+		}
+		return args
+	},
+	"phoenixchain_origin": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("origin", 0)}
+	},
+	"phoenixchain_caller": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("caller", 0)}
+	},
+	"phoenixchain_call_value": func(h *hostArgs) []WasmArg {
+		if n, ok := h.ret(); ok {
+			return []WasmArg{h.amount("value", 0, uint64(n))}
+		}
+		return nil
+	},
+	"phoenixchain_address": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("address", 0)}
+	},
+	"phoenixchain_sha3": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("data", 0, h.arg(1)), h.bytes("hash", 2, common.HashLength)}
+	},
+	"phoenixchain_transfer": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("to", 0), h.amount("amount", 1, h.arg(2))}
+	},
+	"phoenixchain_set_state": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("key", 0, h.arg(1)), h.bytes("value", 2, h.arg(3))}
+	},
+	"phoenixchain_get_state_length": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("key", 0, h.arg(1))}
+	},
+	"phoenixchain_get_state": func(h *hostArgs) []WasmArg {
+		args := []WasmArg{h.bytes("key", 0, h.arg(1))}
+		if n, ok := h.ret(); ok && n >= 0 {
+			args = append(args, h.bytes("value", 2, uint64(n)))
+		}
+		return args
+	},
+	"phoenixchain_return": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("data", 0, h.arg(1))}
+	},
+	"phoenixchain_debug": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.string("message", 0, 1)}
+	},
+	"phoenixchain_call": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("to", 0)}, h.call(1, true)...)
+	},
+	"phoenixchain_delegate_call": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("to", 0)}, h.call(1, false)...)
+	},
+	"phoenixchain_static_call": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("to", 0)}, h.call(1, false)...)
+	},
+	"phoenixchain_destroy": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("beneficiary", 0)}
+	},
+	"phoenixchain_migrate": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("newAddress", 0)}, h.call(1, true)...)
+	},
+	"phoenixchain_clone_migrate": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("oldAddress", 0), h.address("newAddress", 1)}, h.call(2, true)...)
+	},
+	"phoenixchain_deploy": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("newAddress", 0)}, h.call(1, true)...)
+	},
+	"phoenixchain_clone": func(h *hostArgs) []WasmArg {
+		return append([]WasmArg{h.address("oldAddress", 0), h.address("newAddress", 1)}, h.call(2, true)...)
+	},
+	"phoenixchain_event": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("topics", 0, h.arg(1)), h.bytes("data", 2, h.arg(3))}
+	},
+	"phoenixchain_ecrecover": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("hash", 0, common.HashLength), h.bytes("signature", 1, h.arg(2)), h.address("address", 3)}
+	},
+	"phoenixchain_ripemd160": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("data", 0, h.arg(1)), h.bytes("hash", 2, 20)}
+	},
+	"phoenixchain_sha256": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.bytes("data", 0, h.arg(1)), h.bytes("hash", 2, common.HashLength)}
+	},
+	"phoenixchain_contract_code_length": func(h *hostArgs) []WasmArg {
+		return []WasmArg{h.address("address", 0)}
+	},
+}
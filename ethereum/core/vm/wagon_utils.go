@@ -13,13 +13,7 @@ import (
 )
 
 func ReadWasmModule(Code []byte, verify bool) (*exec.CompiledModule, error) {
-	m, err := wasm.ReadModule(bytes.NewReader(Code), func(name string) (*wasm.Module, error) {
-		switch name {
-		case "env":
-			return NewHostModule(), nil
-		}
-		return nil, fmt.Errorf("module %q unknown", name)
-	})
+	m, err := decodeWasmModule(Code)
 	if err != nil {
 		return nil, err
 	}
@@ -40,6 +34,18 @@ func ReadWasmModule(Code []byte, verify bool) (*exec.CompiledModule, error) {
 	return compiled, nil
 }
 
+// decodeWasmModule parses the code of the contract and resolves its imports
+// of the host functions.
+func decodeWasmModule(code []byte) (*wasm.Module, error) {
+	return wasm.ReadModule(bytes.NewReader(code), func(name string) (*wasm.Module, error) {
+		switch name {
+		case "env":
+			return NewHostModule(), nil
+		}
+		return nil, fmt.Errorf("module %q unknown", name)
+	})
+}
+
 func decodeFuncAndParams(input []byte) (uint64, []byte, error) {
 	content, _, err := rlp.SplitList(input)
 	if nil != err {
@@ -12,6 +12,8 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/validate"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +50,7 @@ type wagonEngine struct {
 	gasTable configs.GasTable
 	vm       *exec.CompileVM
 	contract *Contract
+	tracer   *wagonTracer
 }
 
 func (engine *wagonEngine) EVM() *EVM {
@@ -66,6 +69,12 @@ func (engine *wagonEngine) Contract() *Contract {
 	return engine.contract
 }
 
+// postMVPEnabled reports whether the contracts can use the features of the
+// post-MVP proposals wagon supports, they are enabled by the 1.2.0 version.
+func (engine *wagonEngine) postMVPEnabled() bool {
+	return gov.Gte120VersionState(engine.StateDB())
+}
+
 func (engine *wagonEngine) Run(input []byte, readOnly bool) ([]byte, error) {
 
 	if engine.Contract().DeployContract { // deploy contract
@@ -117,7 +126,7 @@ func (engine *wagonEngine) Run(input []byte, readOnly bool) ([]byte, error) {
 }
 
 func (engine *wagonEngine) prepare(module *exec.CompiledModule, input []byte) error {
-	vm, err := exec.NewVMWithCompiled(module, memoryLimit)
+	vm, err := exec.NewVMWithCompiled(module, memoryLimit, exec.DisablePostMVP(!engine.postMVPEnabled()))
 	if nil != err {
 		return err
 	}
@@ -138,6 +147,18 @@ func (engine *wagonEngine) prepare(module *exec.CompiledModule, input []byte) er
 			panic(ErrOutOfGas)
 		}
 	})
+	vm.SetUseMemoryGas(func(op byte, size uint64) {
+		gas := WasmBulkMemoryWordGas * toWordSize(size)
+		if !ctx.contract.UseGas(gas) {
+			panic(ErrOutOfGas)
+		}
+	})
+	if engine.config.Debug {
+		if tracer, ok := engine.config.Tracer.(WasmTracer); ok {
+			engine.tracer = newWagonTracer(tracer, engine, module.RawModule)
+			vm.SetTracer(engine.tracer)
+		}
+	}
 	engine.vm = vm
 	return nil
 }
@@ -152,6 +173,9 @@ func (engine *wagonEngine) exec(index int64) (ret []byte, err error) {
 				log.Error("Failed to exec wagon vm", "the undefined err", fmt.Sprintf("%v", e))
 				ret, err = nil, ErrWASMUndefinedPanic
 			}
+			if engine.tracer != nil {
+				engine.tracer.fault(err)
+			}
 		}
 	}()
 
@@ -220,10 +244,15 @@ func validateCallFunc(input []byte) error {
 func (engine *wagonEngine) makeModuleWithDeploy() (*exec.CompiledModule, int64, error) {
 
 	cache := &lru.WasmModule{}
-	module, err := ReadWasmModule(engine.Contract().Code, verifyModule)
+	module, err := readWasmModule(engine.Contract().Code, verifyModule, false)
 	if nil != err {
 		return nil, 0, err
 	}
+	if !engine.postMVPEnabled() {
+		if err := validate.VerifyModuleMVP(module.RawModule); nil != err {
+			return nil, 0, err
+		}
+	}
 	// Short circuit if the `invoke` function is not existing in the module
 	entry, ok := module.RawModule.Export.Entries[callEntryName]
 	if !ok {
@@ -249,7 +278,7 @@ func (engine *wagonEngine) makeModuleWithCall() (*exec.CompiledModule, int64, er
 	if !ok || (ok && nil == cache.Module) {
 		cache = &lru.WasmModule{}
 
-		module, err := ReadWasmModule(engine.Contract().Code, unVerifyModule)
+		module, err := readWasmModule(engine.Contract().Code, unVerifyModule, true)
 		if nil != err {
 			return nil, 0, err
 		}
@@ -267,6 +296,16 @@ func (engine *wagonEngine) makeModuleWithCall() (*exec.CompiledModule, int64, er
 	return mod, index, nil
 }
 
+// readWasmModule returns the compiled module of the code from the module store,
+// or compiles it if there is no store. Only the modules of the contracts called
+// are persisted, a deployed code isn't committed yet.
+func readWasmModule(code []byte, verify, persist bool) (*exec.CompiledModule, error) {
+	if store := getWasmModuleStore(); store != nil {
+		return store.ReadModule(code, verify, persist)
+	}
+	return ReadWasmModule(code, verify)
+}
+
 func (engine *wagonEngine) isReadOnly() bool {
 	ctx := engine.vm.HostCtx().(*VMContext)
 	return ctx.readOnly
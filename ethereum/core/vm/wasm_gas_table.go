@@ -4,6 +4,11 @@ import "math"
 
 const (
 	MigrateContractGas = uint64(68000)
+
+	// WasmBulkMemoryWordGas is the gas of each 32-byte word of the memory
+	// copied or filled by memory.copy and memory.fill, on top of their cost
+	// in WasmGasCostTable.
+	WasmBulkMemoryWordGas = uint64(3)
 )
 
 var WasmGasCostTable [255]uint64
@@ -191,6 +196,24 @@ func init() {
 	WasmGasCostTable[I64ReinterpretF64] = 3
 	WasmGasCostTable[F32ReinterpretI32] = math.MaxInt64
 	WasmGasCostTable[F64ReinterpretI64] = math.MaxInt64
+
+	WasmGasCostTable[I32Extend8S] = 1
+	WasmGasCostTable[I32Extend16S] = 1
+	WasmGasCostTable[I64Extend8S] = 1
+	WasmGasCostTable[I64Extend16S] = 1
+	WasmGasCostTable[I64Extend32S] = 1
+
+	WasmGasCostTable[I32TruncSatSF32] = 3
+	WasmGasCostTable[I32TruncSatUF32] = 3
+	WasmGasCostTable[I32TruncSatSF64] = 3
+	WasmGasCostTable[I32TruncSatUF64] = 3
+	WasmGasCostTable[I64TruncSatSF32] = 3
+	WasmGasCostTable[I64TruncSatUF32] = 3
+	WasmGasCostTable[I64TruncSatSF64] = 3
+	WasmGasCostTable[I64TruncSatUF64] = 3
+
+	WasmGasCostTable[MemoryCopy] = 3
+	WasmGasCostTable[MemoryFill] = 3
 }
 
 var WasmInstrString = map[byte]string{
@@ -376,6 +399,24 @@ var WasmInstrString = map[byte]string{
 	I64ReinterpretF64: "I64ReinterpretF64",
 	F32ReinterpretI32: "F32ReinterpretI32",
 	F64ReinterpretI64: "F64ReinterpretI64",
+
+	I32Extend8S:  "I32Extend8S",
+	I32Extend16S: "I32Extend16S",
+	I64Extend8S:  "I64Extend8S",
+	I64Extend16S: "I64Extend16S",
+	I64Extend32S: "I64Extend32S",
+
+	I32TruncSatSF32: "I32TruncSatSF32",
+	I32TruncSatUF32: "I32TruncSatUF32",
+	I32TruncSatSF64: "I32TruncSatSF64",
+	I32TruncSatUF64: "I32TruncSatUF64",
+	I64TruncSatSF32: "I64TruncSatSF32",
+	I64TruncSatUF32: "I64TruncSatUF32",
+	I64TruncSatSF64: "I64TruncSatSF64",
+	I64TruncSatUF64: "I64TruncSatUF64",
+
+	MemoryCopy: "MemoryCopy",
+	MemoryFill: "MemoryFill",
 }
 
 const (
@@ -563,4 +604,23 @@ const (
 	I64ReinterpretF64 = 0xbd
 	F32ReinterpretI32 = 0xbe
 	F64ReinterpretI64 = 0xbf
+
+	I32Extend8S  = 0xc0
+	I32Extend16S = 0xc1
+	I64Extend8S  = 0xc2
+	I64Extend16S = 0xc3
+	I64Extend32S = 0xc4
+
+	// The operators prefixed by 0xfc are assigned the free opcodes from
+	// 0xe0 by wagon.
+	I32TruncSatSF32 = 0xe0
+	I32TruncSatUF32 = 0xe1
+	I32TruncSatSF64 = 0xe2
+	I32TruncSatUF64 = 0xe3
+	I64TruncSatSF32 = 0xe4
+	I64TruncSatUF32 = 0xe5
+	I64TruncSatSF64 = 0xe6
+	I64TruncSatUF64 = 0xe7
+	MemoryCopy      = 0xe8
+	MemoryFill      = 0xe9
 )
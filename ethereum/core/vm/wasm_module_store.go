@@ -0,0 +1,269 @@
+package vm
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/metrics"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/validate"
+)
+
+var (
+	wasmModuleHitMeter     = metrics.NewRegisteredMeter("vm/wasm/modulestore/hit", nil)
+	wasmModuleMissMeter    = metrics.NewRegisteredMeter("vm/wasm/modulestore/miss", nil)
+	wasmModuleWriteMeter   = metrics.NewRegisteredMeter("vm/wasm/modulestore/write", nil)
+	wasmModuleEvictMeter   = metrics.NewRegisteredMeter("vm/wasm/modulestore/evict", nil)
+	wasmModuleCorruptMeter = metrics.NewRegisteredMeter("vm/wasm/modulestore/corrupt", nil)
+	wasmModuleSizeGauge    = metrics.NewRegisteredGauge("vm/wasm/modulestore/size", nil)
+	wasmModuleLoadTimer    = metrics.NewRegisteredTimer("vm/wasm/modulestore/load", nil)
+	wasmModuleCompileTimer = metrics.NewRegisteredTimer("vm/wasm/modulestore/compile", nil)
+)
+
+// The layout of a stored module is checksum (32 bytes) | flags (1 byte) | compiled
+// functions, the checksum is the hash of the flags and the compiled functions.
+const (
+	wasmModuleHeaderLen = common.HashLength + 1
+
+	wasmModuleVerified byte = 1 // the module passed the validation of a deployment
+)
+
+// wasmModuleStore is the store the wagon engine loads the compiled modules
+// from, nil if the modules are always compiled.
+var (
+	wasmModuleStore     *WasmModuleStore
+	wasmModuleStoreLock sync.RWMutex
+)
+
+// SetWasmModuleStore sets the store of the compiled modules of the wagon engine,
+// nil disables it.
+func SetWasmModuleStore(store *WasmModuleStore) {
+	wasmModuleStoreLock.Lock()
+	defer wasmModuleStoreLock.Unlock()
+	wasmModuleStore = store
+}
+
+func getWasmModuleStore() *WasmModuleStore {
+	wasmModuleStoreLock.RLock()
+	defer wasmModuleStoreLock.RUnlock()
+	return wasmModuleStore
+}
+
+// WasmModuleStore keeps the compiled WASM modules in the database, keyed by the
+// hash of the contract code and the version of the compiler, so that the contracts
+// aren't compiled again after a restart. Whether the module passed the validation
+// of a deployment is stored along. The least recently used modules are evicted
+// when the modules exceed the size limit.
+type WasmModuleStore struct {
+	db    ethdb.KeyValueStore
+	limit int // the limit in bytes of the modules stored
+
+	lock    sync.Mutex
+	size    int
+	entries map[common.Hash]*list.Element
+	order   *list.List // the entries of the modules stored, least recently used first
+}
+
+type wasmModuleEntry struct {
+	hash common.Hash
+	size int
+}
+
+// NewWasmModuleStore returns the store of the compiled modules kept in db up to
+// limit bytes. The modules compiled by another version of the compiler are removed.
+func NewWasmModuleStore(db ethdb.KeyValueStore, limit int) *WasmModuleStore {
+	store := &WasmModuleStore{
+		db:      db,
+		limit:   limit,
+		entries: make(map[common.Hash]*list.Element),
+		order:   list.New(),
+	}
+
+	type staleModule struct {
+		version uint32
+		hash    common.Hash
+	}
+	var stale []staleModule
+	rawdb.IterateWasmModules(db, func(version uint32, hash common.Hash, size int) {
+		if version != exec.CompilerVersion {
+			stale = append(stale, staleModule{version, hash})
+			return
+		}
+		store.entries[hash] = store.order.PushBack(&wasmModuleEntry{hash: hash, size: size})
+		store.size += size
+	})
+	for _, module := range stale {
+		rawdb.DeleteWasmModule(db, module.version, module.hash)
+	}
+
+	store.lock.Lock()
+	store.evict()
+	store.lock.Unlock()
+	log.Info("Opened compiled wasm module store", "modules", store.order.Len(), "size", common.StorageSize(store.size),
+		"limit", common.StorageSize(limit), "stale", len(stale))
+	return store
+}
+
+// ReadModule returns the compiled module of the code, loaded from the store or
+// compiled. The module is validated first if verify is set, unless the store holds
+// a module of the code already validated. A compiled module is stored only if persist
+// is set and the code is committed in the database, so the code deployed or
+// executed on a pending state never fills the store.
+func (s *WasmModuleStore) ReadModule(code []byte, verify, persist bool) (*exec.CompiledModule, error) {
+	start := time.Now()
+	module, err := decodeWasmModule(code)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := crypto.Keccak256Hash(code)
+	if flags, compiled, ok := s.get(hash); ok {
+		if verify && flags&wasmModuleVerified == 0 {
+			if err := validate.VerifyModule(module); err != nil {
+				return nil, err
+			}
+		}
+		decoded, err := exec.DecodeCompiled(module, compiled)
+		if err == nil {
+			wasmModuleHitMeter.Mark(1)
+			wasmModuleLoadTimer.UpdateSince(start)
+			if verify && flags&wasmModuleVerified == 0 && persist && s.committed(hash) {
+				s.put(hash, flags|wasmModuleVerified, compiled)
+			}
+			return decoded, nil
+		}
+		log.Warn("Removed undecodable compiled wasm module", "hash", hash, "err", err)
+		wasmModuleCorruptMeter.Mark(1)
+		s.remove(hash)
+	}
+	wasmModuleMissMeter.Mark(1)
+
+	if verify {
+		if err := validate.VerifyModule(module); err != nil {
+			return nil, err
+		}
+	}
+	decoded, err := exec.CompileModule(module)
+	if err != nil {
+		return nil, err
+	}
+	wasmModuleCompileTimer.UpdateSince(start)
+	if !persist || !s.committed(hash) {
+		return decoded, nil
+	}
+
+	compiled, err := exec.EncodeCompiled(decoded)
+	if err != nil {
+		log.Warn("Failed to encode compiled wasm module", "hash", hash, "err", err)
+		return decoded, nil
+	}
+	var flags byte
+	if verify {
+		flags |= wasmModuleVerified
+	}
+	s.put(hash, flags, compiled)
+	return decoded, nil
+}
+
+// committed reports whether the code of the hash is committed in the database,
+// the code is written by the trie database keyed by its hash.
+func (s *WasmModuleStore) committed(hash common.Hash) bool {
+	ok, err := s.db.Has(hash.Bytes())
+	return err == nil && ok
+}
+
+// get returns the flags and the compiled functions of the module of the code hash,
+// a module failing the integrity check is removed.
+func (s *WasmModuleStore) get(hash common.Hash) (byte, []byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	elem, ok := s.entries[hash]
+	if !ok {
+		return 0, nil, false
+	}
+	data := rawdb.ReadWasmModule(s.db, exec.CompilerVersion, hash)
+	if len(data) < wasmModuleHeaderLen || !bytes.Equal(data[:common.HashLength], crypto.Keccak256(data[common.HashLength:])) {
+		log.Warn("Removed corrupted compiled wasm module", "hash", hash, "size", len(data))
+		wasmModuleCorruptMeter.Mark(1)
+		s.delete(elem)
+		return 0, nil, false
+	}
+	s.order.MoveToBack(elem)
+	return data[common.HashLength], data[wasmModuleHeaderLen:], true
+}
+
+// put stores the module of the code hash, the least recently used modules are
+// evicted if the size limit is exceeded.
+func (s *WasmModuleStore) put(hash common.Hash, flags byte, compiled []byte) {
+	data := make([]byte, wasmModuleHeaderLen+len(compiled))
+	data[common.HashLength] = flags
+	copy(data[wasmModuleHeaderLen:], compiled)
+	copy(data, crypto.Keccak256(data[common.HashLength:]))
+	if len(data) > s.limit {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if elem, ok := s.entries[hash]; ok {
+		entry := elem.Value.(*wasmModuleEntry)
+		s.size += len(data) - entry.size
+		entry.size = len(data)
+		s.order.MoveToBack(elem)
+	} else {
+		s.entries[hash] = s.order.PushBack(&wasmModuleEntry{hash: hash, size: len(data)})
+		s.size += len(data)
+	}
+	rawdb.WriteWasmModule(s.db, exec.CompilerVersion, hash, data)
+	wasmModuleWriteMeter.Mark(1)
+	s.evict()
+}
+
+func (s *WasmModuleStore) remove(hash common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if elem, ok := s.entries[hash]; ok {
+		s.delete(elem)
+	}
+}
+
+// evict removes the least recently used modules until the size limit is met.
+func (s *WasmModuleStore) evict() {
+	for s.size > s.limit && s.order.Len() > 0 {
+		s.delete(s.order.Front())
+		wasmModuleEvictMeter.Mark(1)
+	}
+	wasmModuleSizeGauge.Update(int64(s.size))
+}
+
+func (s *WasmModuleStore) delete(elem *list.Element) {
+	entry := s.order.Remove(elem).(*wasmModuleEntry)
+	delete(s.entries, entry.hash)
+	s.size -= entry.size
+	rawdb.DeleteWasmModule(s.db, exec.CompilerVersion, entry.hash)
+	wasmModuleSizeGauge.Update(int64(s.size))
+}
+
+// Len returns the number of the modules stored.
+func (s *WasmModuleStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.order.Len()
+}
+
+// Size returns the size in bytes of the modules stored.
+func (s *WasmModuleStore) Size() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.size
+}
@@ -0,0 +1,131 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/lru"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec"
+)
+
+func TestWasmModuleStore(t *testing.T) {
+	contract := helloContract(t)
+	defer os.Remove(contract)
+	code, err := ioutil.ReadFile(contract)
+	assert.Nil(t, err)
+	hash := crypto.Keccak256Hash(code)
+
+	db := rawdb.NewMemoryDatabase()
+	store := NewWasmModuleStore(db, 1024*1024)
+
+	// the module of a code not committed, or not persisted, isn't stored
+	_, err = store.ReadModule(code, false, true)
+	assert.Nil(t, err)
+	assert.Zero(t, store.Len())
+	assert.Nil(t, db.Put(hash.Bytes(), code))
+	_, err = store.ReadModule(code, true, false)
+	assert.Nil(t, err)
+	assert.Zero(t, store.Len())
+
+	// the module of the committed code is compiled and stored, not validated
+	_, err = store.ReadModule(code, false, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, store.Len())
+	flags, _, ok := store.get(hash)
+	assert.True(t, ok)
+	assert.Zero(t, flags&wasmModuleVerified)
+
+	// the stored module is validated once
+	_, err = store.ReadModule(code, true, true)
+	assert.Nil(t, err)
+	flags, _, ok = store.get(hash)
+	assert.True(t, ok)
+	assert.NotZero(t, flags&wasmModuleVerified)
+
+	// the module is kept after a restart, the modules of another compiler are removed
+	rawdb.WriteWasmModule(db, exec.CompilerVersion+1, hash, []byte{1})
+	size := store.Size()
+	store = NewWasmModuleStore(db, 1024*1024)
+	assert.Equal(t, 1, store.Len())
+	assert.Equal(t, size, store.Size())
+	assert.Empty(t, rawdb.ReadWasmModule(db, exec.CompilerVersion+1, hash))
+
+	// a corrupted module is removed and compiled again
+	data := rawdb.ReadWasmModule(db, exec.CompilerVersion, hash)
+	data[len(data)-1] ^= 0xff
+	rawdb.WriteWasmModule(db, exec.CompilerVersion, hash, data)
+	_, _, ok = store.get(hash)
+	assert.False(t, ok)
+	assert.Zero(t, store.Len())
+	assert.Empty(t, rawdb.ReadWasmModule(db, exec.CompilerVersion, hash))
+	_, err = store.ReadModule(code, false, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, store.Len())
+
+	// a module failing the validation isn't stored
+	_, err = store.ReadModule([]byte{0x00, 0x61, 0x73}, true, true)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestWasmModuleStore_Evict(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	store := NewWasmModuleStore(db, 2*(wasmModuleHeaderLen+100))
+
+	hashes := []common.Hash{{1}, {2}, {3}}
+	store.put(hashes[0], 0, make([]byte, 100))
+	store.put(hashes[1], 0, make([]byte, 100))
+	assert.Equal(t, 2, store.Len())
+
+	// the least recently used module is evicted
+	_, _, ok := store.get(hashes[0])
+	assert.True(t, ok)
+	store.put(hashes[2], 0, make([]byte, 100))
+	assert.Equal(t, 2, store.Len())
+	assert.Equal(t, 2*(wasmModuleHeaderLen+100), store.Size())
+	_, _, ok = store.get(hashes[1])
+	assert.False(t, ok)
+	assert.Empty(t, rawdb.ReadWasmModule(db, exec.CompilerVersion, hashes[1]))
+
+	// a module exceeding the limit isn't stored
+	store.put(common.Hash{4}, 0, make([]byte, 2*(wasmModuleHeaderLen+100)))
+	assert.Equal(t, 2, store.Len())
+
+	// the store is reduced to the limit after a restart
+	store = NewWasmModuleStore(db, wasmModuleHeaderLen+100)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestWasmModuleStore_Run(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	SetWasmModuleStore(NewWasmModuleStore(db, 1024*1024))
+	defer SetWasmModuleStore(nil)
+
+	engine := newTracedWasmEngine(t, nil)
+	code, err := engine.Run(nil, false)
+	assert.Nil(t, err)
+	engine.evm.StateDB.SetCode(addr2, code)
+	assert.Zero(t, getWasmModuleStore().Len())
+
+	// the module of the committed contract is stored when it is called cold
+	assert.Nil(t, db.Put(crypto.Keccak256(code), code))
+	lru.WasmCache().Purge()
+	engine.contract.DeployContract = false
+	engine.contract.Code = code
+	_, err = engine.Run(callData(t, "add_message"), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, getWasmModuleStore().Len())
+
+	// the contract is called with the module loaded from the store after a restart
+	lru.WasmCache().Purge()
+	SetWasmModuleStore(NewWasmModuleStore(db, 1024*1024))
+	_, err = engine.Run(callData(t, "add_message"), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, getWasmModuleStore().Len())
+}
@@ -0,0 +1,230 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+)
+
+// WasmTracer is a Tracer also tracing the WASM contracts. When the vm is in
+// debug mode, the wasm engine reports to it the instructions, the function
+// calls, the host calls and the memory growth of the contracts.
+type WasmTracer interface {
+	Tracer
+	// CaptureWasmState is called before the instruction op at pc of the compiled
+	// code of the function fn is executed, cost is the gas of the instruction.
+	CaptureWasmState(env *EVM, fn string, pc uint64, op string, gas, cost uint64, memSize int, contract *Contract, depth int) error
+	// CaptureWasmEnter is called when a function or a host function is called.
+	CaptureWasmEnter(env *EVM, call *WasmCall, contract *Contract, depth int) error
+	// CaptureWasmExit is called with the call of CaptureWasmEnter when the function
+	// returns, or when it's unwound by an error.
+	CaptureWasmExit(env *EVM, call *WasmCall, contract *Contract, depth int) error
+	// CaptureWasmMemory is called after the memory was asked to grow by pages,
+	// the size is unchanged if the memory limit is exceeded.
+	CaptureWasmMemory(env *EVM, pages int32, oldSize, newSize int, gas uint64, contract *Contract, depth int) error
+}
+
+// WasmCall is a call of a function of a WASM contract, the arguments of the
+// known host functions are decoded from the memory of the contract when they return.
+type WasmCall struct {
+	Func    string
+	Host    bool
+	Args    []WasmArg
+	Returns []uint64
+	Gas     uint64 // the gas left when the function is called
+	GasCost uint64 // the gas used by the function, the nested calls included
+	Err     error  // the error unwinding the function
+}
+
+// WasmArg is a named argument of a WasmCall
+type WasmArg struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// The types of the WasmStructLog
+const (
+	WasmLogStep   = "step"
+	WasmLogEnter  = "enter"
+	WasmLogExit   = "exit"
+	WasmLogHost   = "host"
+	WasmLogMemory = "memory"
+)
+
+// WasmStructLog is emitted by a WASM contract to the WasmStructLogger, it's an
+// instruction, a function entry or exit, a host call or a memory growth.
+type WasmStructLog struct {
+	Type     string         `json:"type"`
+	Depth    int            `json:"depth"`
+	Contract common.Address `json:"contract"`
+	Func     string         `json:"func"`
+	Pc       uint64         `json:"pc,omitempty"`
+	Op       string         `json:"op,omitempty"`
+	Gas      uint64         `json:"gas"`
+	GasCost  uint64         `json:"gasCost"`
+	MemSize  int            `json:"memSize,omitempty"`
+	Args     []WasmArg      `json:"args,omitempty"`
+	Returns  []uint64       `json:"returns,omitempty"`
+	Pages    int32          `json:"pages,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// WasmStructLogger is a WasmTracer collecting the struct logs of the WASM contracts,
+// the host calls are logged once with the arguments and the gas cost of the call.
+// The EVM opcodes aren't logged.
+type WasmStructLogger struct {
+	cfg LogConfig
+
+	logs  []WasmStructLog
+	hosts []int // the indexes of the host calls in progress
+
+	output []byte
+	err    error
+}
+
+// NewWasmStructLogger returns a new WASM logger, the instructions aren't logged
+// if DisableWasmSteps is set.
+func NewWasmStructLogger(cfg *LogConfig) *WasmStructLogger {
+	logger := &WasmStructLogger{}
+	if cfg != nil {
+		logger.cfg = *cfg
+	}
+	return logger
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (l *WasmStructLogger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface, the EVM opcodes aren't logged.
+func (l *WasmStructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, rData []byte, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (l *WasmStructLogger) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (l *WasmStructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	l.output = output
+	l.err = err
+	return nil
+}
+
+// CaptureWasmState logs the instruction unless the steps are disabled.
+func (l *WasmStructLogger) CaptureWasmState(env *EVM, fn string, pc uint64, op string, gas, cost uint64, memSize int, contract *Contract, depth int) error {
+	if l.cfg.DisableWasmSteps {
+		return nil
+	}
+	return l.append(WasmStructLog{
+		Type:     WasmLogStep,
+		Depth:    depth,
+		Contract: contract.Address(),
+		Func:     fn,
+		Pc:       pc,
+		Op:       op,
+		Gas:      gas,
+		GasCost:  cost,
+		MemSize:  memSize,
+	})
+}
+
+// CaptureWasmEnter logs the entry of a function, or the host call
+// completed by CaptureWasmExit.
+func (l *WasmStructLogger) CaptureWasmEnter(env *EVM, call *WasmCall, contract *Contract, depth int) error {
+	typ := WasmLogEnter
+	if call.Host {
+		typ = WasmLogHost
+	}
+	if err := l.append(WasmStructLog{
+		Type:     typ,
+		Depth:    depth,
+		Contract: contract.Address(),
+		Func:     call.Func,
+		Gas:      call.Gas,
+		Args:     call.Args,
+	}); err != nil {
+		if call.Host {
+			l.hosts = append(l.hosts, -1)
+		}
+		return err
+	}
+	if call.Host {
+		l.hosts = append(l.hosts, len(l.logs)-1)
+	}
+	return nil
+}
+
+// CaptureWasmExit logs the exit of a function, or completes the host call.
+func (l *WasmStructLogger) CaptureWasmExit(env *EVM, call *WasmCall, contract *Contract, depth int) error {
+	var errString string
+	if call.Err != nil {
+		errString = call.Err.Error()
+	}
+	if call.Host {
+		if len(l.hosts) == 0 {
+			return errors.New("no host call in progress")
+		}
+		index := l.hosts[len(l.hosts)-1]
+		l.hosts = l.hosts[:len(l.hosts)-1]
+		if index < 0 {
+			return errTraceLimitReached
+		}
+		host := &l.logs[index]
+		host.Args = call.Args
+		host.Returns = call.Returns
+		host.GasCost = call.GasCost
+		host.Error = errString
+		return nil
+	}
+	return l.append(WasmStructLog{
+		Type:     WasmLogExit,
+		Depth:    depth,
+		Contract: contract.Address(),
+		Func:     call.Func,
+		Gas:      call.Gas - call.GasCost,
+		GasCost:  call.GasCost,
+		Returns:  call.Returns,
+		Error:    errString,
+	})
+}
+
+// CaptureWasmMemory logs the growth of the memory.
+func (l *WasmStructLogger) CaptureWasmMemory(env *EVM, pages int32, oldSize, newSize int, gas uint64, contract *Contract, depth int) error {
+	log := WasmStructLog{
+		Type:     WasmLogMemory,
+		Depth:    depth,
+		Contract: contract.Address(),
+		Gas:      gas,
+		MemSize:  newSize,
+		Pages:    pages,
+	}
+	if oldSize == newSize && pages != 0 {
+		log.Error = fmt.Sprintf("memory limit exceeded at %d bytes", oldSize)
+	}
+	return l.append(log)
+}
+
+func (l *WasmStructLogger) append(log WasmStructLog) error {
+	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+		return errTraceLimitReached
+	}
+	l.logs = append(l.logs, log)
+	return nil
+}
+
+// StructLogs returns the captured log entries.
+func (l *WasmStructLogger) StructLogs() []WasmStructLog { return l.logs }
+
+// Error returns the VM error captured by the trace.
+func (l *WasmStructLogger) Error() error { return l.err }
+
+// Output returns the VM return value captured by the trace.
+func (l *WasmStructLogger) Output() []byte { return l.output }
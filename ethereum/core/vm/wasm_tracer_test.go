@@ -0,0 +1,165 @@
+package vm
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+)
+
+// helloContract returns the file of the hello contract importing the host functions
+// by their current names, the contract of the testdata imports them by their former
+// platon_ names.
+func helloContract(t *testing.T) string {
+	raw, err := ioutil.ReadFile("./testdata/contract_hello.wasm")
+	assert.Nil(t, err)
+	module, err := wasm.DecodeModule(bytes.NewReader(raw))
+	assert.Nil(t, err)
+	for i, entry := range module.Import.Entries {
+		if strings.HasPrefix(entry.FieldName, "platon_") {
+			module.Import.Entries[i].FieldName = "phoenixchain_" + strings.TrimPrefix(entry.FieldName, "platon_")
+		}
+	}
+	file, err := ioutil.TempFile("", "contract_hello")
+	assert.Nil(t, err)
+	defer file.Close()
+	assert.Nil(t, wasm.EncodeModule(file, module))
+	return file.Name()
+}
+
+func newTracedWasmEngine(t *testing.T, tracer Tracer) *wagonEngine {
+	contract := helloContract(t)
+	defer os.Remove(contract)
+	return &wagonEngine{
+		evm: &EVM{Context: Context{
+			CanTransfer: func(db StateDB, addr common.Address, amount *big.Int) bool {
+				return db.GetBalance(addr).Cmp(amount) >= 0
+			},
+			Transfer: func(db StateDB, sender, recipient common.Address, amount *big.Int) {
+				db.SubBalance(sender, amount)
+				db.AddBalance(recipient, amount)
+			},
+			Ctx: context.TODO(),
+		},
+			StateDB: &mock.MockStateDB{
+				Balance: map[common.Address]*big.Int{
+					addr1: big.NewInt(2000),
+					addr2: big.NewInt(1000),
+				},
+				State:    map[common.Address]map[string][]byte{},
+				Code:     map[common.Address][]byte{},
+				CodeHash: map[common.Address][]byte{},
+				Journal:  mock.NewJournal(),
+			}},
+		config: Config{WasmType: Wagon, Debug: true, Tracer: tracer},
+		contract: &Contract{
+			self:           &AccountRef{1, 2, 3},
+			Gas:            1000000,
+			Code:           deployData(t, "init", contract),
+			CodeAddr:       &addr2,
+			CodeHash:       common.ZeroHash,
+			DeployContract: true,
+		},
+	}
+}
+
+func TestWasmStructLogger(t *testing.T) {
+	logger := NewWasmStructLogger(nil)
+	engine := newTracedWasmEngine(t, logger)
+	code, err := engine.Run(nil, false)
+	assert.Nil(t, err)
+	engine.evm.StateDB.SetCode(addr2, code)
+
+	logger.logs = nil
+	engine.contract.DeployContract = false
+	engine.contract.Gas = 1000000
+	_, err = engine.Run(callData(t, "add_message"), false)
+	assert.Nil(t, err)
+
+	logs := logger.StructLogs()
+	if assert.NotEmpty(t, logs) {
+		assert.Equal(t, WasmLogEnter, logs[0].Type)
+		assert.Equal(t, callEntryName, logs[0].Func)
+		assert.Equal(t, uint64(1000000), logs[0].Gas)
+		last := logs[len(logs)-1]
+		assert.Equal(t, WasmLogExit, last.Type)
+		assert.Equal(t, callEntryName, last.Func)
+		assert.Equal(t, 1000000-engine.contract.Gas, last.GasCost)
+	}
+
+	var (
+		depth, steps int
+		setState     *WasmStructLog
+	)
+	for i, log := range logs {
+		switch log.Type {
+		case WasmLogEnter:
+			depth++
+		case WasmLogExit:
+			depth--
+		case WasmLogStep:
+			steps++
+			assert.NotEmpty(t, log.Op)
+			assert.NotZero(t, log.MemSize)
+		case WasmLogHost:
+			if log.Func == "phoenixchain_set_state" {
+				setState = &logs[i]
+			}
+		}
+		assert.Equal(t, engine.contract.Address(), log.Contract)
+	}
+	assert.Zero(t, depth, "the functions entered and exited don't match")
+	assert.NotZero(t, steps)
+	if assert.NotNil(t, setState, "no phoenixchain_set_state host call") {
+		assert.Equal(t, []string{"key", "value"}, []string{setState.Args[0].Name, setState.Args[1].Name})
+		assert.NotZero(t, setState.GasCost)
+	}
+
+	// the instructions aren't logged if disabled
+	logger = NewWasmStructLogger(&LogConfig{DisableWasmSteps: true})
+	engine.config.Tracer = logger
+	_, err = engine.Run(callData(t, "add_message"), false)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, logger.StructLogs())
+	for _, log := range logger.StructLogs() {
+		assert.NotEqual(t, WasmLogStep, log.Type)
+	}
+}
+
+func TestWasmStructLogger_Fault(t *testing.T) {
+	logger := NewWasmStructLogger(&LogConfig{DisableWasmSteps: true})
+	engine := newTracedWasmEngine(t, logger)
+	code, err := engine.Run(nil, false)
+	assert.Nil(t, err)
+	engine.evm.StateDB.SetCode(addr2, code)
+
+	// the storage is written in a read only call
+	logger.logs = nil
+	engine.contract.DeployContract = false
+	_, err = engine.Run(callData(t, "add_message"), true)
+	assert.Equal(t, ErrWASMWriteProtection, err)
+
+	logs := logger.StructLogs()
+	if assert.NotEmpty(t, logs) {
+		last := logs[len(logs)-1]
+		assert.Equal(t, WasmLogExit, last.Type)
+		assert.Equal(t, callEntryName, last.Func)
+		assert.Equal(t, ErrWASMWriteProtection.Error(), last.Error)
+	}
+	for _, log := range logs {
+		if log.Type == WasmLogHost && log.Func == "phoenixchain_set_state" {
+			assert.Equal(t, ErrWASMWriteProtection.Error(), log.Error)
+			return
+		}
+	}
+	t.Error("no faulted phoenixchain_set_state host call")
+}
@@ -38,6 +38,10 @@ const (
 	// and reexecute to produce missing historical state necessary to run a specific
 	// trace.
 	defaultTraceReexec = uint64(128)
+
+	// wasmCallTracer is the name of the tracer collecting the struct logs of the
+	// WASM contracts instead of the EVM ones.
+	wasmCallTracer = "wasmCallTracer"
 )
 
 // TraceConfig holds extra parameters to trace functions.
@@ -273,7 +277,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				traced += uint64(len(txs))
 			}
 			// Generate the next state snapshot fast without tracing
-			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, api.vmConfig())
 			if err != nil {
 				failed = err
 				break
@@ -485,7 +489,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		msg, _ := tx.AsMessage(signer)
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 
-		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vm.Config{})
+		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), api.vmConfig())
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
 			failed = err
 			break
@@ -553,7 +557,7 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 			msg, _ = tx.AsMessage(signer)
 			vmctx  = core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 
-			vmConf vm.Config
+			vmConf = api.vmConfig()
 			dump   *os.File
 			writer *bufio.Writer
 			err    error
@@ -571,10 +575,8 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 
 			// Swap out the noop logger to the standard tracer
 			writer = bufio.NewWriter(dump)
-			vmConf = vm.Config{
-				Debug:  true,
-				Tracer: vm.NewJSONLogger(&logConfig, writer),
-			}
+			vmConf = api.vmConfig()
+			vmConf.Debug, vmConf.Tracer = true, vm.NewJSONLogger(&logConfig, writer)
 		}
 		// Execute the transaction and flush any traces to disk
 		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vmConf)
@@ -657,7 +659,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 		if block = api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1); block == nil {
 			return nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
 		}
-		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, api.vmConfig())
 		if err != nil {
 			return nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
 		}
@@ -710,6 +712,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		err    error
 	)
 	switch {
+	case config != nil && config.Tracer != nil && *config.Tracer == wasmCallTracer:
+		tracer = vm.NewWasmStructLogger(config.LogConfig)
+
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
@@ -737,7 +742,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
 	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+	vmConf := api.vmConfig()
+	vmConf.Debug, vmConf.Tracer = true, tracer
+	vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vmConf)
 
 	res, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
@@ -753,6 +760,14 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
+	case *vm.WasmStructLogger:
+		return &ethapi.WasmExecutionResult{
+			Gas:         res.UsedGas,
+			Failed:      res.Failed(),
+			ReturnValue: fmt.Sprintf("%x", res.Return()),
+			StructLogs:  tracer.StructLogs(),
+		}, nil
+
 	case *tracers.Tracer:
 		return tracer.GetResult()
 
@@ -761,6 +776,12 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	}
 }
 
+// vmConfig returns the config of the vm replaying the transactions, the WASM
+// contracts can't run without the wasm engine of the node.
+func (api *PrivateDebugAPI) vmConfig() vm.Config {
+	return vm.Config{WasmType: vm.Str2WasmType(api.eth.config.VMWasmType)}
+}
+
 // computeTxEnv returns the execution environment of a certain transaction.
 func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, reexec uint64) (core.Message, vm.Context, *state.StateDB, error) {
 	// Create the parent state database
@@ -786,7 +807,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 			return msg, context, statedb, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
-		vmenv := vm.NewEVM(context, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vm.Config{})
+		vmenv := vm.NewEVM(context, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), api.vmConfig())
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
 			return nil, vm.Context{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
@@ -35,6 +35,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/miner"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/reporter"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/node"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
@@ -81,6 +82,7 @@ type Ethereum struct {
 	APIBackend *EthAPIBackend
 
 	miner         *miner.Miner
+	reporter      *reporter.Reporter
 	gasPrice      *big.Int
 	networkID     uint64
 	netRPCService *ethapi2.PublicNetAPI
@@ -113,6 +115,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		return nil, err
 	}
 	snapshotdb.SetDBOptions(config.DatabaseCache, config.DatabaseHandles)
+	snapshotdb.SetDBArchive(config.DBArchive)
 
 	snapshotBaseDB, err := snapshotdb.Open(ctx.ResolvePath(snapshotdb.DBPath), config.DatabaseCache, config.DatabaseHandles, true)
 	if err != nil {
@@ -232,6 +235,10 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		}
 	}
 
+	if config.VMWasmModuleStore > 0 {
+		vm.SetWasmModuleStore(vm.NewWasmModuleStore(chainDb, config.VMWasmModuleStore*1024*1024))
+	}
+
 	var (
 		vmConfig = vm.Config{
 			ConsoleOutput: config.Debug,
@@ -351,6 +358,22 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, gpoParams)
 
+	if config.Reporter.Enabled() {
+		engine, ok := eth.engine.(*pbft.Pbft)
+		if !ok {
+			return nil, errors.New("evidence reporter requires the pbft consensus engine")
+		}
+		reporterConfig := config.Reporter
+		if reporterConfig.GasPrice == nil {
+			reporterConfig.GasPrice = config.Miner.GasPrice
+		}
+		eth.reporter, err = reporter.New(reporterConfig, chainConfig.ChainID, reporter.NewBackend(eth.blockchain, eth.txPool),
+			engine.EvidencePool(), eth.accountManager, ctx.ResolvePath("reporter"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create evidence reporter: %v", err)
+		}
+	}
+
 	return eth, nil
 }
 
@@ -428,6 +451,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   xplugin.NewPublicDPOSAPI(),
+		}, {
+			Namespace: "ppos",
+			Version:   "1.0",
+			Service:   xplugin.NewPublicPPOSAPI(),
+			Public:    true,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -585,6 +613,9 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	}
 	srvr.StartWatching(s.eventMux)
 
+	if s.reporter != nil {
+		s.reporter.Start()
+	}
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
@@ -602,11 +633,15 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.reporter != nil {
+		s.reporter.Stop()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.blockchain.Stop()
 	s.engine.Close()
 	core.GetReactorInstance().Close()
+	vm.SetWasmModuleStore(nil)
 	s.chainDb.Close()
 	s.eventMux.Stop()
 	return nil
@@ -4,6 +4,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/eth/downloader"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/eth/gasprice"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/miner"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/reporter"
 	"math/big"
 	"time"
 
@@ -20,6 +21,7 @@ var DefaultConfig = Config{
 		WalMode:           true,
 		PeerMsgQueueSize:  1024,
 		EvidenceDir:       "evidence",
+		ProtectionDir:     "protection",
 		MaxPingLatency:    5000,
 		MaxQueuesLimit:    4096,
 		BlacklistDeadline: 60,
@@ -38,6 +40,7 @@ var DefaultConfig = Config{
 	DBGCMpt:           true,
 	DBGCBlock:         10,
 	VMWasmType:        "wagon",
+	VMWasmModuleStore: 256,
 	VmTimeoutDuration: 0, // default 0 ms for vm exec timeout
 	Miner: miner.Config{
 		GasFloor: configs.GenesisGasLimit,
@@ -107,10 +110,12 @@ type Config struct {
 	DBGCTimeout  time.Duration
 	DBGCMpt      bool
 	DBGCBlock    int
+	DBArchive    bool
 
 	// VM options
 	VMWasmType        string
 	VmTimeoutDuration uint64
+	VMWasmModuleStore int // Megabytes of the compiled WASM modules kept in the database, 0 disables it
 
 	// Mining options
 	Miner	miner.Config
@@ -144,6 +149,9 @@ type Config struct {
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
+	// Evidence reporter options
+	Reporter reporter.Config
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
@@ -11,6 +11,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/miner"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/reporter"
 )
 
 // MarshalTOML marshals as TOML.
@@ -35,8 +36,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DBGCTimeout              time.Duration
 		DBGCMpt                  bool
 		DBGCBlock                int
+		DBArchive                bool
 		VMWasmType               string
 		VmTimeoutDuration        uint64
+		VMWasmModuleStore        int
 		Miner                    miner.Config
 		MiningLogAtDepth         uint
 		TxChanSize               int
@@ -60,6 +63,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DefaultBroadcastInterval time.Duration
 		TxPool             core.TxPoolConfig
 		GPO                gasprice.Config
+		Reporter           reporter.Config
 		DocRoot            string `toml:"-"`
 		Debug                    bool
 		RPCGasCap                *big.Int `toml:",omitempty"`
@@ -84,8 +88,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DBGCTimeout = c.DBGCTimeout
 	enc.DBGCMpt = c.DBGCMpt
 	enc.DBGCBlock = c.DBGCBlock
+	enc.DBArchive = c.DBArchive
 	enc.VMWasmType = c.VMWasmType
 	enc.VmTimeoutDuration = c.VmTimeoutDuration
+	enc.VMWasmModuleStore = c.VMWasmModuleStore
 	enc.Miner = c.Miner
 	enc.MiningLogAtDepth = c.MiningLogAtDepth
 	enc.TxChanSize = c.TxChanSize
@@ -109,6 +115,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DefaultBroadcastInterval = c.DefaultBroadcastInterval
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
+	enc.Reporter = c.Reporter
 	enc.DocRoot = c.DocRoot
 	enc.Debug = c.Debug
 	enc.RPCGasCap = c.RPCGasCap
@@ -137,8 +144,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DBGCTimeout              *time.Duration
 		DBGCMpt                  *bool
 		DBGCBlock                *int
+		DBArchive                *bool
 		VMWasmType               *string
 		VmTimeoutDuration        *uint64
+		VMWasmModuleStore        *int
 		Miner                    *miner.Config
 		MiningLogAtDepth         *uint
 		TxChanSize               *int
@@ -162,6 +171,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DefaultBroadcastInterval *time.Duration
 		TxPool                   *core.TxPoolConfig
 		GPO                      *gasprice.Config
+		Reporter                 *reporter.Config
 		DocRoot                  *string `toml:"-"`
 		Debug                    *bool
 		RPCGasCap                *big.Int `toml:",omitempty"`
@@ -227,12 +237,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DBGCBlock != nil {
 		c.DBGCBlock = *dec.DBGCBlock
 	}
+	if dec.DBArchive != nil {
+		c.DBArchive = *dec.DBArchive
+	}
 	if dec.VMWasmType != nil {
 		c.VMWasmType = *dec.VMWasmType
 	}
 	if dec.VmTimeoutDuration != nil {
 		c.VmTimeoutDuration = *dec.VmTimeoutDuration
 	}
+	if dec.VMWasmModuleStore != nil {
+		c.VMWasmModuleStore = *dec.VMWasmModuleStore
+	}
 	if dec.Miner != nil {
 		c.Miner = *dec.Miner
 	}
@@ -302,6 +318,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
+	if dec.Reporter != nil {
+		c.Reporter = *dec.Reporter
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
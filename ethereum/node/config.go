@@ -358,15 +358,21 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 
 // BlsKey retrieves the currently configured private key of the node,
 // falling back to the one found in the configured
-// data folder. If no key can be found, a new one is generated.
-func (c *Config) BlsKey() *bls.SecretKey {
+// data folder, an encrypted key file is unlocked by auth. If no key
+// file exists, a new one is generated. An existing key file that can't
+// be loaded is fatal, it is never overwritten.
+func (c *Config) BlsKey(auth string) *bls.SecretKey {
 	// Generate ephemeral key if no datadir is being used.
 	if c.DataDir == "" {
 		return bls.GenerateKey()
 	}
 
 	keyfile := c.ResolvePath(datadirBlsKey)
-	if key, err := bls.LoadBLS(keyfile); err == nil {
+	if _, err := os.Stat(keyfile); err == nil {
+		key, err := keystore.LoadBlsKey(keyfile, auth)
+		if err != nil {
+			log.Crit(fmt.Sprintf("Failed to load bls key %s: %v", keyfile, err))
+		}
 		return key
 	}
 
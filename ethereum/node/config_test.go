@@ -8,8 +8,10 @@ import (
 	"runtime"
 	"testing"
 
-	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
 )
 
 // Tests that datadirs can be successfully created, be them manually configured
@@ -144,3 +146,39 @@ func TestNodeKeyPersistency(t *testing.T) {
 		t.Fatalf("ephemeral node key persisted to disk")
 	}
 }
+
+// Tests that an encrypted bls key in the data directory is unlocked and never
+// replaced by a generated one.
+func TestBlsKeyEncrypted(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	dir, err := ioutil.TempDir("", "node-test")
+	if err != nil {
+		t.Fatalf("failed to create temporary data directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := bls.GenerateKey()
+	keyjson, err := keystore.EncryptBlsKey(key, "foo", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to encrypt bls key: %v", err)
+	}
+	keyfile := filepath.Join(dir, "unit-test", datadirBlsKey)
+	if err := os.MkdirAll(filepath.Dir(keyfile), 0700); err != nil {
+		t.Fatalf("failed to create instance directory: %v", err)
+	}
+	if err := ioutil.WriteFile(keyfile, keyjson, 0600); err != nil {
+		t.Fatalf("failed to write bls key: %v", err)
+	}
+
+	config := &Config{Name: "unit-test", DataDir: dir}
+	if loaded := config.BlsKey("foo"); !bytes.Equal(loaded.Serialize(), key.Serialize()) {
+		t.Fatalf("loaded bls key mismatch: have %x, want %x", loaded.Serialize(), key.Serialize())
+	}
+	blob, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		t.Fatalf("failed to read bls key: %v", err)
+	}
+	if !bytes.Equal(blob, keyjson) {
+		t.Fatalf("encrypted bls key overwritten")
+	}
+}
@@ -0,0 +1,50 @@
+package reporter
+
+import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/plugin"
+)
+
+// chainBackend implements Backend on top of the local chain and transaction pool.
+type chainBackend struct {
+	chain  *core.BlockChain
+	txPool *core.TxPool
+}
+
+// NewBackend creates a Backend reading the chain and submitting to the txPool.
+func NewBackend(chain *core.BlockChain, txPool *core.TxPool) Backend {
+	return &chainBackend{chain: chain, txPool: txPool}
+}
+
+func (b *chainBackend) CurrentHeader() *types.Header {
+	return b.chain.CurrentHeader()
+}
+
+func (b *chainBackend) Slashed(header *types.Header, nodeID discover.NodeID, blockNumber uint64, dupType consensus.EvidenceType) (bool, error) {
+	state, err := b.chain.StateAt(header.Root)
+	if err != nil {
+		return false, err
+	}
+	txHash, err := plugin.SlashInstance().CheckDuplicateSign(nodeID, blockNumber, dupType, state)
+	if err != nil {
+		return false, err
+	}
+	return len(txHash) > 0, nil
+}
+
+func (b *chainBackend) MaxEvidenceAge(header *types.Header) (uint32, error) {
+	return gov.GovernMaxEvidenceAge(header.Number.Uint64(), header.Hash())
+}
+
+func (b *chainBackend) Nonce(addr common.Address) uint64 {
+	return b.txPool.Nonce(addr)
+}
+
+func (b *chainBackend) SendTx(tx *types.Transaction) error {
+	return b.txPool.AddLocal(tx)
+}
@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+)
+
+var (
+	// Evidence record prefix
+	recordPrefix = []byte("r")
+)
+
+// Status of an evidence record.
+const (
+	statusPending   uint8 = iota // waiting to be reported
+	statusSubmitted              // report transaction sent, waiting to be slashed
+	statusReported               // slashed on chain
+	statusExpired                // older than MaxEvidenceAge
+	statusFailed                 // gave up after maxAttempts
+)
+
+// record is the persisted state of an evidence to report.
+type record struct {
+	Type        uint8
+	NodeID      discover.NodeID
+	BlockNumber uint64
+	Data        string // json encoded evidence, the payload of reportDuplicateSign
+	Status      uint8
+	TxHash      common.Hash
+	SubmittedAt uint64 // block number of the last submission
+	Attempts    uint8
+}
+
+// done reports whether nothing is left to do with the record.
+func (r *record) done() bool {
+	return r.Status != statusPending && r.Status != statusSubmitted
+}
+
+// recordKey builds the key of an evidence, evidences are deduplicated on chain by
+// node, block number and type so is the reporter.
+func recordKey(nodeID discover.NodeID, blockNumber uint64, dupType consensus.EvidenceType) []byte {
+	key := append(append([]byte{}, recordPrefix...), nodeID.Bytes()...)
+	return append(append(key, common.Uint64ToBytes(blockNumber)...), uint8(dupType))
+}
+
+func (r *record) key() []byte {
+	return recordKey(r.NodeID, r.BlockNumber, consensus.EvidenceType(r.Type))
+}
+
+// database persists the evidence records so that the pending reports survive
+// restarts.
+type database struct {
+	db *leveldb.DB
+}
+
+func openDatabase(path string) (*database, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &database{db: db}, nil
+}
+
+func (d *database) has(key []byte) (bool, error) {
+	return d.db.Has(key, nil)
+}
+
+func (d *database) put(r *record) error {
+	buf, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return d.db.Put(r.key(), buf, &opt.WriteOptions{Sync: true})
+}
+
+// records returns all the records, done or not.
+func (d *database) records() ([]*record, error) {
+	var records []*record
+	it := d.db.NewIterator(util.BytesPrefix(recordPrefix), nil)
+	defer it.Release()
+	for it.Next() {
+		var r record
+		if err := rlp.DecodeBytes(it.Value(), &r); err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, it.Error()
+}
+
+func (d *database) close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,280 @@
+// Package reporter implements the automatic submission of the duplicate sign
+// evidences detected by the consensus engine to the slashing contract.
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/consensus"
+	commonvm "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
+)
+
+const (
+	// Interval between two scans of the evidences
+	reportInterval = 10 * time.Second
+
+	// Number of blocks after which a report that did not lead to a slash is
+	// submitted again, the transaction may have been dropped or reverted.
+	resubmitBlocks = 100
+
+	// Maximum number of submissions of a report
+	maxAttempts = 3
+)
+
+var errNoAccount = errors.New("evidence reporter account not set")
+
+// Config is the configuration parameters of the evidence reporter.
+type Config struct {
+	Account  common.Address `toml:",omitempty"` // Account signing the report transactions, the reporter is disabled if empty
+	GasPrice *big.Int       `toml:",omitempty"` // Gas price of the report transactions
+}
+
+// Enabled reports whether an account is configured to report evidences.
+func (c *Config) Enabled() bool {
+	return c.Account != (common.Address{})
+}
+
+// EvidenceSource provides the duplicate signatures detected by the consensus engine.
+type EvidenceSource interface {
+	Evidences() consensus.Evidences
+}
+
+// Backend wraps the chain and transaction pool access required by the reporter.
+type Backend interface {
+	// CurrentHeader returns the head of the chain.
+	CurrentHeader() *types.Header
+	// Slashed reports whether the duplicate sign was already punished as of header.
+	Slashed(header *types.Header, nodeID discover.NodeID, blockNumber uint64, dupType consensus.EvidenceType) (bool, error)
+	// MaxEvidenceAge returns the validity period of the evidences in epochs as of header.
+	MaxEvidenceAge(header *types.Header) (uint32, error)
+	// Nonce returns the next nonce of addr, including the pending transactions.
+	Nonce(addr common.Address) uint64
+	// SendTx submits a signed transaction.
+	SendTx(tx *types.Transaction) error
+}
+
+// Reporter submits a reportDuplicateSign transaction for each evidence that is
+// neither slashed nor expired, and keeps track of them in its database.
+type Reporter struct {
+	config   Config
+	chainID  *big.Int
+	backend  Backend
+	source   EvidenceSource
+	accounts *accounts.Manager
+	db       *database
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a reporter persisting its state to the database at path.
+func New(config Config, chainID *big.Int, backend Backend, source EvidenceSource, am *accounts.Manager, path string) (*Reporter, error) {
+	if !config.Enabled() {
+		return nil, errNoAccount
+	}
+	if _, err := am.Find(accounts.Account{Address: config.Account}); err != nil {
+		return nil, err
+	}
+	if config.GasPrice == nil {
+		config.GasPrice = new(big.Int)
+	}
+	db, err := openDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reporter{
+		config:   config,
+		chainID:  chainID,
+		backend:  backend,
+		source:   source,
+		accounts: am,
+		db:       db,
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+// Start starts scanning the evidences in the background.
+func (r *Reporter) Start() {
+	log.Info("Starting evidence reporter", "account", r.config.Account)
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop terminates the reporter and closes its database.
+func (r *Reporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+	r.db.close()
+	log.Info("Evidence reporter stopped")
+}
+
+func (r *Reporter) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		r.update()
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// update records the new evidences and advances the reports that are not done.
+func (r *Reporter) update() {
+	if err := r.collect(); err != nil {
+		log.Error("Failed to collect evidences", "err", err)
+		return
+	}
+	records, err := r.db.records()
+	if err != nil {
+		log.Error("Failed to load evidence records", "err", err)
+		return
+	}
+	header := r.backend.CurrentHeader()
+	age, err := r.backend.MaxEvidenceAge(header)
+	if err != nil {
+		log.Error("Failed to query MaxEvidenceAge", "number", header.Number, "err", err)
+		return
+	}
+	for _, rec := range records {
+		if rec.done() {
+			continue
+		}
+		if err := r.process(header, age, rec); err != nil {
+			log.Error("Failed to report evidence", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber,
+				"type", rec.Type, "err", err)
+		}
+	}
+}
+
+// collect adds a pending record for the evidences seen for the first time.
+func (r *Reporter) collect() error {
+	for _, ev := range r.source.Evidences() {
+		key := recordKey(ev.NodeID(), ev.BlockNumber(), ev.Type())
+		if ok, err := r.db.has(key); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		rec := &record{
+			Type:        uint8(ev.Type()),
+			NodeID:      ev.NodeID(),
+			BlockNumber: ev.BlockNumber(),
+			Data:        string(data),
+		}
+		if err := r.db.put(rec); err != nil {
+			return err
+		}
+		log.Info("New duplicate sign evidence", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber, "type", rec.Type)
+	}
+	return nil
+}
+
+// process moves a record forward according to the state of the chain at header.
+func (r *Reporter) process(header *types.Header, age uint32, rec *record) error {
+	number := header.Number.Uint64()
+	if expired(number, rec.BlockNumber, age) {
+		log.Warn("Duplicate sign evidence expired", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber, "type", rec.Type)
+		rec.Status = statusExpired
+		return r.db.put(rec)
+	}
+	slashed, err := r.backend.Slashed(header, rec.NodeID, rec.BlockNumber, consensus.EvidenceType(rec.Type))
+	if err != nil {
+		return err
+	}
+	if slashed {
+		log.Info("Duplicate sign evidence reported", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber,
+			"type", rec.Type, "txHash", rec.TxHash)
+		rec.Status = statusReported
+		return r.db.put(rec)
+	}
+	if rec.Status == statusSubmitted && number < rec.SubmittedAt+resubmitBlocks {
+		return nil
+	}
+	if rec.Attempts >= maxAttempts {
+		log.Warn("Giving up reporting duplicate sign evidence", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber,
+			"type", rec.Type, "attempts", rec.Attempts)
+		rec.Status = statusFailed
+		return r.db.put(rec)
+	}
+
+	tx, err := r.newReportTx(rec)
+	if err != nil {
+		return err
+	}
+	if err := r.backend.SendTx(tx); err != nil {
+		return err
+	}
+	log.Info("Submitted duplicate sign report", "nodeID", rec.NodeID.TerminalString(), "blockNumber", rec.BlockNumber,
+		"type", rec.Type, "txHash", tx.Hash())
+	rec.Status = statusSubmitted
+	rec.TxHash = tx.Hash()
+	rec.SubmittedAt = number
+	rec.Attempts++
+	return r.db.put(rec)
+}
+
+// newReportTx builds and signs the reportDuplicateSign transaction of rec.
+func (r *Reporter) newReportTx(rec *record) (*types.Transaction, error) {
+	data, err := encodeReport(rec.Type, rec.Data)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := core.IntrinsicGas(data, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	gas += configs.SlashingGas + configs.ReportDuplicateSignGas + configs.DuplicateEvidencesGas
+
+	account := accounts.Account{Address: r.config.Account}
+	wallet, err := r.accounts.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTransaction(r.backend.Nonce(account.Address), commonvm.SlashingContractAddr, new(big.Int), gas, r.config.GasPrice, data)
+	return wallet.SignTx(account, tx, r.chainID)
+}
+
+// encodeReport encodes the input of the reportDuplicateSign function of the
+// slashing contract.
+func encodeReport(dupType uint8, data string) ([]byte, error) {
+	var params [][]byte
+	for _, p := range []interface{}{uint16(vm.TxReportDuplicateSign), dupType, data} {
+		b, err := rlp.EncodeToBytes(p)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, b)
+	}
+	return rlp.EncodeToBytes(params)
+}
+
+// expired reports whether an evidence of blockNumber can no longer be reported
+// at number, following the check of the slashing plugin.
+func expired(number, blockNumber uint64, age uint32) bool {
+	blocksOfEpoch := xutil.CalcBlocksEachEpoch()
+	invalidNum := xutil.CalculateEpoch(blockNumber) * blocksOfEpoch
+	return invalidNum < number && number-invalidNum > blocksOfEpoch*uint64(age)
+}
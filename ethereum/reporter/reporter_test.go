@@ -0,0 +1,152 @@
+package reporter
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
+)
+
+type testEvidence struct {
+	Number uint64          `json:"number"`
+	Node   discover.NodeID `json:"node"`
+}
+
+func (e *testEvidence) Equal(ev consensus.Evidence) bool { return false }
+func (e *testEvidence) BlockNumber() uint64              { return e.Number }
+func (e *testEvidence) Epoch() uint64                    { return 1 }
+func (e *testEvidence) ViewNumber() uint64               { return 0 }
+func (e *testEvidence) Hash() []byte                     { return nil }
+func (e *testEvidence) NodeID() discover.NodeID          { return e.Node }
+func (e *testEvidence) BlsPubKey() *bls.PublicKey        { return nil }
+func (e *testEvidence) Validate() error                  { return nil }
+func (e *testEvidence) Type() consensus.EvidenceType     { return 1 }
+func (e *testEvidence) ValidateMsg() bool                { return true }
+
+type testSource struct {
+	evidences consensus.Evidences
+}
+
+func (s *testSource) Evidences() consensus.Evidences { return s.evidences }
+
+type testBackend struct {
+	number  uint64
+	slashed map[uint64]bool
+	sent    []*types.Transaction
+}
+
+func (b *testBackend) CurrentHeader() *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(b.number)}
+}
+
+func (b *testBackend) Slashed(header *types.Header, nodeID discover.NodeID, blockNumber uint64, dupType consensus.EvidenceType) (bool, error) {
+	return b.slashed[blockNumber], nil
+}
+
+func (b *testBackend) MaxEvidenceAge(header *types.Header) (uint32, error) {
+	return 1, nil
+}
+
+func (b *testBackend) Nonce(addr common.Address) uint64 {
+	return uint64(len(b.sent))
+}
+
+func (b *testBackend) SendTx(tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func newTestReporter(t *testing.T, dir string, backend Backend, source EvidenceSource) *Reporter {
+	ks := keystore.NewKeyStore(filepath.Join(dir, "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	var account accounts.Account
+	if accs := ks.Accounts(); len(accs) > 0 {
+		account = accs[0]
+	} else {
+		var err error
+		account, err = ks.NewAccount("")
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, ks.Unlock(account, ""))
+	am := accounts.NewManager(&accounts.Config{}, ks)
+
+	r, err := New(Config{Account: account.Address}, big.NewInt(1), backend, source, am, filepath.Join(dir, "reporter"))
+	assert.Nil(t, err)
+	return r
+}
+
+func TestReporter(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	dir, _ := ioutil.TempDir("", "reporter")
+	defer os.RemoveAll(dir)
+
+	epoch := xutil.CalcBlocksEachEpoch()
+	backend := &testBackend{number: epoch + 1, slashed: make(map[uint64]bool)}
+	source := &testSource{evidences: consensus.Evidences{
+		&testEvidence{Number: epoch, Node: discover.NodeID{1}},
+		&testEvidence{Number: epoch - 1, Node: discover.NodeID{2}},
+	}}
+
+	r := newTestReporter(t, dir, backend, source)
+	r.update()
+	assert.Len(t, backend.sent, 2)
+	for _, tx := range backend.sent {
+		assert.Equal(t, vm.SlashingContractAddr, *tx.To())
+	}
+
+	// Reports are not sent again while waiting for the slash.
+	backend.number++
+	backend.slashed[epoch] = true
+	r.update()
+	assert.Len(t, backend.sent, 2)
+	records, err := r.db.records()
+	assert.Nil(t, err)
+	status := make(map[uint64]uint8)
+	for _, rec := range records {
+		status[rec.BlockNumber] = rec.Status
+	}
+	assert.Equal(t, statusReported, status[epoch])
+	assert.Equal(t, statusSubmitted, status[epoch-1])
+	r.db.close()
+
+	// The pending report survives a restart and is resubmitted once the
+	// previous submission is considered lost, until the evidence expires.
+	source.evidences = nil
+	r = newTestReporter(t, dir, backend, source)
+	defer r.db.close()
+	backend.number += resubmitBlocks
+	r.update()
+	assert.Len(t, backend.sent, 3)
+
+	backend.number = 3 * epoch
+	r.update()
+	assert.Len(t, backend.sent, 3)
+	records, err = r.db.records()
+	assert.Nil(t, err)
+	for _, rec := range records {
+		assert.True(t, rec.done())
+	}
+}
+
+func TestExpired(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	epoch := xutil.CalcBlocksEachEpoch()
+
+	assert.False(t, expired(epoch, epoch, 1))
+	assert.False(t, expired(2*epoch, epoch, 1))
+	assert.True(t, expired(2*epoch+1, epoch, 1))
+	assert.False(t, expired(2*epoch+1, epoch+1, 1))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/snapshotdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/accounts"
@@ -675,6 +676,8 @@ func DoCall(ctx context.Context, b Backend, args CallArgs, blockNr rpc.BlockNumb
 		return nil, err
 	}
 	defer state.ClearParentReference()
+	// In the archive mode the PPOS queries read the snapshotdb state of the block
+	defer snapshotdb.OpenHistory(header.Hash())()
 	// Set sender address or use a default if none specified
 	var addr common.Address
 	if args.From == nil {
@@ -888,6 +891,16 @@ type ExecutionResult struct {
 	StructLogs  []StructLogRes `json:"structLogs"`
 }
 
+// WasmExecutionResult groups the structured logs emitted by the WASM contracts
+// while replaying a transaction with the wasmCallTracer, as well as the
+// transaction execution status, the amount of gas used and the return value
+type WasmExecutionResult struct {
+	Gas         uint64             `json:"gas"`
+	Failed      bool               `json:"failed"`
+	ReturnValue string             `json:"returnValue"`
+	StructLogs  []vm.WasmStructLog `json:"structLogs"`
+}
+
 // StructLogRes stores a structured log emitted by the EVM while replaying a
 // transaction in debug mode
 type StructLogRes struct {
@@ -6,6 +6,8 @@ var Modules = map[string]string{
 	"debug":    DebugJs,
 	"phoenixchain":   PhoenixchainJs,
 	"miner":    MinerJs,
+	"pbft":     PbftJs,
+	"ppos":     PposJs,
 	"net":      NetJs,
 	"personal": PersonalJs,
 	"rpc":      RpcJs,
@@ -532,3 +534,36 @@ web3._extend({
 	]
 });
 `
+
+const PbftJs = `
+web3._extend({
+	property: 'pbft',
+	methods: [
+		new web3._extend.Method({
+			name: 'getFinalityProof',
+			call: 'pbft_getFinalityProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorTransitions',
+			call: 'pbft_getValidatorTransitions',
+			params: 1
+		}),
+	],
+	properties: []
+});
+`
+
+const PposJs = `
+web3._extend({
+	property: 'ppos',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEpochEconomics',
+			call: 'ppos_getEpochEconomics',
+			params: 1
+		}),
+	],
+	properties: []
+});
+`
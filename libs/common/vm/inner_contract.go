@@ -10,6 +10,7 @@ var (
 	SlashingContractAddr       = common.HexToAddress("0x1000000000000000000000000000000000000004") // The PhoenixChain Precompiled contract addr for slashing
 	GovContractAddr            = common.HexToAddress("0x1000000000000000000000000000000000000005") // The PhoenixChain Precompiled contract addr for governance
 	DelegateRewardPoolAddr     = common.HexToAddress("0x1000000000000000000000000000000000000006") // The PhoenixChain Precompiled contract addr for delegate reward
+	TreasuryPoolAddr           = common.HexToAddress("0x1000000000000000000000000000000000000007") // The PhoenixChain treasury addr, only paid out by the treasury proposals
 	ValidatorInnerContractAddr = common.HexToAddress("0x2000000000000000000000000000000000000000") // The PhoenixChain Precompiled contract addr for pbft inner
 )
 
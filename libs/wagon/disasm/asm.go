@@ -18,10 +18,20 @@ import (
 func Assemble(instr []Instr) ([]byte, error) {
 	body := new(bytes.Buffer)
 	for _, ins := range instr {
-		body.WriteByte(ins.Op.Code)
+		if ins.Op.Prefix != 0 {
+			body.WriteByte(ins.Op.Prefix)
+			leb128.WriteVarUint32(body, ins.Op.SubCode)
+		} else {
+			body.WriteByte(ins.Op.Code)
+		}
 		switch op := ins.Op.Code; op {
 		case ops.Block, ops.Loop, ops.If:
-			body.WriteByte(byte(ins.Immediates[0].(wasm.BlockType)))
+			switch sig := ins.Immediates[0].(type) {
+			case wasm.BlockType:
+				body.WriteByte(byte(sig))
+			case wasm.BlockTypeIndex:
+				leb128.WriteVarint64(body, int64(sig))
+			}
 		case ops.Br, ops.BrIf:
 			leb128.WriteVarUint32(body, ins.Immediates[0].(uint32))
 		case ops.BrTable:
@@ -55,8 +65,10 @@ func Assemble(instr []Instr) ([]byte, error) {
 		case ops.I32Load, ops.I64Load, ops.F32Load, ops.F64Load, ops.I32Load8s, ops.I32Load8u, ops.I32Load16s, ops.I32Load16u, ops.I64Load8s, ops.I64Load8u, ops.I64Load16s, ops.I64Load16u, ops.I64Load32s, ops.I64Load32u, ops.I32Store, ops.I64Store, ops.F32Store, ops.F64Store, ops.I32Store8, ops.I32Store16, ops.I64Store8, ops.I64Store16, ops.I64Store32:
 			leb128.WriteVarUint32(body, ins.Immediates[0].(uint32))
 			leb128.WriteVarUint32(body, ins.Immediates[1].(uint32))
-		case ops.CurrentMemory, ops.GrowMemory:
-			leb128.WriteVarUint32(body, uint32(ins.Immediates[0].(uint8)))
+		case ops.CurrentMemory, ops.GrowMemory, ops.MemoryCopy, ops.MemoryFill:
+			for _, imm := range ins.Immediates {
+				leb128.WriteVarUint32(body, uint32(imm.(uint8)))
+			}
 		}
 	}
 	return body.Bytes(), nil
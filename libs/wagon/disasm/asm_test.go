@@ -12,6 +12,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/disasm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+	ops "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/operators"
 )
 
 var testPaths = []string{
@@ -59,3 +60,29 @@ func TestAssemble(t *testing.T) {
 		}
 	}
 }
+
+func TestAssemblePrefixed(t *testing.T) {
+	code := []byte{
+		ops.Block, 0x85, 0x01, // a block type index of two bytes
+		ops.I32Const, 0, ops.I32Const, 0, ops.I32Const, 0, ops.PrefixMisc, 0x0a, 0, 0,
+		ops.F64Const, 0, 0, 0, 0, 0, 0, 0, 0, ops.PrefixMisc, 0x07, ops.Drop,
+		ops.End,
+	}
+	instrs, err := disasm.Disassemble(code)
+	if err != nil {
+		t.Fatalf("disassemble failed: %v", err)
+	}
+	if sig := instrs[0].Immediates[0]; sig != wasm.BlockTypeIndex(133) {
+		t.Fatalf("block signature is %v, want type index 133", sig)
+	}
+	if instrs[4].Op.Code != ops.MemoryCopy || instrs[6].Op.Code != ops.I64TruncSatUF64 {
+		t.Fatalf("unexpected operators %s and %s", instrs[4].Op.Name, instrs[6].Op.Name)
+	}
+	assembled, err := disasm.Assemble(instrs)
+	if err != nil {
+		t.Fatalf("assemble failed: %v", err)
+	}
+	if !bytes.Equal(code, assembled) {
+		t.Fatalf("code is different: %x", assembled)
+	}
+}
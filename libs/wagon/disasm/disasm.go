@@ -26,7 +26,7 @@ type Instr struct {
 	// Immediates are arguments to an operator in the bytecode stream itself.
 	// Valid value types are:
 	// - (u)(int/float)(32/64)
-	// - wasm.BlockType
+	// - wasm.BlockType, wasm.BlockTypeIndex
 	Immediates  []interface{}
 	NewStack    *StackInfo // non-nil if the instruction creates or unwinds a stack.
 	Block       *BlockInfo // non-nil if the instruction starts or ends a new block.
@@ -45,13 +45,21 @@ type Instr struct {
 type StackInfo struct {
 	StackTopDiff int64 // The difference between the stack depths at the end of the block
 	PreserveTop  bool  // Whether the value on the top of the stack should be preserved while unwinding
+	Preserve     int   // The number of values on the top of the stack preserved while unwinding, 1 if PreserveTop is set
 	IsReturn     bool  // Whether the unwind is equivalent to a return
 }
 
 // BlockInfo stores details about a block created or ended by an instruction.
 type BlockInfo struct {
 	Start     bool           // If true, this instruction starts a block. Else this instruction ends it.
-	Signature wasm.BlockType // The block signature
+	Signature wasm.BlockType // The block signature, wasm.BlockTypeEmpty if the signature is a type index
+	Params    int            // The number of values the block takes from the stack
+	Results   int            // The number of values the block leaves on the stack
+
+	// The number of values a branch to the block carries. The branches to
+	// the blocks of a single value type carry it, loops included, as wagon
+	// always did.
+	Arity int
 
 	// Indices to the accompanying control operator.
 	// For 'if', this is the index to the 'else' operator.
@@ -86,6 +94,47 @@ func isInstrReachable(indexStack [][]int) bool {
 
 var ErrStackUnderflow = errors.New("disasm: stack underflow")
 
+// maxBranchArity is the maximum number of values a branch can carry, the
+// compiled br_if encodes it in a byte.
+const maxBranchArity = math.MaxUint8
+
+// newBlockInfo returns the details of a block of signature sig.
+func newBlockInfo(module *wasm.Module, op byte, sig interface{}) (*BlockInfo, error) {
+	info := &BlockInfo{Start: true}
+	if sig, ok := sig.(wasm.BlockType); ok {
+		info.Signature = sig
+		if sig != wasm.BlockTypeEmpty {
+			info.Results, info.Arity = 1, 1
+		}
+		return info, nil
+	}
+
+	params, results, err := module.BlockSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	info.Signature = wasm.BlockTypeEmpty
+	info.Params, info.Results = len(params), len(results)
+	info.Arity = info.Results
+	if op == ops.Loop {
+		info.Arity = info.Params
+	}
+	if info.Arity > maxBranchArity {
+		return nil, errors.New("disasm: too many values carried by a branch")
+	}
+	return info, nil
+}
+
+// branchStack returns the details of the stack unwound by a branch discarding
+// the values above the height of the block, except the values it carries.
+func branchStack(block *BlockInfo, elemsDiscard int) StackInfo {
+	return StackInfo{
+		StackTopDiff: int64(elemsDiscard),
+		PreserveTop:  block.Arity == 1,
+		Preserve:     block.Arity,
+	}
+}
+
 // NewDisassembly disassembles the given function. It also takes the function's
 // parent module as an argument for locating any other functions referenced by
 // fn.
@@ -169,10 +218,13 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 			stackDepths.SetTop(stackDepths.Top() - uint64(len(fn.Sig.ReturnTypes)))
 			pushPolymorphicOp(blockPolymorphicOps, curIndex)
 		case ops.End, ops.Else:
-			blockSig := disas.Code[blockStartIndex].Block.Signature
+			startBlock := disas.Code[blockStartIndex].Block
 			instr.Block = &BlockInfo{
 				Start:     false,
-				Signature: blockSig,
+				Signature: startBlock.Signature,
+				Params:    startBlock.Params,
+				Results:   startBlock.Results,
+				Arity:     startBlock.Arity,
 			}
 			if op == ops.End {
 				instr.Block.BlockStartIndex = int(blockStartIndex)
@@ -184,15 +236,15 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 
 			// The max depth reached while execing the last block
 			// If the signature of the current block is not empty,
-			// this will be incremented.
+			// this will be incremented by the number of results.
 			// Same with ops.Br/BrIf, we subtract 2 instead of 1
 			// to get the depth of the *parent* block of the branch
 			// we want to take.
 			prevDepthIndex := stackDepths.Len() - 2
 			prevDepth := stackDepths.Get(prevDepthIndex)
 
-			if op != ops.Else && blockSig != wasm.BlockTypeEmpty {
-				stackDepths.Set(prevDepthIndex, prevDepth+1)
+			if op != ops.Else && startBlock.Results != 0 {
+				stackDepths.Set(prevDepthIndex, prevDepth+uint64(startBlock.Results))
 				disas.checkMaxDepth(int(stackDepths.Get(prevDepthIndex)))
 			}
 
@@ -201,18 +253,25 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 
 			stackDepths.Pop()
 			if op == ops.Else {
-				stackDepths.Push(stackDepths.Top())
+				// the else branch takes the params of the block again
+				stackDepths.Push(stackDepths.Top() + uint64(startBlock.Params))
 				blockPolymorphicOps = append(blockPolymorphicOps, []int{})
 			}
 		case ops.Block, ops.Loop, ops.If:
-			sig := instr.Immediates[0].(wasm.BlockType)
+			instr.Block, err = newBlockInfo(module, op, instr.Immediates[0])
+			if err != nil {
+				return nil, err
+			}
 			logger.Printf("if, depth is %d", stackDepths.Top())
-			stackDepths.Push(stackDepths.Top())
-			blockPolymorphicOps = append(blockPolymorphicOps, []int{})
-			instr.Block = &BlockInfo{
-				Start:     true,
-				Signature: sig,
+			// the params of the block are moved from the parent stack
+			// to the stack of the block
+			top := int(stackDepths.Top()) - instr.Block.Params
+			if top < 0 {
+				return nil, ErrStackUnderflow
 			}
+			stackDepths.SetTop(uint64(top))
+			stackDepths.Push(uint64(top + instr.Block.Params))
+			blockPolymorphicOps = append(blockPolymorphicOps, []int{})
 		case ops.Br, ops.BrIf:
 			depth := instr.Immediates[0].(uint32)
 			if int(depth) == blockIndices.Len() {
@@ -232,13 +291,23 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 
 				// No need to subtract 2 here, we are getting the block
 				// we need to branch to.
-				// No need Discard one. and PreserveTop.
-				if elemsDiscard > 1 {
-					index := blockIndices.Get(blockIndices.Len() - 1 - int(depth))
-					instr.NewStack = &StackInfo{
-						StackTopDiff: int64(elemsDiscard),
-						PreserveTop:  disas.Code[index].Block.Signature != wasm.BlockTypeEmpty,
+				index := blockIndices.Get(blockIndices.Len() - 1 - int(depth))
+				block := disas.Code[index].Block
+				if block.Params != 0 || block.Results > 1 {
+					// a block of a type index, the values above the
+					// ones carried by the branch are discarded
+					if elemsDiscard < block.Arity {
+						return nil, ErrStackUnderflow
+					}
+					if elemsDiscard > block.Arity {
+						info := branchStack(block, elemsDiscard)
+						instr.NewStack = &info
 					}
+				} else if elemsDiscard > 1 && (op == ops.Br || block.Arity != 0) {
+					// No need Discard one. and PreserveTop. br_if only
+					// unwinds the stack of the blocks with a signature.
+					info := branchStack(block, elemsDiscard)
+					instr.NewStack = &info
 				}
 			}
 			if op == ops.Br {
@@ -264,8 +333,7 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 						return nil, ErrStackUnderflow
 					}
 					index := blockIndices.Get(blockIndices.Len() - 1 - int(entry))
-					info.StackTopDiff = int64(elemsDiscard)
-					info.PreserveTop = disas.Code[index].Block.Signature != wasm.BlockTypeEmpty
+					info = branchStack(disas.Code[index].Block, elemsDiscard)
 				}
 				instr.Branches = append(instr.Branches, info)
 			}
@@ -284,8 +352,7 @@ func NewDisassembly(fn wasm.Function, module *wasm.Module) (*Disassembly, error)
 					return nil, ErrStackUnderflow
 				}
 				index := blockIndices.Get(blockIndices.Len() - 1 - int(defaultTarget))
-				info.StackTopDiff = int64(elemsDiscard)
-				info.PreserveTop = disas.Code[index].Block.Signature != wasm.BlockTypeEmpty
+				info = branchStack(disas.Code[index].Block, elemsDiscard)
 			}
 			instr.Branches = append(instr.Branches, info)
 			pushPolymorphicOp(blockPolymorphicOps, curIndex)
@@ -352,9 +419,22 @@ func Disassemble(code []byte) ([]Instr, error) {
 			return nil, err
 		}
 
-		opStr, err := ops.New(op)
-		if err != nil {
-			return nil, err
+		var opStr ops.Op
+		if ops.IsPrefix(op) {
+			sub, err := leb128.ReadVarUint32(reader)
+			if err != nil {
+				return nil, err
+			}
+			opStr, err = ops.NewPrefixed(op, sub)
+			if err != nil {
+				return nil, err
+			}
+			op = opStr.Code
+		} else {
+			opStr, err = ops.New(op)
+			if err != nil {
+				return nil, err
+			}
 		}
 		instr := Instr{
 			Op: opStr,
@@ -362,11 +442,11 @@ func Disassemble(code []byte) ([]Instr, error) {
 
 		switch op {
 		case ops.Block, ops.Loop, ops.If:
-			sig, err := wasm.ReadByte(reader)
+			sig, err := wasm.ReadBlockType(reader)
 			if err != nil {
 				return nil, err
 			}
-			instr.Immediates = append(instr.Immediates, wasm.BlockType(sig))
+			instr.Immediates = append(instr.Immediates, sig)
 		case ops.Br, ops.BrIf:
 			depth, err := leb128.ReadVarUint32(reader)
 			if err != nil {
@@ -453,7 +533,7 @@ func Disassemble(code []byte) ([]Instr, error) {
 				return nil, err
 			}
 			instr.Immediates = append(instr.Immediates, offset)
-		case ops.CurrentMemory, ops.GrowMemory:
+		case ops.CurrentMemory, ops.GrowMemory, ops.MemoryFill:
 			idx, err := wasm.ReadByte(reader)
 			if err != nil {
 				return nil, err
@@ -462,6 +542,18 @@ func Disassemble(code []byte) ([]Instr, error) {
 				return nil, errors.New("disasm: memory index must be 0")
 			}
 			instr.Immediates = append(instr.Immediates, uint8(idx))
+		case ops.MemoryCopy:
+			// the destination and the source memory indices
+			for i := 0; i < 2; i++ {
+				idx, err := wasm.ReadByte(reader)
+				if err != nil {
+					return nil, err
+				}
+				if idx != 0x00 {
+					return nil, errors.New("disasm: memory index must be 0")
+				}
+				instr.Immediates = append(instr.Immediates, uint8(idx))
+			}
 		}
 		out = append(out, instr)
 	}
@@ -0,0 +1,115 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec/internal/compile"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+)
+
+// CompilerVersion is the version of the code produced by CompileModule, it must
+// be increased whenever the compiled code changes so that the modules encoded by
+// a former version aren't decoded.
+const CompilerVersion uint32 = 2
+
+var errFuncsMismatch = errors.New("the compiled functions don't match the module")
+
+// encodedModule is the encoding of the compiled functions of a module, the host
+// functions are only marked.
+type encodedModule struct {
+	Funcs []encodedFunction
+}
+
+type encodedFunction struct {
+	Host           bool
+	Code           []byte
+	BranchTables   []encodedBranchTable
+	MaxDepth       int
+	TotalLocalVars int
+	Args           int
+	Returns        int
+}
+
+type encodedBranchTable struct {
+	Targets       []compile.Target
+	DefaultTarget compile.Target
+}
+
+// EncodeCompiled encodes the compiled functions of the module, see DecodeCompiled.
+func EncodeCompiled(module *CompiledModule) ([]byte, error) {
+	enc := encodedModule{Funcs: make([]encodedFunction, len(module.funcs))}
+	for i, fn := range module.funcs {
+		compiled, ok := fn.(compiledFunction)
+		if !ok {
+			enc.Funcs[i].Host = true
+			continue
+		}
+		if len(compiled.asm) != 0 {
+			return nil, fmt.Errorf("function %d holds native code", i)
+		}
+		tables := make([]encodedBranchTable, len(compiled.branchTables))
+		for j, table := range compiled.branchTables {
+			tables[j] = encodedBranchTable{Targets: table.Targets, DefaultTarget: table.DefaultTarget}
+		}
+		enc.Funcs[i] = encodedFunction{
+			Code:           compiled.code,
+			BranchTables:   tables,
+			MaxDepth:       compiled.maxDepth,
+			TotalLocalVars: compiled.totalLocalVars,
+			Args:           compiled.args,
+			Returns:        compiled.returns,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&enc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeCompiled returns the compiled module of the module from the functions
+// encoded by EncodeCompiled, instead of compiling them again. The encoding
+// must come from the same module and the same CompilerVersion.
+func DecodeCompiled(module *wasm.Module, data []byte) (*CompiledModule, error) {
+	var enc encodedModule
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&enc); err != nil {
+		return nil, err
+	}
+	if len(enc.Funcs) != len(module.FunctionIndexSpace) {
+		return nil, errFuncsMismatch
+	}
+
+	compiled, err := newCompiledModule(module)
+	if err != nil {
+		return nil, err
+	}
+	for i, fn := range module.FunctionIndexSpace {
+		encoded := enc.Funcs[i]
+		if fn.IsHost() != encoded.Host {
+			return nil, errFuncsMismatch
+		}
+		if fn.IsHost() {
+			continue
+		}
+		if encoded.Args != len(fn.Sig.ParamTypes) || encoded.Returns != len(fn.Sig.ReturnTypes) {
+			return nil, errFuncsMismatch
+		}
+		tables := make([]*compile.BranchTable, len(encoded.BranchTables))
+		for j, table := range encoded.BranchTables {
+			tables[j] = &compile.BranchTable{Targets: table.Targets, DefaultTarget: table.DefaultTarget}
+		}
+		compiled.funcs[i] = compiledFunction{
+			code:           encoded.Code,
+			branchTables:   tables,
+			maxDepth:       encoded.MaxDepth,
+			totalLocalVars: encoded.TotalLocalVars,
+			args:           encoded.Args,
+			returns:        encoded.Returns,
+		}
+	}
+	return compiled, nil
+}
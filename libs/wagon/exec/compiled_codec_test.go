@@ -0,0 +1,66 @@
+package exec
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+)
+
+func readTestModule(t *testing.T, name string) *wasm.Module {
+	raw, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	module, err := wasm.ReadModule(bytes.NewReader(raw), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return module
+}
+
+func TestEncodeCompiled(t *testing.T) {
+	module := readTestModule(t, "brtable.wasm")
+	compiled, err := CompileModule(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := EncodeCompiled(compiled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeCompiled(module, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compiled.memory, decoded.memory) || !reflect.DeepEqual(compiled.globals, decoded.globals) {
+		t.Fatal("the memory or the globals of the decoded module differ")
+	}
+	if len(compiled.funcs) != len(decoded.funcs) {
+		t.Fatalf("decoded %d functions, want %d", len(decoded.funcs), len(compiled.funcs))
+	}
+	for i := range compiled.funcs {
+		want, got := compiled.funcs[i].(compiledFunction), decoded.funcs[i].(compiledFunction)
+		if !bytes.Equal(want.code, got.code) || want.maxDepth != got.maxDepth || want.totalLocalVars != got.totalLocalVars ||
+			want.args != got.args || want.returns != got.returns {
+			t.Errorf("function %d differs", i)
+		}
+		if len(want.branchTables) != len(got.branchTables) {
+			t.Fatalf("function %d: decoded %d branch tables, want %d", i, len(got.branchTables), len(want.branchTables))
+		}
+		for j := range want.branchTables {
+			if !reflect.DeepEqual(want.branchTables[j].Targets, got.branchTables[j].Targets) ||
+				want.branchTables[j].DefaultTarget != got.branchTables[j].DefaultTarget {
+				t.Errorf("function %d: branch table %d differs", i, j)
+			}
+		}
+	}
+
+	// the encoding doesn't decode with another module
+	if _, err := DecodeCompiled(readTestModule(t, "basic.wasm"), data); err == nil {
+		t.Error("decoded the functions of another module")
+	}
+}
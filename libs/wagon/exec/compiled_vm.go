@@ -22,23 +22,11 @@ type CompiledModule struct {
 }
 
 func CompileModule(module *wasm.Module) (*CompiledModule, error) {
-	var compiled CompiledModule
-
-	if module.Memory != nil && len(module.Memory.Entries) != 0 {
-		if len(module.Memory.Entries) > 1 {
-			return nil, ErrMultipleLinearMemories
-		}
-
-		memsize := uint(module.Memory.Entries[0].Limits.Initial) * wasmPageSize
-		compiled.memory = make([]byte, memsize)
-		copy(compiled.memory, module.LinearMemoryIndexSpace[0])
+	compiled, err := newCompiledModule(module)
+	if err != nil {
+		return nil, err
 	}
 
-	compiled.funcs = make([]function, len(module.FunctionIndexSpace))
-	compiled.globals = make([]uint64, len(module.GlobalIndexSpace))
-	compiled.RawModule = module
-
-	nNatives := 0
 	for i, fn := range module.FunctionIndexSpace {
 		// Skip native methods as they need not be
 		// disassembled; simply add them at the end
@@ -47,11 +35,6 @@ func CompileModule(module *wasm.Module) (*CompiledModule, error) {
 		// section of:
 		// https://webassembly.github.io/spec/core/exec/modules.html#allocation
 		if fn.IsHost() {
-			compiled.funcs[i] = goFunction{
-				typ: fn.Host.Type(),
-				val: fn.Host,
-			}
-			nNatives++
 			continue
 		}
 
@@ -72,7 +55,38 @@ func CompileModule(module *wasm.Module) (*CompiledModule, error) {
 			maxDepth:       disassembly.MaxDepth,
 			totalLocalVars: totalLocalVars,
 			args:           len(fn.Sig.ParamTypes),
-			returns:        len(fn.Sig.ReturnTypes) != 0,
+			returns:        len(fn.Sig.ReturnTypes),
+		}
+	}
+
+	return compiled, nil
+}
+
+// newCompiledModule returns the module with its memory, globals and host
+// functions set up, the functions of the module are left to be compiled.
+func newCompiledModule(module *wasm.Module) (*CompiledModule, error) {
+	var compiled CompiledModule
+
+	if module.Memory != nil && len(module.Memory.Entries) != 0 {
+		if len(module.Memory.Entries) > 1 {
+			return nil, ErrMultipleLinearMemories
+		}
+
+		memsize := uint(module.Memory.Entries[0].Limits.Initial) * wasmPageSize
+		compiled.memory = make([]byte, memsize)
+		copy(compiled.memory, module.LinearMemoryIndexSpace[0])
+	}
+
+	compiled.funcs = make([]function, len(module.FunctionIndexSpace))
+	compiled.globals = make([]uint64, len(module.GlobalIndexSpace))
+	compiled.RawModule = module
+
+	for i, fn := range module.FunctionIndexSpace {
+		if fn.IsHost() {
+			compiled.funcs[i] = goFunction{
+				typ: fn.Host.Type(),
+				val: fn.Host,
+			}
 		}
 	}
 
@@ -108,8 +122,18 @@ var memoryPool = sync.Pool{
 	New: func() interface{} { return new(bytes.Buffer) },
 }
 
-func NewVMWithCompiled(module *CompiledModule, memLimit uint64) (*CompileVM, error) {
+func NewVMWithCompiled(module *CompiledModule, memLimit uint64, opts ...VMOption) (*CompileVM, error) {
 	var vm CompileVM
+	var options config
+	for _, opt := range opts {
+		opt(&options)
+	}
+	vm.newFuncTable()
+	if options.DisablePostMVP {
+		if err := vm.disablePostMVP(module.RawModule); err != nil {
+			return nil, err
+		}
+	}
 
 	memsize := len(module.memory)
 	if uint64(memsize) > memLimit {
@@ -125,7 +149,6 @@ func NewVMWithCompiled(module *CompiledModule, memLimit uint64) (*CompileVM, err
 	vm.funcs = module.funcs
 	vm.globals = make([]uint64, len(module.RawModule.GlobalIndexSpace))
 	copy(vm.globals, module.globals)
-	vm.newFuncTable()
 	vm.module = module.RawModule
 
 	return &vm, nil
@@ -91,3 +91,82 @@ func (vm *VM) f64ConvertUI64() {
 func (vm *VM) f64PromoteF32() {
 	vm.pushFloat64(float64(vm.popFloat32()))
 }
+
+// The non-trapping conversions saturate to the bounds of the integer type,
+// NaN converts to 0.
+
+func truncSatS32(v float64) int32 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v <= math.MinInt32:
+		return math.MinInt32
+	case v >= math.MaxInt32:
+		return math.MaxInt32
+	}
+	return int32(v)
+}
+
+func truncSatU32(v float64) uint32 {
+	switch {
+	case math.IsNaN(v) || v <= 0:
+		return 0
+	case v >= math.MaxUint32:
+		return math.MaxUint32
+	}
+	return uint32(v)
+}
+
+func truncSatS64(v float64) int64 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v <= math.MinInt64:
+		return math.MinInt64
+	case v >= 1<<63:
+		return math.MaxInt64
+	}
+	return int64(v)
+}
+
+func truncSatU64(v float64) uint64 {
+	switch {
+	case math.IsNaN(v) || v <= 0:
+		return 0
+	case v >= 1<<64:
+		return math.MaxUint64
+	}
+	return uint64(v)
+}
+
+func (vm *VM) i32TruncSatSF32() {
+	vm.pushInt32(truncSatS32(float64(vm.popFloat32())))
+}
+
+func (vm *VM) i32TruncSatUF32() {
+	vm.pushUint32(truncSatU32(float64(vm.popFloat32())))
+}
+
+func (vm *VM) i32TruncSatSF64() {
+	vm.pushInt32(truncSatS32(vm.popFloat64()))
+}
+
+func (vm *VM) i32TruncSatUF64() {
+	vm.pushUint32(truncSatU32(vm.popFloat64()))
+}
+
+func (vm *VM) i64TruncSatSF32() {
+	vm.pushInt64(truncSatS64(float64(vm.popFloat32())))
+}
+
+func (vm *VM) i64TruncSatUF32() {
+	vm.pushUint64(truncSatU64(float64(vm.popFloat32())))
+}
+
+func (vm *VM) i64TruncSatSF64() {
+	vm.pushInt64(truncSatS64(vm.popFloat64()))
+}
+
+func (vm *VM) i64TruncSatUF64() {
+	vm.pushUint64(truncSatU64(vm.popFloat64()))
+}
@@ -20,10 +20,10 @@ type compiledFunction struct {
 	code           []byte
 	codeMeta       *compile.BytecodeMetadata
 	branchTables   []*compile.BranchTable
-	maxDepth       int  // maximum stack depth reached while executing the function body
-	totalLocalVars int  // number of local variables used by the function
-	args           int  // number of arguments the function accepts
-	returns        bool // whether the function returns a value
+	maxDepth       int // maximum stack depth reached while executing the function body
+	totalLocalVars int // number of local variables used by the function
+	args           int // number of arguments the function accepts
+	returns        int // number of values the function returns
 
 	asm []asmBlock
 }
@@ -54,9 +54,16 @@ func (fn goFunction) call(vm *VM, index int64) {
 	}
 	args[0] = reflect.ValueOf(proc)
 
+	var raws []uint64
+	if vm.tracer != nil {
+		raws = make([]uint64, numIn-1)
+	}
 	for i := numIn - 1; i >= 1; i-- {
 		val := reflect.New(fn.typ.In(i)).Elem()
 		raw := vm.popUint64()
+		if raws != nil {
+			raws[i-1] = raw
+		}
 		kind := fn.typ.In(i).Kind()
 
 		switch kind {
@@ -73,6 +80,9 @@ func (fn goFunction) call(vm *VM, index int64) {
 		args[i] = val
 	}
 
+	if vm.tracer != nil {
+		vm.tracer.CaptureEnter(index, raws)
+	}
 	rtrns := fn.val.Call(args)
 	for i, out := range rtrns {
 		kind := out.Kind()
@@ -87,6 +97,9 @@ func (fn goFunction) call(vm *VM, index int64) {
 			panic(fmt.Sprintf("exec: return value %d invalid kind=%v", i, kind))
 		}
 	}
+	if vm.tracer != nil {
+		vm.tracer.CaptureExit(index, traceValues(vm.ctx.stack[len(vm.ctx.stack)-len(rtrns):]))
+	}
 }
 
 func (compiled compiledFunction) call(vm *VM, index int64) {
@@ -99,6 +112,10 @@ func (compiled compiledFunction) call(vm *VM, index int64) {
 		locals[i] = vm.popUint64()
 	}
 
+	if vm.tracer != nil {
+		vm.tracer.CaptureEnter(index, traceValues(locals[:compiled.args]))
+	}
+
 	//save execution context
 	prevCtxt := vm.ctx
 
@@ -116,7 +133,10 @@ func (compiled compiledFunction) call(vm *VM, index int64) {
 	//restore execution context
 	vm.ctx = prevCtxt
 
-	if compiled.returns {
-		vm.pushUint64(rtrn)
+	if vm.tracer != nil {
+		vm.tracer.CaptureExit(index, traceValues(rtrn))
+	}
+	for _, v := range rtrn {
+		vm.pushUint64(v)
 	}
 }
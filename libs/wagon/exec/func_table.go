@@ -142,6 +142,20 @@ func (vm *VM) newFuncTable() {
 	vm.funcTable[ops.F64ConvertSI64] = vm.f64ConvertSI64
 	vm.funcTable[ops.F64ConvertUI64] = vm.f64ConvertUI64
 	vm.funcTable[ops.F64PromoteF32] = vm.f64PromoteF32
+	vm.funcTable[ops.I32TruncSatSF32] = vm.i32TruncSatSF32
+	vm.funcTable[ops.I32TruncSatUF32] = vm.i32TruncSatUF32
+	vm.funcTable[ops.I32TruncSatSF64] = vm.i32TruncSatSF64
+	vm.funcTable[ops.I32TruncSatUF64] = vm.i32TruncSatUF64
+	vm.funcTable[ops.I64TruncSatSF32] = vm.i64TruncSatSF32
+	vm.funcTable[ops.I64TruncSatUF32] = vm.i64TruncSatUF32
+	vm.funcTable[ops.I64TruncSatSF64] = vm.i64TruncSatSF64
+	vm.funcTable[ops.I64TruncSatUF64] = vm.i64TruncSatUF64
+
+	vm.funcTable[ops.I32Extend8S] = vm.i32Extend8S
+	vm.funcTable[ops.I32Extend16S] = vm.i32Extend16S
+	vm.funcTable[ops.I64Extend8S] = vm.i64Extend8S
+	vm.funcTable[ops.I64Extend16S] = vm.i64Extend16S
+	vm.funcTable[ops.I64Extend32S] = vm.i64Extend32S
 
 	vm.funcTable[ops.I32Load] = vm.i32Load
 	vm.funcTable[ops.I64Load] = vm.i64Load
@@ -168,6 +182,8 @@ func (vm *VM) newFuncTable() {
 	vm.funcTable[ops.I64Store32] = vm.i64Store32
 	vm.funcTable[ops.CurrentMemory] = vm.currentMemory
 	vm.funcTable[ops.GrowMemory] = vm.growMemory
+	vm.funcTable[ops.MemoryCopy] = vm.memoryCopy
+	vm.funcTable[ops.MemoryFill] = vm.memoryFill
 
 	vm.funcTable[ops.Drop] = vm.drop
 	vm.funcTable[ops.Select] = vm.selectOp
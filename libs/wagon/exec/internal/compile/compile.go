@@ -57,7 +57,8 @@ import (
 // operator. A block with a signature will push a value of that type on the parent
 // stack (that is, the stack of the parent block where this block started). The
 // OpDiscardPreserveTop operator allows us to preserve this value while
-// discarding the remaining ones.
+// discarding the remaining ones, OpDiscardPreserve preserves the values of a
+// block returning several ones.
 
 // Branches are rewritten as
 //     <jmp> <addr>
@@ -71,13 +72,17 @@ var (
 	OpJmpZ byte = 0x03
 	// OpJmpNz jumps to the given address if the value at the top of the
 	// stack is not zero. It also discards elements and optionally preserves
-	// the topmost value on the stack
+	// the topmost values on the stack
 	OpJmpNz byte = 0x0d
 	// OpDiscard discards a given number of elements from the execution stack.
 	OpDiscard byte = 0x0b
 	// OpDiscardPreserveTop discards a given number of elements from the
 	// execution stack, while preserving the value on the top of the stack.
 	OpDiscardPreserveTop byte = 0x05
+	// OpDiscardPreserve discards a given number of elements from the
+	// execution stack, while preserving a given number of values on the top
+	// of the stack.
+	OpDiscardPreserve byte = 0x04
 )
 
 const (
@@ -88,9 +93,13 @@ const (
 	// conditional if branch.
 	// Byte 0     - represents the opcode.
 	// Byte 1-8   - represents the branch address.
-	// Byte 9     - 1 if the top of stack should be preserved, 0 otherwise.
+	// Byte 9     - the number of values on the top of stack to preserve.
 	// Byte 10-18 - number of stack positions to discard.
 	ifBranchLen = 18
+	// discardPreserveLen represents the number of bytes consumed by a wire
+	// representation of an instruction and two int64, the number of values
+	// to preserve and the number of elements to discard.
+	discardPreserveLen = 17
 )
 
 // Target is the "target" of a br_table instruction.
@@ -100,6 +109,7 @@ type Target struct {
 	Addr        int64 // The absolute address of the target
 	Discard     int64 // The number of elements to discard
 	PreserveTop bool  // Whether the top of the stack is to be preserved
+	Preserve    int64 // The number of values on the top of the stack to preserve, 1 if PreserveTop is set
 	Return      bool  // Whether to return in order to take this branch/target
 }
 
@@ -255,12 +265,18 @@ func Compile(disassembly []disasm.Instr) ([]byte, *BytecodeMetadata) {
 			continue
 		case ops.Br:
 			if instr.NewStack != nil && instr.NewStack.StackTopDiff != 0 {
-				op := OpDiscard
-				if instr.NewStack.PreserveTop {
-					op = OpDiscardPreserveTop
+				switch {
+				case instr.NewStack.Preserve > 1:
+					emitMetadata(OpDiscardPreserve, buffer.Len(), discardPreserveLen)
+					buffer.WriteByte(OpDiscardPreserve)
+					binary.Write(buffer, binary.LittleEndian, int64(instr.NewStack.Preserve))
+				case instr.NewStack.PreserveTop:
+					emitMetadata(OpDiscardPreserveTop, buffer.Len(), instAndInt64Len)
+					buffer.WriteByte(OpDiscardPreserveTop)
+				default:
+					emitMetadata(OpDiscard, buffer.Len(), instAndInt64Len)
+					buffer.WriteByte(OpDiscard)
 				}
-				emitMetadata(op, buffer.Len(), instAndInt64Len)
-				buffer.WriteByte(op)
 				binary.Write(buffer, binary.LittleEndian, instr.NewStack.StackTopDiff)
 			}
 			emitMetadata(OpJmp, buffer.Len(), instAndInt64Len)
@@ -281,12 +297,12 @@ func Compile(disassembly []disasm.Instr) ([]byte, *BytecodeMetadata) {
 			binary.Write(buffer, binary.LittleEndian, int64(0))
 
 			var stackTopDiff int64
-			// write whether we need to preserve the top
-			if instr.NewStack == nil || !instr.NewStack.PreserveTop || instr.NewStack.StackTopDiff == 0 {
+			// write the number of values on the top we need to preserve
+			if instr.NewStack == nil || instr.NewStack.StackTopDiff == 0 {
 				buffer.WriteByte(byte(0))
 			} else {
 				stackTopDiff = instr.NewStack.StackTopDiff
-				buffer.WriteByte(byte(1))
+				buffer.WriteByte(byte(instr.NewStack.Preserve))
 			}
 			// write the number of elements on the stack we need to discard
 			binary.Write(buffer, binary.LittleEndian, stackTopDiff)
@@ -308,6 +324,7 @@ func Compile(disassembly []disasm.Instr) ([]byte, *BytecodeMetadata) {
 				branchTable.Targets[i].Return = branch.IsReturn
 				branchTable.Targets[i].Discard = branch.StackTopDiff
 				branchTable.Targets[i].PreserveTop = branch.PreserveTop
+				branchTable.Targets[i].Preserve = int64(branch.Preserve)
 			}
 			defaultLabel := int64(instr.Immediates[len(instr.Immediates)-1].(uint32))
 			branchTable.DefaultTarget.Addr = defaultLabel
@@ -315,6 +332,7 @@ func Compile(disassembly []disasm.Instr) ([]byte, *BytecodeMetadata) {
 			branchTable.DefaultTarget.Return = defaultBranch.IsReturn
 			branchTable.DefaultTarget.Discard = defaultBranch.StackTopDiff
 			branchTable.DefaultTarget.PreserveTop = defaultBranch.PreserveTop
+			branchTable.DefaultTarget.Preserve = int64(defaultBranch.Preserve)
 			branchTables = append(branchTables, branchTable)
 			for _, block := range blocks {
 				block.branchTables = append(block.branchTables, branchTable)
@@ -7,6 +7,8 @@ package exec
 import (
 	"errors"
 	"math"
+
+	ops "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/operators"
 )
 
 // ErrOutOfBoundsMemoryAccess is the error value used while trapping the VM
@@ -211,10 +213,52 @@ func (vm *VM) growMemory() {
 	n := vm.popInt32()
 
 	if vm.MemoryLimitation > 0 && uint64(len(vm.memory))+uint64(n*wasmPageSize) > vm.MemoryLimitation {
+		if vm.tracer != nil {
+			vm.tracer.CaptureGrowMemory(n, len(vm.memory), len(vm.memory))
+		}
 		vm.pushInt32(-1)
 		return
 	}
 
+	oldSize := len(vm.memory)
 	vm.memory = append(vm.memory, make([]byte, n*wasmPageSize)...)
+	if vm.tracer != nil {
+		vm.tracer.CaptureGrowMemory(n, oldSize, len(vm.memory))
+	}
 	vm.pushInt32(int32(curLen))
 }
+
+// memoryRange returns the range of n bytes of the linear memory from addr.
+func (vm *VM) memoryRange(addr, n uint64) []byte {
+	if addr+n > uint64(len(vm.memory)) {
+		panic(ErrOutOfBoundsMemoryAccess)
+	}
+	return vm.memory[addr : addr+n]
+}
+
+func (vm *VM) memoryCopy() {
+	_ = vm.fetchInt8() // reserved destination memory index
+	_ = vm.fetchInt8() // reserved source memory index
+	n := uint64(vm.popUint32())
+	src := uint64(vm.popUint32())
+	dst := uint64(vm.popUint32())
+	if vm.useMemoryGas != nil {
+		vm.useMemoryGas(ops.MemoryCopy, n)
+	}
+	// copy handles the overlapping ranges
+	copy(vm.memoryRange(dst, n), vm.memoryRange(src, n))
+}
+
+func (vm *VM) memoryFill() {
+	_ = vm.fetchInt8() // reserved memory index
+	n := uint64(vm.popUint32())
+	val := byte(vm.popUint32())
+	dst := uint64(vm.popUint32())
+	if vm.useMemoryGas != nil {
+		vm.useMemoryGas(ops.MemoryFill, n)
+	}
+	mem := vm.memoryRange(dst, n)
+	for i := range mem {
+		mem[i] = val
+	}
+}
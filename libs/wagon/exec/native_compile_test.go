@@ -139,7 +139,7 @@ func TestBasicAMD64(t *testing.T) {
 	vm := &VM{
 		funcs: []function{
 			compiledFunction{
-				returns:      true,
+				returns:      1,
 				maxDepth:     6,
 				code:         code,
 				branchTables: meta.BranchTables,
@@ -506,3 +506,23 @@ func (vm *VM) f64Ge() {
 	v1 := vm.popFloat64()
 	vm.pushBool(v1 >= v2)
 }
+
+func (vm *VM) i32Extend8S() {
+	vm.pushInt32(int32(int8(vm.popInt32())))
+}
+
+func (vm *VM) i32Extend16S() {
+	vm.pushInt32(int32(int16(vm.popInt32())))
+}
+
+func (vm *VM) i64Extend8S() {
+	vm.pushInt64(int64(int8(vm.popInt64())))
+}
+
+func (vm *VM) i64Extend16S() {
+	vm.pushInt64(int64(int16(vm.popInt64())))
+}
+
+func (vm *VM) i64Extend32S() {
+	vm.pushInt64(int64(int32(vm.popInt64())))
+}
@@ -0,0 +1,254 @@
+package exec
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/validate"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/leb128"
+	ops "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/operators"
+)
+
+const (
+	i32 = wasm.ValueTypeI32
+	i64 = wasm.ValueTypeI64
+	f64 = wasm.ValueTypeF64
+)
+
+// proposalTypes are the function and block types of proposalCodes.
+var proposalTypes = []wasm.FunctionSig{
+	{ParamTypes: []wasm.ValueType{i32}, ReturnTypes: []wasm.ValueType{i32}},
+	{ParamTypes: []wasm.ValueType{i64}, ReturnTypes: []wasm.ValueType{i64}},
+	{ParamTypes: []wasm.ValueType{f64}, ReturnTypes: []wasm.ValueType{i32}},
+	{ParamTypes: []wasm.ValueType{f64}, ReturnTypes: []wasm.ValueType{i64}},
+	{ParamTypes: []wasm.ValueType{i32, i32, i32}},
+	{ReturnTypes: []wasm.ValueType{i32, i64}},
+	{ParamTypes: []wasm.ValueType{i32, i32}, ReturnTypes: []wasm.ValueType{i32, i32}},
+	{ParamTypes: []wasm.ValueType{i32}, ReturnTypes: []wasm.ValueType{i32, i32}},
+	{ReturnTypes: []wasm.ValueType{i32, i32}},
+	{ParamTypes: []wasm.ValueType{i32, i32}, ReturnTypes: []wasm.ValueType{i32}},
+}
+
+// proposalCodes are the type index and the code of the functions of the test
+// module, none declares locals.
+var proposalCodes = []struct {
+	typ  uint32
+	code []byte
+}{
+	// 0: (i32.extend8_s (get_local 0))
+	{0, []byte{ops.GetLocal, 0, ops.I32Extend8S, ops.End}},
+	// 1: (i64.extend32_s (get_local 0))
+	{1, []byte{ops.GetLocal, 0, ops.I64Extend32S, ops.End}},
+	// 2: (i32.trunc_sat_f64_s (get_local 0))
+	{2, []byte{ops.GetLocal, 0, ops.PrefixMisc, 0x02, ops.End}},
+	// 3: (i64.trunc_sat_f64_u (get_local 0))
+	{3, []byte{ops.GetLocal, 0, ops.PrefixMisc, 0x07, ops.End}},
+	// 4: (memory.copy (get_local 0) (get_local 1) (get_local 2))
+	{4, []byte{ops.GetLocal, 0, ops.GetLocal, 1, ops.GetLocal, 2, ops.PrefixMisc, 0x0a, 0, 0, ops.End}},
+	// 5: (memory.fill (get_local 0) (get_local 1) (get_local 2))
+	{4, []byte{ops.GetLocal, 0, ops.GetLocal, 1, ops.GetLocal, 2, ops.PrefixMisc, 0x0b, 0, ops.End}},
+	// 6: (i32.const 1) (i64.const 2)
+	{5, []byte{ops.I32Const, 1, ops.I64Const, 2, ops.End}},
+	// 7: swaps the params in a block of type 6, the branch discards a value
+	// (get_local 0) (get_local 1)
+	// (block (type 6) (i32.const 7) (get_local 1) (get_local 0) (br 0))
+	{6, []byte{
+		ops.GetLocal, 0, ops.GetLocal, 1,
+		ops.Block, 6, ops.I32Const, 7, ops.GetLocal, 1, ops.GetLocal, 0, ops.Br, 0, ops.End,
+		ops.End,
+	}},
+	// 8: returns (3, 4) if the param is set, else (1, 2)
+	// (i32.const 1) (i32.const 2)
+	// (block (type 6) (i32.const 3) (i32.const 4) (br_if 0 (get_local 0)) (drop) (drop))
+	{7, []byte{
+		ops.I32Const, 1, ops.I32Const, 2,
+		ops.Block, 6, ops.I32Const, 3, ops.I32Const, 4, ops.GetLocal, 0, ops.BrIf, 0, ops.Drop, ops.Drop, ops.End,
+		ops.End,
+	}},
+	// 9: returns (6, 7) whichever block the branch targets
+	// (block (type 8) (block (type 8) (i32.const 5) (i32.const 6) (i32.const 7) (br_table 0 1 (get_local 0))))
+	{7, []byte{
+		ops.Block, 8, ops.Block, 8,
+		ops.I32Const, 5, ops.I32Const, 6, ops.I32Const, 7, ops.GetLocal, 0, ops.BrTable, 1, 0, 1,
+		ops.End, ops.End,
+		ops.End,
+	}},
+	// 10: the sum of the integers up to the param, in a loop of type 9
+	// (i32.const 0) (get_local 0)
+	// (loop (type 9)
+	//   (tee_local 0) (i32.add)
+	//   (tee_local 0 (i32.sub (get_local 0) (i32.const 1)))
+	//   (br_if 0 (get_local 0)) (drop))
+	{0, []byte{
+		ops.I32Const, 0, ops.GetLocal, 0,
+		ops.Loop, 9,
+		ops.TeeLocal, 0, ops.I32Add,
+		ops.GetLocal, 0, ops.I32Const, 1, ops.I32Sub, ops.TeeLocal, 0,
+		ops.GetLocal, 0, ops.BrIf, 0, ops.Drop,
+		ops.End,
+		ops.End,
+	}},
+	// 11: (i32.const 10) (if (type 0) (get_local 0) (then (i32.add (i32.const 1))) (else (i32.sub (i32.const 2))))
+	{0, []byte{
+		ops.I32Const, 10, ops.GetLocal, 0,
+		ops.If, 0, ops.I32Const, 1, ops.I32Add, ops.Else, ops.I32Const, 2, ops.I32Sub, ops.End,
+		ops.End,
+	}},
+}
+
+// encodeProposalModule encodes the module of proposalTypes and proposalCodes
+// with a memory of a page.
+func encodeProposalModule() []byte {
+	section := func(id byte, count int, body []byte) []byte {
+		content := leb128.AppendUleb128(nil, uint64(count))
+		content = append(content, body...)
+		sec := append([]byte{id}, leb128.AppendUleb128(nil, uint64(len(content)))...)
+		return append(sec, content...)
+	}
+	valueTypes := func(b []byte, types []wasm.ValueType) []byte {
+		b = leb128.AppendUleb128(b, uint64(len(types)))
+		for _, t := range types {
+			b = append(b, byte(t))
+		}
+		return b
+	}
+
+	raw := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	var types []byte
+	for _, sig := range proposalTypes {
+		types = append(types, 0x60)
+		types = valueTypes(types, sig.ParamTypes)
+		types = valueTypes(types, sig.ReturnTypes)
+	}
+	raw = append(raw, section(byte(wasm.SectionIDType), len(proposalTypes), types)...)
+
+	var funcs, codes []byte
+	for _, fn := range proposalCodes {
+		funcs = leb128.AppendUleb128(funcs, uint64(fn.typ))
+		body := append([]byte{0x00}, fn.code...) // no locals
+		codes = leb128.AppendUleb128(codes, uint64(len(body)))
+		codes = append(codes, body...)
+	}
+	raw = append(raw, section(byte(wasm.SectionIDFunction), len(proposalCodes), funcs)...)
+	raw = append(raw, section(byte(wasm.SectionIDMemory), 1, []byte{0x00, 0x01})...)
+	return append(raw, section(byte(wasm.SectionIDCode), len(proposalCodes), codes)...)
+}
+
+func newProposalVM(t *testing.T) *VM {
+	module, err := wasm.ReadModule(bytes.NewReader(encodeProposalModule()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validate.VerifyModule(module); err != nil {
+		t.Fatal(err)
+	}
+	vm, err := NewVM(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vm
+}
+
+func TestPostMVPProposals(t *testing.T) {
+	vm := newProposalVM(t)
+
+	tcs := []struct {
+		name string
+		fn   int64
+		args []uint64
+		want interface{}
+	}{
+		{"i32.extend8_s", 0, []uint64{0x80}, uint32(0xffffff80)},
+		{"i32.extend8_s positive", 0, []uint64{0x17f}, uint32(0x7f)},
+		{"i64.extend32_s", 1, []uint64{0x80000000}, uint64(0xffffffff80000000)},
+		{"i32.trunc_sat_f64_s", 2, []uint64{math.Float64bits(-3.9)}, uint32(0xfffffffd)},
+		{"i32.trunc_sat_f64_s overflow", 2, []uint64{math.Float64bits(1e20)}, uint32(math.MaxInt32)},
+		{"i32.trunc_sat_f64_s underflow", 2, []uint64{math.Float64bits(math.Inf(-1))}, uint32(0x80000000)},
+		{"i32.trunc_sat_f64_s NaN", 2, []uint64{math.Float64bits(math.NaN())}, uint32(0)},
+		{"i64.trunc_sat_f64_u", 3, []uint64{math.Float64bits(42.5)}, uint64(42)},
+		{"i64.trunc_sat_f64_u negative", 3, []uint64{math.Float64bits(-1)}, uint64(0)},
+		{"i64.trunc_sat_f64_u overflow", 3, []uint64{math.Float64bits(1e30)}, uint64(math.MaxUint64)},
+		{"multiple returns", 6, nil, []interface{}{uint32(1), uint64(2)}},
+		{"block params", 7, []uint64{1, 2}, []interface{}{uint32(2), uint32(1)}},
+		{"br_if taken", 8, []uint64{1}, []interface{}{uint32(3), uint32(4)}},
+		{"br_if not taken", 8, []uint64{0}, []interface{}{uint32(1), uint32(2)}},
+		{"br_table inner block", 9, []uint64{0}, []interface{}{uint32(6), uint32(7)}},
+		{"br_table outer block", 9, []uint64{1}, []interface{}{uint32(6), uint32(7)}},
+		{"loop params", 10, []uint64{4}, uint32(10)},
+		{"if params then", 11, []uint64{1}, uint32(11)},
+		{"if params else", 11, []uint64{0}, uint32(8)},
+	}
+	for _, tc := range tcs {
+		got, err := vm.ExecCode(tc.fn, tc.args...)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBulkMemory(t *testing.T) {
+	vm := newProposalVM(t)
+	vm.RecoverPanic = true
+	var sizes []uint64
+	vm.SetUseMemoryGas(func(op byte, size uint64) {
+		sizes = append(sizes, size)
+	})
+
+	// fill 8 bytes, then copy them 4 bytes further, the ranges overlap
+	if _, err := vm.ExecCode(5, 0, 0x1ab, 8); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.ExecCode(4, 4, 0, 8); err != nil {
+		t.Fatal(err)
+	}
+	want := bytes.Repeat([]byte{0xab}, 12)
+	if mem := vm.Memory(); !bytes.Equal(mem[:12], want) || mem[12] != 0 {
+		t.Fatalf("unexpected memory %x", mem[:16])
+	}
+	if !reflect.DeepEqual(sizes, []uint64{8, 8}) {
+		t.Fatalf("charged memory sizes %v, want [8 8]", sizes)
+	}
+
+	// the accesses out of the memory trap, even if the size wraps
+	size := uint64(len(vm.Memory()))
+	for _, args := range [][]uint64{{size - 4, 0, 8}, {0, size, 1}, {8, 0, math.MaxUint32}} {
+		if _, err := vm.ExecCode(4, args...); err != ErrOutOfBoundsMemoryAccess {
+			t.Errorf("memory.copy %v: got error %v, want %v", args, err, ErrOutOfBoundsMemoryAccess)
+		}
+	}
+	if _, err := vm.ExecCode(5, size, 0, 1); err != ErrOutOfBoundsMemoryAccess {
+		t.Errorf("memory.fill: got error %v, want %v", err, ErrOutOfBoundsMemoryAccess)
+	}
+	// an empty range at the end of the memory is in bounds
+	if _, err := vm.ExecCode(5, size, 0, 0); err != nil {
+		t.Errorf("memory.fill: unexpected error %v", err)
+	}
+}
+
+func TestDisablePostMVP(t *testing.T) {
+	module, err := wasm.ReadModule(bytes.NewReader(encodeProposalModule()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVM(module, DisablePostMVP(true)); err != ErrMultipleReturns {
+		t.Fatalf("got error %v, want %v", err, ErrMultipleReturns)
+	}
+
+	// the operators trap, the module is checked on its own
+	vm := newProposalVM(t)
+	vm.RecoverPanic = true
+	if err := vm.disablePostMVP(&wasm.Module{}); err != nil {
+		t.Fatal(err)
+	}
+	for fn, args := range [][]uint64{{0}, {0}, {0}, {0}, {0, 0, 0}, {0, 0, 0}} {
+		if _, err := vm.ExecCode(int64(fn), args...); err != ErrPostMVPOperator {
+			t.Errorf("function %d: got error %v, want %v", fn, err, ErrPostMVPOperator)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/exec/internal/compile"
+	ops "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/operators"
+)
+
+// Tracer is notified of the execution of the VM, see SetTracer.
+// The functions are identified by their index in the function index space
+// of the module, the host functions included.
+type Tracer interface {
+	// CaptureStep is called before the instruction op at pc of the compiled
+	// code of the function is executed, before its gas is used.
+	CaptureStep(fnIndex int64, pc int64, op byte)
+	// CaptureEnter is called when the function is called with the args.
+	CaptureEnter(fnIndex int64, args []uint64)
+	// CaptureExit is called when the function returns, a function unwound
+	// by a panic doesn't exit.
+	CaptureExit(fnIndex int64, returns []uint64)
+	// CaptureGrowMemory is called after the memory was asked to grow by pages,
+	// the size is unchanged if the memory limitation is exceeded.
+	CaptureGrowMemory(pages int32, oldSize, newSize int)
+}
+
+// traceValues copies the values passed to the tracer, the stack and the
+// locals they are read from are reused by the VM.
+func traceValues(vals []uint64) []uint64 {
+	return append([]uint64(nil), vals...)
+}
+
+// SetTracer sets the tracer notified of the execution, nil disables the tracing.
+func (vm *VM) SetTracer(tracer Tracer) {
+	vm.tracer = tracer
+}
+
+// OpName returns the name of the instruction op of the compiled code,
+// the compiler rewrites the branches to its own jumps.
+func OpName(op byte) string {
+	switch op {
+	case compile.OpJmp:
+		return "jmp"
+	case compile.OpJmpZ:
+		return "jmpz"
+	case compile.OpJmpNz:
+		return "jmpnz"
+	case compile.OpDiscard:
+		return "discard"
+	case compile.OpDiscardPreserveTop:
+		return "discard_preserve_top"
+	case compile.OpDiscardPreserve:
+		return "discard_preserve"
+	case ops.WagonNativeExec:
+		return "wagon.nativeExec"
+	}
+	if o, err := ops.New(op); err == nil {
+		return o.Name
+	}
+	if o, ok := ops.Internal(op); ok {
+		return o.Name
+	}
+	return fmt.Sprintf("%#x", op)
+}
@@ -26,6 +26,13 @@ var (
 	// ErrInvalidArgumentCount is returned by (*VM).ExecCode when an invalid
 	// number of arguments to the WebAssembly function are passed to it.
 	ErrInvalidArgumentCount = errors.New("exec: invalid number of arguments to function")
+	// ErrMultipleReturns is returned by NewVM and NewVMWithCompiled when the
+	// post-MVP features are disabled and a function type of the module
+	// returns several values.
+	ErrMultipleReturns = errors.New("exec: function returning several values in module")
+	// ErrPostMVPOperator is the error value used while trapping the VM when
+	// an operator of the post-MVP proposals is executed while they are disabled.
+	ErrPostMVPOperator = errors.New("exec: post-MVP operator is disabled")
 )
 
 // InvalidReturnTypeError is returned by (*VM).ExecCode when the module
@@ -79,6 +86,11 @@ type VM struct {
 	hostCtx interface{}
 
 	useGas func(byte)
+	// useMemoryGas charges the size in bytes of the memory a bulk memory
+	// operator accesses, on top of the gas of the operator.
+	useMemoryGas func(op byte, size uint64)
+
+	tracer Tracer
 
 	//memory limitation
 	MemoryLimitation uint64
@@ -90,7 +102,8 @@ const wasmPageSize = 65536 // (64 KB)
 var endianess = binary.LittleEndian
 
 type config struct {
-	EnableAOT bool
+	EnableAOT      bool
+	DisablePostMVP bool
 }
 
 // VMOption describes a customization that can be applied to the VM.
@@ -105,6 +118,33 @@ func EnableAOT(v bool) VMOption {
 	}
 }
 
+// DisablePostMVP disables the features of the post-MVP proposals: the
+// operators trap the VM and the modules with functions returning several
+// values are rejected.
+func DisablePostMVP(v bool) VMOption {
+	return func(c *config) {
+		c.DisablePostMVP = v
+	}
+}
+
+// disablePostMVP replaces the post-MVP operators of the function table by a
+// trap, and checks the function types of the module return at most one value.
+func (vm *VM) disablePostMVP(module *wasm.Module) error {
+	if module.Types != nil {
+		for _, sig := range module.Types.Entries {
+			if len(sig.ReturnTypes) > 1 {
+				return ErrMultipleReturns
+			}
+		}
+	}
+	for code := range vm.funcTable {
+		if ops.IsPostMVP(byte(code)) {
+			vm.funcTable[code] = func() { panic(ErrPostMVPOperator) }
+		}
+	}
+	return nil
+}
+
 // NewVM creates a new VM from a given module and options. If the module defines
 // a start function, it will be executed.
 func NewVM(module *wasm.Module, opts ...VMOption) (*VM, error) {
@@ -126,6 +166,11 @@ func NewVM(module *wasm.Module, opts ...VMOption) (*VM, error) {
 	vm.globals = make([]uint64, len(module.GlobalIndexSpace))
 	vm.newFuncTable()
 	vm.module = module
+	if options.DisablePostMVP {
+		if err := vm.disablePostMVP(module); err != nil {
+			return nil, err
+		}
+	}
 
 	nNatives := 0
 	for i, fn := range module.FunctionIndexSpace {
@@ -162,7 +207,7 @@ func NewVM(module *wasm.Module, opts ...VMOption) (*VM, error) {
 			maxDepth:       disassembly.MaxDepth,
 			totalLocalVars: totalLocalVars,
 			args:           len(fn.Sig.ParamTypes),
-			returns:        len(fn.Sig.ReturnTypes) != 0,
+			returns:        len(fn.Sig.ReturnTypes),
 		}
 	}
 
@@ -228,6 +273,12 @@ func (vm *VM) SetUseGas(useGas func(byte)) {
 	vm.useGas = useGas
 }
 
+// SetUseMemoryGas sets the function charging the memory accessed by the bulk
+// memory operators, called before the memory is accessed.
+func (vm *VM) SetUseMemoryGas(useMemoryGas func(op byte, size uint64)) {
+	vm.useMemoryGas = useMemoryGas
+}
+
 func (vm *VM) pushBool(v bool) {
 	if v {
 		vm.pushUint64(1)
@@ -331,7 +382,8 @@ func (vm *VM) pushFloat32(f float32) {
 
 // ExecCode calls the function with the given index and arguments.
 // fnIndex should be a valid index into the function index space of
-// the VM's module.
+// the VM's module. The values returned by a function returning several
+// ones are returned as a []interface{}.
 func (vm *VM) ExecCode(fnIndex int64, args ...uint64) (rtrn interface{}, err error) {
 	// If used as a library, client code should set vm.RecoverPanic to true
 	// in order to have an error returned.
@@ -375,30 +427,66 @@ func (vm *VM) ExecCode(fnIndex int64, args ...uint64) (rtrn interface{}, err err
 		vm.ctx.locals[i] = arg
 	}
 
+	if vm.tracer != nil {
+		vm.tracer.CaptureEnter(fnIndex, traceValues(args))
+	}
 	res := vm.execCode(compiled)
-	if compiled.returns {
-		rtrnType := vm.module.GetFunction(int(fnIndex)).Sig.ReturnTypes[0]
+	if vm.tracer != nil {
+		vm.tracer.CaptureExit(fnIndex, traceValues(res))
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	rtrnTypes := vm.module.GetFunction(int(fnIndex)).Sig.ReturnTypes
+	rtrns := make([]interface{}, len(res))
+	for i, rtrnType := range rtrnTypes {
 		switch rtrnType {
 		case wasm.ValueTypeI32:
-			rtrn = uint32(res)
+			rtrns[i] = uint32(res[i])
 		case wasm.ValueTypeI64:
-			rtrn = uint64(res)
+			rtrns[i] = uint64(res[i])
 		case wasm.ValueTypeF32:
-			rtrn = math.Float32frombits(uint32(res))
+			rtrns[i] = math.Float32frombits(uint32(res[i]))
 		case wasm.ValueTypeF64:
-			rtrn = math.Float64frombits(res)
+			rtrns[i] = math.Float64frombits(res[i])
 		default:
 			return nil, InvalidReturnTypeError(rtrnType)
 		}
 	}
-
-	return rtrn, nil
+	if len(rtrns) == 1 {
+		return rtrns[0], nil
+	}
+	return rtrns, nil
+}
+
+// unwind discards elements from the stack while preserving the given number
+// of values on its top.
+func (vm *VM) unwind(preserve int, discard int64) {
+	stack := vm.ctx.stack
+	switch preserve {
+	case 0:
+		vm.ctx.stack = stack[:len(stack)-int(discard)]
+	case 1:
+		top := stack[len(stack)-1]
+		vm.ctx.stack = stack[:len(stack)-int(discard)]
+		vm.pushUint64(top)
+	default:
+		base := len(stack) - int(discard)
+		copy(stack[base:], stack[len(stack)-preserve:])
+		vm.ctx.stack = stack[:base+preserve]
+	}
 }
 
-func (vm *VM) execCode(compiled compiledFunction) uint64 {
+// execCode executes the code of the function, and returns the values it
+// returns, on the top of the stack.
+func (vm *VM) execCode(compiled compiledFunction) []uint64 {
 outer:
 	for int(vm.ctx.pc) < len(vm.ctx.code) && !vm.abort {
 		op := vm.ctx.code[vm.ctx.pc]
+		if vm.tracer != nil {
+			vm.tracer.CaptureStep(vm.ctx.curFunc, vm.ctx.pc, op)
+		}
 		if vm.useGas != nil {
 			vm.useGas(op)
 		}
@@ -417,18 +505,11 @@ outer:
 			}
 		case compile.OpJmpNz:
 			target := vm.fetchInt64()
-			preserveTop := vm.fetchBool()
+			preserve := uint8(vm.fetchInt8())
 			discard := vm.fetchInt64()
 			if vm.popUint32() != 0 {
 				vm.ctx.pc = target
-				var top uint64
-				if preserveTop {
-					top = vm.ctx.stack[len(vm.ctx.stack)-1]
-				}
-				vm.ctx.stack = vm.ctx.stack[:len(vm.ctx.stack)-int(discard)]
-				if preserveTop {
-					vm.pushUint64(top)
-				}
+				vm.unwind(int(preserve), discard)
 				continue
 			}
 		case ops.BrTable:
@@ -450,14 +531,11 @@ outer:
 				break outer
 			}
 			vm.ctx.pc = target.Addr
-			var top uint64
+			preserve := int(target.Preserve)
 			if target.PreserveTop {
-				top = vm.ctx.stack[len(vm.ctx.stack)-1]
-			}
-			vm.ctx.stack = vm.ctx.stack[:len(vm.ctx.stack)-int(target.Discard)]
-			if target.PreserveTop {
-				vm.pushUint64(top)
+				preserve = 1
 			}
+			vm.unwind(preserve, target.Discard)
 			continue
 		case compile.OpDiscard:
 			place := vm.fetchInt64()
@@ -467,6 +545,10 @@ outer:
 			place := vm.fetchInt64()
 			vm.ctx.stack = vm.ctx.stack[:len(vm.ctx.stack)-int(place)]
 			vm.pushUint64(top)
+		case compile.OpDiscardPreserve:
+			preserve := vm.fetchInt64()
+			place := vm.fetchInt64()
+			vm.unwind(int(preserve), place)
 
 		case ops.WagonNativeExec:
 			i := vm.fetchUint32()
@@ -476,10 +558,10 @@ outer:
 		}
 	}
 
-	if compiled.returns && !vm.abort {
-		return vm.ctx.stack[len(vm.ctx.stack)-1]
+	if compiled.returns != 0 && !vm.abort {
+		return vm.ctx.stack[len(vm.ctx.stack)-compiled.returns:]
 	}
-	return 0
+	return nil
 }
 
 // Restart readies the VM for another run.
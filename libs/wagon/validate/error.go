@@ -113,6 +113,14 @@ func (e NoSectionError) Error() string {
 	return fmt.Sprintf("reference to non existant section (id %d) in module", wasm.SectionID(e))
 }
 
+// MultipleReturnsError is returned if a function type of a module verified
+// without the multi-value proposal returns several values.
+type MultipleReturnsError int
+
+func (e MultipleReturnsError) Error() string {
+	return fmt.Sprintf("function type returns %d values, at most one is allowed", int(e))
+}
+
 // UnbalancedStackErr is returned if there are too many items on the stack
 // than is valid for the current block or function.
 type UnbalancedStackErr wasm.ValueType
@@ -16,7 +16,8 @@ import (
 )
 
 // vibhavp: TODO: We do not verify whether blocks don't access for the parent block, do that.
-func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Module) (*mockVM, error) {
+// The operators and the block types of the post-MVP proposals are rejected if mvp is set.
+func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Module, mvp bool) (*mockVM, error) {
 	vm := &mockVM{
 		stack:      make([]operand, 0, 6),
 		code:       bytes.NewReader(body.Code),
@@ -56,9 +57,25 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 			return vm, err
 		}
 
-		opStruct, err := ops.New(op)
-		if err != nil {
-			return vm, err
+		var opStruct ops.Op
+		if mvp && (ops.IsPrefix(op) || ops.IsPostMVP(op)) {
+			return vm, ops.InvalidOpcodeError(op)
+		}
+		if ops.IsPrefix(op) {
+			sub, err := vm.fetchVarUint()
+			if err != nil {
+				return vm, err
+			}
+			opStruct, err = ops.NewPrefixed(op, sub)
+			if err != nil {
+				return vm, err
+			}
+			op = opStruct.Code
+		} else {
+			opStruct, err = ops.New(op)
+			if err != nil {
+				return vm, err
+			}
 		}
 
 		logger.Printf("PC: %d OP: %s unreachable: %v", vm.pc(), opStruct.Name, vm.topFrameUnreachable())
@@ -71,34 +88,30 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 
 		switch op {
 
-		case ops.Block, ops.If: // If operand is handled in adjustStack()
-			sig, err := vm.fetchByte()
+		case ops.Block, ops.Loop, ops.If: // If operand is handled in adjustStack()
+			sig, err := wasm.ReadBlockType(vm.code)
 			if err != nil {
-				return vm, err
+				return vm, InvalidImmediateError{"block_type", opStruct.Name}
 			}
-
-			switch retVal := wasm.ValueType(sig); retVal {
-			case wasm.ValueType(wasm.BlockTypeEmpty):
-				vm.pushFrame(op, nil, nil)
-			case wasm.ValueTypeI32, wasm.ValueTypeI64, wasm.ValueTypeF32, wasm.ValueTypeF64:
-				vm.pushFrame(op, []wasm.ValueType{retVal}, []wasm.ValueType{retVal})
-			default:
+			if _, ok := sig.(wasm.BlockTypeIndex); ok && mvp {
 				return vm, InvalidImmediateError{"block_type", opStruct.Name}
 			}
-
-		case ops.Loop:
-			sig, err := vm.fetchByte()
+			params, results, err := module.BlockSignature(sig)
 			if err != nil {
 				return vm, err
 			}
 
-			switch retVal := wasm.ValueType(sig); retVal {
-			case wasm.ValueType(wasm.BlockTypeEmpty):
-				vm.pushFrame(op, nil, nil)
-			case wasm.ValueTypeI32, wasm.ValueTypeI64, wasm.ValueTypeF32, wasm.ValueTypeF64:
-				vm.pushFrame(op, nil, []wasm.ValueType{retVal})
-			default:
-				return vm, InvalidImmediateError{"block_type", opStruct.Name}
+			// The params of the block are moved to the stack of the block.
+			if err := vm.popOperands(params); err != nil {
+				return vm, err
+			}
+			if op == ops.Loop {
+				vm.pushFrame(op, params, params, results)
+			} else {
+				vm.pushFrame(op, params, results, results)
+			}
+			for _, t := range params {
+				vm.pushOperand(t)
 			}
 
 		case ops.Else:
@@ -109,7 +122,10 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 			if frame == nil || frame.op != ops.If {
 				return vm, UnmatchedOpError(op)
 			}
-			vm.pushFrame(op, frame.endTypes, frame.endTypes)
+			vm.pushFrame(op, frame.startTypes, frame.endTypes, frame.endTypes)
+			for _, t := range frame.startTypes {
+				vm.pushOperand(t)
+			}
 
 		case ops.End:
 			// Block 'return' type is validated in popFrame().
@@ -121,9 +137,11 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 			// END should match with a IF/BLOCK/LOOP frame.
 			case frame == nil || frame.op == operators.Call:
 				return vm, UnmatchedOpError(op)
-			// IF block with no else cannot have a result.
-			case frame.op == operators.If && len(frame.endTypes) > 0:
-				return vm, UnmatchedIfValueErr(frame.endTypes[0])
+			// IF block with no else returns its params.
+			case frame.op == operators.If:
+				if err := checkImplicitElse(frame); err != nil {
+					return vm, err
+				}
 			}
 			for _, t := range frame.endTypes {
 				vm.pushOperand(t)
@@ -236,18 +254,8 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 			vm.setUnreachable()
 
 		case ops.Return:
-			if len(fn.ReturnTypes) > 1 {
-				panic("not implemented")
-			}
-			if len(fn.ReturnTypes) != 0 {
-				// only single returns supported for now
-				op, err := vm.popOperand()
-				if err != nil {
-					return vm, err
-				}
-				if !op.Equal(fn.ReturnTypes[0]) {
-					return vm, InvalidTypeError{fn.ReturnTypes[0], op.Type}
-				}
+			if err := vm.popOperands(fn.ReturnTypes); err != nil {
+				return vm, err
 			}
 			vm.setUnreachable()
 
@@ -358,7 +366,7 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 				}
 			}
 
-		case ops.CurrentMemory, ops.GrowMemory:
+		case ops.CurrentMemory, ops.GrowMemory, ops.MemoryFill:
 			memIndex, err := vm.fetchByte()
 			if err != nil {
 				return vm, err
@@ -367,6 +375,18 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 				return vm, InvalidTableIndexError{"memory", uint32(memIndex)}
 			}
 
+		case ops.MemoryCopy:
+			// the destination and the source memory indices
+			for i := 0; i < 2; i++ {
+				memIndex, err := vm.fetchByte()
+				if err != nil {
+					return vm, err
+				}
+				if memIndex != 0x00 {
+					return vm, InvalidTableIndexError{"memory", uint32(memIndex)}
+				}
+			}
+
 		case ops.Call:
 			index, err := vm.fetchVarUint()
 			if err != nil {
@@ -492,14 +512,10 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 		default:
 			return vm, err
 		}
-	case 1:
-		op, err := vm.popOperand()
-		if err != nil {
+	default:
+		if err := vm.popOperands(fn.ReturnTypes); err != nil {
 			return vm, err
 		}
-		if !op.Equal(fn.ReturnTypes[0]) {
-			return vm, InvalidTypeError{fn.ReturnTypes[0], op.Type}
-		}
 	}
 
 	f, err := vm.popFrame()
@@ -514,14 +530,33 @@ func verifyBody(fn *wasm.FunctionSig, body *wasm.FunctionBody, module *wasm.Modu
 }
 
 // VerifyModule verifies the given module according to WebAssembly verification
-// specs.
+// specs, the features of the post-MVP proposals wagon supports are accepted.
 func VerifyModule(module *wasm.Module) error {
+	return verifyModule(module, false)
+}
+
+// VerifyModuleMVP verifies the given module like VerifyModule, but rejects the
+// features of the post-MVP proposals: the sign-extension operators, the
+// non-trapping float-to-int conversions, the bulk memory operators and the
+// multi-value block types and function results.
+func VerifyModuleMVP(module *wasm.Module) error {
+	return verifyModule(module, true)
+}
+
+func verifyModule(module *wasm.Module, mvp bool) error {
 	if module.Function == nil || module.Types == nil || len(module.Types.Entries) == 0 {
 		return nil
 	}
 	if module.Code == nil {
 		return NoSectionError(wasm.SectionIDCode)
 	}
+	if mvp {
+		for _, sig := range module.Types.Entries {
+			if len(sig.ReturnTypes) > 1 {
+				return MultipleReturnsError(len(sig.ReturnTypes))
+			}
+		}
+	}
 
 	logger.Printf("There are %d functions", len(module.Function.Types))
 	for i, fn := range module.FunctionIndexSpace {
@@ -529,7 +564,7 @@ func VerifyModule(module *wasm.Module) error {
 		if fn.IsHost() {
 			continue
 		}
-		if vm, err := verifyBody(fn.Sig, fn.Body, module); err != nil {
+		if vm, err := verifyBody(fn.Sig, fn.Body, module, mvp); err != nil {
 			return Error{vm.pc(), i, err}
 		}
 		logger.Printf("No errors in function %d (%q)", i, fn.Name)
@@ -154,7 +154,7 @@ func TestValidateAlignment(t *testing.T) {
 			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
 			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -214,7 +214,7 @@ func TestValidateMemory(t *testing.T) {
 			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
 			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -392,7 +392,7 @@ func TestValidateFuncTypecheck(t *testing.T) {
 				},
 			}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -551,7 +551,7 @@ func TestValidateLocalsGlobals(t *testing.T) {
 				},
 			}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -939,7 +939,7 @@ func TestValidateBlockTypecheck(t *testing.T) {
 			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
 			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -1126,7 +1126,7 @@ func TestValidateIfBlock(t *testing.T) {
 			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
 			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
@@ -1303,10 +1303,251 @@ func TestValidateStackTypechecking(t *testing.T) {
 			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
 			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
 
-			_, err := verifyBody(&sig, &fn, &mod)
+			_, err := verifyBody(&sig, &fn, &mod, false)
 			if err != tc.err {
 				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
 			}
 		})
 	}
 }
+
+func TestValidatePostMVP(t *testing.T) {
+	i32, i64 := wasm.ValueTypeI32, wasm.ValueTypeI64
+	tcs := []struct {
+		name    string
+		code    []byte
+		returns []wasm.ValueType
+		err     error
+	}{
+		{
+			name: "sign extension",
+			// (drop (i32.extend16_s (i32.const 0)))
+			code: []byte{operators.I32Const, 0, operators.I32Extend16S, operators.Drop},
+			err:  nil,
+		},
+		{
+			name: "sign extension type",
+			// (drop (i64.extend8_s (i32.const 0)))
+			code: []byte{operators.I32Const, 0, operators.I64Extend8S, operators.Drop},
+			err:  InvalidTypeError{i64, i32},
+		},
+		{
+			name: "trunc_sat",
+			// (drop (i64.trunc_sat_f64_s (f64.const 0)))
+			code: []byte{operators.F64Const, 0, 0, 0, 0, 0, 0, 0, 0, operators.PrefixMisc, 0x06, operators.Drop},
+			err:  nil,
+		},
+		{
+			name: "unknown prefixed opcode",
+			code: []byte{operators.PrefixMisc, 0x0c},
+			err:  operators.InvalidSubOpcodeError{Prefix: operators.PrefixMisc, SubCode: 0x0c},
+		},
+		{
+			name: "memory.copy",
+			// (memory.copy (i32.const 0) (i32.const 0) (i32.const 0))
+			code: []byte{operators.I32Const, 0, operators.I32Const, 0, operators.I32Const, 0, operators.PrefixMisc, 0x0a, 0, 0},
+			err:  nil,
+		},
+		{
+			name: "memory.fill memory index",
+			// (memory.fill 1 (i32.const 0) (i32.const 0) (i32.const 0))
+			code: []byte{operators.I32Const, 0, operators.I32Const, 0, operators.I32Const, 0, operators.PrefixMisc, 0x0b, 1},
+			err:  InvalidTableIndexError{"memory", 1},
+		},
+		{
+			name: "block params",
+			// (i32.const 0) (i64.const 0) (block (type 0) (drop)) (drop)
+			code: []byte{
+				operators.I32Const, 0, operators.I64Const, 0,
+				operators.Block, 0, operators.Drop, operators.End,
+				operators.Drop,
+			},
+			err: nil,
+		},
+		{
+			name: "block params type",
+			// (i64.const 0) (i32.const 0) (block (type 0) (drop)) (drop)
+			code: []byte{
+				operators.I64Const, 0, operators.I32Const, 0,
+				operators.Block, 0, operators.Drop, operators.End,
+				operators.Drop,
+			},
+			err: InvalidTypeError{i64, i32},
+		},
+		{
+			name: "block params underflow",
+			// (i64.const 0) (block (type 0) (drop))
+			code: []byte{operators.I64Const, 0, operators.Block, 0, operators.Drop, operators.End},
+			err:  ErrStackUnderflow,
+		},
+		{
+			name: "block type index",
+			code: []byte{operators.Block, 5, operators.End},
+			err:  wasm.InvalidTypeIndexError(5),
+		},
+		{
+			name: "loop label params",
+			// (i32.const 0) (i64.const 0) (loop (type 0) (br 0))
+			code: []byte{
+				operators.I32Const, 0, operators.I64Const, 0,
+				operators.Loop, 0, operators.Br, 0, operators.End,
+			},
+			returns: []wasm.ValueType{i32},
+			err:     nil,
+		},
+		{
+			name: "if params without else",
+			// (i32.const 0) (if (type 1) (i32.const 1)) (drop)
+			code: []byte{
+				operators.I32Const, 0, operators.I32Const, 1,
+				operators.If, 1, operators.End,
+				operators.Drop,
+			},
+			err: nil,
+		},
+		{
+			name: "if params without else results",
+			// (i32.const 0) (i64.const 0) (if (type 0) (drop))
+			code: []byte{
+				operators.I32Const, 0, operators.I64Const, 0, operators.I32Const, 1,
+				operators.If, 0, operators.Drop, operators.End,
+				operators.Drop,
+			},
+			err: UnbalancedStackErr(i64),
+		},
+		{
+			name: "multiple returns",
+			// (i32.const 0) (i64.const 0)
+			code:    []byte{operators.I32Const, 0, operators.I64Const, 0},
+			returns: []wasm.ValueType{i32, i64},
+			err:     nil,
+		},
+		{
+			name: "multiple returns return",
+			// (return (i32.const 0) (i64.const 0))
+			code:    []byte{operators.I32Const, 0, operators.I64Const, 0, operators.Return},
+			returns: []wasm.ValueType{i32, i64},
+			err:     nil,
+		},
+		{
+			name: "multiple returns order",
+			// (i64.const 0) (i32.const 0)
+			code:    []byte{operators.I64Const, 0, operators.I32Const, 0},
+			returns: []wasm.ValueType{i32, i64},
+			err:     InvalidTypeError{i64, i32},
+		},
+	}
+
+	for i := range tcs {
+		tc := tcs[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mod := wasm.Module{
+				Types: &wasm.SectionTypes{
+					Entries: []wasm.FunctionSig{
+						{Form: 0x60, ParamTypes: []wasm.ValueType{i32, i64}, ReturnTypes: []wasm.ValueType{i32}},
+						{Form: 0x60, ParamTypes: []wasm.ValueType{i32}, ReturnTypes: []wasm.ValueType{i32}},
+					},
+				},
+			}
+			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */, ReturnTypes: tc.returns}
+			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
+
+			_, err := verifyBody(&sig, &fn, &mod, false)
+			if err != tc.err {
+				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestValidateMVP(t *testing.T) {
+	i32, i64 := wasm.ValueTypeI32, wasm.ValueTypeI64
+	tcs := []struct {
+		name string
+		code []byte
+		err  error
+	}{
+		{
+			name: "mvp",
+			// (drop (i32.const 0))
+			code: []byte{operators.I32Const, 0, operators.Drop},
+			err:  nil,
+		},
+		{
+			name: "block result",
+			// (drop (block (result i32) (i32.const 0)))
+			code: []byte{operators.Block, byte(i32), operators.I32Const, 0, operators.End, operators.Drop},
+			err:  nil,
+		},
+		{
+			name: "sign extension",
+			// (drop (i32.extend16_s (i32.const 0)))
+			code: []byte{operators.I32Const, 0, operators.I32Extend16S, operators.Drop},
+			err:  operators.InvalidOpcodeError(operators.I32Extend16S),
+		},
+		{
+			name: "trunc_sat",
+			// (drop (i64.trunc_sat_f64_s (f64.const 0)))
+			code: []byte{operators.F64Const, 0, 0, 0, 0, 0, 0, 0, 0, operators.PrefixMisc, 0x06, operators.Drop},
+			err:  operators.InvalidOpcodeError(operators.PrefixMisc),
+		},
+		{
+			name: "memory.fill",
+			// (memory.fill (i32.const 0) (i32.const 0) (i32.const 0))
+			code: []byte{operators.I32Const, 0, operators.I32Const, 0, operators.I32Const, 0, operators.PrefixMisc, 0x0b, 0},
+			err:  operators.InvalidOpcodeError(operators.PrefixMisc),
+		},
+		{
+			name: "block type index",
+			// (i32.const 0) (i64.const 0) (block (type 0) (drop)) (drop)
+			code: []byte{
+				operators.I32Const, 0, operators.I64Const, 0,
+				operators.Block, 0, operators.Drop, operators.End,
+				operators.Drop,
+			},
+			err: InvalidImmediateError{"block_type", "block"},
+		},
+	}
+
+	mod := wasm.Module{
+		Types: &wasm.SectionTypes{
+			Entries: []wasm.FunctionSig{
+				{Form: 0x60, ParamTypes: []wasm.ValueType{i32, i64}, ReturnTypes: []wasm.ValueType{i32}},
+			},
+		},
+	}
+	for i := range tcs {
+		tc := tcs[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sig := wasm.FunctionSig{Form: 0x60 /* Must always be 0x60 */}
+			fn := wasm.FunctionBody{Module: &mod, Code: tc.code}
+
+			_, err := verifyBody(&sig, &fn, &mod, true)
+			if err != tc.err {
+				t.Fatalf("verify returned '%v', want '%v'", err, tc.err)
+			}
+		})
+	}
+
+	t.Run("multiple returns", func(t *testing.T) {
+		mod := wasm.Module{
+			Types: &wasm.SectionTypes{
+				Entries: []wasm.FunctionSig{
+					{Form: 0x60, ReturnTypes: []wasm.ValueType{i32, i64}},
+				},
+			},
+			Function: &wasm.SectionFunctions{Types: []uint32{0}},
+			Code:     &wasm.SectionCode{},
+		}
+		if err := VerifyModuleMVP(&mod); err != MultipleReturnsError(2) {
+			t.Fatalf("verify returned '%v', want '%v'", err, MultipleReturnsError(2))
+		}
+		if err := VerifyModule(&mod); err != nil {
+			t.Fatalf("verify returned '%v', want nil", err)
+		}
+	})
+}
@@ -32,6 +32,7 @@ type mockVM struct {
 // blocks.
 type frame struct {
 	pc          int              // the pc of the instruction declaring the frame
+	startTypes  []wasm.ValueType // type signatures of frame params
 	labelTypes  []wasm.ValueType // types signatures of associated labels
 	endTypes    []wasm.ValueType // type signatures of frame return values
 	stackHeight int              // height of the stack when the frame was started
@@ -86,10 +87,11 @@ func (vm *mockVM) fetchUint64() (uint64, error) {
 	return binary.LittleEndian.Uint64(buf[:]), nil
 }
 
-func (vm *mockVM) pushFrame(op byte, labelTypes, returnTypes []wasm.ValueType) {
+func (vm *mockVM) pushFrame(op byte, startTypes, labelTypes, returnTypes []wasm.ValueType) {
 	vm.ctrlFrames = append(vm.ctrlFrames, frame{
 		pc:          vm.pc(),
 		stackHeight: len(vm.stack),
+		startTypes:  startTypes,
 		labelTypes:  labelTypes,
 		endTypes:    returnTypes,
 		op:          op,
@@ -172,6 +174,37 @@ func (vm *mockVM) popOperand() (op operand, err error) {
 	return op, nil
 }
 
+// popOperands pops operands of the types ts, the last one first.
+func (vm *mockVM) popOperands(ts []wasm.ValueType) error {
+	for i := len(ts) - 1; i >= 0; i-- {
+		op, err := vm.popOperand()
+		if err != nil {
+			return err
+		}
+		if !op.Equal(ts[i]) {
+			return InvalidTypeError{ts[i], op.Type}
+		}
+	}
+	return nil
+}
+
+// checkImplicitElse checks the results of an if block with no else, which
+// returns its params if the condition is false.
+func checkImplicitElse(f *frame) error {
+	for i, t := range f.endTypes {
+		if i >= len(f.startTypes) {
+			return UnmatchedIfValueErr(t)
+		}
+		if f.startTypes[i] != t {
+			return InvalidTypeError{t, f.startTypes[i]}
+		}
+	}
+	if len(f.startTypes) > len(f.endTypes) {
+		return UnbalancedStackErr(f.startTypes[len(f.endTypes)])
+	}
+	return nil
+}
+
 func (vm *mockVM) pushOperand(t wasm.ValueType) {
 	o := operand{t}
 	// logger.Printf("Stack top: %d, Len of stack :%d", vm.stack[len(vm.stack)-1], len(vm.stack))
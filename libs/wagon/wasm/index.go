@@ -17,6 +17,12 @@ func (e InvalidTableIndexError) Error() string {
 	return fmt.Sprintf("wasm: Invalid table to table index space: %d", uint32(e))
 }
 
+type InvalidTypeIndexError uint32
+
+func (e InvalidTypeIndexError) Error() string {
+	return fmt.Sprintf("wasm: Invalid type index: %d", uint32(e))
+}
+
 type InvalidValueTypeInitExprError struct {
 	Wanted reflect.Kind
 	Got    reflect.Kind
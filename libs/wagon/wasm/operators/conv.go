@@ -27,7 +27,7 @@ func valType(s string) wasm.ValueType {
 	}
 }
 
-func newConversionOp(code byte, name string) byte {
+func conversionTypes(name string) ([]wasm.ValueType, wasm.ValueType) {
 	matches := reCvrtOp.FindStringSubmatch(name)
 	if len(matches) == 0 {
 		panic(name + " is not a conversion operator")
@@ -36,7 +36,17 @@ func newConversionOp(code byte, name string) byte {
 	returns := valType(matches[1])
 	param := valType(matches[2])
 
-	return newOp(code, name, []wasm.ValueType{param}, returns)
+	return []wasm.ValueType{param}, returns
+}
+
+func newConversionOp(code byte, name string) byte {
+	args, returns := conversionTypes(name)
+	return newOp(code, name, args, returns)
+}
+
+func newMiscConversionOp(sub uint32, code byte, name string) byte {
+	args, returns := conversionTypes(name)
+	return newPrefixedOp(PrefixMisc, sub, code, name, args, returns)
 }
 
 var (
@@ -62,3 +72,15 @@ var (
 	F64ConvertUI64 = newConversionOp(0xba, "f64.convert_u/i64")
 	F64PromoteF32  = newConversionOp(0xbb, "f64.promote/f32")
 )
+
+// The non-trapping float-to-int conversions, they saturate instead of trapping.
+var (
+	I32TruncSatSF32 = newMiscConversionOp(0x00, 0xe0, "i32.trunc_sat_s/f32")
+	I32TruncSatUF32 = newMiscConversionOp(0x01, 0xe1, "i32.trunc_sat_u/f32")
+	I32TruncSatSF64 = newMiscConversionOp(0x02, 0xe2, "i32.trunc_sat_s/f64")
+	I32TruncSatUF64 = newMiscConversionOp(0x03, 0xe3, "i32.trunc_sat_u/f64")
+	I64TruncSatSF32 = newMiscConversionOp(0x04, 0xe4, "i64.trunc_sat_s/f32")
+	I64TruncSatUF32 = newMiscConversionOp(0x05, 0xe5, "i64.trunc_sat_u/f32")
+	I64TruncSatSF64 = newMiscConversionOp(0x06, 0xe6, "i64.trunc_sat_s/f64")
+	I64TruncSatUF64 = newMiscConversionOp(0x07, 0xe7, "i64.trunc_sat_u/f64")
+)
@@ -39,3 +39,9 @@ var (
 	CurrentMemory = newOp(0x3f, "memory.size", nil, wasm.ValueTypeI32)
 	GrowMemory    = newOp(0x40, "memory.grow", []wasm.ValueType{wasm.ValueTypeI32}, wasm.ValueTypeI32)
 )
+
+// The bulk memory operators, both take an immediate memory index that must be 0.
+var (
+	MemoryCopy = newPrefixedOp(PrefixMisc, 0x0a, 0xe8, "memory.copy", []wasm.ValueType{wasm.ValueTypeI32, wasm.ValueTypeI32, wasm.ValueTypeI32}, noReturn)
+	MemoryFill = newPrefixedOp(PrefixMisc, 0x0b, 0xe9, "memory.fill", []wasm.ValueType{wasm.ValueTypeI32, wasm.ValueTypeI32, wasm.ValueTypeI32}, noReturn)
+)
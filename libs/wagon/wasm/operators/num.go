@@ -74,3 +74,12 @@ var (
 	F64Max      = newOp(0xa5, "f64.max", []wasm.ValueType{wasm.ValueTypeF64, wasm.ValueTypeF64}, wasm.ValueTypeF64)
 	F64Copysign = newOp(0xa6, "f64.copysign", []wasm.ValueType{wasm.ValueTypeF64, wasm.ValueTypeF64}, wasm.ValueTypeF64)
 )
+
+// The sign-extension operators.
+var (
+	I32Extend8S  = newOp(0xc0, "i32.extend8_s", []wasm.ValueType{wasm.ValueTypeI32}, wasm.ValueTypeI32)
+	I32Extend16S = newOp(0xc1, "i32.extend16_s", []wasm.ValueType{wasm.ValueTypeI32}, wasm.ValueTypeI32)
+	I64Extend8S  = newOp(0xc2, "i64.extend8_s", []wasm.ValueType{wasm.ValueTypeI64}, wasm.ValueTypeI64)
+	I64Extend16S = newOp(0xc3, "i64.extend16_s", []wasm.ValueType{wasm.ValueTypeI64}, wasm.ValueTypeI64)
+	I64Extend32S = newOp(0xc4, "i64.extend32_s", []wasm.ValueType{wasm.ValueTypeI64}, wasm.ValueTypeI64)
+)
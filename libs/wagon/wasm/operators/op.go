@@ -12,9 +12,18 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm"
 )
 
+// PrefixMisc is the prefix byte of the non-trapping float-to-int conversions
+// and of the bulk memory operators. The prefixed operators are assigned the
+// free opcodes from 0xe0, which are invalid in a wasm binary.
+const PrefixMisc byte = 0xfc
+
 var (
 	ops      [256]Op // an array of Op values mapped by wasm opcodes, used by New().
 	noReturn = wasm.ValueType(wasm.BlockTypeEmpty)
+
+	// the internal opcodes of the prefixed operators mapped by prefix and
+	// sub-opcode, used by NewPrefixed().
+	prefixedOps = make(map[byte]map[uint32]byte)
 )
 
 // Op describes a WASM operator.
@@ -28,6 +37,11 @@ type Op struct {
 	Polymorphic bool
 	Args        []wasm.ValueType // an array of value types used by the operator as arguments, is nil for polymorphic operators
 	Returns     wasm.ValueType   // the value returned (pushed) by the operator, is 0 for polymorphic operators
+
+	// The prefix byte and the sub-opcode of the operators encoded with a
+	// prefix, Code is then the internal opcode wagon assigns to the operator.
+	Prefix  byte
+	SubCode uint32
 }
 
 func (o Op) IsValid() bool {
@@ -64,6 +78,23 @@ func newPolymorphicOp(code byte, name string) byte {
 	return code
 }
 
+// newPrefixedOp assigns the internal opcode code to the operator encoded as the
+// sub-opcode sub of prefix.
+func newPrefixedOp(prefix byte, sub uint32, code byte, name string, args []wasm.ValueType, returns wasm.ValueType) byte {
+	if _, ok := prefixedOps[prefix][sub]; ok {
+		panic(fmt.Errorf("Sub-opcode %#x:%#x is already assigned", prefix, sub))
+	}
+
+	newOp(code, name, args, returns)
+	ops[code].Prefix = prefix
+	ops[code].SubCode = sub
+	if prefixedOps[prefix] == nil {
+		prefixedOps[prefix] = make(map[uint32]byte)
+	}
+	prefixedOps[prefix][sub] = code
+	return code
+}
+
 type InvalidOpcodeError byte
 
 func (e InvalidOpcodeError) Error() string {
@@ -75,7 +106,7 @@ func (e InvalidOpcodeError) Error() string {
 func New(code byte) (Op, error) {
 	var op Op
 
-	if int(code) >= len(ops) || internalOpcodes[code] {
+	if int(code) >= len(ops) || internalOpcodes[code] || ops[code].Prefix != 0 {
 		return op, InvalidOpcodeError(code)
 	}
 
@@ -85,3 +116,43 @@ func New(code byte) (Op, error) {
 	}
 	return op, nil
 }
+
+// InvalidSubOpcodeError is returned for an unknown sub-opcode of a prefix.
+type InvalidSubOpcodeError struct {
+	Prefix  byte
+	SubCode uint32
+}
+
+func (e InvalidSubOpcodeError) Error() string {
+	return fmt.Sprintf("Invalid opcode: %#x %#x", e.Prefix, e.SubCode)
+}
+
+// NewPrefixed returns the Op object for the sub-opcode sub of prefix, its Code
+// is the internal opcode wagon assigns to the operator.
+func NewPrefixed(prefix byte, sub uint32) (Op, error) {
+	code, ok := prefixedOps[prefix][sub]
+	if !ok {
+		return Op{}, InvalidSubOpcodeError{Prefix: prefix, SubCode: sub}
+	}
+	return ops[code], nil
+}
+
+// IsPrefix returns whether code is the prefix byte of prefixed operators.
+func IsPrefix(code byte) bool {
+	return prefixedOps[code] != nil
+}
+
+// IsPostMVP returns whether code is the opcode of an operator of the post-MVP
+// proposals wagon supports: the sign-extension operators, the non-trapping
+// float-to-int conversions and the bulk memory operators. The prefixed
+// operators are identified by their internal opcodes.
+func IsPostMVP(code byte) bool {
+	return (code >= I32Extend8S && code <= I64Extend32S) || ops[code].Prefix != 0
+}
+
+// Internal returns the Op object of an internal opcode wagon assigns to a
+// prefixed operator.
+func Internal(code byte) (Op, bool) {
+	op := ops[code]
+	return op, op.Prefix != 0
+}
@@ -28,3 +28,28 @@ func TestNew(t *testing.T) {
 		t.Fatalf("0xff: operator %v is valid (should be invalid)", op2)
 	}
 }
+
+func TestNewPrefixed(t *testing.T) {
+	op, err := NewPrefixed(PrefixMisc, 0x0a)
+	if err != nil {
+		t.Fatalf("unexpected error from NewPrefixed: %v", err)
+	}
+	if op.Code != MemoryCopy || op.Name != "memory.copy" || op.Prefix != PrefixMisc || op.SubCode != 0x0a {
+		t.Fatalf("0xfc 0x0a: unexpected Op %v", op)
+	}
+
+	// the internal opcode isn't a valid wasm opcode
+	if _, err := New(op.Code); err == nil {
+		t.Fatalf("%#x: expected error while getting Op value", op.Code)
+	}
+	if internal, ok := Internal(op.Code); !ok || internal.Name != op.Name {
+		t.Fatalf("%#x: unexpected internal Op %v", op.Code, internal)
+	}
+
+	if _, err := NewPrefixed(PrefixMisc, 0x0c); err == nil {
+		t.Fatalf("0xfc 0x0c: expected error while getting Op value")
+	}
+	if !IsPrefix(PrefixMisc) || IsPrefix(Unreachable) {
+		t.Fatal("unexpected prefix bytes")
+	}
+}
@@ -5,9 +5,11 @@
 package wasm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/wagon/wasm/leb128"
 )
@@ -75,6 +77,56 @@ func (b BlockType) String() string {
 	return ValueType(b).String()
 }
 
+// BlockTypeIndex represents the signature of a structured block given by an
+// index in the type section, the blocks of the multi-value proposal can take
+// params and return several results.
+type BlockTypeIndex uint32
+
+func (b BlockTypeIndex) String() string {
+	return fmt.Sprintf("<type %d>", uint32(b))
+}
+
+// ReadBlockType reads the signature of a structured block, either a BlockType
+// or a BlockTypeIndex encoded as a positive signed 33-bit integer.
+func ReadBlockType(r io.Reader) (interface{}, error) {
+	b, err := ReadByte(r)
+	if err != nil {
+		return nil, err
+	}
+	switch b {
+	case byte(BlockTypeEmpty), byte(ValueTypeI32), byte(ValueTypeI64), byte(ValueTypeF32), byte(ValueTypeF64):
+		return BlockType(b), nil
+	}
+	index, err := leb128.ReadVarint64(io.MultiReader(bytes.NewReader([]byte{b}), r))
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index > math.MaxUint32 {
+		return nil, fmt.Errorf("wasm: invalid block type %d", index)
+	}
+	return BlockTypeIndex(index), nil
+}
+
+// BlockSignature returns the param and the result types of a structured block
+// of signature sig, a BlockType or a BlockTypeIndex.
+func (m *Module) BlockSignature(sig interface{}) ([]ValueType, []ValueType, error) {
+	switch sig := sig.(type) {
+	case BlockType:
+		if sig == BlockTypeEmpty {
+			return nil, nil, nil
+		}
+		return nil, []ValueType{ValueType(sig)}, nil
+	case BlockTypeIndex:
+		if m.Types == nil || int(sig) >= len(m.Types.Entries) {
+			return nil, nil, InvalidTypeIndexError(sig)
+		}
+		entry := m.Types.Entries[sig]
+		return entry.ParamTypes, entry.ReturnTypes, nil
+	default:
+		return nil, nil, fmt.Errorf("wasm: invalid block signature %v", sig)
+	}
+}
+
 // ElemType describes the type of a table's elements
 type ElemType uint8 // varint7
 // ElemTypeAnyFunc descibres an any_func value
@@ -378,11 +378,15 @@ func (w *writer) writeCode(code []byte, isInit bool) {
 		case operators.Block, operators.Loop, operators.If:
 			tabs++
 			block++
-			b := ins.Immediates[0].(wasm.BlockType)
-			if b != wasm.BlockTypeEmpty {
-				w.WriteString(" (result ")
-				w.WriteString(b.String())
-				w.WriteString(")")
+			switch b := ins.Immediates[0].(type) {
+			case wasm.BlockType:
+				if b != wasm.BlockTypeEmpty {
+					w.WriteString(" (result ")
+					w.WriteString(b.String())
+					w.WriteString(")")
+				}
+			case wasm.BlockTypeIndex:
+				w.Print(" (type %d)", uint32(b))
 			}
 			w.Print("  ;; label = @%d", block)
 			continue
@@ -420,7 +424,7 @@ func (w *writer) writeCode(code []byte, isInit bool) {
 			i1 := ins.Immediates[0].(uint32)
 			w.Print(" (type %d)", i1)
 			continue
-		case operators.CurrentMemory, operators.GrowMemory:
+		case operators.CurrentMemory, operators.GrowMemory, operators.MemoryCopy, operators.MemoryFill:
 			r := ins.Immediates[0].(uint8)
 			if r == 0 {
 				continue
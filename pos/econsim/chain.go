@@ -0,0 +1,65 @@
+package econsim
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+)
+
+// chain is the synthetic header chain of the simulation, the blocks are produced
+// exactly every interval, so the header of any number is known without storing it
+type chain struct {
+	startTime uint64
+
+	lock    sync.RWMutex
+	current *types.Header
+}
+
+func newChain(startTime uint64) *chain {
+	c := &chain{startTime: startTime}
+	c.current = c.header(0)
+	return c
+}
+
+// header returns the unsigned header of the block number
+func (c *chain) header(number uint64) *types.Header {
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Time:   c.blockTime(number),
+		Extra:  make([]byte, 32),
+	}
+}
+
+func (c *chain) blockTime(number uint64) uint64 {
+	return c.startTime + number*xcom.Interval()*1000
+}
+
+func (c *chain) setCurrent(header *types.Header) {
+	c.lock.Lock()
+	c.current = header
+	c.lock.Unlock()
+}
+
+func (c *chain) CurrentHeader() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.current
+}
+
+func (c *chain) GetHeaderByHash(hash common.Hash) *types.Header {
+	current := c.CurrentHeader()
+	if current.Hash() == hash {
+		return current
+	}
+	return nil
+}
+
+func (c *chain) GetHeaderByNumber(number uint64) *types.Header {
+	if number > c.CurrentHeader().Number.Uint64() {
+		return nil
+	}
+	return c.header(number)
+}
@@ -0,0 +1,236 @@
+package econsim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"math/rand"
+)
+
+const (
+	// the distributions of the amounts
+	DistUniform = "uniform"
+	DistPareto  = "pareto"
+
+	// how the delegators choose the candidate
+	TargetUniform = "uniform"
+	TargetStake   = "stake"
+)
+
+var phc = big.NewInt(1e18)
+
+func phcAmount(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), phc)
+}
+
+// Config is the synthetic chain simulated on the economic model,
+// the amounts are in von
+type Config struct {
+	// the number of chain years to simulate
+	Years uint32 `json:"years"`
+	// the seed of the generated keys, amounts and choices
+	Seed int64 `json:"seed"`
+	// the timestamp of the genesis block in milliseconds
+	StartTime uint64 `json:"startTime"`
+	// the genesis balance of the reward pool
+	RewardPool *big.Int `json:"rewardPool"`
+	// the genesis allocation besides the foundations and the reward pool,
+	// the stakes and the delegations are paid from it
+	GeneralBalance *big.Int          `json:"generalBalance"`
+	Candidates     []*CandidateGroup `json:"candidates"`
+	Delegators     []*DelegatorGroup `json:"delegators"`
+}
+
+// CandidateGroup is a number of candidates staking in the same epoch,
+// the epoch 0 means the genesis block
+type CandidateGroup struct {
+	Count     int       `json:"count"`
+	JoinEpoch uint64    `json:"joinEpoch"`
+	Stake     *Amount   `json:"stake"`
+	RewardPer *PerRange `json:"rewardPer"`
+}
+
+// DelegatorGroup is a number of delegators delegating in the same epoch,
+// every delegator delegates to one of the candidates that joined no later than it
+type DelegatorGroup struct {
+	Count     int     `json:"count"`
+	JoinEpoch uint64  `json:"joinEpoch"`
+	Amount    *Amount `json:"amount"`
+	// the share of the delegators that compound the delegate reward, [0, 1]
+	AutoCompound float64 `json:"autoCompound"`
+	// uniform: any candidate, stake: weighted by the stake of the candidates
+	Target string `json:"target"`
+}
+
+// Amount is the distribution of the amounts in [Min, Max],
+// pareto draws Min / U^(1/Alpha) and caps it by Max
+type Amount struct {
+	Distribution string   `json:"distribution"`
+	Min          *big.Int `json:"min"`
+	Max          *big.Int `json:"max"`
+	Alpha        float64  `json:"alpha"`
+}
+
+// PerRange is the range of the reward ratio of the candidates, in basis points
+type PerRange struct {
+	Min uint16 `json:"min"`
+	Max uint16 `json:"max"`
+}
+
+// DefaultConfig is a main net like chain of 150 candidates and 500 delegators,
+// the delegators dominate the run time as their rewards are settled every epoch
+func DefaultConfig() *Config {
+	return &Config{
+		Years:          3,
+		Seed:           1,
+		StartTime:      1656043200000,
+		RewardPool:     phcAmount(200000000),
+		GeneralBalance: phcAmount(9727638019),
+		Candidates: []*CandidateGroup{
+			{
+				Count: 150,
+				Stake: &Amount{
+					Distribution: DistPareto,
+					Min:          phcAmount(100000),
+					Max:          phcAmount(10000000),
+					Alpha:        1.2,
+				},
+				RewardPer: &PerRange{Min: 1000, Max: 8000},
+			},
+		},
+		Delegators: []*DelegatorGroup{
+			{
+				Count: 500,
+				Amount: &Amount{
+					Distribution: DistPareto,
+					Min:          phcAmount(100),
+					Max:          phcAmount(5000000),
+					Alpha:        1.1,
+				},
+				AutoCompound: 0.5,
+				Target:       TargetStake,
+			},
+		},
+	}
+}
+
+// LoadConfig reads the config from the json file, the fields missing in the file
+// keep the values of DefaultConfig, the groups in the file replace the default groups
+func LoadConfig(file string) (*Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg, def := DefaultConfig(), DefaultConfig()
+	cfg.Candidates, cfg.Delegators = nil, nil
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid distribution file %s: %v", file, err)
+	}
+	if cfg.Candidates == nil {
+		cfg.Candidates = def.Candidates
+	}
+	if cfg.Delegators == nil {
+		cfg.Delegators = def.Delegators
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.Years == 0 {
+		return errors.New("the years to simulate can't be zero")
+	}
+	if c.RewardPool == nil || c.GeneralBalance == nil {
+		return errors.New("the rewardPool and the generalBalance are required")
+	}
+	for i, g := range c.Candidates {
+		if g.Count <= 0 {
+			return fmt.Errorf("candidate group %d: the count must be positive", i)
+		}
+		if err := g.Stake.validate(); err != nil {
+			return fmt.Errorf("candidate group %d: %v", i, err)
+		}
+		if g.RewardPer == nil || g.RewardPer.Min > g.RewardPer.Max || g.RewardPer.Max > 10000 {
+			return fmt.Errorf("candidate group %d: the rewardPer must be a range in [0, 10000]", i)
+		}
+	}
+	// the verifiers of the first epoch are elected at the genesis block
+	if !c.hasCandidateBefore(0) {
+		return errors.New("at least one candidate group must join at the genesis block")
+	}
+	for i, g := range c.Delegators {
+		if g.Count <= 0 {
+			return fmt.Errorf("delegator group %d: the count must be positive", i)
+		}
+		if err := g.Amount.validate(); err != nil {
+			return fmt.Errorf("delegator group %d: %v", i, err)
+		}
+		if g.AutoCompound < 0 || g.AutoCompound > 1 {
+			return fmt.Errorf("delegator group %d: the autoCompound must be in [0, 1]", i)
+		}
+		if g.Target != TargetUniform && g.Target != TargetStake {
+			return fmt.Errorf("delegator group %d: unknown target %q", i, g.Target)
+		}
+		if !c.hasCandidateBefore(g.JoinEpoch) {
+			return fmt.Errorf("delegator group %d: no candidate joins before epoch %d", i, g.JoinEpoch)
+		}
+	}
+	return nil
+}
+
+func (c *Config) hasCandidateBefore(epoch uint64) bool {
+	for _, g := range c.Candidates {
+		if g.JoinEpoch <= epoch {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Amount) validate() error {
+	if a == nil || a.Min == nil || a.Max == nil {
+		return errors.New("the amount range is required")
+	}
+	if a.Min.Sign() <= 0 || a.Min.Cmp(a.Max) > 0 {
+		return fmt.Errorf("invalid amount range [%v, %v]", a.Min, a.Max)
+	}
+	switch a.Distribution {
+	case DistUniform:
+	case DistPareto:
+		if a.Alpha <= 0 {
+			return errors.New("the alpha of the pareto distribution must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q", a.Distribution)
+	}
+	return nil
+}
+
+// sample draws an amount, it is rounded down to whole PHC
+// unless the range is narrower than that
+func (a *Amount) sample(rng *rand.Rand) *big.Int {
+	var amount *big.Float
+	switch a.Distribution {
+	case DistPareto:
+		factor := math.Pow(1-rng.Float64(), -1/a.Alpha)
+		amount = new(big.Float).Mul(new(big.Float).SetInt(a.Min), big.NewFloat(factor))
+	default:
+		span := new(big.Float).SetInt(new(big.Int).Sub(a.Max, a.Min))
+		amount = new(big.Float).Mul(span, big.NewFloat(rng.Float64()))
+		amount.Add(amount, new(big.Float).SetInt(a.Min))
+	}
+	v, _ := amount.Int(nil)
+	if v.Cmp(a.Max) > 0 {
+		v.Set(a.Max)
+	}
+	if rounded := new(big.Int).Sub(v, new(big.Int).Mod(v, phc)); rounded.Cmp(a.Min) >= 0 {
+		v = rounded
+	}
+	return v
+}
+
+func (p *PerRange) sample(rng *rand.Rand) uint16 {
+	return p.Min + uint16(rng.Intn(int(p.Max-p.Min)+1))
+}
@@ -0,0 +1,122 @@
+package econsim
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"no year", func(cfg *Config) { cfg.Years = 0 }},
+		{"no genesis candidate", func(cfg *Config) { cfg.Candidates[0].JoinEpoch = 2 }},
+		{"bad range", func(cfg *Config) { cfg.Candidates[0].Stake.Max = big.NewInt(1) }},
+		{"bad alpha", func(cfg *Config) { cfg.Delegators[0].Amount.Alpha = 0 }},
+		{"bad rewardPer", func(cfg *Config) { cfg.Candidates[0].RewardPer.Max = 10001 }},
+		{"bad target", func(cfg *Config) { cfg.Delegators[0].Target = "random" }},
+	}
+	if err := DefaultConfig().validate(); err != nil {
+		t.Fatalf("the default config is invalid: %v", err)
+	}
+	for _, test := range tests {
+		cfg := DefaultConfig()
+		test.modify(cfg)
+		if err := cfg.validate(); err == nil {
+			t.Errorf("%s: expect an error", test.name)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+
+	cfg := DefaultConfig()
+	cfg.Years = 2
+	cfg.Candidates[0].Count = 30
+	cfg.Candidates = append(cfg.Candidates, &CandidateGroup{
+		Count:     5,
+		JoinEpoch: 3,
+		Stake: &Amount{
+			Distribution: DistUniform,
+			Min:          phcAmount(20000000),
+			Max:          phcAmount(30000000),
+		},
+		RewardPer: &PerRange{Min: 5000, Max: 5000},
+	})
+	cfg.Delegators[0].Count = 100
+
+	var progress []uint32
+	report, err := Simulate(cfg, func(year *YearReport) {
+		progress = append(progress, year.Year)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Years) != 2 || len(progress) != 2 {
+		t.Fatalf("expect 2 years, got %d, progress %v", len(report.Years), progress)
+	}
+
+	genesis := new(big.Int).Add(xcom.CDFBalance(), xcom.PhoenixChainFundBalance())
+	genesis.Add(genesis, cfg.RewardPool)
+	genesis.Add(genesis, cfg.GeneralBalance)
+	first := report.Years[0]
+	if issued := new(big.Int).Sub(first.CumulativeIssue, first.IncreaseIssuance); issued.Cmp(genesis) != 0 {
+		t.Errorf("the issue before the first increase: expect %v, got %v", genesis, issued)
+	}
+	expectIncrease := new(big.Int).Div(new(big.Int).Mul(genesis, big.NewInt(int64(xcom.IncreaseIssuanceRatio()))), big.NewInt(10000))
+	if first.IncreaseIssuance.Cmp(expectIncrease) != 0 {
+		t.Errorf("the first increase: expect %v, got %v", expectIncrease, first.IncreaseIssuance)
+	}
+
+	for _, year := range report.Years {
+		if year.Verifiers != int(xcom.MaxValidators()) {
+			t.Errorf("year %d: expect %d verifiers, got %d", year.Year, xcom.MaxValidators(), year.Verifiers)
+		}
+		if year.ValidatorAPY <= 0 || year.DelegatorAPY <= 0 {
+			t.Errorf("year %d: expect positive APYs, got %f and %f", year.Year, year.ValidatorAPY, year.DelegatorAPY)
+		}
+		if year.PackageReward.Sign() <= 0 || year.StakingReward.Sign() <= 0 {
+			t.Errorf("year %d: expect the rewards paid", year.Year)
+		}
+	}
+
+	// the developer foundation issuance is kept by the treasury pool
+	if first.TreasuryPoolBalance.Cmp(first.DeveloperFoundationIssuance) != 0 {
+		t.Errorf("the treasury pool: expect %v, got %v", first.DeveloperFoundationIssuance, first.TreasuryPoolBalance)
+	}
+
+	// the blocks are produced by the validators elected for the rounds, each of them produces its share of the round
+	for _, year := range report.Years {
+		for _, node := range year.Nodes {
+			if node.Blocks%xcom.BlocksWillCreate() != 0 {
+				t.Errorf("year %d: the candidate %s produced %d blocks, not whole rounds",
+					year.Year, node.NodeID.TerminalString(), node.Blocks)
+			}
+		}
+	}
+
+	// the large candidates joining later are elected
+	second := report.Years[1]
+	if second.Candidates != 35 {
+		t.Fatalf("expect 35 candidates, got %d", second.Candidates)
+	}
+	for _, node := range second.Nodes[30:] {
+		if node.ElectedEpochs == 0 {
+			t.Errorf("the candidate %s joined at epoch 3 is never elected", node.NodeID.TerminalString())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteYearsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 {
+		t.Errorf("expect a header and 2 rows, got %d lines", len(lines))
+	}
+}
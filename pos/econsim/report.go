@@ -0,0 +1,316 @@
+package econsim
+
+import (
+	"encoding/csv"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
+)
+
+const msPerYear = 365 * 24 * 3600 * 1000
+
+// Report is the projection of the chain year by year, the amounts are in von
+type Report struct {
+	Config *Config             `json:"config"`
+	Model  *xcom.EconomicModel `json:"economicModel"`
+	Years  []*YearReport       `json:"years"`
+}
+
+// YearReport is a chain year, which ends at the epoch the issuance is increased.
+// The APYs are the rewards over the staked and delegated amounts, annualized by the time staked.
+type YearReport struct {
+	Year       uint32  `json:"year"`
+	StartBlock uint64  `json:"startBlock"`
+	EndBlock   uint64  `json:"endBlock"`
+	Epochs     uint64  `json:"epochs"`
+	Days       float64 `json:"days"`
+
+	// the issuance at the end of the year and its allocation
+	CumulativeIssue                *big.Int `json:"cumulativeIssue"`
+	IncreaseIssuance               *big.Int `json:"increaseIssuance"`
+	RewardPoolIssuance             *big.Int `json:"rewardPoolIssuance"`
+	DeveloperFoundationIssuance    *big.Int `json:"developerFoundationIssuance"`
+	PhoenixChainFoundationIssuance *big.Int `json:"phoenixchainFoundationIssuance"`
+
+	// the rewards paid in the year, the delegate rewards are included in neither of the others
+	PackageReward  *big.Int `json:"packageReward"`
+	StakingReward  *big.Int `json:"stakingReward"`
+	DelegateReward *big.Int `json:"delegateReward"`
+	// the rewards of the last epoch of the year
+	NewBlockReward     *big.Int `json:"newBlockReward"`
+	EpochStakingReward *big.Int `json:"epochStakingReward"`
+
+	// the balances at the end of the year
+	RewardPoolBalance          *big.Int `json:"rewardPoolBalance"`
+	DelegateRewardPoolBalance  *big.Int `json:"delegateRewardPoolBalance"`
+	DeveloperFoundationBalance *big.Int `json:"developerFoundationBalance"`
+	TreasuryPoolBalance        *big.Int `json:"treasuryPoolBalance"` // the developer foundation issuance since version 1.2.0
+	PhoenixChainFundBalance    *big.Int `json:"phoenixchainFundBalance"`
+	RestrictingBalance         *big.Int `json:"restrictingBalance"`
+
+	TotalStake      *big.Int `json:"totalStake"`
+	TotalDelegation *big.Int `json:"totalDelegation"`
+	// the staked and delegated amounts over the cumulative issue
+	StakingRatio float64 `json:"stakingRatio"`
+	ValidatorAPY float64 `json:"validatorApy"`
+	DelegatorAPY float64 `json:"delegatorApy"`
+
+	// the election of the epoch after the year
+	Candidates        int      `json:"candidates"`
+	Verifiers         int      `json:"verifiers"`
+	MinVerifierShares *big.Int `json:"minVerifierShares"`
+	// the candidates elected in any epoch of the year
+	ElectedCandidates int `json:"electedCandidates"`
+
+	Nodes []*CandidateReport `json:"nodes"`
+}
+
+// CandidateReport is a candidate in a year
+type CandidateReport struct {
+	NodeID    discover.NodeID `json:"nodeId"`
+	JoinEpoch uint64          `json:"joinEpoch"`
+	RewardPer uint16          `json:"rewardPer"`
+	Stake     *big.Int        `json:"stake"`
+	// the delegation at the end of the year
+	Delegation     *big.Int `json:"delegation"`
+	ElectedEpochs  uint64   `json:"electedEpochs"`
+	Blocks         uint64   `json:"blocks"`
+	PackageReward  *big.Int `json:"packageReward"`
+	StakingReward  *big.Int `json:"stakingReward"`
+	DelegateReward *big.Int `json:"delegateReward"`
+	APY            float64  `json:"apy"`
+	DelegatorAPY   float64  `json:"delegatorApy"`
+
+	// the epochs of the year the candidate existed, and the sum of its delegation at the end of them
+	epochs          uint64
+	delegationEpoch *big.Int
+}
+
+type yearStats struct {
+	report *YearReport
+	nodes  map[discover.NodeID]*CandidateReport
+}
+
+func (s *simulator) newYearStats(startBlock uint64) *yearStats {
+	y := &yearStats{
+		report: &YearReport{
+			Year:                           s.yearNumber + 1,
+			StartBlock:                     startBlock,
+			IncreaseIssuance:               new(big.Int),
+			RewardPoolIssuance:             new(big.Int),
+			DeveloperFoundationIssuance:    new(big.Int),
+			PhoenixChainFoundationIssuance: new(big.Int),
+			PackageReward:                  new(big.Int),
+			StakingReward:                  new(big.Int),
+			DelegateReward:                 new(big.Int),
+			TotalStake:                     new(big.Int),
+			TotalDelegation:                new(big.Int),
+			MinVerifierShares:              new(big.Int),
+		},
+		nodes: make(map[discover.NodeID]*CandidateReport),
+	}
+	for _, c := range s.candidates {
+		y.add(c)
+	}
+	return y
+}
+
+func (y *yearStats) add(c *candidate) {
+	node := &CandidateReport{
+		NodeID:          c.nodeID,
+		JoinEpoch:       c.joinEpoch,
+		RewardPer:       c.rewardPer,
+		Stake:           c.stake,
+		Delegation:      new(big.Int),
+		PackageReward:   new(big.Int),
+		StakingReward:   new(big.Int),
+		DelegateReward:  new(big.Int),
+		delegationEpoch: new(big.Int),
+	}
+	y.nodes[c.nodeID] = node
+	y.report.Nodes = append(y.report.Nodes, node)
+}
+
+// collect adds the epoch to the year, and closes the year if the issuance was increased
+func (s *simulator) collect(hash common.Hash, epoch, last uint64, producers []*candidate) error {
+	year := s.year.report
+	year.Epochs++
+
+	ledger, err := plugin.LoadEpochEconomics(hash, s.db, epoch)
+	if err != nil {
+		return err
+	}
+	year.IncreaseIssuance.Add(year.IncreaseIssuance, ledger.IncreaseIssuance)
+	year.RewardPoolIssuance.Add(year.RewardPoolIssuance, ledger.RewardPoolIssuance)
+	year.DeveloperFoundationIssuance.Add(year.DeveloperFoundationIssuance, ledger.DeveloperFoundation)
+	year.PhoenixChainFoundationIssuance.Add(year.PhoenixChainFoundationIssuance, ledger.PhoenixChainFoundation)
+	year.NewBlockReward = ledger.NewBlockReward
+	year.EpochStakingReward = ledger.StakingReward
+	for _, n := range ledger.Nodes {
+		node, ok := s.year.nodes[n.NodeID]
+		if !ok {
+			continue
+		}
+		node.Blocks += n.Blocks
+		node.PackageReward.Add(node.PackageReward, n.PackageReward)
+		node.StakingReward.Add(node.StakingReward, n.StakingReward)
+		node.DelegateReward.Add(node.DelegateReward, n.DelegateReward)
+	}
+	for _, c := range producers {
+		s.year.nodes[c.nodeID].ElectedEpochs++
+	}
+	for _, c := range s.candidates {
+		can, err := plugin.StakingInstance().GetCandidateInfo(hash, c.addr)
+		if err != nil {
+			return err
+		}
+		node := s.year.nodes[c.nodeID]
+		node.Delegation = new(big.Int).Add(can.DelegateTotal, can.DelegateTotalHes)
+		node.delegationEpoch.Add(node.delegationEpoch, node.Delegation)
+		node.epochs++
+	}
+
+	yearNumber, err := plugin.LoadChainYearNumber(hash, s.db)
+	if err != nil {
+		return err
+	}
+	if yearNumber == s.yearNumber {
+		return nil
+	}
+	s.yearNumber = yearNumber
+	if err := s.closeYear(hash, last); err != nil {
+		return err
+	}
+	if s.progress != nil {
+		s.progress(year)
+	}
+	s.report.Years = append(s.report.Years, year)
+	s.year = s.newYearStats(last + 1)
+	return nil
+}
+
+func (s *simulator) closeYear(hash common.Hash, last uint64) error {
+	year := s.year.report
+	year.EndBlock = last
+	year.Days = float64(s.chain.blockTime(last)-s.chain.blockTime(year.StartBlock-1)) / (24 * 3600 * 1000)
+
+	year.CumulativeIssue = plugin.GetHistoryCumulativeIssue(s.state, s.yearNumber)
+	year.RewardPoolBalance = s.state.GetBalance(vm.RewardManagerPoolAddr)
+	year.DelegateRewardPoolBalance = s.state.GetBalance(vm.DelegateRewardPoolAddr)
+	year.DeveloperFoundationBalance = s.state.GetBalance(xcom.CDFAccount())
+	year.TreasuryPoolBalance = s.state.GetBalance(vm.TreasuryPoolAddr)
+	year.PhoenixChainFundBalance = s.state.GetBalance(xcom.PhoenixChainFundAccount())
+	year.RestrictingBalance = s.state.GetBalance(vm.RestrictingContractAddr)
+
+	// the principal in von-epochs, so the candidates joined in the year are weighted by their time
+	epochsPerYear := float64(msPerYear) / float64(xutil.CalcBlocksEachEpoch()*xcom.Interval()*1000)
+	validatorReward, delegateReward := new(big.Int), new(big.Int)
+	stakeEpochs, delegationEpochs := new(big.Int), new(big.Int)
+	for _, node := range year.Nodes {
+		reward := new(big.Int).Add(node.PackageReward, node.StakingReward)
+		nodeStakeEpochs := new(big.Int).Mul(node.Stake, new(big.Int).SetUint64(node.epochs))
+		node.APY = annualize(reward, nodeStakeEpochs, epochsPerYear)
+		node.DelegatorAPY = annualize(node.DelegateReward, node.delegationEpoch, epochsPerYear)
+
+		year.PackageReward.Add(year.PackageReward, node.PackageReward)
+		year.StakingReward.Add(year.StakingReward, node.StakingReward)
+		year.DelegateReward.Add(year.DelegateReward, node.DelegateReward)
+		year.TotalStake.Add(year.TotalStake, node.Stake)
+		year.TotalDelegation.Add(year.TotalDelegation, node.Delegation)
+		validatorReward.Add(validatorReward, reward)
+		delegateReward.Add(delegateReward, node.DelegateReward)
+		stakeEpochs.Add(stakeEpochs, nodeStakeEpochs)
+		delegationEpochs.Add(delegationEpochs, node.delegationEpoch)
+		if node.ElectedEpochs > 0 {
+			year.ElectedCandidates++
+		}
+	}
+	year.ValidatorAPY = annualize(validatorReward, stakeEpochs, epochsPerYear)
+	year.DelegatorAPY = annualize(delegateReward, delegationEpochs, epochsPerYear)
+	year.StakingRatio = ratio(new(big.Int).Add(year.TotalStake, year.TotalDelegation), year.CumulativeIssue)
+
+	verifiers, err := plugin.StakingInstance().GetVerifierList(hash, last+1, plugin.QueryStartNotIrr)
+	if err != nil {
+		return err
+	}
+	year.Candidates = len(year.Nodes)
+	year.Verifiers = len(verifiers)
+	for i, v := range verifiers {
+		shares := v.Shares.ToInt()
+		if i == 0 || shares.Cmp(year.MinVerifierShares) < 0 {
+			year.MinVerifierShares = new(big.Int).Set(shares)
+		}
+	}
+	return nil
+}
+
+func annualize(reward, principalEpochs *big.Int, epochsPerYear float64) float64 {
+	return ratio(reward, principalEpochs) * epochsPerYear
+}
+
+func ratio(x, y *big.Int) float64 {
+	if y.Sign() == 0 {
+		return 0
+	}
+	r, _ := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt(y)).Float64()
+	return r
+}
+
+// WriteYearsCSV writes a row for every year
+func (r *Report) WriteYearsCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"year", "startBlock", "endBlock", "epochs", "days",
+		"cumulativeIssue", "increaseIssuance", "rewardPoolIssuance", "developerFoundationIssuance", "phoenixchainFoundationIssuance",
+		"packageReward", "stakingReward", "delegateReward", "newBlockReward", "epochStakingReward",
+		"rewardPoolBalance", "delegateRewardPoolBalance", "developerFoundationBalance", "treasuryPoolBalance", "phoenixchainFundBalance", "restrictingBalance",
+		"totalStake", "totalDelegation", "stakingRatio", "validatorApy", "delegatorApy",
+		"candidates", "verifiers", "minVerifierShares", "electedCandidates"})
+	for _, y := range r.Years {
+		out.Write([]string{
+			strconv.FormatUint(uint64(y.Year), 10), strconv.FormatUint(y.StartBlock, 10), strconv.FormatUint(y.EndBlock, 10),
+			strconv.FormatUint(y.Epochs, 10), formatFloat(y.Days),
+			y.CumulativeIssue.String(), y.IncreaseIssuance.String(), y.RewardPoolIssuance.String(),
+			y.DeveloperFoundationIssuance.String(), y.PhoenixChainFoundationIssuance.String(),
+			y.PackageReward.String(), y.StakingReward.String(), y.DelegateReward.String(),
+			y.NewBlockReward.String(), y.EpochStakingReward.String(),
+			y.RewardPoolBalance.String(), y.DelegateRewardPoolBalance.String(), y.DeveloperFoundationBalance.String(),
+			y.TreasuryPoolBalance.String(), y.PhoenixChainFundBalance.String(), y.RestrictingBalance.String(),
+			y.TotalStake.String(), y.TotalDelegation.String(), formatFloat(y.StakingRatio),
+			formatFloat(y.ValidatorAPY), formatFloat(y.DelegatorAPY),
+			strconv.Itoa(y.Candidates), strconv.Itoa(y.Verifiers), y.MinVerifierShares.String(), strconv.Itoa(y.ElectedCandidates),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteNodesCSV writes a row for every candidate in every year
+func (r *Report) WriteNodesCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"year", "nodeId", "joinEpoch", "rewardPer", "stake", "delegation", "electedEpochs", "blocks",
+		"packageReward", "stakingReward", "delegateReward", "apy", "delegatorApy"})
+	for _, y := range r.Years {
+		for _, n := range y.Nodes {
+			out.Write([]string{
+				strconv.FormatUint(uint64(y.Year), 10), n.NodeID.String(), strconv.FormatUint(n.JoinEpoch, 10),
+				strconv.FormatUint(uint64(n.RewardPer), 10), n.Stake.String(), n.Delegation.String(),
+				strconv.FormatUint(n.ElectedEpochs, 10), strconv.FormatUint(n.Blocks, 10),
+				n.PackageReward.String(), n.StakingReward.String(), n.DelegateReward.String(),
+				formatFloat(n.APY), formatFloat(n.DelegatorAPY),
+			})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 6, 64)
+}
@@ -0,0 +1,571 @@
+// Package econsim projects the economic model on a synthetic chain.
+//
+// The chain is driven through the real staking, restricting and reward plugins
+// on a temporary snapshotdb and an in-memory state, one snapshotdb block per epoch:
+// the plugins run at the first and the last block of every epoch, and the package
+// rewards of the blocks in between are allocated to the verifiers at once.
+//
+// The validators of every round are elected by the staking plugin as on the chain,
+// the simulation doesn't model the consensus beyond it: the blocks of a round are
+// produced by its validators in turn, and nobody is slashed, unstakes or votes.
+// The plugins are singletons, so a process can only run one simulation at a time.
+package econsim
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"os"
+	"sort"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/snapshotdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
+	// registers the precompiled contracts the state checks accounts against
+	_ "github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/handler"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/reward"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/staking"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
+)
+
+// the genesis restricting plans are paid by the developer foundation
+var genesisRestricting = phcAmount(62265742 + 259096239)
+
+type candidate struct {
+	nodeID          discover.NodeID
+	addr            common.NodeAddress
+	benefitAddress  common.Address
+	stakingBlockNum uint64
+	joinEpoch       uint64
+	stake           *big.Int
+	rewardPer       uint16
+	// signed once by the node key, the number, the time, the parent and the nonce are
+	// replaced by the block it produces, the producer is kept by the cached public key
+	header *types.Header
+}
+
+func (c *candidate) produce(number, time uint64) *types.Header {
+	c.header.Number = new(big.Int).SetUint64(number)
+	c.header.Time = time
+	return c.header
+}
+
+type simulator struct {
+	cfg      *Config
+	rng      *rand.Rand
+	chain    *chain
+	db       snapshotdb.DB
+	state    *state.StateDB
+	progress func(year *YearReport)
+
+	// the genesis account paying the stakes and the delegations
+	general    common.Address
+	candidates []*candidate
+	nodes      map[discover.NodeID]*candidate
+	parent     common.Hash
+
+	report     *Report
+	year       *yearStats
+	yearNumber uint32
+}
+
+// Simulate runs the chain of the config on the current economic model,
+// progress is called with every projected year if it isn't nil.
+func Simulate(cfg *Config, progress func(year *YearReport)) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if err := xcom.CheckEconomicModel(); err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "econsim")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	s := &simulator{
+		cfg:      cfg,
+		rng:      rand.New(rand.NewSource(cfg.Seed)),
+		chain:    newChain(cfg.StartTime),
+		progress: progress,
+		nodes:    make(map[discover.NodeID]*candidate),
+		report: &Report{
+			Config: cfg,
+			Model:  xcom.GetEc(xcom.DefaultMainNet),
+		},
+	}
+	s.general = s.newAddress()
+
+	snapshotdb.SetDBPathWithNode(dir)
+	snapshotdb.SetDBBlockChain(s.chain)
+	s.db = snapshotdb.Instance()
+	defer s.db.Clear()
+	handler.NewVrfHandler(nil)
+
+	if s.state, err = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase())); err != nil {
+		return nil, err
+	}
+	if err := s.genesis(); err != nil {
+		return nil, err
+	}
+	if err := s.run(); err != nil {
+		return nil, err
+	}
+	return s.report, nil
+}
+
+func (s *simulator) genesis() error {
+	if xcom.CDFBalance().Cmp(genesisRestricting) < 0 {
+		return fmt.Errorf("the developer foundation balance %v can't pay the genesis restricting plans %v",
+			xcom.CDFBalance(), genesisRestricting)
+	}
+	issue := new(big.Int)
+	for _, alloc := range []struct {
+		addr    common.Address
+		balance *big.Int
+	}{
+		{xcom.PhoenixChainFundAccount(), xcom.PhoenixChainFundBalance()},
+		{xcom.CDFAccount(), xcom.CDFBalance()},
+		{vm.RewardManagerPoolAddr, s.cfg.RewardPool},
+		{s.general, s.cfg.GeneralBalance},
+	} {
+		s.state.AddBalance(alloc.addr, alloc.balance)
+		issue.Add(issue, alloc.balance)
+	}
+
+	// the same as the genesis block, see genesisPluginState
+	if _, err := gov.InitGenesisGovernParam(common.ZeroHash, s.db, configs.GenesisVersion); err != nil {
+		return err
+	}
+	plugin.SetYearEndCumulativeIssue(s.state, 0, issue)
+	// the simulation runs with every feature of the current code version active
+	activeVersions, err := json.Marshal([]gov.ActiveVersionValue{{ActiveVersion: configs.CodeVersion(), ActiveBlock: 0}})
+	if err != nil {
+		return err
+	}
+	s.state.SetState(vm.GovContractAddr, gov.KeyActiveVersions(), activeVersions)
+	if err := plugin.NewRestrictingPlugin(nil).InitGenesisRestrictingPlans(s.state); err != nil {
+		return err
+	}
+	plugin.SetYearEndBalance(s.state, 0, s.state.GetBalance(vm.RewardManagerPoolAddr))
+
+	// the candidates of the genesis block elect the verifiers of the first epoch
+	header := s.chain.header(0)
+	hash := header.Hash()
+	if err := s.db.NewBlock(snapshotBlock(0), common.ZeroHash, hash); err != nil {
+		return err
+	}
+	if err := staking.NewStakingDB().SetEpochValIndex(hash, staking.ValArrIndexQueue{{Start: 0, End: 0}}); err != nil {
+		return err
+	}
+	if err := s.join(hash, 0, 0); err != nil {
+		return err
+	}
+	if err := plugin.StakingInstance().ElectNextVerifierList(hash, 0, s.state); err != nil {
+		return err
+	}
+	if err := s.genesisRound(hash); err != nil {
+		return err
+	}
+	s.state.Finalise(true)
+	if err := s.db.Commit(hash); err != nil {
+		return err
+	}
+	s.parent = hash
+	s.year = s.newYearStats(1)
+	return nil
+}
+
+// genesisRound stores the round indexes of the genesis block, the first round
+// is validated by the leading verifiers of the first epoch
+func (s *simulator) genesisRound(hash common.Hash) error {
+	db := staking.NewStakingDB()
+	validators, err := db.GetEpochValListByBlockHash(hash, 1, xutil.CalcBlocksEachEpoch())
+	if err != nil {
+		return err
+	}
+	if uint64(len(validators)) > xcom.MaxConsensusVals() {
+		validators = validators[:xcom.MaxConsensusVals()]
+	}
+	indexes := staking.ValArrIndexQueue{{Start: 0, End: 0}, {Start: 1, End: xutil.ConsensusSize()}}
+	if err := db.SetRoundValIndex(hash, indexes); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := db.SetRoundValList(hash, index.Start, index.End, validators); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotBlock returns the snapshotdb block number of the epoch, the block 0 is
+// the committed base of an empty snapshotdb, so the genesis epoch starts from 1
+func snapshotBlock(epoch uint64) *big.Int {
+	return new(big.Int).SetUint64(epoch + 1)
+}
+
+func (s *simulator) run() error {
+	// the first year is shorter, twice of the expected epochs is enough for any year
+	maxEpochs := 2 * uint64(s.cfg.Years) * (xutil.EpochsPerYear() + 1)
+	for epoch := uint64(1); s.yearNumber < s.cfg.Years; epoch++ {
+		if epoch > maxEpochs {
+			return fmt.Errorf("the year %d didn't end in %d epochs", s.yearNumber+1, maxEpochs)
+		}
+		if err := s.runEpoch(epoch); err != nil {
+			return fmt.Errorf("epoch %d: %v", epoch, err)
+		}
+	}
+	return nil
+}
+
+func (s *simulator) runEpoch(epoch uint64) error {
+	var (
+		sk     = plugin.StakingInstance()
+		rm     = plugin.RewardMgrInstance()
+		blocks = xutil.CalcBlocksEachEpoch()
+		first  = (epoch-1)*blocks + 1
+		last   = epoch * blocks
+		end    = s.chain.header(last)
+		hash   = end.Hash()
+	)
+	if err := s.db.NewBlock(snapshotBlock(epoch), s.parent, hash); err != nil {
+		return err
+	}
+	s.chain.setCurrent(end)
+
+	if err := sk.BeginBlock(hash, s.chain.header(first), s.state); err != nil {
+		return err
+	}
+	if err := s.join(hash, first, epoch); err != nil {
+		return err
+	}
+
+	verifiers, err := sk.GetVerifierList(hash, first, plugin.QueryStartNotIrr)
+	if err != nil {
+		return err
+	}
+	elected, err := s.lookup(verifiers)
+	if err != nil {
+		return err
+	}
+
+	var head *types.Header
+	for start := first; start < last; start += xutil.ConsensusSize() {
+		roundEnd := start + xutil.ConsensusSize() - 1
+		validators, err := sk.GetValidatorList(hash, start, plugin.CurrentRound, plugin.QueryStartNotIrr)
+		if err != nil {
+			return err
+		}
+		producers, err := s.lookup(validators)
+		if err != nil {
+			return err
+		}
+		producer := func(number uint64) *types.Header {
+			return producers[(number-start)%uint64(len(producers))].produce(number, s.chain.blockTime(number))
+		}
+
+		// the rewards of the first epoch are calculated by the first block
+		from := start
+		if start == 1 {
+			if err := rm.EndBlock(hash, producer(1), s.state); err != nil {
+				return err
+			}
+			from++
+		}
+		// the last block of the epoch is packaged by the reward plugin
+		to := roundEnd + 1
+		if roundEnd == last {
+			to = last
+		}
+		if err := s.packageBlocks(hash, epoch, start, from, to, producers); err != nil {
+			return err
+		}
+		if err := s.election(hash, producer(roundEnd-xcom.ElectionDistance())); err != nil {
+			return err
+		}
+		if roundEnd == last {
+			head = producer(last)
+		}
+	}
+
+	// the last block of the epoch, in the order of the blockchain reactor
+	if err := plugin.RestrictingInstance().EndBlock(hash, head, s.state); err != nil {
+		return err
+	}
+	if err := rm.EndBlock(hash, head, s.state); err != nil {
+		return err
+	}
+	if err := sk.EndBlock(hash, head, s.state); err != nil {
+		return err
+	}
+
+	s.state.Finalise(true)
+	if err := s.db.Commit(hash); err != nil {
+		return err
+	}
+	s.parent = hash
+	return s.collect(hash, epoch, last, elected)
+}
+
+// lookup returns the candidates of the validators
+func (s *simulator) lookup(validators staking.ValidatorExQueue) ([]*candidate, error) {
+	candidates := make([]*candidate, 0, len(validators))
+	for _, v := range validators {
+		c, ok := s.nodes[v.NodeId]
+		if !ok {
+			return nil, fmt.Errorf("unknown validator %s", v.NodeId.TerminalString())
+		}
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("no validator to produce the blocks")
+	}
+	return candidates, nil
+}
+
+// election elects the validators of the next round by the block. The simulation
+// doesn't produce the vrf proofs, the nonces of the block and of the previous blocks
+// are drawn from the seed and stored as the nonces of the parent, which is the epoch block.
+func (s *simulator) election(hash common.Hash, header *types.Header) error {
+	maxValidators, err := gov.GovernMaxValidators(header.Number.Uint64(), hash)
+	if err != nil {
+		return err
+	}
+	nonces := make([][]byte, maxValidators)
+	for i := range nonces {
+		nonces[i] = make([]byte, common.HashLength)
+		s.rng.Read(nonces[i])
+	}
+	value, err := rlp.EncodeToBytes(nonces)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Put(hash, handler.NonceStorageKey, value); err != nil {
+		return err
+	}
+	s.rng.Read(header.Nonce[:])
+	header.ParentHash = hash
+	return plugin.StakingInstance().Election(hash, header, s.state)
+}
+
+// packageBlocks allocates the package rewards of the blocks [from, last),
+// the blocks of a producer are allocated by one call and counted in the ledger afterwards.
+func (s *simulator) packageBlocks(hash common.Hash, epoch, first, from, last uint64, producers []*candidate) error {
+	if from >= last {
+		return nil
+	}
+	blockReward, err := plugin.LoadNewBlockReward(hash, s.db)
+	if err != nil {
+		return err
+	}
+	counts := make([]uint64, len(producers))
+	for number := from; number < last; number++ {
+		counts[(number-first)%uint64(len(producers))]++
+	}
+	for i, c := range producers {
+		if counts[i] == 0 {
+			continue
+		}
+		amount := new(big.Int).Mul(blockReward, new(big.Int).SetUint64(counts[i]))
+		if err := plugin.RewardMgrInstance().AllocatePackageBlock(hash, c.produce(from, s.chain.blockTime(from)), amount, s.state); err != nil {
+			return err
+		}
+	}
+	return plugin.UpdateEpochEconomics(hash, s.state, s.db, epoch, func(ledger *reward.EpochEconomics) {
+		ledger.NewBlockReward = blockReward
+		for i, c := range producers {
+			if counts[i] > 1 {
+				ledger.Node(c.nodeID).Blocks += counts[i] - 1
+			}
+		}
+	})
+}
+
+// join creates the candidates and the delegations of the groups joining in the epoch
+func (s *simulator) join(hash common.Hash, number, epoch uint64) error {
+	for i, g := range s.cfg.Candidates {
+		if g.JoinEpoch != epoch {
+			continue
+		}
+		for n := 0; n < g.Count; n++ {
+			if err := s.createCandidate(hash, number, epoch, g); err != nil {
+				return fmt.Errorf("candidate group %d: %v", i, err)
+			}
+		}
+	}
+	for i, g := range s.cfg.Delegators {
+		if g.JoinEpoch != epoch {
+			continue
+		}
+		for n := 0; n < g.Count; n++ {
+			if err := s.delegate(hash, number, g); err != nil {
+				return fmt.Errorf("delegator group %d: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *simulator) createCandidate(hash common.Hash, number, epoch uint64, g *CandidateGroup) error {
+	key, err := s.newKey()
+	if err != nil {
+		return err
+	}
+	amount, rewardPer := g.Stake.sample(s.rng), g.RewardPer.sample(s.rng)
+	if ok, threshold := plugin.CheckStakeThreshold(number, hash, amount); !ok {
+		return fmt.Errorf("the stake %v is lower than the threshold %v", amount, threshold)
+	}
+
+	c := &candidate{
+		nodeID:          discover.PubkeyID(&key.PublicKey),
+		addr:            crypto.PubkeyToNodeAddress(key.PublicKey),
+		benefitAddress:  s.newAddress(),
+		stakingBlockNum: number,
+		joinEpoch:       epoch,
+		stake:           amount,
+		rewardPer:       rewardPer,
+	}
+	stakingAddress := s.newAddress()
+	if err := s.fund(stakingAddress, amount); err != nil {
+		return err
+	}
+
+	can := &staking.Candidate{
+		CandidateBase: &staking.CandidateBase{
+			NodeId:          c.nodeID,
+			StakingAddress:  stakingAddress,
+			BenefitAddress:  c.benefitAddress,
+			StakingBlockNum: number,
+			StakingTxIndex:  uint32(len(s.candidates)),
+			ProgramVersion:  configs.CodeVersion(),
+			Description: staking.Description{
+				NodeName: fmt.Sprintf("econsim-%d", len(s.candidates)),
+			},
+		},
+		CandidateMutable: &staking.CandidateMutable{
+			Shares:               amount,
+			Released:             new(big.Int).SetInt64(0),
+			ReleasedHes:          new(big.Int).SetInt64(0),
+			RestrictingPlan:      new(big.Int).SetInt64(0),
+			RestrictingPlanHes:   new(big.Int).SetInt64(0),
+			RewardPer:            rewardPer,
+			NextRewardPer:        rewardPer,
+			RewardPerChangeEpoch: uint32(xutil.CalculateEpoch(number)),
+			DelegateRewardTotal:  new(big.Int).SetInt64(0),
+		},
+	}
+	if err := plugin.StakingInstance().CreateCandidate(s.state, hash, new(big.Int).SetUint64(number), amount, plugin.FreeVon, c.addr, can); err != nil {
+		return err
+	}
+
+	c.header = s.chain.header(0)
+	c.header.Coinbase = c.benefitAddress
+	c.header.Extra = make([]byte, 32+common.ExtraSeal)
+	sign, err := crypto.Sign(c.header.SealHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	copy(c.header.Extra[32:], sign)
+	if c.header.CachePublicKey() == nil {
+		return errors.New("failed to recover the producer of the header")
+	}
+
+	s.candidates = append(s.candidates, c)
+	s.nodes[c.nodeID] = c
+	if s.year != nil {
+		s.year.add(c)
+	}
+	return nil
+}
+
+func (s *simulator) delegate(hash common.Hash, number uint64, g *DelegatorGroup) error {
+	c := s.chooseCandidate(g)
+	amount := g.Amount.sample(s.rng)
+	if ok, threshold := plugin.CheckOperatingThreshold(number, hash, amount); !ok {
+		return fmt.Errorf("the delegation %v is lower than the threshold %v", amount, threshold)
+	}
+	delAddr := s.newAddress()
+	if err := s.fund(delAddr, amount); err != nil {
+		return err
+	}
+
+	sk := plugin.StakingInstance()
+	can, err := sk.GetCandidateInfo(hash, c.addr)
+	if err != nil {
+		return err
+	}
+	if err := sk.Delegate(s.state, hash, new(big.Int).SetUint64(number), delAddr, staking.NewDelegation(),
+		c.addr, can, plugin.FreeVon, amount, nil); err != nil {
+		return err
+	}
+	if s.rng.Float64() < g.AutoCompound {
+		return sk.SetAutoCompound(hash, delAddr, c.nodeID, c.stakingBlockNum, true)
+	}
+	return nil
+}
+
+// chooseCandidate picks one of the candidates that joined no later than the group
+func (s *simulator) chooseCandidate(g *DelegatorGroup) *candidate {
+	eligible := make([]*candidate, 0, len(s.candidates))
+	for _, c := range s.candidates {
+		if c.joinEpoch <= g.JoinEpoch {
+			eligible = append(eligible, c)
+		}
+	}
+	if g.Target == TargetUniform {
+		return eligible[s.rng.Intn(len(eligible))]
+	}
+	weights := make([]float64, len(eligible))
+	var total float64
+	for i, c := range eligible {
+		w, _ := new(big.Float).SetInt(c.stake).Float64()
+		total += w
+		weights[i] = total
+	}
+	point := s.rng.Float64() * total
+	return eligible[sort.SearchFloat64s(weights, point)%len(eligible)]
+}
+
+func (s *simulator) fund(addr common.Address, amount *big.Int) error {
+	if s.state.GetBalance(s.general).Cmp(amount) < 0 {
+		return fmt.Errorf("the general balance is not enough to pay %v", amount)
+	}
+	s.state.SubBalance(s.general, amount)
+	s.state.AddBalance(addr, amount)
+	return nil
+}
+
+func (s *simulator) newKey() (*ecdsa.PrivateKey, error) {
+	for {
+		seed := make([]byte, 32)
+		s.rng.Read(seed)
+		// a seed out of the curve order is rare, draw again
+		if key, err := crypto.ToECDSA(seed); err == nil {
+			return key, nil
+		}
+	}
+}
+
+func (s *simulator) newAddress() common.Address {
+	var addr common.Address
+	s.rng.Read(addr[:])
+	return addr
+}
@@ -25,6 +25,7 @@ type Staking interface {
 	GetCanBase(blockHash common.Hash, addr common.NodeAddress) (*staking.CandidateBase, error)
 	GetCanMutable(blockHash common.Hash, addr common.NodeAddress) (*staking.CandidateMutable, error)
 	DeclarePromoteNotify(blockHash common.Hash, blockNumber uint64, nodeId discover.NodeID, programVersion uint32) error
+	GetOperator(blockHash common.Hash, nodeId discover.NodeID, stakingBlockNum uint64) (common.Address, error)
 }
 
 const (
@@ -34,6 +35,7 @@ const (
 	ModuleTxPool      = "txPool"
 	ModuleReward      = "reward"
 	ModuleRestricting = "restricting"
+	ModuleGov         = "gov"
 )
 
 const (
@@ -54,6 +56,9 @@ const (
 	KeyIncreaseIssuanceRatio      = "increaseIssuanceRatio"
 	KeyZeroProduceFreezeDuration  = "zeroProduceFreezeDuration"
 	KeyRestrictingMinimumAmount   = "minimumRelease"
+	KeyVoteParticipationThreshold = "voteParticipationThreshold"
+	KeySlashVoteBlocksReward      = "slashVoteBlocksReward"
+	KeyStakeWeightedTally         = "stakeWeightedTally"
 )
 
 func Gte110VersionState(state xcom.StateDB) bool {
@@ -64,6 +69,14 @@ func Gte110Version(version uint32) bool {
 	return version >= configs.FORKVERSION_1_1_0
 }
 
+func Gte120VersionState(state xcom.StateDB) bool {
+	return Gte120Version(GetCurrentActiveVersion(state))
+}
+
+func Gte120Version(version uint32) bool {
+	return version >= configs.FORKVERSION_1_2_0
+}
+
 func GetVersionForStaking(blockHash common.Hash, state xcom.StateDB) uint32 {
 	preActiveVersion := GetPreActiveVersion(blockHash)
 	if preActiveVersion > 0 {
@@ -106,7 +119,7 @@ func Submit(from common.Address, proposal Proposal, blockHash common.Hash, block
 	}
 
 	//check caller and proposer
-	if err := checkVerifier(from, proposal.GetProposer(), blockHash, proposal.GetSubmitBlock(), stk); err != nil {
+	if err := checkVerifier(from, proposal.GetProposer(), blockHash, proposal.GetSubmitBlock(), stk, false); err != nil {
 		return err
 	}
 
@@ -153,7 +166,7 @@ func Vote(from common.Address, vote VoteInfo, blockHash common.Hash, blockNumber
 	}
 
 	//check caller and voter
-	if err := checkVerifier(from, vote.VoteNodeID, blockHash, blockNumber, stk); err != nil {
+	if err := checkVerifier(from, vote.VoteNodeID, blockHash, blockNumber, stk, true); err != nil {
 		return err
 	}
 
@@ -224,6 +237,64 @@ func Vote(from common.Address, vote VoteInfo, blockHash common.Hash, blockNumber
 	return nil
 }
 
+// VoteByDelegator saves the vote of a delegator, it overrides the votes of the verifiers
+// for the delegations of the delegator in the stake weighted tally.
+// The delegations are weighted by their amounts at the time of the vote.
+func VoteByDelegator(from common.Address, proposalID common.Hash, option VoteOption, delegations []DelegationWeight, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+	log.Debug("call VoteByDelegator", "from", from, "proposalID", proposalID, "voteOption", option, "blockHash", blockHash, "blockNumber", blockNumber)
+	if proposalID == common.ZeroHash {
+		return ProposalIDEmpty
+	}
+
+	if option != Yes && option != No && option != Abstention {
+		return VoteOptionError
+	}
+
+	if enabled, err := GovernStakeWeightedTally(blockNumber, blockHash); err != nil {
+		return err
+	} else if !enabled {
+		return StakeWeightedTallyDisabled
+	}
+
+	proposal, err := GetProposal(proposalID, state)
+	if err != nil {
+		log.Error("find proposal error", "proposalID", proposalID)
+		return err
+	} else if proposal == nil {
+		return ProposalNotFound
+	}
+
+	//the version proposal is tallied by the verifiers
+	if proposal.GetProposalType() == Version {
+		return DelegatorVoteNotSupported
+	}
+
+	votingIDs, err := ListVotingProposalID(blockHash)
+	if err != nil {
+		log.Error("list voting proposal error", "blockHash", blockHash, "blockNumber", blockNumber, "err", err)
+		return err
+	} else if !xutil.InHashList(proposalID, votingIDs) {
+		return ProposalNotAtVoting
+	}
+
+	if len(delegations) == 0 {
+		return DelegationNotFound
+	}
+
+	if voted, err := HasDelegatorVote(proposalID, from, blockHash); err != nil {
+		log.Error("find delegator vote error", "proposalID", proposalID, "delegator", from, "blockHash", blockHash, "blockNumber", blockNumber)
+		return err
+	} else if voted {
+		return VoteDuplicated
+	}
+
+	if err := AddDelegatorVote(proposalID, from, option, delegations, blockHash); err != nil {
+		log.Error("save delegator vote error", "proposalID", proposalID)
+		return err
+	}
+	return nil
+}
+
 // node declares it's version
 func DeclareVersion(from common.Address, declaredNodeID discover.NodeID, declaredVersion uint32, programVersionSign common.VersionSign, blockHash common.Hash, blockNumber uint64, stk Staking, state xcom.StateDB) error {
 	log.Debug("call DeclareVersion", "from", from, "blockHash", blockHash, "blockNumber", blockNumber, "declaredNodeID", declaredNodeID, "declaredVersion", declaredVersion, "versionSign", programVersionSign)
@@ -314,8 +385,9 @@ func DeclareVersion(from common.Address, declaredNodeID discover.NodeID, declare
 	return nil
 }
 
-// check if the node a verifier, and the caller address is same as the staking address
-func checkVerifier(from common.Address, nodeID discover.NodeID, blockHash common.Hash, blockNumber uint64, stk Staking) error {
+// check if the node a verifier, and the caller address is same as the staking address,
+// or the operator of the node if the operator is allowed
+func checkVerifier(from common.Address, nodeID discover.NodeID, blockHash common.Hash, blockNumber uint64, stk Staking, allowOperator bool) error {
 	log.Debug("call checkVerifier", "from", from, "blockHash", blockHash, "blockNumber", blockNumber, "nodeID", nodeID)
 
 	_, err := xutil.NodeId2Addr(nodeID)
@@ -334,7 +406,13 @@ func checkVerifier(from common.Address, nodeID discover.NodeID, blockHash common
 
 	for _, verifier := range verifierList {
 		if verifier != nil && verifier.NodeId == nodeID {
-			if verifier.StakingAddress == from {
+			isSender := verifier.StakingAddress == from
+			if !isSender && allowOperator {
+				if isSender, err = isOperator(from, verifier.NodeId, verifier.StakingBlockNum, blockHash, stk); err != nil {
+					return err
+				}
+			}
+			if isSender {
 				nodeAddress, err := xutil.NodeId2Addr(verifier.NodeId)
 				if err != nil {
 					return err
@@ -604,7 +682,7 @@ func FindGovernParam(module, name string, blockHash common.Hash) (*GovernParam,
 	return nil, nil
 }
 
-// check if the node a candidate, and the caller address is same as the staking address
+// check if the node a candidate, and the caller address is same as the staking address or the operator
 func checkCandidate(from common.Address, nodeID discover.NodeID, blockHash common.Hash, blockNumber uint64, stk Staking) error {
 
 	_, err := xutil.NodeId2Addr(nodeID)
@@ -623,15 +701,46 @@ func checkCandidate(from common.Address, nodeID discover.NodeID, blockHash commo
 		if candidate.NodeId == nodeID {
 			if candidate.StakingAddress == from {
 				return nil
-			} else {
-				return TxSenderDifferFromStaking
 			}
+			if ok, err := isOperator(from, candidate.NodeId, candidate.StakingBlockNum, blockHash, stk); err != nil {
+				return err
+			} else if ok {
+				return nil
+			}
+			return TxSenderDifferFromStaking
 		}
 	}
 	return TxSenderIsNotCandidate
 }
 
-type ParamVerifier func(blockNumber uint64, blockHash common.Hash, value string) error
+// check if the caller address is the operator registered for the node
+func isOperator(from common.Address, nodeID discover.NodeID, stakingBlockNum uint64, blockHash common.Hash, stk Staking) (bool, error) {
+	operator, err := stk.GetOperator(blockHash, nodeID, stakingBlockNum)
+	if err != nil {
+		log.Error("get operator error", "blockHash", blockHash, "nodeID", nodeID, "err", err)
+		return false, err
+	}
+	return operator != common.ZeroAddr && operator == from, nil
+}
+
+// ParamVerifier verifies the new value of a governed parameter, the parameters it
+// depends on are read from the pending values first, which are changed together with it.
+type ParamVerifier func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error
+
+// PendingParams holds the new values of the parameters changed together by a proposal, keyed by module/name.
+type PendingParams map[string]string
+
+// Uint64 returns the pending value of the parameter if it is changed, or the governed value.
+func (p PendingParams) Uint64(module, name string, blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, ok := p[module+"/"+name]
+	if !ok {
+		var err error
+		if valueStr, err = GetGovernParamValue(module, name, blockNumber, blockHash); nil != err {
+			return 0, err
+		}
+	}
+	return strconv.ParseUint(valueStr, 10, 64)
+}
 
 func GetGovernParamValue(module, name string, blockNumber uint64, blockHash common.Hash) (string, error) {
 	paramValue, err := findGovernParamValue(module, name, blockHash)
@@ -819,6 +928,51 @@ func GovernZeroProduceCumulativeTime(blockNumber uint64, blockHash common.Hash)
 	return uint16(value), nil
 }
 
+func GovernVoteParticipationThreshold(blockNumber uint64, blockHash common.Hash) (uint16, error) {
+	valueStr, err := GetGovernParamValue(ModuleSlashing, KeyVoteParticipationThreshold, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint16(value), nil
+}
+
+func GovernSlashVoteBlocksReward(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	rewardStr, err := GetGovernParamValue(ModuleSlashing, KeySlashVoteBlocksReward, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	reward, err := strconv.Atoi(rewardStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(reward), nil
+}
+
+// GovernStakeWeightedTally returns false before the parameter is added
+func GovernStakeWeightedTally(blockNumber uint64, blockHash common.Hash) (bool, error) {
+	paramValue, err := findGovernParamValue(ModuleGov, KeyStakeWeightedTally, blockHash)
+	if nil != err {
+		return false, err
+	}
+	if paramValue == nil {
+		return false, nil
+	}
+
+	valueStr := paramValue.StaleValue
+	if blockNumber >= paramValue.ActiveBlock {
+		valueStr = paramValue.Value
+	}
+	return strconv.ParseBool(valueStr)
+}
+
 func GovernRewardPerMaxChangeRange(blockNumber uint64, blockHash common.Hash) (uint16, error) {
 	valueStr, err := GetGovernParamValue(ModuleStaking, KeyRewardPerMaxChangeRange, blockNumber, blockHash)
 	if nil != err {
@@ -2,6 +2,7 @@ package gov
 
 import (
 	"encoding/json"
+	"math/big"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
 
@@ -66,6 +67,20 @@ func GetProposal(proposalID common.Hash, state xcom.StateDB) (Proposal, error) {
 			return nil, e
 		}
 		return &proposal, nil
+	} else if pType == byte(ParamBatch) {
+		var proposal ParamBatchProposal
+		if e := json.Unmarshal(pData, &proposal); e != nil {
+			log.Error("cannot parse data to param batch proposal")
+			return nil, e
+		}
+		return &proposal, nil
+	} else if pType == byte(Treasury) {
+		var proposal TreasuryProposal
+		if e := json.Unmarshal(pData, &proposal); e != nil {
+			log.Error("cannot parse data to treasury proposal")
+			return nil, e
+		}
+		return &proposal, nil
 	} else {
 		return nil, common.InternalError.Wrap("Incorrect proposal type.")
 	}
@@ -146,6 +161,63 @@ func TallyVoteValue(proposalID common.Hash, blockHash common.Hash) (yeas, nays,
 	return yes, no, abst, err
 }
 
+// AddDelegatorVote saves the vote of a delegator, and adds the amounts of its delegations
+// to the stakes voted on the stakings they are delegated to
+func AddDelegatorVote(proposalID common.Hash, delegator common.Address, option VoteOption, delegations []DelegationWeight, blockHash common.Hash) error {
+	if err := put(blockHash, KeyDelegatorVote(proposalID, delegator), option); err != nil {
+		return err
+	}
+	for _, delegation := range delegations {
+		stake, err := GetDelegatorVoteStake(proposalID, delegation.NodeID, delegation.StakingBlockNum, blockHash)
+		if err != nil {
+			return err
+		}
+		if stake == nil {
+			stake = &DelegatorVoteStake{Yeas: new(big.Int), Nays: new(big.Int), Abstentions: new(big.Int)}
+		}
+		switch option {
+		case Yes:
+			stake.Yeas.Add(stake.Yeas, delegation.Amount)
+		case No:
+			stake.Nays.Add(stake.Nays, delegation.Amount)
+		case Abstention:
+			stake.Abstentions.Add(stake.Abstentions, delegation.Amount)
+		}
+		if err := put(blockHash, KeyDelegatorVoteStake(proposalID, delegation.NodeID, delegation.StakingBlockNum), stake); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasDelegatorVote checks if the delegator has voted for the proposal
+func HasDelegatorVote(proposalID common.Hash, delegator common.Address, blockHash common.Hash) (bool, error) {
+	_, err := get(blockHash, KeyDelegatorVote(proposalID, delegator))
+	if err == snapshotdb.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDelegatorVoteStake returns the stakes voted by the delegators on the staking of the node,
+// nil if there is no delegator voted
+func GetDelegatorVoteStake(proposalID common.Hash, nodeID discover.NodeID, stakingBlockNum uint64, blockHash common.Hash) (*DelegatorVoteStake, error) {
+	stakeBytes, err := get(blockHash, KeyDelegatorVoteStake(proposalID, nodeID, stakingBlockNum))
+	if err == snapshotdb.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var stake DelegatorVoteStake
+	if err = rlp.DecodeBytes(stakeBytes, &stake); err != nil {
+		return nil, err
+	}
+	return &stake, nil
+}
+
 func ClearVoteValue(proposalID common.Hash, blockHash common.Hash) error {
 	if err := del(blockHash, KeyVote(proposalID)); err != nil {
 		log.Error("clear vote value in snapshot db failed", "proposalID", proposalID, "blockHash", blockHash.Hex(), "error", err)
@@ -275,6 +347,27 @@ func AddVotingProposalID(blockHash common.Hash, proposalID common.Hash) error {
 	return nil
 }
 
+// ListPendingTreasuryID returns the passed treasury proposals which have not been paid yet
+func ListPendingTreasuryID(blockHash common.Hash) ([]common.Hash, error) {
+	return getProposalIDListByKey(blockHash, KeyPendingTreasuries())
+}
+
+func AddPendingTreasuryID(blockHash common.Hash, proposalID common.Hash) error {
+	return addProposalByKey(blockHash, KeyPendingTreasuries(), proposalID)
+}
+
+func RemovePendingTreasuryID(blockHash common.Hash, proposalID common.Hash) error {
+	pending, err := getProposalIDListByKey(blockHash, KeyPendingTreasuries())
+	if err != nil {
+		return err
+	}
+	pending = remove(pending, proposalID)
+	if len(pending) == 0 {
+		return del(blockHash, KeyPendingTreasuries())
+	}
+	return put(blockHash, KeyPendingTreasuries(), pending)
+}
+
 func MoveVotingProposalIDToPreActive(blockHash common.Hash, proposalID common.Hash, preactiveVersion uint32) error {
 	voting, err := getVotingIDList(blockHash)
 	if err != nil {
@@ -37,4 +37,13 @@ var (
 	VotingParamProposalExist          = common.NewBizError(302032, "Another parameter proposal already existed at voting stage")
 	GovernParamValueError             = common.NewBizError(302033, "Govern parameter value error")
 	ParamProposalIsSameValue          = common.NewBizError(302034, "The new value of the parameter proposal is the same as the old one")
+	ParamBatchChangesCountError       = common.NewBizError(302035, "The number of the parameter changes is out of range")
+	ParamBatchChangesDuplicated       = common.NewBizError(302036, "Duplicated parameters found in the parameter changes")
+	TreasuryRecipientInvalid          = common.NewBizError(302037, "The recipient of the treasury proposal is invalid")
+	TreasuryAmountInvalid             = common.NewBizError(302038, "The amount of the treasury proposal must be more than zero")
+	TreasuryPlansInvalid              = common.NewBizError(302039, "The restricting plans of the treasury proposal are invalid")
+	TreasuryBalanceNotEnough          = common.NewBizError(302040, "The balance of the treasury account is not enough")
+	StakeWeightedTallyDisabled        = common.NewBizError(302041, "The stake weighted tally is disabled")
+	DelegatorVoteNotSupported         = common.NewBizError(302042, "Delegators cannot vote for this type of proposal")
+	DelegationNotFound                = common.NewBizError(302043, "The sender has no delegation")
 )
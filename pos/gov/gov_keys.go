@@ -3,6 +3,7 @@ package gov
 import (
 	"bytes"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 )
 
@@ -10,9 +11,12 @@ var (
 	KeyDelimiter               = []byte(":")
 	keyPrefixProposal          = []byte("PID")
 	keyPrefixVote              = []byte("Vote")
+	keyPrefixDelegatorVote     = []byte("DelVote")
+	keyPrefixDelegatorStake    = []byte("DelVoteStake")
 	keyPrefixTallyResult       = []byte("Result")
 	keyPrefixVotingProposals   = []byte("Votings")
 	keyPrefixEndProposals      = []byte("Ends")
+	keyPrefixPendingTreasuries = []byte("PendTreasuries")
 	keyPrefixPreActiveProposal = []byte("PreActPID")
 	keyPrefixPreActiveVersion  = []byte("PreActVer")
 	keyPrefixActiveVersions    = []byte("ActVers")
@@ -39,6 +43,23 @@ func KeyVote(proposalID common.Hash) []byte {
 	}, KeyDelimiter)
 }
 
+func KeyDelegatorVote(proposalID common.Hash, delegator common.Address) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixDelegatorVote,
+		proposalID.Bytes(),
+		delegator.Bytes(),
+	}, KeyDelimiter)
+}
+
+func KeyDelegatorVoteStake(proposalID common.Hash, nodeID discover.NodeID, stakingBlockNum uint64) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixDelegatorStake,
+		proposalID.Bytes(),
+		nodeID.Bytes(),
+		common.Uint64ToBytes(stakingBlockNum),
+	}, KeyDelimiter)
+}
+
 func KeyTallyResult(proposalID common.Hash) []byte {
 	return bytes.Join([][]byte{
 		keyPrefixTallyResult,
@@ -50,6 +71,10 @@ func KeyVotingProposals() []byte {
 	return keyPrefixVotingProposals
 }
 
+func KeyPendingTreasuries() []byte {
+	return keyPrefixPendingTreasuries
+}
+
 func KeyPreActiveProposal() []byte {
 	return keyPrefixPreActiveProposal
 }
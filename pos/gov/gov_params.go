@@ -40,7 +40,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyStakeThreshold,
 				fmt.Sprintf("minimum amount of stake, range: [%d, %d]", xcom.StakeLowerLimit, xcom.StakeUpperLimit)},
 			ParamValue: &ParamValue{"", xcom.StakeThreshold().String(), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				threshold, ok := new(big.Int).SetString(value, 10)
 				if !ok {
@@ -58,7 +58,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyOperatingThreshold,
 				fmt.Sprintf("minimum amount of stake increasing funds, delegation funds, or delegation withdrawing funds, range: [%d, %d]", xcom.DelegateLowerLimit, xcom.DelegateUpperLimit)},
 			ParamValue: &ParamValue{"", xcom.OperatingThreshold().String(), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				threshold, ok := new(big.Int).SetString(value, 10)
 				if !ok {
@@ -78,7 +78,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyMaxValidators,
 				fmt.Sprintf("maximum amount of validator, range: [%d, %d]", xcom.MaxConsensusVals(), xcom.CeilMaxValidators)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MaxValidators())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				num, err := strconv.Atoi(value)
 				if nil != err {
@@ -98,18 +98,18 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyUnStakeFreezeDuration,
 				fmt.Sprintf("quantity of epoch for skake withdrawal, range: (MaxEvidenceAge, %d]", xcom.CeilUnStakeFreezeDuration)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.UnStakeFreezeDuration())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				num, err := strconv.Atoi(value)
 				if nil != err {
 					return fmt.Errorf("Parsed UnStakeFreezeDuration is failed: %v", err)
 				}
 
-				age, err := GovernMaxEvidenceAge(blockNumber, blockHash)
+				age, err := pending.Uint64(ModuleSlashing, KeyMaxEvidenceAge, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
-				epochNumber, err := GovernZeroProduceFreezeDuration(blockNumber, blockHash)
+				epochNumber, err := pending.Uint64(ModuleSlashing, KeyZeroProduceFreezeDuration, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
@@ -130,7 +130,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeySlashFractionDuplicateSign,
 				fmt.Sprintf("quantity of base point(1BP=1‱). Node's stake will be deducted(BPs*staking amount*1‱) it the node sign block duplicatlly, range: (%d, %d]", xcom.Zero, xcom.TenThousand)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashFractionDuplicateSign())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				fraction, err := strconv.Atoi(value)
 				if nil != err {
@@ -149,7 +149,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyDuplicateSignReportReward,
 				fmt.Sprintf("quantity of base point(1bp=1%%). Bonus(BPs*deduction amount for sign block duplicatlly*%%) to the node who reported another's duplicated-signature, range: (%d, %d]", xcom.Zero, xcom.Eighty)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.DuplicateSignReportReward())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				fraction, err := strconv.Atoi(value)
 				if nil != err {
@@ -168,14 +168,14 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyMaxEvidenceAge,
 				fmt.Sprintf("quantity of epoch. During these epochs after a node duplicated-sign, others can report it, range: (%d, UnStakeFreezeDuration)", xcom.Zero)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MaxEvidenceAge())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				age, err := strconv.Atoi(value)
 				if nil != err {
 					return fmt.Errorf("Parsed MaxEvidenceAge is failed: %v", err)
 				}
 
-				duration, err := GovernUnStakeFreezeDuration(blockNumber, blockHash)
+				duration, err := pending.Uint64(ModuleStaking, KeyUnStakeFreezeDuration, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
@@ -191,7 +191,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeySlashBlocksReward,
 				fmt.Sprintf("quantity of block, the total bonus amount for these blocks will be deducted from a inefficient node's stake, range: [%d, %d)", xcom.Zero, xcom.CeilBlocksReward)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashBlocksReward())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				rewards, err := strconv.Atoi(value)
 				if nil != err {
@@ -213,7 +213,7 @@ func initParam() []*GovernParam {
 		{
 			ParamItem:  &ParamItem{ModuleBlock, KeyMaxBlockGasLimit, fmt.Sprintf("maximum gas limit per block, range: [%d, %d]", int(configs.GenesisGasLimit), int(configs.MaxGasCeil))},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(configs.DefaultMinerGasCeil)), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				gasLimit, err := strconv.Atoi(value)
 				if nil != err {
@@ -233,18 +233,18 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyZeroProduceCumulativeTime,
 				fmt.Sprintf("Time range for recording the number of behaviors of zero production blocks, range: [ZeroProduceNumberThreshold, %d]", xcom.MaxZeroProduceCumulativeTime)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.ZeroProduceCumulativeTime())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				roundNumber, err := strconv.Atoi(value)
 				if nil != err {
 					return fmt.Errorf("parsed ZeroProduceCumulativeTime is failed")
 				}
 
-				numberThreshold, err := GovernZeroProduceNumberThreshold(blockNumber, blockHash)
+				numberThreshold, err := pending.Uint64(ModuleSlashing, KeyZeroProduceNumberThreshold, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
-				if err := xcom.CheckZeroProduceCumulativeTime(uint16(roundNumber), numberThreshold); nil != err {
+				if err := xcom.CheckZeroProduceCumulativeTime(uint16(roundNumber), uint16(numberThreshold)); nil != err {
 					return err
 				}
 				return nil
@@ -255,18 +255,18 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyZeroProduceNumberThreshold,
 				fmt.Sprintf("Number of zero production blocks, range: [1, ZeroProduceCumulativeTime]")},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.ZeroProduceNumberThreshold())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				number, err := strconv.Atoi(value)
 				if nil != err {
 					return fmt.Errorf("parsed ZeroProduceNumberThreshold is failed")
 				}
 
-				roundNumber, err := GovernZeroProduceCumulativeTime(blockNumber, blockHash)
+				roundNumber, err := pending.Uint64(ModuleSlashing, KeyZeroProduceCumulativeTime, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
-				if err := xcom.CheckZeroProduceNumberThreshold(roundNumber, uint16(number)); nil != err {
+				if err := xcom.CheckZeroProduceNumberThreshold(uint16(roundNumber), uint16(number)); nil != err {
 					return err
 				}
 				return nil
@@ -277,7 +277,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyRewardPerMaxChangeRange,
 				fmt.Sprintf("Delegated Reward Ratio The maximum adjustable range of each modification, range: [%d, %d]", xcom.RewardPerMaxChangeRangeLowerLimit, xcom.RewardPerMaxChangeRangeUpperLimit)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.RewardPerMaxChangeRange())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				number, err := strconv.Atoi(value)
 				if nil != err {
@@ -295,7 +295,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleStaking, KeyRewardPerChangeInterval,
 				fmt.Sprintf("The interval for each modification of the commission reward ratio, range: [%d, %d]", xcom.RewardPerChangeIntervalLowerLimit, xcom.RewardPerChangeIntervalUpperLimit)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.RewardPerChangeInterval())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				number, err := strconv.Atoi(value)
 				if nil != err {
@@ -313,7 +313,7 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleReward, KeyIncreaseIssuanceRatio,
 				fmt.Sprintf("Increase the ratio of issuance, range: [%d, %d]", xcom.IncreaseIssuanceRatioLowerLimit, xcom.IncreaseIssuanceRatioUpperLimit)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.IncreaseIssuanceRatio())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				number, err := strconv.Atoi(value)
 				if nil != err {
@@ -331,14 +331,14 @@ func initParam() []*GovernParam {
 			ParamItem: &ParamItem{ModuleSlashing, KeyZeroProduceFreezeDuration,
 				fmt.Sprintf("Zero production frozen time, range: [1, UnStakeFreezeDuration)")},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.ZeroProduceFreezeDuration())), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 
 				number, err := strconv.Atoi(value)
 				if nil != err {
 					return fmt.Errorf("parsed KeyZeroProduceFreezeDuration is failed")
 				}
 
-				epochNumber, err := GovernUnStakeFreezeDuration(blockNumber, blockHash)
+				epochNumber, err := pending.Uint64(ModuleStaking, KeyUnStakeFreezeDuration, blockNumber, blockHash)
 				if nil != err {
 					return err
 				}
@@ -355,7 +355,7 @@ func initParam() []*GovernParam {
 				fmt.Sprintf("minimum restricting amount to be released in each epoch, range: [%d, %d]",
 					xcom.FloorMinimumRelease, xcom.CeilMinimumRelease)},
 			ParamValue: &ParamValue{"", xcom.RestrictingMinimumRelease().String(), 0},
-			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
 				v, ok := new(big.Int).SetString(value, 10)
 				if !ok {
 					return fmt.Errorf("parsed KeyRestrictingMinimumAmount is failed")
@@ -369,6 +369,95 @@ func initParam() []*GovernParam {
 	}
 }
 
+// The parameters of the missed vote slashing are not stored at genesis,
+// which would change the genesis of the existing chains. They are added by
+// InitVoteParticipationParam once the blocks carry the vote participation.
+func voteParticipationParam() []*GovernParam {
+	return []*GovernParam{
+		{
+
+			ParamItem: &ParamItem{ModuleSlashing, KeyVoteParticipationThreshold,
+				fmt.Sprintf("Percentage of the quorum certificates of a round that a validator must have signed, 0 disables the missed vote slashing, range: [%d, %d]", xcom.Zero, xcom.Hundred)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.VoteParticipationThreshold())), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
+
+				threshold, err := strconv.Atoi(value)
+				if nil != err || threshold < 0 {
+					return fmt.Errorf("parsed VoteParticipationThreshold is failed")
+				}
+
+				if err := xcom.CheckVoteParticipationThreshold(uint16(threshold)); nil != err {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+
+			ParamItem: &ParamItem{ModuleSlashing, KeySlashVoteBlocksReward,
+				fmt.Sprintf("quantity of block, the total bonus amount for these blocks will be deducted from the stake of a node missing votes, range: [%d, %d)", xcom.Zero, xcom.CeilBlocksReward)},
+			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.SlashBlocksReward())), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
+
+				rewards, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("Parsed SlashVoteBlocksReward is failed: %v", err)
+				}
+
+				if err := xcom.CheckSlashBlocksReward(rewards); nil != err {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// InitVoteParticipationParam adds the parameters of the missed vote slashing
+// which are not added yet, with the default values.
+func InitVoteParticipationParam(blockHash common.Hash) error {
+	return initMissingParam(voteParticipationParam(), blockHash)
+}
+
+// The stake weighted tally is disabled by default,
+// the parameter is added at the begin of the first epoch after upgrading.
+func stakeWeightedTallyParam() []*GovernParam {
+	return []*GovernParam{
+		{
+
+			ParamItem: &ParamItem{ModuleGov, KeyStakeWeightedTally,
+				"Whether the proposals are tallied by the stakes of the verifiers and the delegators, range: [false, true]"},
+			ParamValue: &ParamValue{"", strconv.FormatBool(false), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string, pending PendingParams) error {
+
+				if _, err := strconv.ParseBool(value); nil != err {
+					return fmt.Errorf("parsed StakeWeightedTally is failed")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// InitStakeWeightedTallyParam adds the parameter of the stake weighted tally if it's not added yet.
+func InitStakeWeightedTallyParam(blockHash common.Hash) error {
+	return initMissingParam(stakeWeightedTallyParam(), blockHash)
+}
+
+func initMissingParam(params []*GovernParam, blockHash common.Hash) error {
+	for _, param := range params {
+		if exist, err := FindGovernParam(param.ParamItem.Module, param.ParamItem.Name, blockHash); nil != err {
+			return err
+		} else if exist != nil {
+			continue
+		}
+		if err := SetGovernParam(param.ParamItem.Module, param.ParamItem.Name, param.ParamItem.Desc, param.ParamValue.Value, 0, blockHash); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
 var ParamVerifierMap = make(map[string]ParamVerifier)
 
 func InitGenesisGovernParam(prevHash common.Hash, snapDB snapshotdb.BaseDB, genesisVersion uint32) (common.Hash, error) {
@@ -412,6 +501,12 @@ func RegisterGovernParamVerifiers() {
 	for _, param := range queryInitParam() {
 		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
 	}
+	for _, param := range voteParticipationParam() {
+		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
+	}
+	for _, param := range stakeWeightedTallyParam() {
+		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
+	}
 }
 
 func RegGovernParamVerifier(module, name string, callback ParamVerifier) {
@@ -43,6 +43,7 @@ var (
 
 type MockStaking struct {
 	DeclaeredVodes map[discover.NodeID]uint32
+	Operator       common.Address
 }
 
 func (stk *MockStaking) GetVerifierList(blockHash common.Hash, blockNumber uint64, isCommit bool) (staking.ValidatorExQueue, error) {
@@ -90,6 +91,10 @@ func (stk *MockStaking) DeclarePromoteNotify(blockHash common.Hash, blockNumber
 	return nil
 }
 
+func (stk *MockStaking) GetOperator(blockHash common.Hash, nodeId discover.NodeID, stakingBlockNum uint64) (common.Address, error) {
+	return stk.Operator, nil
+}
+
 func (stk *MockStaking) ListDeclaredNode() map[discover.NodeID]uint32 {
 	return stk.DeclaeredVodes
 }
@@ -315,6 +320,78 @@ func TestGov_Vote(t *testing.T) {
 	}
 }
 
+func TestGov_VoteByOperator(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	submitText(t, chain)
+
+	commit_sndb(chain)
+	prepair_sndb(chain)
+
+	versionSign := common.BytesToVersionSign(sign(configs.GenesisVersion))
+	operator := common.Address{0x1}
+	stk := NewMockStaking()
+
+	vi := VoteInfo{ProposalID: tpProposalID, VoteNodeID: nodeID, VoteOption: Yes}
+	err := Vote(operator, vi, chain.CurrentHeader().Hash(), chain.CurrentHeader().Number.Uint64(), configs.GenesisVersion, versionSign, stk, chain.StateDB)
+	assert.Equal(t, TxSenderDifferFromStaking, err)
+
+	stk.Operator = operator
+	if err := Vote(operator, vi, chain.CurrentHeader().Hash(), chain.CurrentHeader().Number.Uint64(), configs.GenesisVersion, versionSign, stk, chain.StateDB); err != nil {
+		t.Error("Vote, err", err)
+		return
+	}
+
+	if vvList, err := ListVoteValue(tpProposalID, chain.CurrentHeader().Hash()); err != nil {
+		t.Error("ListVoteValue, err", err)
+	} else {
+		assert.Equal(t, 1, len(vvList))
+		assert.Equal(t, nodeID, vvList[0].VoteNodeID)
+	}
+}
+
+func TestGov_VoteByDelegator(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	submitText(t, chain)
+
+	commit_sndb(chain)
+	prepair_sndb(chain)
+
+	blockHash, blockNumber := chain.CurrentHeader().Hash(), chain.CurrentHeader().Number.Uint64()
+	delegations := []DelegationWeight{{NodeID: nodeID, StakingBlockNum: 1, Amount: big.NewInt(100)}}
+	err := VoteByDelegator(sender, tpProposalID, No, delegations, blockHash, blockNumber, chain.StateDB)
+	assert.Equal(t, StakeWeightedTallyDisabled, err)
+
+	if err := InitStakeWeightedTallyParam(blockHash); err != nil {
+		t.Fatal("InitStakeWeightedTallyParam, err", err)
+	}
+	if err := UpdateGovernParamValue(ModuleGov, KeyStakeWeightedTally, "true", blockNumber, blockHash); err != nil {
+		t.Fatal("UpdateGovernParamValue, err", err)
+	}
+
+	err = VoteByDelegator(common.Address{0x1}, tpProposalID, No, nil, blockHash, blockNumber, chain.StateDB)
+	assert.Equal(t, DelegationNotFound, err)
+
+	if err := VoteByDelegator(sender, tpProposalID, No, delegations, blockHash, blockNumber, chain.StateDB); err != nil {
+		t.Fatal("VoteByDelegator, err", err)
+	}
+	err = VoteByDelegator(sender, tpProposalID, Yes, delegations, blockHash, blockNumber, chain.StateDB)
+	assert.Equal(t, VoteDuplicated, err)
+
+	// the amounts of the votes are added up by the staking
+	if err := VoteByDelegator(common.Address{0x1}, tpProposalID, No, delegations, blockHash, blockNumber, chain.StateDB); err != nil {
+		t.Fatal("VoteByDelegator, err", err)
+	}
+	if stake, err := GetDelegatorVoteStake(tpProposalID, nodeID, 1, blockHash); err != nil {
+		t.Error("GetDelegatorVoteStake, err", err)
+	} else {
+		assert.Equal(t, &DelegatorVoteStake{Yeas: new(big.Int), Nays: big.NewInt(200), Abstentions: new(big.Int)}, stake)
+	}
+}
+
 // no voting proposal, no pre-active proposal
 func TestGov_DeclareVersion_1(t *testing.T) {
 	chain := setup(t)
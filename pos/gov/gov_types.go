@@ -1,6 +1,8 @@
 package gov
 
 import (
+	"math/big"
+
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 )
@@ -13,6 +15,12 @@ type TallyResult struct {
 	AccuVerifiers uint64         `json:"accuVerifiers"`
 	Status        ProposalStatus `json:"status"`
 	CanceledBy    common.Hash    `json:"canceledBy"`
+	// The stake weighted tally, it's kept only if the stake weighted tally mode is enabled.
+	// The vote of a verifier carries all the stakes of it, except the delegations whose delegators voted by themselves.
+	StakeYeas        *big.Int `json:"stakeYeas,omitempty"`
+	StakeNays        *big.Int `json:"stakeNays,omitempty"`
+	StakeAbstentions *big.Int `json:"stakeAbstentions,omitempty"`
+	StakeTotal       *big.Int `json:"stakeTotal,omitempty"`
 }
 
 type VoteInfo struct {
@@ -26,6 +34,21 @@ type VoteValue struct {
 	VoteOption VoteOption      `json:"voteOption"`
 }
 
+// DelegationWeight is a delegation of a voting delegator, the amount is taken at the time of the vote
+type DelegationWeight struct {
+	NodeID          discover.NodeID
+	StakingBlockNum uint64
+	Amount          *big.Int
+}
+
+// DelegatorVoteStake is the stakes voted by the delegators on the delegations to a staking,
+// they override the vote of the verifier for these delegations
+type DelegatorVoteStake struct {
+	Yeas        *big.Int
+	Nays        *big.Int
+	Abstentions *big.Int
+}
+
 type ActiveVersionValue struct {
 	ActiveVersion uint32 `json:"ActiveVersion"`
 	ActiveBlock   uint64 `json:"ActiveBlock"`
@@ -5,8 +5,10 @@ import (
 	"math/big"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/restricting"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
 )
@@ -22,6 +24,10 @@ const (
 	Version ProposalType = 0x02
 	Param   ProposalType = 0x03
 	Cancel  ProposalType = 0x04
+	// ParamBatch changes several parameters together
+	ParamBatch ProposalType = 0x05
+	// Treasury transfers an amount from the treasury account to a recipient
+	Treasury ProposalType = 0x06
 )
 
 type ProposalStatus uint8
@@ -234,7 +240,7 @@ func (vp *VersionProposal) Verify(submitBlock uint64, blockHash common.Hash, sta
 		return NewVersionError
 	}
 
-	if exist, err := FindVotingProposal(blockHash, state, Version, Param); err != nil {
+	if exist, err := FindVotingProposal(blockHash, state, Version, Param, ParamBatch); err != nil {
 		return err
 	} else if exist != nil {
 		if exist.GetProposalType() == Version {
@@ -342,7 +348,7 @@ func (cp *CancelProposal) Verify(submitBlock uint64, blockHash common.Hash, stat
 		return err
 	} else if tobeCanceled == nil {
 		return TobeCanceledProposalNotFound
-	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param {
+	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param && tobeCanceled.GetProposalType() != ParamBatch && tobeCanceled.GetProposalType() != Treasury {
 		return TobeCanceledProposalTypeError
 	} else if votingList, err := ListVotingProposal(blockHash); err != nil {
 		log.Error("list voting proposal error", "err", err)
@@ -427,18 +433,18 @@ func (pp *ParamProposal) Verify(submitBlock uint64, blockHash common.Hash, state
 	}
 
 	if paramVerifier, ok := ParamVerifierMap[pp.Module+"/"+pp.Name]; ok {
-		if err := paramVerifier(submitBlock, blockHash, pp.NewValue); err != nil {
+		if err := paramVerifier(submitBlock, blockHash, pp.NewValue, nil); err != nil {
 			return err
 		}
 	} else {
 		return UnsupportedGovernParam
 	}
 
-	if exist, err := FindVotingProposal(blockHash, state, Param, Version); err != nil {
+	if exist, err := FindVotingProposal(blockHash, state, Param, ParamBatch, Version); err != nil {
 		log.Error("find voting param proposal error", "err", err)
 		return err
 	} else if exist != nil {
-		if exist.GetProposalType() == Param {
+		if exist.GetProposalType() != Version {
 			return VotingParamProposalExist
 		} else {
 			return VotingVersionProposalExist
@@ -481,6 +487,249 @@ func (pp *ParamProposal) String() string {
 		pp.ProposalID, pp.ProposalType, pp.PIPID, pp.Proposer, pp.SubmitBlock, pp.EndVotingBlock, pp.Module, pp.Name, pp.NewValue)
 }
 
+// ParamChange is the new value of a parameter in a ParamBatchProposal
+type ParamChange struct {
+	Module   string
+	Name     string
+	NewValue string
+}
+
+// ParamBatchProposal changes several parameters together, the changes are verified against
+// the values after all of them are applied, and take effect in the same block.
+type ParamBatchProposal struct {
+	ProposalID     common.Hash
+	ProposalType   ProposalType
+	PIPID          string
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	Proposer       discover.NodeID
+	Result         TallyResult `json:"-"`
+	Changes        []ParamChange
+}
+
+func (bp *ParamBatchProposal) GetProposalID() common.Hash {
+	return bp.ProposalID
+}
+
+func (bp *ParamBatchProposal) GetProposalType() ProposalType {
+	return bp.ProposalType
+}
+
+func (bp *ParamBatchProposal) GetPIPID() string {
+	return bp.PIPID
+}
+
+func (bp *ParamBatchProposal) GetSubmitBlock() uint64 {
+	return bp.SubmitBlock
+}
+
+func (bp *ParamBatchProposal) GetEndVotingBlock() uint64 {
+	return bp.EndVotingBlock
+}
+
+func (bp *ParamBatchProposal) GetProposer() discover.NodeID {
+	return bp.Proposer
+}
+
+func (bp *ParamBatchProposal) GetTallyResult() TallyResult {
+	return bp.Result
+}
+
+func (bp *ParamBatchProposal) Verify(submitBlock uint64, blockHash common.Hash, state xcom.StateDB, chainID *big.Int) error {
+	if bp.ProposalType != ParamBatch {
+		return ProposalTypeError
+	}
+	if err := verifyBasic(bp, blockHash, state); err != nil {
+		return err
+	}
+
+	if len(bp.Changes) == 0 || uint64(len(bp.Changes)) > xcom.MaxParamBatchChanges() {
+		return ParamBatchChangesCountError
+	}
+	pending := make(PendingParams, len(bp.Changes))
+	for _, change := range bp.Changes {
+		key := change.Module + "/" + change.Name
+		if _, ok := pending[key]; ok {
+			return ParamBatchChangesDuplicated
+		}
+		param, err := FindGovernParam(change.Module, change.Name, blockHash)
+		if err != nil {
+			log.Error("find govern parameter error", "err", err)
+			return err
+		} else if param == nil {
+			return UnsupportedGovernParam
+		} else if param.ParamValue.Value == change.NewValue {
+			return ParamProposalIsSameValue
+		}
+		pending[key] = change.NewValue
+	}
+
+	// every change is verified with the new values of the others
+	for _, change := range bp.Changes {
+		if paramVerifier, ok := ParamVerifierMap[change.Module+"/"+change.Name]; ok {
+			if err := paramVerifier(submitBlock, blockHash, change.NewValue, pending); err != nil {
+				return err
+			}
+		} else {
+			return UnsupportedGovernParam
+		}
+	}
+
+	if exist, err := FindVotingProposal(blockHash, state, Param, ParamBatch, Version); err != nil {
+		log.Error("find voting param proposal error", "err", err)
+		return err
+	} else if exist != nil {
+		if exist.GetProposalType() != Version {
+			return VotingParamProposalExist
+		} else {
+			return VotingVersionProposalExist
+		}
+	}
+
+	//another VersionProposal in Pre-active process，exit
+	proposalID, err := GetPreActiveProposalID(blockHash)
+	if err != nil {
+		log.Error("check pre-active version proposal error", "blockNumber", submitBlock, "blockHash", blockHash)
+		return err
+	}
+	if proposalID != common.ZeroHash {
+		return PreActiveVersionProposalExist
+	}
+
+	var voteDuration = xcom.ParamProposalVote_DurationSeconds()
+
+	endVotingBlock := xutil.EstimateEndVotingBlockForParaProposal(submitBlock, voteDuration)
+	if endVotingBlock <= submitBlock {
+		log.Error("the end-voting-block is lower than submit-block. Please check configuration")
+		return common.InternalError
+	}
+	bp.EndVotingBlock = endVotingBlock
+	log.Debug("verify Parameter Batch Proposal", "PIPID", bp.PIPID, "changes", len(bp.Changes), "voteDuration", voteDuration, "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+
+	return nil
+}
+
+func (bp *ParamBatchProposal) String() string {
+	return fmt.Sprintf(`Proposal %x: 
+  Type:               	%x
+  PIPID:			    %s
+  Proposer:            	%x
+  SubmitBlock:        	%d
+  EndVotingBlock:   	%d
+  Changes:   			%v`,
+		bp.ProposalID, bp.ProposalType, bp.PIPID, bp.Proposer, bp.SubmitBlock, bp.EndVotingBlock, bp.Changes)
+}
+
+// TreasuryProposal transfers the Amount from the treasury pool to the Recipient when it passes.
+// If the Plans is not empty, the Amount is locked as restricting plans of the Recipient,
+// the epoch of the plans is counted from the epoch the proposal is paid.
+type TreasuryProposal struct {
+	ProposalID     common.Hash
+	ProposalType   ProposalType
+	PIPID          string
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	Proposer       discover.NodeID
+	Result         TallyResult `json:"-"`
+	Recipient      common.Address
+	Amount         *big.Int
+	Plans          []restricting.RestrictingPlan
+}
+
+func (tp *TreasuryProposal) GetProposalID() common.Hash {
+	return tp.ProposalID
+}
+
+func (tp *TreasuryProposal) GetProposalType() ProposalType {
+	return tp.ProposalType
+}
+
+func (tp *TreasuryProposal) GetPIPID() string {
+	return tp.PIPID
+}
+
+func (tp *TreasuryProposal) GetSubmitBlock() uint64 {
+	return tp.SubmitBlock
+}
+
+func (tp *TreasuryProposal) GetEndVotingBlock() uint64 {
+	return tp.EndVotingBlock
+}
+
+func (tp *TreasuryProposal) GetProposer() discover.NodeID {
+	return tp.Proposer
+}
+
+func (tp *TreasuryProposal) GetTallyResult() TallyResult {
+	return tp.Result
+}
+
+func (tp *TreasuryProposal) Verify(submitBlock uint64, blockHash common.Hash, state xcom.StateDB, chainID *big.Int) error {
+	if tp.ProposalType != Treasury {
+		return ProposalTypeError
+	}
+
+	if err := verifyBasic(tp, blockHash, state); err != nil {
+		return err
+	}
+
+	treasury := vm.TreasuryPoolAddr
+	if tp.Recipient == (common.Address{}) || tp.Recipient == treasury {
+		return TreasuryRecipientInvalid
+	}
+	if tp.Amount == nil || tp.Amount.Sign() <= 0 {
+		return TreasuryAmountInvalid
+	}
+
+	if len(tp.Plans) > 0 {
+		if len(tp.Plans) > restricting.RestrictTxPlanSize {
+			return TreasuryPlansInvalid
+		}
+		minimumAmount, err := GovernRestrictingMinimumAmount(submitBlock, blockHash)
+		if err != nil {
+			return err
+		}
+		total := new(big.Int)
+		for _, plan := range tp.Plans {
+			if plan.Epoch == 0 || plan.Amount == nil || plan.Amount.Cmp(minimumAmount) < 0 {
+				return TreasuryPlansInvalid
+			}
+			total.Add(total, plan.Amount)
+		}
+		if total.Cmp(tp.Amount) != 0 {
+			return TreasuryPlansInvalid
+		}
+	}
+
+	if state.GetBalance(treasury).Cmp(tp.Amount) < 0 {
+		return TreasuryBalanceNotEnough
+	}
+
+	// the treasury proposal is voted as long as the param proposal
+	endVotingBlock := xutil.CalEndVotingBlock(submitBlock, xutil.EstimateConsensusRoundsForGov(xcom.ParamProposalVote_DurationSeconds()))
+	if endVotingBlock <= submitBlock {
+		log.Error("the end-voting-block is lower than submit-block. Please check configuration")
+		return common.InternalError
+	}
+	tp.EndVotingBlock = endVotingBlock
+
+	log.Debug("verify Treasury Proposal", "PIPID", tp.PIPID, "recipient", tp.Recipient, "amount", tp.Amount, "plans", len(tp.Plans), "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+	return nil
+}
+
+func (tp *TreasuryProposal) String() string {
+	return fmt.Sprintf(`Proposal %x: 
+  Type:               	%x
+  PIPID:			    %s
+  Proposer:            	%x
+  SubmitBlock:        	%d
+  EndVotingBlock:   	%d
+  Recipient:   			%s
+  Amount:   			%s
+  Plans:   				%v`,
+		tp.ProposalID, tp.ProposalType, tp.PIPID, tp.Proposer, tp.SubmitBlock, tp.EndVotingBlock, tp.Recipient.String(), tp.Amount, tp.Plans)
+}
+
 func verifyBasic(p Proposal, blockHash common.Hash, state xcom.StateDB) error {
 	log.Debug("verify proposal basic parameters", "proposalID", p.GetProposalID(), "proposer", p.GetProposer(), "pipID", p.GetPIPID(), "endVotingBlock", p.GetEndVotingBlock(), "submitBlock", p.GetSubmitBlock())
 
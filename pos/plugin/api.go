@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/snapshotdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/reward"
 )
 
 // Provides an API interface to obtain data related to the economic model
@@ -23,3 +25,27 @@ func (p *PublicDPOSAPI) GetWaitSlashingNodeList() string {
 	}
 	return fmt.Sprintf("%+v", list)
 }
+
+// PublicPPOSAPI provides an API to query the economics of the PPOS
+type PublicPPOSAPI struct {
+	snapshotDB snapshotdb.DB
+}
+
+func NewPublicPPOSAPI() *PublicPPOSAPI {
+	return &PublicPPOSAPI{snapshotdb.Instance()}
+}
+
+// GetEpochEconomics returns the issuance, the rewards and the slashed amounts of the epoch
+func (p *PublicPPOSAPI) GetEpochEconomics(epoch uint64) (*reward.EpochEconomicsPresenter, error) {
+	ledgerByte, err := p.snapshotDB.Get(common.ZeroHash, reward.GetEpochEconomicsKey(epoch))
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return nil, reward.ErrEpochEconomicsNotFound
+	} else if nil != err {
+		return nil, err
+	}
+	var ledger reward.EpochEconomics
+	if err := rlp.DecodeBytes(ledgerByte, &ledger); nil != err {
+		return nil, err
+	}
+	return ledger.Presenter(), nil
+}
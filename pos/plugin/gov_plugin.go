@@ -7,9 +7,11 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/snapshotdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
@@ -56,6 +58,10 @@ func (govPlugin *GovPlugin) BeginBlock(blockHash common.Hash, header *types.Head
 			log.Error("accumulates all distinct verifiers for voting proposal failed.", "blockNumber", blockNumber, "err", err)
 			return err
 		}
+		if err := gov.InitStakeWeightedTallyParam(blockHash); err != nil {
+			log.Error("init the stake weighted tally parameter failed.", "blockNumber", blockNumber, "err", err)
+			return err
+		}
 	}
 
 	//check if there's a pre-active version proposal that can be activated
@@ -133,6 +139,12 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 		return nil
 	}
 
+	if isElection {
+		if err := payPendingTreasuries(blockHash, blockNumber, state); err != nil {
+			return err
+		}
+	}
+
 	votingProposalIDs, err := gov.ListVotingProposal(blockHash)
 	if err != nil {
 		return err
@@ -168,11 +180,21 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 				if err != nil {
 					return err
 				}
+			} else if votingProposal.GetProposalType() == gov.Treasury && isElection {
+				_, err := tallyTreasury(votingProposal.(*gov.TreasuryProposal), blockHash, blockNumber, state)
+				if err != nil {
+					return err
+				}
 			} else if votingProposal.GetProposalType() == gov.Param && isEndOfEpoch {
 				_, err := tallyParam(votingProposal.(*gov.ParamProposal), blockHash, blockNumber, state)
 				if err != nil {
 					return err
 				}
+			} else if votingProposal.GetProposalType() == gov.ParamBatch && isEndOfEpoch {
+				_, err := tallyParamBatch(votingProposal.(*gov.ParamBatchProposal), blockHash, blockNumber, state)
+				if err != nil {
+					return err
+				}
 			} else {
 				log.Error("invalid proposal type", "type", votingProposal.GetProposalType())
 				return gov.ProposalTypeError
@@ -320,7 +342,7 @@ func tallyCancel(cp *gov.CancelProposal, blockHash common.Hash, blockNumber uint
 	} else if pass {
 		if proposal, err := gov.GetExistProposal(cp.TobeCanceled, state); err != nil {
 			return false, err
-		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param {
+		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param && proposal.GetProposalType() != gov.ParamBatch && proposal.GetProposalType() != gov.Treasury {
 			return false, gov.TobeCanceledProposalTypeError
 		}
 		if votingProposalIDList, err := gov.ListVotingProposalID(blockHash); err != nil {
@@ -395,6 +417,105 @@ func tallyParam(pp *gov.ParamProposal, blockHash common.Hash, blockNumber uint64
 	return true, nil
 }
 
+// tallyParamBatch tallies a param batch proposal, all the changes take effect in the next block if it passes
+func tallyParamBatch(bp *gov.ParamBatchProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+	if pass, err := tally(gov.ParamBatch, bp.ProposalID, bp.PIPID, blockHash, blockNumber, state); err != nil {
+		return false, err
+	} else if pass {
+		for _, change := range bp.Changes {
+			if err := gov.UpdateGovernParamValue(change.Module, change.Name, change.NewValue, blockNumber+1, blockHash); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// tallyTreasury tallies a treasury proposal, the amount is paid from the treasury pool as soon as it passes,
+// and the status becomes Active after paid. If the treasury cannot pay, the status stays at Pass
+// and the payment is retried at the later election blocks.
+func tallyTreasury(tp *gov.TreasuryProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+	if pass, err := tally(gov.Treasury, tp.ProposalID, tp.PIPID, blockHash, blockNumber, state); err != nil {
+		return false, err
+	} else if !pass {
+		return false, nil
+	}
+
+	paid, err := payTreasury(tp, blockHash, blockNumber, state)
+	if err != nil {
+		return false, err
+	}
+	if !paid {
+		if err := gov.AddPendingTreasuryID(blockHash, tp.ProposalID); err != nil {
+			log.Error("add pending treasury proposal failed", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "err", err)
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// payPendingTreasuries retries to pay the passed treasury proposals which could not be paid when they passed
+func payPendingTreasuries(blockHash common.Hash, blockNumber uint64, state xcom.StateDB) error {
+	pendingIDs, err := gov.ListPendingTreasuryID(blockHash)
+	if err != nil {
+		return err
+	}
+	for _, proposalID := range pendingIDs {
+		proposal, err := gov.GetExistProposal(proposalID, state)
+		if err != nil {
+			return err
+		}
+		tp, ok := proposal.(*gov.TreasuryProposal)
+		if !ok {
+			log.Error("the pending proposal is not a treasury proposal", "proposalID", proposalID, "type", proposal.GetProposalType())
+			return gov.ProposalTypeError
+		}
+		paid, err := payTreasury(tp, blockHash, blockNumber, state)
+		if err != nil {
+			return err
+		}
+		if paid {
+			if err := gov.RemovePendingTreasuryID(blockHash, proposalID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// payTreasury pays the passed treasury proposal from the treasury pool and makes it Active,
+// it returns false if the treasury cannot pay for now.
+func payTreasury(tp *gov.TreasuryProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (bool, error) {
+	treasury := vm.TreasuryPoolAddr
+	if state.GetBalance(treasury).Cmp(tp.Amount) < 0 {
+		log.Warn("the treasury proposal is passed, but the balance of the treasury is not enough", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "amount", tp.Amount, "balance", state.GetBalance(treasury))
+		return false, nil
+	}
+	if len(tp.Plans) == 0 {
+		state.SubBalance(treasury, tp.Amount)
+		state.AddBalance(tp.Recipient, tp.Amount)
+	} else if err := RestrictingInstance().AddRestrictingRecord(treasury, tp.Recipient, blockNumber, blockHash, tp.Plans, state, tp.ProposalID); err != nil {
+		if _, ok := err.(*common.BizError); ok {
+			log.Warn("the treasury proposal is passed, but the restricting plans cannot be created", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "err", err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	tallyResult, err := gov.GetTallyResult(tp.ProposalID, state)
+	if err != nil || tallyResult == nil {
+		log.Error("find treasury proposal tally result failed", "blockNumber", blockNumber, "proposalID", tp.ProposalID)
+		return false, err
+	}
+	tallyResult.Status = gov.Active
+	if err := gov.SetTallyResult(*tallyResult, state); err != nil {
+		log.Error("update treasury proposal tally result failed", "blockNumber", blockNumber, "proposalID", tp.ProposalID)
+		return false, err
+	}
+	log.Info("treasury proposal is active", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "recipient", tp.Recipient, "amount", tp.Amount, "plans", len(tp.Plans))
+	return true, nil
+}
+
 func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
 	//log.Debug("proposal tally", "proposalID", proposalID, "blockHash", blockHash, "blockNumber", blockNumber, "proposalID", proposalID)
 
@@ -418,6 +539,28 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 		supportRate = (yeas * gov.RateCoefficient) / (yeas + nays + abstentions)
 	}
 
+	// the rates are calculated by the stakes if the stake weighted tally is enabled
+	var stakeYeas, stakeNays, stakeAbstentions, stakeTotal *big.Int
+	weighted, err := gov.GovernStakeWeightedTally(blockNumber, blockHash)
+	if err != nil {
+		return false, err
+	}
+	if weighted {
+		stakeYeas, stakeNays, stakeAbstentions, stakeTotal, err = tallyStake(proposalID, verifierList, blockHash)
+		if err != nil {
+			log.Error("tally the stakes failed", "proposalID", proposalID, "blockNumber", blockNumber, "blockHash", blockHash, "err", err)
+			return false, err
+		}
+		voteRate, supportRate = 0, 0
+		voted := new(big.Int).Add(stakeYeas, stakeNays)
+		voted.Add(voted, stakeAbstentions)
+		if voted.Sign() > 0 && stakeTotal.Sign() > 0 {
+			rateCoefficient := new(big.Int).SetUint64(gov.RateCoefficient)
+			voteRate = new(big.Int).Div(new(big.Int).Mul(voted, rateCoefficient), stakeTotal).Uint64()
+			supportRate = new(big.Int).Div(new(big.Int).Mul(stakeYeas, rateCoefficient), voted).Uint64()
+		}
+	}
+
 	switch proposalType {
 	case gov.Text:
 		//log.Debug("text proposal", "voteRate", voteRate, "required", xcom.TextProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.TextProposalSupportRate()))
@@ -433,7 +576,7 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 		} else {
 			status = gov.Failed
 		}
-	case gov.Param:
+	case gov.Param, gov.ParamBatch, gov.Treasury:
 		//log.Debug("param proposal", "voteRate", voteRate, "required", xcom.ParamProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.ParamProposalSupportRate()))
 		if voteRate > xcom.ParamProposal_VoteRate() && supportRate >= xcom.ParamProposal_SupportRate() {
 			status = gov.Pass
@@ -448,6 +591,11 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 		Abstentions:   abstentions,
 		AccuVerifiers: verifiersCnt,
 		Status:        status,
+
+		StakeYeas:        stakeYeas,
+		StakeNays:        stakeNays,
+		StakeAbstentions: stakeAbstentions,
+		StakeTotal:       stakeTotal,
 	}
 	if err := gov.SetTallyResult(*tallyResult, state); err != nil {
 		log.Error("save tally result failed", "tallyResult", tallyResult)
@@ -476,6 +624,75 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 	return status == gov.Pass, nil
 }
 
+// tallyStake tallies the votes by the stakes of the accumulated verifiers.
+// The vote of a verifier carries its staking and all the delegations to it,
+// except the delegations of the delegators who voted by themselves,
+// which are counted by the amounts taken at the time of their votes.
+func tallyStake(proposalID common.Hash, verifierList []discover.NodeID, blockHash common.Hash) (yeas, nays, abstentions, total *big.Int, err error) {
+	yeas, nays, abstentions, total = new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	count := func(option gov.VoteOption, amount *big.Int) {
+		switch option {
+		case gov.Yes:
+			yeas.Add(yeas, amount)
+		case gov.No:
+			nays.Add(nays, amount)
+		case gov.Abstention:
+			abstentions.Add(abstentions, amount)
+		}
+	}
+
+	// the shares of the verifiers not overridden by the delegators
+	shares := make(map[discover.NodeID]*big.Int, len(verifierList))
+	for _, nodeID := range verifierList {
+		addr, err := xutil.NodeId2Addr(nodeID)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		can, err := stk.GetCandidateInfo(blockHash, addr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return nil, nil, nil, nil, err
+		}
+		// the verifier has withdrawn or been slashed, its stakes are not counted
+		if can == nil || can.IsInvalid() {
+			continue
+		}
+		total.Add(total, can.Shares)
+		remain := new(big.Int).Set(can.Shares)
+		shares[nodeID] = remain
+
+		stake, err := gov.GetDelegatorVoteStake(proposalID, nodeID, can.StakingBlockNum, blockHash)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if stake == nil {
+			continue
+		}
+		// the delegations may be withdrawn after the votes, scale the votes down to the shares
+		voted := new(big.Int).Add(stake.Yeas, stake.Nays)
+		voted.Add(voted, stake.Abstentions)
+		amounts := []*big.Int{stake.Yeas, stake.Nays, stake.Abstentions}
+		for i, option := range []gov.VoteOption{gov.Yes, gov.No, gov.Abstention} {
+			amount := amounts[i]
+			if voted.Cmp(can.Shares) > 0 {
+				amount = new(big.Int).Div(new(big.Int).Mul(amount, can.Shares), voted)
+			}
+			count(option, amount)
+			remain.Sub(remain, amount)
+		}
+	}
+
+	voteList, err := gov.ListVoteValue(proposalID, blockHash)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	for _, vote := range voteList {
+		if remain, ok := shares[vote.VoteNodeID]; ok {
+			count(vote.VoteOption, remain)
+		}
+	}
+	return yeas, nays, abstentions, total, nil
+}
+
 func Decimal(value float64) int {
 	return int(math.Floor(value * 1000))
 }
@@ -2,10 +2,12 @@ package plugin
 
 import (
 	"encoding/hex"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/node"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
@@ -17,11 +19,13 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/restricting"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xcom"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/xutil"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
 	//	"github.com/PhoenixGlobal/Phoenix-Chain-Core/core/state"
 	//	"github.com/PhoenixGlobal/Phoenix-Chain-Core/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/pos/gov"
@@ -621,6 +625,119 @@ func TestGovPlugin_SubmitCancel_noVersionProposal(t *testing.T) {
 	}
 }
 
+func buildParamBatchProposal(t *testing.T, pid common.Hash) *gov.ParamBatchProposal {
+	gov.RegisterGovernParamVerifiers()
+	duration, err := gov.GovernUnStakeFreezeDuration(lastBlockNumber, lastBlockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the new maxEvidenceAge is valid only with the new unStakeFreezeDuration
+	return &gov.ParamBatchProposal{
+		ProposalID:   pid,
+		ProposalType: gov.ParamBatch,
+		PIPID:        "paramBatchPIPID",
+		SubmitBlock:  1,
+		Proposer:     nodeIdArr[0],
+		Changes: []gov.ParamChange{
+			{Module: gov.ModuleStaking, Name: gov.KeyUnStakeFreezeDuration, NewValue: strconv.FormatUint(duration+1, 10)},
+			{Module: gov.ModuleSlashing, Name: gov.KeyMaxEvidenceAge, NewValue: strconv.FormatUint(duration, 10)},
+		},
+	}
+}
+
+func TestGovPlugin_SubmitParamBatch(t *testing.T) {
+	defer setup(t)()
+
+	bp := buildParamBatchProposal(t, txHashArr[0])
+	state := stateDB.(*mock.MockStateDB)
+	state.Prepare(txHashArr[0], lastBlockHash, 0)
+
+	if err := gov.Submit(sender, bp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID); err != nil {
+		t.Fatalf("submit param batch proposal err: %s", err)
+	}
+
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+	buildBlockNoCommit(2)
+
+	p, err := gov.GetProposal(txHashArr[0], stateDB)
+	if err != nil {
+		t.Fatal("Get the submitted param batch proposal error:", err)
+	}
+	assert.Equal(t, gov.ParamBatch, p.GetProposalType())
+	assert.Equal(t, bp.Changes, p.(*gov.ParamBatchProposal).Changes)
+}
+
+func TestGovPlugin_SubmitParamBatch_duplicated(t *testing.T) {
+	defer setup(t)()
+
+	bp := buildParamBatchProposal(t, txHashArr[0])
+	bp.Changes[1] = bp.Changes[0]
+	state := stateDB.(*mock.MockStateDB)
+	state.Prepare(txHashArr[0], lastBlockHash, 0)
+
+	err := gov.Submit(sender, bp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID)
+	assert.Equal(t, gov.ParamBatchChangesDuplicated, err)
+}
+
+func TestGovPlugin_SubmitParamBatch_invalidAlone(t *testing.T) {
+	defer setup(t)()
+
+	bp := buildParamBatchProposal(t, txHashArr[0])
+	bp.Changes = bp.Changes[1:]
+	state := stateDB.(*mock.MockStateDB)
+	state.Prepare(txHashArr[0], lastBlockHash, 0)
+
+	if err := gov.Submit(sender, bp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID); err == nil {
+		t.Fatal("didn't detect the invalid maxEvidenceAge")
+	}
+}
+
+func submitTreasury(t *testing.T, pid common.Hash, recipient common.Address, amount *big.Int) {
+	stateDB.AddBalance(vm.TreasuryPoolAddr, amount)
+	proposeTreasury(t, pid, recipient, amount)
+}
+
+// proposeTreasury submits the treasury proposal paid by the current treasury pool
+func proposeTreasury(t *testing.T, pid common.Hash, recipient common.Address, amount *big.Int) {
+	tp := &gov.TreasuryProposal{
+		ProposalID:   pid,
+		ProposalType: gov.Treasury,
+		PIPID:        "treasuryPIPID",
+		SubmitBlock:  1,
+		Proposer:     nodeIdArr[0],
+		Recipient:    recipient,
+		Amount:       amount,
+	}
+	if err := gov.Submit(sender, tp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID); err != nil {
+		t.Fatalf("submit treasury proposal err: %s", err)
+	}
+}
+
+func TestGovPlugin_SubmitTreasury_invalidPlans(t *testing.T) {
+	defer setup(t)()
+
+	amount := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	stateDB.AddBalance(vm.TreasuryPoolAddr, amount)
+	tp := &gov.TreasuryProposal{
+		ProposalID:   txHashArr[0],
+		ProposalType: gov.Treasury,
+		PIPID:        "treasuryPIPID",
+		SubmitBlock:  1,
+		Proposer:     nodeIdArr[0],
+		Recipient:    addrArr[0],
+		Amount:       amount,
+		Plans:        []restricting.RestrictingPlan{{Epoch: 1, Amount: new(big.Int).Sub(amount, big.NewInt(1))}},
+	}
+	err := gov.Submit(sender, tp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID)
+	assert.Equal(t, gov.TreasuryPlansInvalid, err)
+
+	tp.Plans = nil
+	tp.Amount = new(big.Int).Add(stateDB.GetBalance(vm.TreasuryPoolAddr), big.NewInt(1))
+	err = gov.Submit(sender, tp, lastBlockHash, lastBlockNumber, stk, stateDB, chainID)
+	assert.Equal(t, gov.TreasuryBalanceNotEnough, err)
+}
+
 func TestGovPlugin_VoteSuccess(t *testing.T) {
 	defer setup(t)()
 	submitVersion(t, txHashArr[0])
@@ -1000,6 +1117,204 @@ func TestGovPlugin_textProposalPassed(t *testing.T) {
 	}
 }
 
+func TestGovPlugin_tallyStake(t *testing.T) {
+	defer setup(t)()
+
+	submitText(t, txHashArr[0])
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	buildBlockNoCommit(2)
+
+	// the verifiers vote yes
+	allVote(t, txHashArr[0])
+	voteList, err := gov.ListVoteValue(txHashArr[0], lastBlockHash)
+	if err != nil {
+		t.Fatal("list votes error", err)
+	}
+	verifierList := make([]discover.NodeID, 0, len(voteList))
+	for _, vote := range voteList {
+		verifierList = append(verifierList, vote.VoteNodeID)
+	}
+
+	shares := make([]*big.Int, 3)
+	total := new(big.Int)
+	for i, nodeID := range verifierList {
+		addr, _ := xutil.NodeId2Addr(nodeID)
+		can, err := stk.GetCandidateInfo(lastBlockHash, addr)
+		if err != nil {
+			t.Fatal("find candidate error", err)
+		}
+		if i < len(shares) {
+			shares[i] = can.Shares
+		}
+		total.Add(total, can.Shares)
+	}
+
+	// the delegators override the votes of the verifiers for their delegations:
+	// half of the shares of the first verifier, more than the shares of the second one,
+	// and a delegation to a former staking of the third one which isn't counted
+	half := new(big.Int).Div(shares[0], big.NewInt(2))
+	delegations := []gov.DelegationWeight{
+		{NodeID: verifierList[0], StakingBlockNum: 1, Amount: half},
+		{NodeID: verifierList[1], StakingBlockNum: 1, Amount: new(big.Int).Add(shares[1], big.NewInt(1))},
+		{NodeID: verifierList[2], StakingBlockNum: 0, Amount: shares[2]},
+	}
+	if err := gov.AddDelegatorVote(txHashArr[0], sender, gov.No, delegations, lastBlockHash); err != nil {
+		t.Fatal("add delegator vote error", err)
+	}
+
+	yeas, nays, abstentions, stakeTotal, err := tallyStake(txHashArr[0], verifierList, lastBlockHash)
+	if err != nil {
+		t.Fatal("tally stake error", err)
+	}
+	expectNays := new(big.Int).Add(half, shares[1])
+	assert.Equal(t, total, stakeTotal)
+	assert.Equal(t, expectNays, nays)
+	assert.Equal(t, new(big.Int).Sub(total, expectNays), yeas)
+	assert.Equal(t, 0, abstentions.Sign())
+}
+
+// votesTreasury votes for the treasury proposal by all verifiers and ends the voting
+func votesTreasury(t *testing.T, pid common.Hash) {
+	buildBlockNoCommit(2)
+
+	allVote(t, pid)
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	p, err := gov.GetProposal(pid, stateDB)
+	if err != nil {
+		t.Fatal("find proposal error", "err", err)
+	}
+
+	lastBlockNumber = uint64(xutil.CalcBlocksEachEpoch() - 1)
+	lastHeader = types.Header{
+		Number: big.NewInt(int64(lastBlockNumber)),
+	}
+	lastBlockHash = lastHeader.Hash()
+	sndb.SetCurrent(lastBlockHash, *big.NewInt(int64(lastBlockNumber)), *big.NewInt(int64(lastBlockNumber)))
+
+	build_staking_data_more(uint64(xutil.CalcBlocksEachEpoch()))
+	beginBlock(t)
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	lastBlockNumber = uint64(p.GetEndVotingBlock() - 1)
+	lastHeader = types.Header{
+		Number: big.NewInt(int64(lastBlockNumber)),
+	}
+	lastBlockHash = lastHeader.Hash()
+	sndb.SetCurrent(lastBlockHash, *big.NewInt(int64(lastBlockNumber)), *big.NewInt(int64(lastBlockNumber)))
+
+	build_staking_data_more(p.GetEndVotingBlock())
+
+	endBlock(t)
+
+	sndb.Commit(lastBlockHash)
+}
+
+func TestGovPlugin_treasuryProposalActive(t *testing.T) {
+	defer setup(t)()
+
+	recipient := addrArr[1]
+	amount := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	balance := new(big.Int).Set(stateDB.GetBalance(recipient))
+
+	submitTreasury(t, txHashArr[0], recipient, amount)
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	votesTreasury(t, txHashArr[0])
+
+	result, err := gov.GetTallyResult(txHashArr[0], stateDB)
+	if err != nil {
+		t.Errorf("%s", err)
+	}
+	if result == nil {
+		t.Fatal("cannot find the tally result")
+	}
+	assert.Equal(t, gov.Active, result.Status)
+	assert.Equal(t, new(big.Int).Add(balance, amount), stateDB.GetBalance(recipient))
+}
+
+func TestGovPlugin_treasuryProposalPending(t *testing.T) {
+	defer setup(t)()
+
+	recipient := addrArr[1]
+	amount := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	balance := new(big.Int).Set(stateDB.GetBalance(recipient))
+
+	submitTreasury(t, txHashArr[0], recipient, amount)
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	// the treasury is spent before the proposal passes
+	stateDB.SubBalance(vm.TreasuryPoolAddr, stateDB.GetBalance(vm.TreasuryPoolAddr))
+	votesTreasury(t, txHashArr[0])
+
+	result, err := gov.GetTallyResult(txHashArr[0], stateDB)
+	if err != nil || result == nil {
+		t.Fatal("cannot find the tally result", err)
+	}
+	assert.Equal(t, gov.Pass, result.Status)
+	pendingIDs, err := gov.ListPendingTreasuryID(lastBlockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []common.Hash{txHashArr[0]}, pendingIDs)
+
+	// the payment is retried at the next election block
+	stateDB.AddBalance(vm.TreasuryPoolAddr, amount)
+	buildBlockNoCommit(int(lastBlockNumber + xutil.ConsensusSize()))
+	endBlock(t)
+
+	result, err = gov.GetTallyResult(txHashArr[0], stateDB)
+	if err != nil || result == nil {
+		t.Fatal("cannot find the tally result", err)
+	}
+	assert.Equal(t, gov.Active, result.Status)
+	assert.Equal(t, new(big.Int).Add(balance, amount), stateDB.GetBalance(recipient))
+	pendingIDs, err = gov.ListPendingTreasuryID(lastBlockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, pendingIDs, 0)
+}
+
+func TestGovPlugin_treasuryFundedByIssuance(t *testing.T) {
+	defer setup(t)()
+
+	// since version 1.2.0 the developer foundation issuance is credited to the treasury pool
+	if err := gov.AddActiveVersion(configs.FORKVERSION_1_2_0, 0, stateDB); err != nil {
+		t.Fatal(err)
+	}
+	SetYearEndCumulativeIssue(stateDB, 0, new(big.Int).Mul(big.NewInt(1000000), big.NewInt(1e18)))
+	cdfBalance := new(big.Int).Set(stateDB.GetBalance(xcom.CDFAccount()))
+	if err := RewardMgrInstance().increaseIssuance(1, 0, stateDB, lastBlockNumber, lastBlockHash); err != nil {
+		t.Fatal(err)
+	}
+	amount := new(big.Int).Set(stateDB.GetBalance(vm.TreasuryPoolAddr))
+	assert.True(t, amount.Sign() > 0)
+	assert.Equal(t, cdfBalance, stateDB.GetBalance(xcom.CDFAccount()))
+
+	recipient := addrArr[1]
+	balance := new(big.Int).Set(stateDB.GetBalance(recipient))
+	proposeTreasury(t, txHashArr[0], recipient, amount)
+	sndb.Commit(lastBlockHash)
+	sndb.Compaction()
+
+	votesTreasury(t, txHashArr[0])
+
+	result, err := gov.GetTallyResult(txHashArr[0], stateDB)
+	if err != nil || result == nil {
+		t.Fatal("cannot find the tally result", err)
+	}
+	assert.Equal(t, gov.Active, result.Status)
+	assert.Equal(t, new(big.Int).Add(balance, amount), stateDB.GetBalance(recipient))
+	assert.Equal(t, 0, stateDB.GetBalance(vm.TreasuryPoolAddr).Sign())
+}
+
 func TestGovPlugin_textProposalFailed(t *testing.T) {
 
 	defer setup(t)()
@@ -218,6 +218,167 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 
 	rp.log.Debug("Call AddRestrictingRecord begin", "sender", from, "account", account, "plans", plans)
 
+	return rp.addRestrictingRecord(from, account, blockNum, blockHash, plans, state, txhash)
+}
+
+// AddVestingRecord locks the vesting plans as AddRestrictingRecord, but only the first release of every plan
+// is recorded, the next one is recorded by releaseRestricting when it is released. So a plan costs the same
+// no matter how many periods it has. The revoker of the revocable vesting plans must be the sender.
+func (rp *RestrictingPlugin) AddVestingRecord(from, account common.Address, blockNum uint64, blockHash common.Hash, vestingPlans []restricting.VestingPlan, state xcom.StateDB, txhash common.Hash) error {
+
+	rp.log.Debug("Call AddVestingRecord begin", "sender", from, "account", account, "plans", vestingPlans)
+
+	if len(vestingPlans) == 0 || len(vestingPlans) > restricting.RestrictTxPlanSize {
+		rp.log.Error(fmt.Sprintf("Failed to AddVestingRecord: the number of vesting plan %d can't be zero or more than %d",
+			len(vestingPlans), restricting.RestrictTxPlanSize))
+		return restricting.ErrCountRestrictPlansInvalid
+	}
+	minimumAmount, err := gov.GovernRestrictingMinimumAmount(blockNum, blockHash)
+	if err != nil {
+		return err
+	}
+	schedules, err := rp.getVestingSchedules(state, account)
+	if err != nil {
+		return err
+	}
+	stored := len(schedules)
+
+	// the epochs of the releases are the same as mergeAmount
+	latestEpoch := xutil.CalculateEpoch(blockNum)
+	totalAmount := new(big.Int)
+	totalPlans := make(map[uint64]*big.Int, len(vestingPlans))
+	revocable := false
+	for _, vestingPlan := range vestingPlans {
+		if err := vestingPlan.Validate(); err != nil {
+			rp.log.Error("Failed to AddVestingRecord: the vesting plan is invalid", "plan", vestingPlan, "err", err)
+			return err
+		}
+		if (vestingPlan.CliffAmount.Sign() > 0 && vestingPlan.CliffAmount.Cmp(minimumAmount) < 0) ||
+			(vestingPlan.Periods > 0 && vestingPlan.Each().Cmp(minimumAmount) < 0) {
+			rp.log.Error("Failed to AddVestingRecord: the released amount must be more than minimumAmount", "plan", vestingPlan, "mini", minimumAmount)
+			return restricting.ErrCreatePlanAmountLessThanMiniAmount
+		}
+		if vestingPlan.Revocable() {
+			if vestingPlan.Revoker != from {
+				rp.log.Error("Failed to AddVestingRecord: the revoker is not the sender", "sender", from, "revoker", vestingPlan.Revoker)
+				return restricting.ErrRevokerInvalid
+			}
+			revocable = true
+		}
+
+		schedule := vestingPlan.Schedule()
+		schedule.Epoch += latestEpoch - 1
+		totalAmount.Add(totalAmount, vestingPlan.Amount)
+		if value, ok := totalPlans[schedule.Epoch]; ok {
+			value.Add(value, schedule.Pending)
+		} else {
+			totalPlans[schedule.Epoch] = new(big.Int).Set(schedule.Pending)
+		}
+		if schedule.Remain.Sign() > 0 || schedule.Revocable() {
+			schedules = append(schedules, schedule)
+		}
+	}
+
+	if revocable {
+		if revokers := vestingRevokers(schedules[:stored]); !containsRevoker(revokers, from) && len(revokers) >= restricting.RestrictRevokerSize {
+			rp.log.Error(fmt.Sprintf("Failed to AddVestingRecord: the number of revoker can't be more than %d",
+				restricting.RestrictRevokerSize), "account", account)
+			return restricting.ErrCountRevokersInvalid
+		}
+	}
+
+	if err := rp.lockRestrictingPlans(from, account, totalAmount, totalPlans, state, txhash); err != nil {
+		return err
+	}
+	if txhash == common.ZeroHash || len(schedules) == stored {
+		return nil
+	}
+	rp.storeVestingSchedules(state, account, schedules)
+	return nil
+}
+
+// RevokeRestrictingPlan transfers the unreleased amount of the revocable plans of the account back to the revoker.
+// The amount advanced to staking can not be revoked until it is returned, the plans released later are revoked first.
+func (rp *RestrictingPlugin) RevokeRestrictingPlan(revoker, account common.Address, state xcom.StateDB) (*big.Int, error) {
+
+	rp.log.Debug("Call RevokeRestrictingPlan begin", "revoker", revoker, "account", account)
+
+	schedules, err := rp.getVestingSchedules(state, account)
+	if err != nil {
+		return nil, err
+	}
+	if !containsRevoker(vestingRevokers(schedules), revoker) {
+		return nil, restricting.ErrRevocablePlanNotFound
+	}
+	restrictingKey, restrictInfo, bizErr := rp.mustGetRestrictingInfoByDecode(state, account)
+	if bizErr != nil {
+		return nil, bizErr
+	}
+
+	available := new(big.Int).Sub(restrictInfo.CachePlanAmount, restrictInfo.AdvanceAmount)
+	if restrictInfo.NeedRelease.Cmp(common.Big0) > 0 || available.Cmp(common.Big0) <= 0 {
+		return nil, restricting.ErrRevokeAmountEmpty
+	}
+
+	revoked := new(big.Int)
+	remaining := make([]restricting.VestingSchedule, 0, len(schedules))
+	for i := len(schedules) - 1; i >= 0; i-- {
+		schedule := schedules[i]
+		if schedule.Revoker != revoker {
+			remaining = append([]restricting.VestingSchedule{schedule}, remaining...)
+			continue
+		}
+
+		// the linear releases not recorded yet are revoked before the recorded one
+		revoke := new(big.Int).Sub(available, revoked)
+		if revoke.Cmp(schedule.Remain) > 0 {
+			revoke.Set(schedule.Remain)
+		}
+		schedule.Remain = new(big.Int).Sub(schedule.Remain, revoke)
+		revoked.Add(revoked, revoke)
+
+		releaseAmountKey, releaseAmount := rp.getReleaseAmount(state, schedule.Epoch, account)
+		revoke = new(big.Int).Sub(available, revoked)
+		if revoke.Cmp(schedule.Pending) > 0 {
+			revoke.Set(schedule.Pending)
+		}
+		if revoke.Cmp(releaseAmount) > 0 {
+			revoke.Set(releaseAmount)
+		}
+		if revoke.Sign() > 0 {
+			releaseAmount.Sub(releaseAmount, revoke)
+			if releaseAmount.Sign() == 0 {
+				state.SetState(vm.RestrictingContractAddr, releaseAmountKey, []byte{})
+				restrictInfo.RemoveEpoch(schedule.Epoch)
+			} else {
+				rp.storeAmount2ReleaseAmount(state, schedule.Epoch, account, releaseAmount)
+			}
+			schedule.Pending = new(big.Int).Sub(schedule.Pending, revoke)
+			revoked.Add(revoked, revoke)
+		}
+		if schedule.Pending.Sign() > 0 || schedule.Remain.Sign() > 0 {
+			remaining = append([]restricting.VestingSchedule{schedule}, remaining...)
+		}
+	}
+	if revoked.Sign() == 0 {
+		return nil, restricting.ErrRevokeAmountEmpty
+	}
+
+	rp.storeVestingSchedules(state, account, remaining)
+	restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, revoked)
+	rp.transferAmount(state, vm.RestrictingContractAddr, revoker, revoked)
+	if restrictInfo.AdvanceAmount.Cmp(common.Big0) == 0 &&
+		len(restrictInfo.ReleaseList) == 0 && restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
+		state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+	} else {
+		rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
+	}
+
+	rp.log.Debug("Call RevokeRestrictingPlan finished", "revoker", revoker, "account", account, "revoked", revoked, "remaining", remaining, "info", restrictInfo)
+	return revoked, nil
+}
+
+func (rp *RestrictingPlugin) addRestrictingRecord(from, account common.Address, blockNum uint64, blockHash common.Hash, plans []restricting.RestrictingPlan, state xcom.StateDB, txhash common.Hash) error {
 	if len(plans) == 0 || len(plans) > restricting.RestrictTxPlanSize {
 		rp.log.Error(fmt.Sprintf("Failed to AddRestrictingRecord: the number of restricting plan %d can't be zero or more than %d",
 			len(plans), restricting.RestrictTxPlanSize))
@@ -228,6 +389,12 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 	if err != nil {
 		return err
 	}
+	return rp.lockRestrictingPlans(from, account, totalAmount, totalPlans, state, txhash)
+}
+
+// lockRestrictingPlans transfers the total amount of the plans from the sender to the restricting contract
+// and records the amounts released at the epochs for the account
+func (rp *RestrictingPlugin) lockRestrictingPlans(from, account common.Address, totalAmount *big.Int, totalPlans map[uint64]*big.Int, state xcom.StateDB, txhash common.Hash) error {
 	// pre-check
 	if state.GetBalance(from).Cmp(totalAmount) < 0 {
 		rp.log.Error("Failed to AddRestrictingRecord: balance of the sender is not enough",
@@ -257,7 +424,7 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 		restrictInfo.ReleaseList = epochArr
 	} else {
 		rp.log.Trace("restricting record exist", "account", account.String())
-		if err = rlp.DecodeBytes(restrictInfoByte, &restrictInfo); err != nil {
+		if err := rlp.DecodeBytes(restrictInfoByte, &restrictInfo); err != nil {
 			rp.log.Error("failed to rlp decode the restricting account", "err", err.Error())
 			return common.InternalError.Wrap(err.Error())
 		}
@@ -275,31 +442,40 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 			restrictInfo.CachePlanAmount.Add(restrictInfo.CachePlanAmount, totalAmount)
 		}
 		for epoch, releaseAmount := range totalPlans {
-			// step1: get restricting amount at target epoch
-			_, currentAmount := rp.getReleaseAmount(state, epoch, account)
-			if currentAmount.Cmp(common.Big0) == 0 {
-				rp.log.Trace("release record not exist on curr epoch ", "account", account, "epoch", epoch)
-				rp.initEpochInfo(state, epoch, account, releaseAmount)
-				restrictInfo.ReleaseList = append(restrictInfo.ReleaseList, epoch)
-			} else {
-				rp.log.Trace("release record exist at curr epoch", "account", account, "epoch", epoch)
-				currentAmount.Add(currentAmount, releaseAmount)
-				// step4: save restricting amount at target epoch
-				rp.storeAmount2ReleaseAmount(state, epoch, account, currentAmount)
-			}
+			rp.addReleaseAmount(state, &restrictInfo, epoch, account, releaseAmount)
 		}
 	}
 
 	// sort release list
-	sort.Slice(restrictInfo.ReleaseList, func(i, j int) bool {
-		return restrictInfo.ReleaseList[i] < restrictInfo.ReleaseList[j]
-	})
+	sortReleaseList(&restrictInfo)
 	rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
 	rp.log.Debug("Call AddRestrictingRecord finished", "account", account, "restrictingInfo", restrictInfo)
 
 	return nil
 }
 
+// addReleaseAmount adds the amount to the release of the account at the epoch, the release list is not sorted
+func (rp *RestrictingPlugin) addReleaseAmount(state xcom.StateDB, restrictInfo *restricting.RestrictingInfo, epoch uint64, account common.Address, releaseAmount *big.Int) {
+	// step1: get restricting amount at target epoch
+	_, currentAmount := rp.getReleaseAmount(state, epoch, account)
+	if currentAmount.Cmp(common.Big0) == 0 {
+		rp.log.Trace("release record not exist on curr epoch ", "account", account, "epoch", epoch)
+		rp.initEpochInfo(state, epoch, account, releaseAmount)
+		restrictInfo.ReleaseList = append(restrictInfo.ReleaseList, epoch)
+	} else {
+		rp.log.Trace("release record exist at curr epoch", "account", account, "epoch", epoch)
+		currentAmount.Add(currentAmount, releaseAmount)
+		// step4: save restricting amount at target epoch
+		rp.storeAmount2ReleaseAmount(state, epoch, account, currentAmount)
+	}
+}
+
+func sortReleaseList(restrictInfo *restricting.RestrictingInfo) {
+	sort.Slice(restrictInfo.ReleaseList, func(i, j int) bool {
+		return restrictInfo.ReleaseList[i] < restrictInfo.ReleaseList[j]
+	})
+}
+
 // AdvanceLockedFunds transfer the money from the restricting contract account to the staking contract account
 func (rp *RestrictingPlugin) AdvanceLockedFunds(account common.Address, amount *big.Int, state xcom.StateDB) error {
 
@@ -532,6 +708,85 @@ func (rp *RestrictingPlugin) storeAmount2ReleaseAmount(state xcom.StateDB, epoch
 	state.SetState(vm.RestrictingContractAddr, releaseAmountKey, amount.Bytes())
 }
 
+func (rp *RestrictingPlugin) getVestingSchedules(state xcom.StateDB, account common.Address) ([]restricting.VestingSchedule, error) {
+	bSchedules := state.GetState(vm.RestrictingContractAddr, restricting.GetVestingKey(account))
+	var schedules []restricting.VestingSchedule
+	if len(bSchedules) > 0 {
+		if err := rlp.DecodeBytes(bSchedules, &schedules); err != nil {
+			rp.log.Error("Failed to rlp decode the vesting schedules", "account", account, "error", err)
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
+
+func (rp *RestrictingPlugin) storeVestingSchedules(state xcom.StateDB, account common.Address, schedules []restricting.VestingSchedule) {
+	if len(schedules) == 0 {
+		state.SetState(vm.RestrictingContractAddr, restricting.GetVestingKey(account), []byte{})
+		return
+	}
+	state.SetState(vm.RestrictingContractAddr, restricting.GetVestingKey(account), common.MustRlpEncode(schedules))
+}
+
+// releaseVestingSchedules records the next releases of the vesting schedules released at the epoch
+func (rp *RestrictingPlugin) releaseVestingSchedules(state xcom.StateDB, account common.Address, epoch uint64, restrictInfo *restricting.RestrictingInfo) error {
+	schedules, err := rp.getVestingSchedules(state, account)
+	if err != nil || len(schedules) == 0 {
+		return err
+	}
+	released := false
+	remaining := schedules[:0]
+	for _, schedule := range schedules {
+		if schedule.Epoch != epoch {
+			remaining = append(remaining, schedule)
+			continue
+		}
+		released = true
+		if schedule.Next() {
+			rp.addReleaseAmount(state, restrictInfo, schedule.Epoch, account, schedule.Pending)
+			remaining = append(remaining, schedule)
+		}
+	}
+	if released {
+		sortReleaseList(restrictInfo)
+		rp.storeVestingSchedules(state, account, remaining)
+	}
+	return nil
+}
+
+// vestingRevokers returns the revokers of the revocable schedules
+func vestingRevokers(schedules []restricting.VestingSchedule) []common.Address {
+	var revokers []common.Address
+	for _, schedule := range schedules {
+		if schedule.Revocable() && !containsRevoker(revokers, schedule.Revoker) {
+			revokers = append(revokers, schedule.Revoker)
+		}
+	}
+	return revokers
+}
+
+func containsRevoker(revokers []common.Address, revoker common.Address) bool {
+	for _, target := range revokers {
+		if target == revoker {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeReleasePlan adds the amount to the plan of the epoch, the plans are kept sorted by the epoch
+func mergeReleasePlan(plans []restricting.RestrictingPlan, epoch uint64, amount *big.Int) []restricting.RestrictingPlan {
+	i := sort.Search(len(plans), func(i int) bool { return plans[i].Epoch >= epoch })
+	if i < len(plans) && plans[i].Epoch == epoch {
+		plans[i].Amount = new(big.Int).Add(plans[i].Amount, amount)
+		return plans
+	}
+	plans = append(plans, restricting.RestrictingPlan{})
+	copy(plans[i+1:], plans[i:])
+	plans[i] = restricting.RestrictingPlan{Epoch: epoch, Amount: new(big.Int).Set(amount)}
+	return plans
+}
+
 // releaseRestricting will release restricting plans on target epoch
 func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB) error {
 
@@ -585,10 +840,15 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 		// info.ReleaseList = info.ReleaseList[1:]
 		restrictInfo.RemoveEpoch(epoch)
 
+		if err := rp.releaseVestingSchedules(state, account, epoch, &restrictInfo); err != nil {
+			return err
+		}
+
 		if restrictInfo.CachePlanAmount.Cmp(common.Big0) == 0 {
 			if restrictInfo.NeedRelease.Cmp(common.Big0) == 0 || len(restrictInfo.ReleaseList) == 0 {
 				//if all is release,remove info
 				state.SetState(vm.RestrictingContractAddr, restrictingKey, []byte{})
+				rp.storeVestingSchedules(state, account, nil)
 			} else {
 				rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
 			}
@@ -617,11 +877,32 @@ func (rp *RestrictingPlugin) getRestrictingInfoToReturn(account common.Address,
 		result restricting.Result
 	)
 
+	schedules, decodeErr := rp.getVestingSchedules(state, account)
+	if decodeErr != nil {
+		return nil, common.InternalError.Wrap(decodeErr.Error())
+	}
+	var releases []restricting.RestrictingPlan
 	for i := 0; i < len(info.ReleaseList); i++ {
 		epoch := info.ReleaseList[i]
 		_, bAmount := rp.getReleaseAmount(state, epoch, account)
-		plan.Height = GetBlockNumberByEpoch(epoch)
-		plan.Amount = (*hexutil.Big)(bAmount)
+		releases = mergeReleasePlan(releases, epoch, bAmount)
+	}
+	revocable := make(map[common.Address][]restricting.RestrictingPlan)
+	for _, schedule := range schedules {
+		scheduled := schedule.Releases()
+		// the first release is recorded in the release list already
+		for _, release := range scheduled[1:] {
+			releases = mergeReleasePlan(releases, release.Epoch, release.Amount)
+		}
+		if schedule.Revocable() {
+			for _, release := range scheduled {
+				revocable[schedule.Revoker] = mergeReleasePlan(revocable[schedule.Revoker], release.Epoch, release.Amount)
+			}
+		}
+	}
+	for _, release := range releases {
+		plan.Height = GetBlockNumberByEpoch(release.Epoch)
+		plan.Amount = (*hexutil.Big)(release.Amount)
 		plans = append(plans, plan)
 	}
 
@@ -629,6 +910,16 @@ func (rp *RestrictingPlugin) getRestrictingInfoToReturn(account common.Address,
 	result.Debt = (*hexutil.Big)(info.NeedRelease)
 	result.Entry = plans
 	result.Pledge = (*hexutil.Big)(info.AdvanceAmount)
+	for _, revoker := range vestingRevokers(schedules) {
+		revocableInfo := restricting.RevocableInfo{Revoker: revoker}
+		for _, release := range revocable[revoker] {
+			revocableInfo.Entry = append(revocableInfo.Entry, restricting.ReleaseAmountInfo{
+				Height: GetBlockNumberByEpoch(release.Epoch),
+				Amount: (*hexutil.Big)(release.Amount),
+			})
+		}
+		result.Revocable = append(result.Revocable, revocableInfo)
+	}
 	rp.log.Debug("Call releaseRestricting: query restricting result", "account", account, "result", result)
 	return &result, nil
 }
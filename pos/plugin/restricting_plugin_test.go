@@ -691,3 +691,145 @@ func TestRestrictingGetRestrictingInfo(t *testing.T) {
 	assert.Equal(t, res.Balance.ToInt(), big.NewInt(6e18))
 
 }
+
+func TestRestrictingPlugin_VestingPlan(t *testing.T) {
+	plugin := NewTestRestrictingPlugin()
+
+	sdb := snapshotdb.Instance()
+	defer sdb.Clear()
+	key := gov.KeyParamValue(gov.ModuleRestricting, gov.KeyRestrictingMinimumAmount)
+	value := common.MustRlpEncode(&gov.ParamValue{Value: new(big.Int).SetInt64(0).String()})
+	if err := sdb.PutBaseDB(key, value); nil != err {
+		t.Error(err)
+		return
+	}
+
+	// the revoker must be the sender and the periods are limited
+	if err := plugin.AddVestingRecord(plugin.from, plugin.to, 1, common.ZeroHash, []restricting.VestingPlan{{
+		Cliff: 1, CliffAmount: big.NewInt(1e18), Interval: 1, Periods: 3, Amount: big.NewInt(4e18), Revoker: plugin.to,
+	}}, plugin.mockDB, RestrictingTxHash); err != restricting.ErrRevokerInvalid {
+		t.Errorf("expect ErrRevokerInvalid, got %v", err)
+	}
+	if err := plugin.AddVestingRecord(plugin.from, plugin.to, 1, common.ZeroHash, []restricting.VestingPlan{{
+		Cliff: 1, CliffAmount: big.NewInt(1e18), Interval: 1, Periods: restricting.RestrictVestingPeriods + 1, Amount: big.NewInt(4e18),
+	}}, plugin.mockDB, RestrictingTxHash); err != restricting.ErrVestingPlanInvalid {
+		t.Errorf("expect ErrVestingPlanInvalid, got %v", err)
+	}
+
+	// release 1e18 at the first epoch, then 1e18 every epoch for 3 epochs
+	plans := []restricting.VestingPlan{{
+		Cliff:       1,
+		CliffAmount: big.NewInt(1e18),
+		Interval:    1,
+		Periods:     3,
+		Amount:      big.NewInt(4e18),
+		Revoker:     plugin.from,
+	}}
+	if err := plugin.AddVestingRecord(plugin.from, plugin.to, xutil.CalcBlocksEachEpoch()-10, common.ZeroHash, plans, plugin.mockDB, RestrictingTxHash); err != nil {
+		t.Fatal(err)
+	}
+	_, info, bizErr := plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, plugin.to)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, []uint64{1}, info.ReleaseList)
+	assert.Equal(t, big.NewInt(4e18), info.CachePlanAmount)
+	result, bizErr := plugin.GetRestrictingInfo(plugin.to, plugin.mockDB)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, 4, len(result.Entry))
+
+	if err := plugin.releaseRestricting(1, plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(1e18), plugin.mockDB.GetBalance(plugin.to))
+	_, info, bizErr = plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, plugin.to)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, []uint64{2}, info.ReleaseList)
+
+	// the staked amount can not be revoked, the later plans are revoked first
+	if err := plugin.AdvanceLockedFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plugin.RevokeRestrictingPlan(plugin.to, plugin.to, plugin.mockDB); err != restricting.ErrRevocablePlanNotFound {
+		t.Errorf("expect ErrRevocablePlanNotFound, got %v", err)
+	}
+	revoked, err := plugin.RevokeRestrictingPlan(plugin.from, plugin.to, plugin.mockDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(2e18), revoked)
+	assert.Equal(t, big.NewInt(7e18), plugin.mockDB.GetBalance(plugin.from))
+	schedules, err := plugin.getVestingSchedules(plugin.mockDB, plugin.to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, []restricting.RestrictingPlan{{Epoch: 2, Amount: big.NewInt(1e18)}}, schedules[0].Releases())
+
+	result, bizErr = plugin.GetRestrictingInfo(plugin.to, plugin.mockDB)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, 1, len(result.Entry))
+	assert.Equal(t, big.NewInt(1e18), result.Balance.ToInt())
+	assert.Equal(t, 1, len(result.Revocable))
+}
+
+// the monthly vest of 4 years is stored as one schedule and released one period after another
+func TestRestrictingPlugin_VestingPlanPeriods(t *testing.T) {
+	plugin := NewTestRestrictingPlugin()
+
+	sdb := snapshotdb.Instance()
+	defer sdb.Clear()
+	key := gov.KeyParamValue(gov.ModuleRestricting, gov.KeyRestrictingMinimumAmount)
+	value := common.MustRlpEncode(&gov.ParamValue{Value: new(big.Int).SetInt64(0).String()})
+	if err := sdb.PutBaseDB(key, value); nil != err {
+		t.Error(err)
+		return
+	}
+
+	plans := []restricting.VestingPlan{{
+		Cliff:       1,
+		CliffAmount: big.NewInt(0),
+		Interval:    2,
+		Periods:     48,
+		Amount:      big.NewInt(48e17 + 5),
+	}}
+	if err := plugin.AddVestingRecord(plugin.from, plugin.to, xutil.CalcBlocksEachEpoch()-10, common.ZeroHash, plans, plugin.mockDB, RestrictingTxHash); err != nil {
+		t.Fatal(err)
+	}
+	_, info, bizErr := plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, plugin.to)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, []uint64{3}, info.ReleaseList)
+	result, bizErr := plugin.GetRestrictingInfo(plugin.to, plugin.mockDB)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, 48, len(result.Entry))
+	assert.Equal(t, GetBlockNumberByEpoch(97), result.Entry[47].Height)
+	assert.Equal(t, big.NewInt(1e17+5), result.Entry[47].Amount.ToInt())
+
+	for epoch := uint64(1); epoch <= 97; epoch++ {
+		if err := plugin.releaseRestricting(epoch, plugin.mockDB); err != nil {
+			t.Fatal(err)
+		}
+		if epoch == 95 {
+			assert.Equal(t, big.NewInt(47e17), plugin.mockDB.GetBalance(plugin.to))
+		}
+	}
+	assert.Equal(t, big.NewInt(48e17+5), plugin.mockDB.GetBalance(plugin.to))
+	if _, _, bizErr := plugin.mustGetRestrictingInfoByDecode(plugin.mockDB, plugin.to); bizErr != restricting.ErrAccountNotFound {
+		t.Errorf("expect ErrAccountNotFound, got %v", bizErr)
+	}
+	schedules, err := plugin.getVestingSchedules(plugin.mockDB, plugin.to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(schedules))
+}
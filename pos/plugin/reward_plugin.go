@@ -148,14 +148,22 @@ func (rmp *RewardMgrPlugin) isLessThanFoundationYear(thisYear uint32) bool {
 	return false
 }
 
-func (rmp *RewardMgrPlugin) addPhoenixChainFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
+func (rmp *RewardMgrPlugin) addPhoenixChainFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) *big.Int {
 	phoenixchainFoundationIncr := percentageCalculation(currIssuance, uint64(allocateRate))
 	state.AddBalance(xcom.PhoenixChainFundAccount(), phoenixchainFoundationIncr)
+	return phoenixchainFoundationIncr
 }
 
-func (rmp *RewardMgrPlugin) addCommunityDeveloperFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
+// addCommunityDeveloperFoundation credits the developer foundation share of the issuance,
+// since version 1.2.0 it is credited to the treasury pool, which is paid out only by the treasury proposals
+func (rmp *RewardMgrPlugin) addCommunityDeveloperFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) *big.Int {
 	developerFoundationIncr := percentageCalculation(currIssuance, uint64(allocateRate))
-	state.AddBalance(xcom.CDFAccount(), developerFoundationIncr)
+	if gov.Gte120VersionState(state) {
+		state.AddBalance(vm.TreasuryPoolAddr, developerFoundationIncr)
+	} else {
+		state.AddBalance(xcom.CDFAccount(), developerFoundationIncr)
+	}
+	return developerFoundationIncr
 }
 func (rmp *RewardMgrPlugin) addRewardPoolIncreaseIssuance(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(allocateRate))
@@ -187,17 +195,24 @@ func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear uint32, state xc
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(RewardPoolIncreaseRate))
 	state.AddBalance(vm.RewardManagerPoolAddr, rewardpoolIncr)
 	lessBalance := new(big.Int).Sub(currIssuance, rewardpoolIncr)
+	developerIncr, phoenixchainIncr := new(big.Int), new(big.Int)
 	if rmp.isLessThanFoundationYear(thisYear) {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer", "thisYear", thisYear, "developBalance", lessBalance)
-		rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate)
+		developerIncr = rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate)
 	} else {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer and phoenixchain", "thisYear", thisYear, "develop and phoenixchain Balance", lessBalance)
-		rmp.addCommunityDeveloperFoundation(state, lessBalance, AfterFoundationYearDeveloperRewardRate)
-		rmp.addPhoenixChainFoundation(state, lessBalance, AfterFoundationYearFoundRewardRate)
+		developerIncr = rmp.addCommunityDeveloperFoundation(state, lessBalance, AfterFoundationYearDeveloperRewardRate)
+		phoenixchainIncr = rmp.addPhoenixChainFoundation(state, lessBalance, AfterFoundationYearFoundRewardRate)
 	}
 	balance := state.GetBalance(vm.RewardManagerPoolAddr)
 	SetYearEndBalance(state, thisYear, balance)
-	return nil
+
+	return UpdateEpochEconomics(blockHash, state, rmp.db, xutil.CalculateEpoch(blockNumber), func(ledger *reward.EpochEconomics) {
+		ledger.IncreaseIssuance.Add(ledger.IncreaseIssuance, currIssuance)
+		ledger.RewardPoolIssuance.Add(ledger.RewardPoolIssuance, rewardpoolIncr)
+		ledger.DeveloperFoundation.Add(ledger.DeveloperFoundation, developerIncr)
+		ledger.PhoenixChainFoundation.Add(ledger.PhoenixChainFoundation, phoenixchainIncr)
+	})
 }
 
 // AllocateStakingReward used for reward staking at the settle block
@@ -210,10 +225,23 @@ func (rmp *RewardMgrPlugin) AllocateStakingReward(blockNumber uint64, blockHash
 		log.Error("Failed to AllocateStakingReward: call GetVerifierList is failed", "blockNumber", blockNumber, "hash", blockHash, "err", err)
 		return nil, err
 	}
-	if err := rmp.rewardStakingByValidatorList(state, verifierList, sreward); err != nil {
+	// the ledger is part of the snapshot of the block, so it is only kept since version 1.2.0
+	var ledger *reward.EpochEconomics
+	if gov.Gte120VersionState(state) {
+		if ledger, err = LoadEpochEconomics(blockHash, rmp.db, xutil.CalculateEpoch(blockNumber)); err != nil {
+			return nil, err
+		}
+	}
+	if err := rmp.rewardStakingByValidatorList(state, verifierList, sreward, ledger); err != nil {
 		log.Error("reward staking by validator list fail", "err", err, "bn", blockNumber, "bh", blockHash)
 		return nil, err
 	}
+	if ledger != nil {
+		ledger.StakingReward = new(big.Int).Set(sreward)
+		if err := StorageEpochEconomics(blockHash, rmp.db, ledger); err != nil {
+			return nil, err
+		}
+	}
 
 	return verifierList, nil
 }
@@ -261,6 +289,11 @@ func (rmp *RewardMgrPlugin) HandleDelegatePerReward(blockHash common.Hash, block
 					"blockNumber", blockNumber, "blockHash", blockHash, "nodeID", verifier.NodeId.String(), "err", err)
 				return err
 			}
+			if err := rmp.compoundDelegateReward(blockHash, blockNumber, canAddr, verifier, state); err != nil {
+				log.Error("Failed to handleDelegatePerReward on rewardMgrPlugin: compound delegate reward failed",
+					"blockNumber", blockNumber, "blockHash", blockHash, "nodeID", verifier.NodeId.String(), "err", err)
+				return err
+			}
 			if err := rmp.stakingPlugin.db.SetCanMutableStore(blockHash, canAddr, verifier.CandidateMutable); err != nil {
 				log.Error("Failed to handleDelegatePerReward on rewardMgrPlugin: setCanMutableStore  failed",
 					"blockNumber", blockNumber, "blockHash", blockHash, "err", err, "mutable", verifier.CandidateMutable)
@@ -274,6 +307,75 @@ func (rmp *RewardMgrPlugin) HandleDelegatePerReward(blockHash common.Hash, block
 	return nil
 }
 
+// compoundDelegateReward adds the delegate income of the auto-compounding delegations of the verifier
+// to their effective von, it is called after the delegate reward per of the current epoch is appended.
+func (rmp *RewardMgrPlugin) compoundDelegateReward(blockHash common.Hash, blockNumber uint64, canAddr common.NodeAddress, verifier *staking.Candidate, state xcom.StateDB) error {
+	iter := rmp.stakingPlugin.db.IteratorAutoCompoundByBlockHash(blockHash, verifier.NodeId, verifier.StakingBlockNum, 0)
+	if err := iter.Error(); nil != err {
+		return err
+	}
+	defer iter.Release()
+
+	currentEpoch := xutil.CalculateEpoch(blockNumber)
+	// The income is settled up to the current epoch, the compounded von is effective from the next one
+	nextEpoch := currentEpoch + 1
+	compounded := new(big.Int)
+	for iter.Valid(); iter.Next(); {
+		addr := staking.DecodeAutoCompoundKey(iter.Key())
+		del, err := rmp.stakingPlugin.db.GetDelegateStore(blockHash, addr, verifier.NodeId, verifier.StakingBlockNum)
+		if snapshotdb.IsDbNotFoundErr(err) {
+			continue
+		} else if nil != err {
+			return err
+		}
+
+		per, err := getDelegateRewardPerList(blockHash, verifier.NodeId, verifier.StakingBlockNum, uint64(del.DelegateEpoch), currentEpoch, rmp.db)
+		if nil != err {
+			return err
+		}
+		rewardsReceive := calcDelegateIncome(nextEpoch, del, per)
+		if err := UpdateDelegateRewardPer(blockHash, verifier.NodeId, verifier.StakingBlockNum, rewardsReceive, rmp.db); err != nil {
+			return err
+		}
+
+		income := del.CumulativeIncome
+		if income.Cmp(common.Big0) > 0 {
+			if pool := state.GetBalance(vm.DelegateRewardPoolAddr); pool.Cmp(income) < 0 {
+				return fmt.Errorf("DelegateRewardPool balance is not enougth,want %v have %v", income, pool)
+			}
+			state.SubBalance(vm.DelegateRewardPoolAddr, income)
+			state.AddBalance(vm.StakingContractAddr, income)
+			del.Released = new(big.Int).Add(del.Released, income)
+			compounded.Add(compounded, income)
+		}
+		del.CleanCumulativeIncome(uint32(nextEpoch))
+		if err := rmp.stakingPlugin.db.SetDelegateStore(blockHash, addr, verifier.NodeId, verifier.StakingBlockNum, del); err != nil {
+			return err
+		}
+		log.Debug("compoundDelegateReward", "blockNum", blockNumber, "nodeId", verifier.NodeId.TerminalString(), "stakingNum", verifier.StakingBlockNum,
+			"delAddr", addr, "income", income, "epoch", currentEpoch)
+	}
+
+	if compounded.Cmp(common.Big0) == 0 {
+		return nil
+	}
+
+	// The compounded von is counted in the power of the candidate, the same as a delegation
+	if verifier.IsValid() {
+		if err := rmp.stakingPlugin.db.DelCanPowerStore(blockHash, verifier); err != nil {
+			return err
+		}
+	}
+	verifier.AddShares(compounded)
+	verifier.DelegateTotal = new(big.Int).Add(verifier.DelegateTotal, compounded)
+	if verifier.IsValid() {
+		if err := rmp.stakingPlugin.db.SetCanPowerStore(blockHash, canAddr, verifier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (rmp *RewardMgrPlugin) WithdrawDelegateReward(blockHash common.Hash, blockNum uint64, account common.Address, list []*DelegationInfoWithRewardPerList, state xcom.StateDB) ([]reward.NodeDelegateReward, error) {
 	log.Debug("Call withdraw delegate reward: begin", "account", account, "list", list, "blockNum", blockNum, "blockHash", blockHash, "epoch", xutil.CalculateEpoch(blockNum))
 
@@ -385,7 +487,7 @@ func (rmp *RewardMgrPlugin) CalDelegateRewardAndNodeReward(totalReward *big.Int,
 	return tmp, new(big.Int).Sub(totalReward, tmp)
 }
 
-func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, list []*staking.Candidate, reward *big.Int) error {
+func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, list []*staking.Candidate, reward *big.Int, ledger *reward.EpochEconomics) error {
 	validatorNum := int64(len(list))
 	everyValidatorReward := new(big.Int).Div(reward, big.NewInt(validatorNum))
 
@@ -401,11 +503,18 @@ func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, lis
 			//the  CurrentEpochDelegateReward will use by cal delegate reward Per
 			value.CurrentEpochDelegateReward.Add(value.CurrentEpochDelegateReward, delegateReward)
 		}
+		nodeStakingReward := new(big.Int)
 		if value.BenefitAddress != vm.RewardManagerPoolAddr {
 			log.Debug("allocate staking reward one-by-one", "nodeId", value.NodeId.String(),
 				"benefitAddress", value.BenefitAddress.String(), "staking reward", stakingReward)
 			state.AddBalance(value.BenefitAddress, stakingReward)
 			totalValidatorReward.Add(totalValidatorReward, stakingReward)
+			nodeStakingReward = stakingReward
+		}
+		if ledger != nil {
+			node := ledger.Node(value.NodeId)
+			node.DelegateReward.Add(node.DelegateReward, delegateReward)
+			node.StakingReward.Add(node.StakingReward, nodeStakingReward)
 		}
 	}
 	state.AddBalance(vm.DelegateRewardPoolAddr, totalValidatorDelegateReward)
@@ -432,6 +541,8 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 		return err
 	}
 
+	blockReward := new(big.Int).Set(reward)
+	delegateReward := new(big.Int)
 	currVerifier, err := rmp.stakingPlugin.IsCurrVerifier(blockHash, head.Number.Uint64(), nodeID, false)
 	if err != nil {
 		log.Error("AllocatePackageBlock IsCurrVerifier fail", "err", err, "blockNumber", head.Number, "blockHash", blockHash)
@@ -444,7 +555,6 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 			return err
 		}
 		if cm.ShouldGiveDelegateReward() {
-			delegateReward := new(big.Int).SetUint64(0)
 			delegateReward, reward = rmp.CalDelegateRewardAndNodeReward(reward, cm.RewardPer)
 
 			state.SubBalance(vm.RewardManagerPoolAddr, delegateReward)
@@ -466,8 +576,21 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 
 		state.SubBalance(vm.RewardManagerPoolAddr, reward)
 		state.AddBalance(head.Coinbase, reward)
+	} else {
+		reward = new(big.Int)
 	}
-	return nil
+	return rmp.recordPackageReward(state, blockHash, head.Number.Uint64(), nodeID, blockReward, reward, delegateReward)
+}
+
+// recordPackageReward adds the reward of the block to the economics ledger of the epoch
+func (rmp *RewardMgrPlugin) recordPackageReward(state xcom.StateDB, blockHash common.Hash, blockNumber uint64, nodeID discover.NodeID, blockReward, nodeReward, delegateReward *big.Int) error {
+	return UpdateEpochEconomics(blockHash, state, rmp.db, xutil.CalculateEpoch(blockNumber), func(ledger *reward.EpochEconomics) {
+		ledger.NewBlockReward = blockReward
+		node := ledger.Node(nodeID)
+		node.Blocks++
+		node.PackageReward.Add(node.PackageReward, nodeReward)
+		node.DelegateReward.Add(node.DelegateReward, delegateReward)
+	})
 }
 
 type DelegationInfoWithRewardPerList struct {
@@ -927,3 +1050,46 @@ func LoadChainYearNumber(hash common.Hash, snapshotDB snapshotdb.DB) (uint32, er
 	}
 	return common.BytesToUint32(chainYearNumberByte), nil
 }
+
+// LoadEpochEconomics returns the economics ledger of the epoch, the ledger is empty if nothing is recorded
+func LoadEpochEconomics(hash common.Hash, snapshotDB snapshotdb.DB, epoch uint64) (*reward.EpochEconomics, error) {
+	ledgerByte, err := snapshotDB.Get(hash, reward.GetEpochEconomicsKey(epoch))
+	if nil != err {
+		if err == snapshotdb.ErrNotFound {
+			return reward.NewEpochEconomics(epoch), nil
+		}
+		log.Error("Failed to execute LoadEpochEconomics function", "hash", hash.TerminalString(), "epoch", epoch, "err", err)
+		return nil, err
+	}
+	var ledger reward.EpochEconomics
+	if err := rlp.DecodeBytes(ledgerByte, &ledger); nil != err {
+		return nil, err
+	}
+	return &ledger, nil
+}
+
+func StorageEpochEconomics(hash common.Hash, snapshotDB snapshotdb.DB, ledger *reward.EpochEconomics) error {
+	ledgerByte, err := rlp.EncodeToBytes(ledger)
+	if nil != err {
+		return err
+	}
+	if err := snapshotDB.Put(hash, reward.GetEpochEconomicsKey(ledger.Epoch), ledgerByte); nil != err {
+		log.Error("Failed to execute StorageEpochEconomics function", "hash", hash.TerminalString(), "epoch", ledger.Epoch, "err", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateEpochEconomics loads the economics ledger of the epoch, applies the change and stores it.
+// The ledger is part of the snapshot of the block, so nothing is recorded before version 1.2.0
+func UpdateEpochEconomics(hash common.Hash, state xcom.StateDB, snapshotDB snapshotdb.DB, epoch uint64, update func(ledger *reward.EpochEconomics)) error {
+	if !gov.Gte120VersionState(state) {
+		return nil
+	}
+	ledger, err := LoadEpochEconomics(hash, snapshotDB, epoch)
+	if nil != err {
+		return err
+	}
+	update(ledger)
+	return StorageEpochEconomics(hash, snapshotDB, ledger)
+}
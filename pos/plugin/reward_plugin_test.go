@@ -662,6 +662,96 @@ func TestAllocatePackageBlock(t *testing.T) {
 
 }
 
+func TestRewardMgrPlugin_EpochEconomics(t *testing.T) {
+	chain := mock.NewChain()
+	defer chain.SnapDB.Clear()
+
+	stkDB := staking.NewStakingDBWithDB(chain.SnapDB)
+	index, queue, can, _ := generateStk(1000, big.NewInt(configs.PHC*3), 10)
+	if err := chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		if err := stkDB.SetEpochValIndex(hash, index); err != nil {
+			return err
+		}
+		if err := stkDB.SetEpochValList(hash, index[0].Start, index[0].End, queue); err != nil {
+			return err
+		}
+		if err := stkDB.SetCanBaseStore(hash, queue[0].NodeAddress, can.CandidateBase); err != nil {
+			return err
+		}
+		return stkDB.SetCanMutableStore(hash, queue[0].NodeAddress, can.CandidateMutable)
+	}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	rm := &RewardMgrPlugin{
+		db: chain.SnapDB,
+		stakingPlugin: &StakingPlugin{
+			db: staking.NewStakingDBWithDB(chain.SnapDB),
+		},
+	}
+	rm.SetCurrentNodeID(can.NodeId)
+
+	blockReward, stakingReward := big.NewInt(100000), big.NewInt(200000)
+	chain.StateDB.AddBalance(vm.RewardManagerPoolAddr, big.NewInt(100000000000000))
+
+	// nothing is recorded before version 1.2.0
+	if err := chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		return rm.recordPackageReward(chain.StateDB, hash, header.Number.Uint64(), can.NodeId, blockReward, blockReward, new(big.Int))
+	}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ledger, err := LoadEpochEconomics(chain.CurrentHeader().Hash(), chain.SnapDB, 1); assert.Nil(t, err) {
+		assert.Len(t, ledger.Nodes, 0)
+	}
+	if err := gov.AddActiveVersion(configs.FORKVERSION_1_2_0, 0, chain.StateDB); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := uint64(0)
+	delegateReward := new(big.Int)
+	for chain.CurrentHeader().Number.Uint64() < xutil.CalcBlocksEachEpoch() {
+		if err := chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+			if err := rm.AllocatePackageBlock(hash, header, blockReward, chain.StateDB); err != nil {
+				return err
+			}
+			blocks++
+			dr, _ := rm.CalDelegateRewardAndNodeReward(blockReward, can.RewardPer)
+			delegateReward.Add(delegateReward, dr)
+			if xutil.IsEndOfEpoch(header.Number.Uint64()) {
+				if _, err := rm.AllocateStakingReward(header.Number.Uint64(), hash, stakingReward, chain.StateDB); err != nil {
+					return err
+				}
+				dr, _ := rm.CalDelegateRewardAndNodeReward(stakingReward, can.RewardPer)
+				delegateReward.Add(delegateReward, dr)
+			}
+			return nil
+		}, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ledger, err := LoadEpochEconomics(chain.CurrentHeader().Hash(), chain.SnapDB, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, blockReward.Cmp(ledger.NewBlockReward) == 0)
+	assert.True(t, stakingReward.Cmp(ledger.StakingReward) == 0)
+	if assert.Len(t, ledger.Nodes, 1) {
+		node := ledger.Nodes[0]
+		assert.Equal(t, can.NodeId, node.NodeID)
+		assert.Equal(t, blocks, node.Blocks)
+		assert.True(t, delegateReward.Cmp(node.DelegateReward) == 0)
+
+		total := new(big.Int).Mul(blockReward, new(big.Int).SetUint64(blocks))
+		total.Add(total, stakingReward)
+		received := new(big.Int).Add(node.PackageReward, node.StakingReward)
+		assert.True(t, total.Cmp(received.Add(received, node.DelegateReward)) == 0)
+	}
+
+	presenter := ledger.Presenter()
+	assert.True(t, delegateReward.Cmp(presenter.TotalDelegateReward.ToInt()) == 0)
+	assert.True(t, presenter.TotalSlashed.ToInt().Sign() == 0)
+}
+
 func generateStk(rewardPer uint16, delegateTotal *big.Int, blockNumber uint64) (staking.ValArrIndexQueue, staking.ValidatorQueue, staking.Candidate, staking.Delegation) {
 	var canMu staking.CandidateMutable
 	canMu.Released = big.NewInt(10000)
@@ -800,6 +890,92 @@ func TestRewardMgrPlugin_GetDelegateReward(t *testing.T) {
 
 }
 
+func TestRewardMgrPlugin_CompoundDelegateReward(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if nil != err {
+		panic(err)
+	}
+	delegateRewardAdd := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	chain := mock.NewChain()
+	defer chain.SnapDB.Clear()
+
+	stkDB := staking.NewStakingDBWithDB(chain.SnapDB)
+	delegateTotal := big.NewInt(configs.PHC * 3)
+	index, queue, can, delegate := generateStk(1000, new(big.Int).Set(delegateTotal), 10)
+	can.Shares = new(big.Int).Add(can.Released, delegateTotal)
+	can.CleanCurrentEpochDelegateReward()
+	chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		if err := stkDB.SetEpochValIndex(hash, index); err != nil {
+			return err
+		}
+		if err := stkDB.SetEpochValList(hash, index[0].Start, index[0].End, queue); err != nil {
+			return err
+		}
+		if err := stkDB.SetCanBaseStore(hash, queue[0].NodeAddress, can.CandidateBase); err != nil {
+			return err
+		}
+		if err := stkDB.SetCanMutableStore(hash, queue[0].NodeAddress, can.CandidateMutable); err != nil {
+			return err
+		}
+		if err := stkDB.SetDelegateStore(hash, delegateRewardAdd, can.CandidateBase.NodeId, can.CandidateBase.StakingBlockNum, &delegate); err != nil {
+			return err
+		}
+		return stkDB.SetAutoCompoundStore(hash, delegateRewardAdd, can.NodeId, can.StakingBlockNum, true)
+	}, nil, nil)
+	rm := &RewardMgrPlugin{
+		db: chain.SnapDB,
+		stakingPlugin: &StakingPlugin{
+			db: staking.NewStakingDBWithDB(chain.SnapDB),
+		},
+	}
+	rm.SetCurrentNodeID(can.NodeId)
+
+	blockReward, stakingReward := big.NewInt(100000), big.NewInt(200000)
+	chain.StateDB.AddBalance(vm.RewardManagerPoolAddr, big.NewInt(100000000000000))
+	for i := 0; i < int(xutil.CalcBlocksEachEpoch()); i++ {
+		if err := chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+			if err := rm.AllocatePackageBlock(hash, header, blockReward, chain.StateDB); err != nil {
+				return err
+			}
+			if xutil.IsEndOfEpoch(header.Number.Uint64()) {
+				verifierList, err := rm.AllocateStakingReward(header.Number.Uint64(), hash, stakingReward, chain.StateDB)
+				if err != nil {
+					return err
+				}
+				if err := rm.HandleDelegatePerReward(hash, header.Number.Uint64(), verifierList, chain.StateDB); err != nil {
+					return err
+				}
+				epoch := xutil.CalculateEpoch(header.Number.Uint64())
+				return stkDB.SetEpochValList(hash, index[epoch].Start, index[epoch].End, queue)
+			}
+			return nil
+		}, nil, nil); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	hash := chain.CurrentHeader().Hash()
+	del, err := stkDB.GetDelegateStore(hash, delegateRewardAdd, can.NodeId, can.StakingBlockNum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compounded := new(big.Int).Sub(del.Released, delegateTotal)
+	assert.True(t, compounded.Cmp(common.Big0) > 0)
+	assert.True(t, del.CumulativeIncome.Cmp(common.Big0) == 0)
+	// the head is the first block of the epoch after the settlement
+	assert.Equal(t, uint32(xutil.CalculateEpoch(chain.CurrentHeader().Number.Uint64())), del.DelegateEpoch)
+	assert.True(t, chain.StateDB.GetBalance(delegateRewardAdd).Cmp(common.Big0) == 0)
+
+	canMutable, err := stkDB.GetCanMutableStore(hash, queue[0].NodeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, canMutable.DelegateTotal.Cmp(del.Released) == 0)
+	assert.True(t, new(big.Int).Add(can.Shares, compounded).Cmp(canMutable.Shares) == 0)
+}
+
 func TestDelegateRewardPerUpdateAndAppend(t *testing.T) {
 	chain := mock.NewChain()
 	defer chain.SnapDB.Clear()
@@ -20,6 +20,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/db/snapshotdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto"
@@ -40,6 +41,10 @@ var (
 	packAmountPrefix = []byte("nodePackAmount")
 	// Nodes with zero block behavior are stored in this list; This value is the key of the list
 	waitSlashingNodeListKey = []byte("waitSlashingNodeList")
+	// The prefix key of the vote participation of the validators in a consensus round
+	voteParticipationPrefix = []byte("voteParticipation")
+	// The key of the block number of the last quorum certificate counted in the vote participation
+	lastVoteQuorumCertKey = []byte("lastVoteQuorumCert")
 	once                    sync.Once
 	slash                   *SlashingPlugin
 )
@@ -62,6 +67,16 @@ func (w *WaitSlashingNode) String() string {
 	return string(v)
 }
 
+// The quorum certificates carried by the block headers are counted in the consensus round
+// of the certified block, to judge whether the validators of the round missed votes.
+type VoteParticipation struct {
+	// The number of the quorum certificates counted in the round
+	Samples uint32
+	// The number of the counted quorum certificates signed by each validator,
+	// in the order of the validators of the round
+	Signed []uint32
+}
+
 type SlashingPlugin struct {
 	db             snapshotdb.DB
 	decodeEvidence func(dupType consensus.EvidenceType, data string) (consensus.Evidence, error)
@@ -99,6 +114,10 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 		log.Error("Failed to BeginBlock, call setPackAmount is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 		return err
 	}
+	if err := sp.countVoteParticipation(blockHash, header); nil != err {
+		log.Error("Failed to BeginBlock, call countVoteParticipation is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
+		return err
+	}
 	// If it is the 230th block of each round,
 	// it will punish the node with abnormal block rate.
 	// Do this from the second consensus round
@@ -143,6 +162,11 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 				return err
 			}
 
+			if slashQueue, err = sp.missedVoteProcess(blockHash, header, preRoundVal.Arr, slashQueue); nil != err {
+				log.Error("Failed to BeginBlock, call missedVoteProcess is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
+				return err
+			}
+
 			// Real to slash the node
 			// If there is no record of the node,
 			// it means that there is no block,
@@ -359,22 +383,16 @@ func (sp *SlashingPlugin) checkSlashing(blockNumber uint64, blockHash common.Has
 				return nil, err
 			}
 
-			slashAmount := new(big.Int).SetUint64(0)
 			totalBalance := calcCanTotalBalance(blockNumber, canMutable)
 			blocksReward, err := gov.GovernSlashBlocksReward(blockNumber, blockHash)
 			if nil != err {
 				log.Error("Failed to zeroProduceProcess, query GovernSlashBlocksReward is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
 				return nil, err
 			}
-			if blocksReward > 0 {
-				slashAmount, err = calcSlashBlockRewards(sp.db, blockHash, uint64(blocksReward))
-				if nil != err {
-					log.Error("Failed to zeroProduceProcess, call calcSlashBlockRewards fail", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
-					return nil, err
-				}
-				if slashAmount.Cmp(totalBalance) > 0 {
-					slashAmount = totalBalance
-				}
+			slashAmount, err := calcSlashAmountByBlocksReward(sp.db, blockHash, blocksReward, totalBalance)
+			if nil != err {
+				log.Error("Failed to zeroProduceProcess, call calcSlashBlockRewards fail", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+				return nil, err
 			}
 
 			slashItem := &staking.SlashNodeItem{
@@ -391,6 +409,169 @@ func (sp *SlashingPlugin) checkSlashing(blockNumber uint64, blockHash common.Has
 	return nil, nil
 }
 
+// countVoteParticipation counts the signers of the quorum certificate carried by the header,
+// each certified block is counted once, by the first block carrying its certificate.
+// The header may carry the certificate of its parent only, which the consensus verifies.
+func (sp *SlashingPlugin) countVoteParticipation(blockHash common.Hash, header *types.Header) error {
+	data, ok := header.VoteQuorumCert()
+	if !ok {
+		return nil
+	}
+	blockNumber := header.Number.Uint64()
+	var qc ctypes.QuorumCert
+	if err := rlp.DecodeBytes(data, &qc); nil != err {
+		return err
+	}
+
+	value, err := sp.db.Get(blockHash, lastVoteQuorumCertKey)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return err
+	}
+	if err == snapshotdb.ErrNotFound {
+		// The blocks begin to carry the quorum certificates, add the governable parameters of the missed vote slashing
+		if err := gov.InitVoteParticipationParam(blockHash); nil != err {
+			log.Error("Failed to countVoteParticipation, call InitVoteParticipationParam is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+			return err
+		}
+	} else if qc.BlockNumber <= common.BytesToUint64(value) {
+		return nil
+	}
+	if err := sp.db.Put(blockHash, lastVoteQuorumCertKey, common.Uint64ToBytes(qc.BlockNumber)); nil != err {
+		return err
+	}
+
+	// The previous round is judged at the election block of the current round,
+	// the certificates of the blocks in it are not counted after that
+	round := xutil.CalculateRound(qc.BlockNumber)
+	if current := xutil.CalculateRound(blockNumber); round != current &&
+		(round+1 != current || blockNumber-(round*xutil.ConsensusSize())+xcom.ElectionDistance() > xutil.ConsensusSize()) {
+		log.Debug("Call countVoteParticipation, the round is judged", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "qcNumber", qc.BlockNumber)
+		return nil
+	}
+
+	validators, err := stk.getCurrValList(blockHash, qc.BlockNumber, QueryStartNotIrr)
+	if nil != err {
+		return err
+	}
+	if qc.ValidatorSet == nil || int(qc.ValidatorSet.Size()) != len(validators.Arr) {
+		return fmt.Errorf("the validator set of the quorum cert mismatch, qcNumber:%d, validators:%d", qc.BlockNumber, len(validators.Arr))
+	}
+
+	participation, err := sp.getVoteParticipation(blockHash, round)
+	if nil != err {
+		return err
+	}
+	if participation == nil {
+		participation = &VoteParticipation{Signed: make([]uint32, len(validators.Arr))}
+	}
+	participation.Samples++
+	for i := range participation.Signed {
+		if qc.ValidatorSet.GetIndex(uint32(i)) {
+			participation.Signed[i]++
+		}
+	}
+	log.Debug("Call countVoteParticipation finished", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "qcNumber", qc.BlockNumber, "round", round, "samples", participation.Samples)
+	return sp.setVoteParticipation(blockHash, round, participation)
+}
+
+// missedVoteProcess judges the vote participation of the validators of the previous round,
+// the validators signed less than the governed percentage of the counted quorum certificates
+// are slashed, unless they are slashed for zero production already.
+func (sp *SlashingPlugin) missedVoteProcess(blockHash common.Hash, header *types.Header, validatorQueue staking.ValidatorQueue, slashQueue staking.SlashQueue) (staking.SlashQueue, error) {
+	blockNumber := header.Number.Uint64()
+	preRound := xutil.CalculateRound(blockNumber) - 1
+	participation, err := sp.getVoteParticipation(blockHash, preRound)
+	if nil != err || participation == nil {
+		return slashQueue, err
+	}
+	if err := sp.db.Del(blockHash, buildVoteParticipationKey(preRound)); nil != err {
+		return nil, err
+	}
+
+	if participation.Samples < xcom.VoteParticipationMinSamples() || len(participation.Signed) != len(validatorQueue) {
+		log.Debug("Call missedVoteProcess, skip the round", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "preRound", preRound,
+			"samples", participation.Samples, "signed", len(participation.Signed), "validators", len(validatorQueue))
+		return slashQueue, nil
+	}
+	threshold, err := gov.GovernVoteParticipationThreshold(blockNumber, blockHash)
+	if nil != err {
+		log.Error("Failed to missedVoteProcess, call GovernVoteParticipationThreshold is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+		return nil, err
+	}
+	if threshold == 0 {
+		return slashQueue, nil
+	}
+	blocksReward, err := gov.GovernSlashVoteBlocksReward(blockNumber, blockHash)
+	if nil != err {
+		log.Error("Failed to missedVoteProcess, call GovernSlashVoteBlocksReward is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+		return nil, err
+	}
+
+	slashed := make(map[discover.NodeID]struct{}, len(slashQueue))
+	for _, item := range slashQueue {
+		slashed[item.NodeId] = struct{}{}
+	}
+	for i, validator := range validatorQueue {
+		nodeId := validator.NodeId
+		if uint64(participation.Signed[i])*HundredDenominator >= uint64(threshold)*uint64(participation.Samples) {
+			continue
+		}
+		if _, ok := slashed[nodeId]; ok {
+			continue
+		}
+
+		canMutable, err := stk.GetCanMutableByIrr(validator.NodeAddress)
+		if nil != err {
+			log.Error("Failed to missedVoteProcess, call candidate mutable info is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+				"nodeAddr", validator.NodeAddress.Hex(), "err", err)
+			if err == snapshotdb.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		totalBalance := calcCanTotalBalance(blockNumber, canMutable)
+		slashAmount, err := calcSlashAmountByBlocksReward(sp.db, blockHash, blocksReward, totalBalance)
+		if nil != err {
+			return nil, err
+		}
+		slashItem := &staking.SlashNodeItem{
+			NodeId:      nodeId,
+			Amount:      slashAmount,
+			SlashType:   staking.MissedVote,
+			BenefitAddr: vm.RewardManagerPoolAddr,
+		}
+		slashQueue = append(slashQueue, slashItem)
+		log.Info("Need to call SlashCandidates missed vote nodes", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "nodeId", nodeId.TerminalString(),
+			"signed", participation.Signed[i], "samples", participation.Samples, "threshold", threshold, "totalBalance", totalBalance, "slashAmount", slashAmount)
+	}
+	return slashQueue, nil
+}
+
+func (sp *SlashingPlugin) getVoteParticipation(blockHash common.Hash, round uint64) (*VoteParticipation, error) {
+	value, err := sp.db.Get(blockHash, buildVoteParticipationKey(round))
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	if err == snapshotdb.ErrNotFound {
+		return nil, nil
+	}
+	var participation VoteParticipation
+	if err := rlp.DecodeBytes(value, &participation); nil != err {
+		log.Error("rlpDecode VoteParticipation failed", "blockHash", blockHash.TerminalString(), "round", round, "err", err)
+		return nil, err
+	}
+	return &participation, nil
+}
+
+func (sp *SlashingPlugin) setVoteParticipation(blockHash common.Hash, round uint64, participation *VoteParticipation) error {
+	enValue, err := rlp.EncodeToBytes(participation)
+	if nil != err {
+		log.Error("rlpEncode VoteParticipation failed", "blockHash", blockHash.TerminalString(), "round", round, "err", err)
+		return err
+	}
+	return sp.db.Put(blockHash, buildVoteParticipationKey(round), enValue)
+}
+
 func (sp *SlashingPlugin) getWaitSlashingNodeList(blockNumber uint64, blockHash common.Hash) ([]*WaitSlashingNode, error) {
 	value, err := sp.db.Get(blockHash, waitSlashingNodeListKey)
 	if snapshotdb.NonDbNotFoundErr(err) {
@@ -570,7 +751,14 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 		return slashing.ErrNodeIdMismatch
 	}
 
-	blsKey, _ := canBase.BlsPubKey.ParseBlsPubKey()
+	// The candidate may have rotated the BLS key after the evidence block
+	blsPubKey, err := stk.getBlsPubKeyAt(blockHash, canAddr, canBase.BlsPubKey, evidence.BlockNumber())
+	if nil != err {
+		log.Error("Failed to Slash, query the BLS key of evidence block is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+			"evidenceBlockNum", evidence.BlockNumber(), "canAddr", canAddr.Hex(), "err", err)
+		return slashing.ErrDuplicateSignVerify
+	}
+	blsKey, _ := blsPubKey.ParseBlsPubKey()
 	if !bytes.Equal(blsKey.Serialize(), evidence.BlsPubKey().Serialize()) {
 		log.Error("Failed to Slash, Mismatch blsPubKey", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
 			"nodeId", canBase.NodeId.TerminalString(), "can blsKey", hex.EncodeToString(blsKey.Serialize()),
@@ -677,6 +865,10 @@ func buildPrefixByRound(round uint64) []byte {
 	return append(packAmountPrefix, common.Uint64ToBytes(round)...)
 }
 
+func buildVoteParticipationKey(round uint64) []byte {
+	return append(common.CopyBytes(voteParticipationPrefix), common.Uint64ToBytes(round)...)
+}
+
 func getNodeId(prefix []byte, key []byte) (discover.NodeID, error) {
 	key = key[len(prefix):]
 	nodeId, err := discover.BytesID(key)
@@ -719,3 +911,18 @@ func calcSlashBlockRewards(db snapshotdb.DB, hash common.Hash, blockRewardAmount
 	}
 	return new(big.Int).Mul(newBlockReward, new(big.Int).SetUint64(blockRewardAmount)), nil
 }
+
+// calcSlashAmountByBlocksReward calculates the rewards of the blocks to slash, not more than the total balance
+func calcSlashAmountByBlocksReward(db snapshotdb.DB, hash common.Hash, blocksReward uint32, totalBalance *big.Int) (*big.Int, error) {
+	if blocksReward == 0 {
+		return new(big.Int).SetUint64(0), nil
+	}
+	slashAmount, err := calcSlashBlockRewards(db, hash, uint64(blocksReward))
+	if nil != err {
+		return nil, err
+	}
+	if slashAmount.Cmp(totalBalance) > 0 {
+		slashAmount = totalBalance
+	}
+	return slashAmount, nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/evidence"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/consensus/pbft/utils"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/mock"
 
@@ -863,3 +865,114 @@ func TestSlashingPlugin_ZeroProduceProcess(t *testing.T) {
 		}
 	}
 }
+
+func TestSlashingPlugin_MissedVoteProcess(t *testing.T) {
+	_, genesis, _ := newChainState()
+	si, stateDB := initInfo(t)
+	blockNumber := new(big.Int).SetUint64(1)
+	if err := snapshotdb.Instance().NewBlock(blockNumber, genesis.Hash(), common.ZeroHash); nil != err {
+		t.Fatal(err)
+	}
+	defer func() {
+		snapshotdb.Instance().Clear()
+	}()
+	pri, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	buildStakingData(0, common.ZeroHash, pri, blsKey, t, stateDB)
+
+	preRoundVal, err := stk.getCurrValList(common.ZeroHash, 1, QueryStartNotIrr)
+	if nil != err {
+		t.Fatal(err)
+	}
+	newHeader := func(number uint64, qcNumber uint64, signers ...uint32) *types.Header {
+		validatorSet := utils.NewBitArray(uint32(len(preRoundVal.Arr)))
+		for _, i := range signers {
+			validatorSet.SetIndex(i, true)
+		}
+		data, err := rlp.EncodeToBytes(&ctypes.QuorumCert{BlockNumber: qcNumber, ValidatorSet: validatorSet})
+		if nil != err {
+			t.Fatal(err)
+		}
+		return &types.Header{
+			Number: new(big.Int).SetUint64(number),
+			Extra:  append(make([]byte, 97), data...),
+		}
+	}
+
+	// The third validator signs one of the quorum certificates only
+	samples := xcom.VoteParticipationMinSamples()
+	for i := uint64(1); i <= uint64(samples); i++ {
+		signers := []uint32{0, 1}
+		if i == 1 {
+			signers = append(signers, 2)
+		}
+		if err := si.countVoteParticipation(common.ZeroHash, newHeader(i+1, i, signers...)); nil != err {
+			t.Fatal(err)
+		}
+	}
+	// The quorum certificate of the counted block is ignored
+	if err := si.countVoteParticipation(common.ZeroHash, newHeader(uint64(samples)+2, uint64(samples), 0, 1, 2)); nil != err {
+		t.Fatal(err)
+	}
+	participation, err := si.getVoteParticipation(common.ZeroHash, 1)
+	if nil != err {
+		t.Fatal(err)
+	}
+	assert.Equal(t, samples, participation.Samples)
+	assert.Equal(t, []uint32{samples, samples, 1}, participation.Signed)
+
+	if err := gov.UpdateGovernParamValue(gov.ModuleSlashing, gov.KeyVoteParticipationThreshold, "50", 1, common.ZeroHash); nil != err {
+		t.Fatal(err)
+	}
+	newBlockReward := new(big.Int).SetUint64(1000)
+	if err := StorageNewBlockReward(common.ZeroHash, snapshotdb.Instance(), newBlockReward); nil != err {
+		t.Fatal(err)
+	}
+
+	nodeIdC := preRoundVal.Arr[2].NodeId
+	canAddr := preRoundVal.Arr[2].NodeAddress
+	canMutable := &staking.CandidateMutable{
+		Shares:             new(big.Int).SetUint64(1000),
+		Released:           new(big.Int).Mul(newBlockReward, new(big.Int).SetUint64(uint64(xcom.SlashBlocksReward())+1)),
+		ReleasedHes:        common.Big0,
+		RestrictingPlan:    common.Big0,
+		RestrictingPlanHes: common.Big0,
+	}
+	if val, err := rlp.EncodeToBytes(canMutable); nil != err {
+		t.Fatal(err)
+	} else if err := snapshotdb.Instance().PutBaseDB(staking.CanMutableKeyByAddr(canAddr), val); nil != err {
+		t.Fatal(err)
+	}
+
+	header := &types.Header{
+		Number: new(big.Int).SetUint64(xutil.ConsensusSize()*2 - xcom.ElectionDistance()),
+		Extra:  make([]byte, 97),
+	}
+	slashQueue, err := si.missedVoteProcess(common.ZeroHash, header, preRoundVal.Arr, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(slashQueue)) {
+		assert.Equal(t, nodeIdC, slashQueue[0].NodeId)
+		assert.Equal(t, staking.MissedVote, slashQueue[0].SlashType)
+		assert.Equal(t, 0, slashQueue[0].Amount.Cmp(new(big.Int).Mul(newBlockReward, new(big.Int).SetUint64(uint64(xcom.SlashBlocksReward())))))
+	}
+
+	// The candidate missing votes is frozen like the low package ratio
+	needInvalid, needRemove, _, changeStatus := handleSlashTypeFn(header.Number.Uint64(), common.ZeroHash, staking.MissedVote, canMutable.Released)
+	assert.True(t, needInvalid)
+	assert.True(t, needRemove)
+	assert.True(t, changeStatus.IsInvalidLowRatio())
+	assert.True(t, changeStatus.IsMissedVote())
+
+	// The participation of the round is removed after the judgment
+	participation, err = si.getVoteParticipation(common.ZeroHash, 1)
+	if nil != err {
+		t.Fatal(err)
+	}
+	assert.Nil(t, participation)
+}
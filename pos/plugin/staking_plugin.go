@@ -27,6 +27,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/bls"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/crypto/vrf"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/event"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/log"
@@ -251,6 +252,9 @@ func (sk *StakingPlugin) GetCandidateCompactInfo(blockHash common.Hash, blockNum
 	epoch := xutil.CalculateEpoch(blockNumber)
 	lazyCalcStakeAmount(epoch, can.CandidateMutable)
 	canHex := buildCanHex(can)
+	if canHex.Operator, err = sk.db.GetOperatorStore(blockHash, can.NodeId, can.StakingBlockNum); nil != err {
+		return nil, err
+	}
 	return canHex, nil
 }
 
@@ -411,6 +415,79 @@ func (sk *StakingPlugin) EditCandidate(blockHash common.Hash, blockNumber *big.I
 	return nil
 }
 
+// RotateBlsKey queues the new BLS public key of the candidate,
+// it replaces the current key at the end of the current epoch.
+// A later rotation in the same epoch overrides the earlier one.
+func (sk *StakingPlugin) RotateBlsKey(blockHash common.Hash, blockNumber uint64, canAddr common.NodeAddress, blsPubKey bls.PublicKeyHex) error {
+
+	nextEpoch := xutil.CalculateEpoch(blockNumber) + 1
+
+	queue, err := sk.db.GetBlsRotationStore(blockHash, nextEpoch)
+	if nil != err {
+		log.Error("Failed to RotateBlsKey: Query BlsRotation is failed", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+
+	var exist bool
+	for _, item := range queue {
+		if item.NodeAddress == canAddr {
+			item.BlsPubKey = blsPubKey
+			exist = true
+			break
+		}
+	}
+	if !exist {
+		queue = append(queue, &staking.BlsKeyRotation{
+			NodeAddress: canAddr,
+			BlsPubKey:   blsPubKey,
+		})
+	}
+
+	if err := sk.db.SetBlsRotationStore(blockHash, nextEpoch, queue); nil != err {
+		log.Error("Failed to RotateBlsKey: Store BlsRotation is failed", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// IsBlsKeyUsed checks whether the BLS public key is used by another candidate,
+// as its current key or as the key queued to replace it at the end of the epoch.
+func (sk *StakingPlugin) IsBlsKeyUsed(blockHash common.Hash, blockNumber uint64, canAddr common.NodeAddress, blsPubKey bls.PublicKeyHex) (bool, error) {
+
+	queue, err := sk.db.GetBlsRotationStore(blockHash, xutil.CalculateEpoch(blockNumber)+1)
+	if nil != err {
+		return false, err
+	}
+	for _, item := range queue {
+		if item.NodeAddress != canAddr && item.BlsPubKey == blsPubKey {
+			return true, nil
+		}
+	}
+
+	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, 0)
+	if err := iter.Error(); nil != err {
+		return false, err
+	}
+	defer iter.Release()
+
+	for iter.Valid(); iter.Next(); {
+		addrSuffix := iter.Value()
+		if bytes.Equal(addrSuffix, canAddr.Bytes()) {
+			continue
+		}
+		can, err := sk.db.GetCanBaseStoreWithSuffix(blockHash, addrSuffix)
+		if nil != err {
+			return false, err
+		}
+		if can.BlsPubKey == blsPubKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (sk *StakingPlugin) IncreaseStaking(state xcom.StateDB, blockHash common.Hash, blockNumber,
 	amount *big.Int, typ uint16, canAddr common.NodeAddress, can *staking.Candidate) error {
 
@@ -799,6 +876,11 @@ func (sk *StakingPlugin) GetDelegateExCompactInfo(blockHash common.Hash, blockNu
 	epoch := xutil.CalculateEpoch(blockNumber)
 	lazyCalcDelegateAmount(epoch, del)
 
+	autoCompound, err := sk.IsAutoCompound(blockHash, delAddr, nodeId, stakeBlockNumber)
+	if nil != err {
+		return nil, err
+	}
+
 	return &staking.DelegationEx{
 		Addr:            delAddr,
 		NodeId:          nodeId,
@@ -810,6 +892,7 @@ func (sk *StakingPlugin) GetDelegateExCompactInfo(blockHash common.Hash, blockNu
 			RestrictingPlan:    (*hexutil.Big)(del.RestrictingPlan),
 			RestrictingPlanHes: (*hexutil.Big)(del.RestrictingPlanHes),
 			CumulativeIncome:   (*hexutil.Big)(del.CumulativeIncome),
+			AutoCompound:       autoCompound,
 		},
 	}, nil
 }
@@ -1038,6 +1121,12 @@ func (sk *StakingPlugin) WithdrewDelegation(state xcom.StateDB, blockHash common
 					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
 				return nil, err
 			}
+			if err := sk.SetAutoCompound(blockHash, delAddr, nodeId, stakingBlockNum, false); nil != err {
+				log.Error("Failed to WithdrewDelegation on stakingPlugin: Delete auto-compound flag is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+					"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+				return nil, err
+			}
 		} else {
 			if err := sk.db.SetDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum, del); nil != err {
 				log.Error("Failed to WithdrewDelegation on stakingPlugin: Store detegate is failed",
@@ -1089,6 +1178,269 @@ func (sk *StakingPlugin) WithdrewDelegation(state xcom.StateDB, blockHash common
 	return issueIncome, nil
 }
 
+// Redelegate moves the amount of the delegation on the candidate nodeId to the target candidate.
+// The von stays in the staking contract, the hesitant and effective parts keep their status
+// and the free and restricting parts keep their source. The pending rewards of both delegations
+// are settled to the delegator first, the settled reward is returned.
+func (sk *StakingPlugin) Redelegate(state xcom.StateDB, blockHash common.Hash, blockNumber, amount *big.Int,
+	delAddr common.Address, nodeId discover.NodeID, stakingBlockNum uint64, del *staking.Delegation,
+	delegateRewardPerList []*reward.DelegateRewardPer, targetAddr common.NodeAddress, target *staking.Candidate,
+	targetDel *staking.Delegation, targetRewardPerList []*reward.DelegateRewardPer) (*big.Int, error) {
+
+	epoch := xutil.CalculateEpoch(blockNumber.Uint64())
+
+	if nodeId == target.NodeId {
+		return nil, staking.ErrRedelegateSameCandidate
+	}
+
+	count, err := sk.db.GetRedelegateCountStore(blockHash, delAddr, epoch)
+	if nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Query redelegate count failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr, "epoch", epoch, "err", err)
+		return nil, err
+	}
+	if count >= xcom.MaxRedelegations() {
+		log.Error("Failed to Redelegate on stakingPlugin: the redelegate count reached the limit",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr, "epoch", epoch,
+			"count", count, "limit", xcom.MaxRedelegations())
+		return nil, staking.ErrRedelegateTooFrequent
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: nodeId parse addr failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	can, err := sk.db.GetCandidateStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to Redelegate on stakingPlugin: Query candidate info failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	if can.IsNotEmpty() && stakingBlockNum > can.StakingBlockNum {
+		log.Error("Failed to Redelegate on stakingPlugin: the stakeBlockNum invalid",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "fn.stakeBlockNum", stakingBlockNum, "can.stakeBlockNum", can.StakingBlockNum)
+		return nil, staking.ErrBlockNumberDisordered
+	}
+
+	total := calcDelegateTotalAmount(del)
+	if total.Cmp(amount) < 0 {
+		log.Error("Failed to Redelegate on stakingPlugin: the amount of valid delegate is not enough",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "delegate amount", total,
+			"redelegate amount", amount)
+		return nil, staking.ErrDelegateVonNoEnough
+	}
+
+	moveAmount := calcRealRefund(blockNumber.Uint64(), blockHash, total, amount)
+
+	if can.IsNotEmpty() && stakingBlockNum == can.StakingBlockNum && can.IsValid() && can.Shares.Cmp(moveAmount) <= 0 {
+		log.Error("Failed to Redelegate on stakingPlugin: the candidate shares is no enough",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(),
+			"can shares", can.Shares, "redelegate amount", moveAmount)
+		return nil, staking.ErrCanSharesNoEnough
+	}
+
+	// Settle the rewards of both delegations, the delegations are converted
+	// from hesitation to effective lazily on the way
+	list := []*DelegationInfoWithRewardPerList{
+		NewDelegationInfoWithRewardPerList(&staking.DelegationInfo{NodeID: nodeId, StakeBlockNumber: stakingBlockNum, Delegation: del}, delegateRewardPerList),
+	}
+	if targetDel.DelegateEpoch > 0 {
+		list = append(list, NewDelegationInfoWithRewardPerList(&staking.DelegationInfo{NodeID: target.NodeId, StakeBlockNumber: target.StakingBlockNum, Delegation: targetDel}, targetRewardPerList))
+	}
+	rewards, err := rm.WithdrawDelegateReward(blockHash, blockNumber.Uint64(), delAddr, list, state)
+	if nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: settle the delegate reward failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+		return nil, err
+	}
+	issueIncome := new(big.Int)
+	for _, r := range rewards {
+		issueIncome.Add(issueIncome, r.Reward)
+	}
+
+	// The hesitant part is moved first, the same as withdrawal
+	remain, hesReleased, hesRestrictingPlan := takeDelegateFn(moveAmount, del.ReleasedHes, del.RestrictingPlanHes)
+	remain, released, restrictingPlan := takeDelegateFn(remain, del.Released, del.RestrictingPlan)
+	if remain.Cmp(common.Big0) != 0 {
+		log.Error("Failed to Redelegate on stakingPlugin: the redelegate remain is not zero",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "del balance", total,
+			"redelegate balance", amount, "move amount", moveAmount, "redelegate remain", remain)
+		return nil, staking.ErrWrongWithdrewDelVonCalc
+	}
+	movedHes := new(big.Int).Add(hesReleased, hesRestrictingPlan)
+	movedEffective := new(big.Int).Add(released, restrictingPlan)
+
+	log.Debug("Call Redelegate", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+		"delAddr", delAddr.String(), "nodeId", nodeId.String(), "StakingNum", stakingBlockNum,
+		"target", target.NodeId.String(), "targetStakingNum", target.StakingBlockNum,
+		"total", total, "amount", amount, "moveAmount", moveAmount, "movedHes", movedHes, "movedEffective", movedEffective)
+
+	// update the source delegation
+	if total.Cmp(moveAmount) == 0 {
+		if err := sk.db.DelDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Delete delegate is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+				"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+			return nil, err
+		}
+		if err := sk.SetAutoCompound(blockHash, delAddr, nodeId, stakingBlockNum, false); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Delete auto-compound flag is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+				"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+			return nil, err
+		}
+	} else {
+		del.ReleasedHes = new(big.Int).Sub(del.ReleasedHes, hesReleased)
+		del.RestrictingPlanHes = new(big.Int).Sub(del.RestrictingPlanHes, hesRestrictingPlan)
+		del.Released = new(big.Int).Sub(del.Released, released)
+		del.RestrictingPlan = new(big.Int).Sub(del.RestrictingPlan, restrictingPlan)
+		del.DelegateEpoch = uint32(epoch)
+		if err := sk.db.SetDelegateStore(blockHash, delAddr, nodeId, stakingBlockNum, del); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Store delegate is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+				"nodeId", nodeId.String(), "stakingBlockNum", stakingBlockNum, "err", err)
+			return nil, err
+		}
+	}
+
+	// update the source candidate
+	if can.IsNotEmpty() && stakingBlockNum == can.StakingBlockNum {
+		lazyCalcNodeTotalDelegateAmount(epoch, can.CandidateMutable)
+		can.DelegateTotalHes = new(big.Int).Sub(can.DelegateTotalHes, movedHes)
+		can.DelegateTotal = new(big.Int).Sub(can.DelegateTotal, movedEffective)
+
+		if can.IsValid() {
+			if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
+				log.Error("Failed to Redelegate on stakingPlugin: Delete candidate old power is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+				return nil, err
+			}
+
+			can.SubShares(moveAmount)
+
+			if err := sk.db.SetCanPowerStore(blockHash, canAddr, can); nil != err {
+				log.Error("Failed to Redelegate on stakingPlugin: Store candidate new power is failed",
+					"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+				return nil, err
+			}
+		} else {
+			if can.Shares != nil && can.Shares.Cmp(moveAmount) > 0 {
+				can.SubShares(moveAmount)
+			}
+		}
+
+		if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
+			log.Error("Failed to Redelegate on stakingPlugin: Store CandidateMutable info is failed",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+			return nil, err
+		}
+	}
+
+	// update the target delegation
+	targetDel.ReleasedHes = new(big.Int).Add(targetDel.ReleasedHes, hesReleased)
+	targetDel.RestrictingPlanHes = new(big.Int).Add(targetDel.RestrictingPlanHes, hesRestrictingPlan)
+	targetDel.Released = new(big.Int).Add(targetDel.Released, released)
+	targetDel.RestrictingPlan = new(big.Int).Add(targetDel.RestrictingPlan, restrictingPlan)
+	targetDel.DelegateEpoch = uint32(epoch)
+	if err := sk.db.SetDelegateStore(blockHash, delAddr, target.NodeId, target.StakingBlockNum, targetDel); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store target delegate is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr,
+			"nodeId", target.NodeId.String(), "stakingBlockNum", target.StakingBlockNum, "err", err)
+		return nil, err
+	}
+
+	// update the target candidate
+	if err := sk.db.DelCanPowerStore(blockHash, target); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Delete target candidate old power is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", target.NodeId.String(), "err", err)
+		return nil, err
+	}
+
+	target.AddShares(moveAmount)
+	lazyCalcNodeTotalDelegateAmount(epoch, target.CandidateMutable)
+	target.DelegateTotalHes = new(big.Int).Add(target.DelegateTotalHes, movedHes)
+	target.DelegateTotal = new(big.Int).Add(target.DelegateTotal, movedEffective)
+	target.DelegateEpoch = uint32(epoch)
+
+	if err := sk.db.SetCanPowerStore(blockHash, targetAddr, target); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store target candidate new power is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", target.NodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if err := sk.db.SetCanMutableStore(blockHash, targetAddr, target.CandidateMutable); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store target CandidateMutable info is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", target.NodeId.String(), "err", err)
+		return nil, err
+	}
+
+	if err := sk.db.AddRedelegateCountStore(blockHash, delAddr, epoch); nil != err {
+		log.Error("Failed to Redelegate on stakingPlugin: Store redelegate count is failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "delAddr", delAddr, "epoch", epoch, "err", err)
+		return nil, err
+	}
+	return issueIncome, nil
+}
+
+func (sk *StakingPlugin) IsAutoCompound(blockHash common.Hash, delAddr common.Address, nodeId discover.NodeID, stakingBlockNum uint64) (bool, error) {
+	return sk.db.GetAutoCompoundStore(blockHash, delAddr, nodeId, stakingBlockNum)
+}
+
+// SetAutoCompound flags or unflags the delegation as auto-compounding, the delegate income
+// of an auto-compounding delegation is added to its effective von at each epoch settlement.
+func (sk *StakingPlugin) SetAutoCompound(blockHash common.Hash, delAddr common.Address, nodeId discover.NodeID, stakingBlockNum uint64, autoCompound bool) error {
+	current, err := sk.db.GetAutoCompoundStore(blockHash, delAddr, nodeId, stakingBlockNum)
+	if nil != err {
+		return err
+	}
+	if current == autoCompound {
+		return nil
+	}
+
+	log.Debug("Call SetAutoCompound", "blockHash", blockHash.Hex(), "delAddr", delAddr, "nodeId", nodeId.String(),
+		"stakingBlockNum", stakingBlockNum, "autoCompound", autoCompound)
+	return sk.db.SetAutoCompoundStore(blockHash, delAddr, nodeId, stakingBlockNum, autoCompound)
+}
+
+// SetOperator registers the operator of the candidate, which is allowed to edit the description,
+// vote on proposals and declare versions on behalf of the staking address.
+// The zero address revokes the operator.
+func (sk *StakingPlugin) SetOperator(blockHash common.Hash, nodeId discover.NodeID, stakingBlockNum uint64, operator common.Address) error {
+	log.Debug("Call SetOperator", "blockHash", blockHash.Hex(), "nodeId", nodeId.String(),
+		"stakingBlockNum", stakingBlockNum, "operator", operator)
+	return sk.db.SetOperatorStore(blockHash, nodeId, stakingBlockNum, operator)
+}
+
+// GetOperator returns the operator of the candidate, or the zero address if there is none.
+// The operator is bound to the staking block number, so it doesn't survive a re-staking.
+func (sk *StakingPlugin) GetOperator(blockHash common.Hash, nodeId discover.NodeID, stakingBlockNum uint64) (common.Address, error) {
+	return sk.db.GetOperatorStore(blockHash, nodeId, stakingBlockNum)
+}
+
+// takeDelegateFn takes the amount from the free von first, then from the restricting von,
+// it returns the remain which could not be taken and the taken parts.
+func takeDelegateFn(amount, released, restrictingPlan *big.Int) (*big.Int, *big.Int, *big.Int) {
+	takeFn := func(remain, source *big.Int) (*big.Int, *big.Int) {
+		if remain.Cmp(source) >= 0 {
+			return new(big.Int).Sub(remain, source), new(big.Int).Set(source)
+		}
+		return new(big.Int).SetInt64(0), new(big.Int).Set(remain)
+	}
+	remain, takenReleased := takeFn(amount, released)
+	remain, takenRestrictingPlan := takeFn(remain, restrictingPlan)
+	return remain, takenReleased, takenRestrictingPlan
+}
+
 func rufundDelegateFn(refundBalance, aboutRelease, aboutRestrictingPlan *big.Int, delAddr common.Address, state xcom.StateDB) (*big.Int, *big.Int, *big.Int, error) {
 
 	refundTmp := refundBalance
@@ -1172,6 +1524,12 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 		return err
 	}
 
+	if err := sk.applyBlsKeyRotations(blockHash, blockNumber); nil != err {
+		log.Error("Failed to ElectNextVerifierList: apply BLS key rotations is failed", "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return err
+	}
+
 	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, int(maxvalidators))
 	if err := iter.Error(); nil != err {
 		log.Error("Failed to ElectNextVerifierList: take iter by candidate power is failed", "blockNumber",
@@ -1249,6 +1607,114 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 	return nil
 }
 
+// Replace the BLS public keys of the candidates that rotated them in the ending epoch.
+// The next round validators are elected before the epoch boundary,
+// so their keys are replaced too, and the old key is retired at the boundary.
+func (sk *StakingPlugin) applyBlsKeyRotations(blockHash common.Hash, blockNumber uint64) error {
+
+	nextEpoch := xutil.CalculateEpoch(blockNumber) + 1
+
+	queue, err := sk.db.GetBlsRotationStore(blockHash, nextEpoch)
+	if nil != err {
+		return err
+	}
+	if len(queue) == 0 {
+		return nil
+	}
+
+	evidenceAge, err := gov.GovernMaxEvidenceAge(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	validBlocks := xutil.CalcBlocksEachEpoch() * uint64(evidenceAge+1)
+
+	rotated := make(map[common.NodeAddress]bls.PublicKeyHex, len(queue))
+
+	for _, item := range queue {
+
+		canBase, err := sk.db.GetCanBaseStore(blockHash, item.NodeAddress)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return err
+		}
+		// the candidate had been removed
+		if canBase.IsEmpty() || canBase.BlsPubKey == item.BlsPubKey {
+			continue
+		}
+
+		retired, err := sk.db.GetRetiredBlsKeyStore(blockHash, item.NodeAddress)
+		if nil != err {
+			return err
+		}
+
+		// Only keep the keys that can still verify an unexpired evidence
+		keys := make([]*staking.RetiredBlsKey, 0, len(retired)+1)
+		for _, key := range retired {
+			if key.RetiredBlock+validBlocks >= blockNumber {
+				keys = append(keys, key)
+			}
+		}
+		keys = append(keys, &staking.RetiredBlsKey{
+			BlsPubKey:    canBase.BlsPubKey,
+			RetiredBlock: blockNumber,
+		})
+		if err := sk.db.SetRetiredBlsKeyStore(blockHash, item.NodeAddress, keys); nil != err {
+			return err
+		}
+
+		canBase.BlsPubKey = item.BlsPubKey
+		if err := sk.db.SetCanBaseStore(blockHash, item.NodeAddress, canBase); nil != err {
+			return err
+		}
+		rotated[item.NodeAddress] = item.BlsPubKey
+
+		log.Debug("Call applyBlsKeyRotations, the BLS key of candidate is rotated", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "nodeId", canBase.NodeId.TerminalString(), "blsPubKey", item.BlsPubKey.String())
+	}
+
+	if err := sk.db.SetBlsRotationStore(blockHash, nextEpoch, nil); nil != err {
+		return err
+	}
+
+	if len(rotated) == 0 {
+		return nil
+	}
+
+	next, err := sk.getNextValList(blockHash, blockNumber, QueryStartNotIrr)
+	if nil != err {
+		return err
+	}
+
+	var changed bool
+	for _, v := range next.Arr {
+		if blsPubKey, ok := rotated[v.NodeAddress]; ok {
+			v.BlsPubKey = blsPubKey
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return sk.setRoundValListByIndex(blockNumber, blockHash, next)
+}
+
+// Find the BLS public key that the candidate used at the target blockNumber
+func (sk *StakingPlugin) getBlsPubKeyAt(blockHash common.Hash, canAddr common.NodeAddress,
+	currKey bls.PublicKeyHex, targetBlockNumber uint64) (bls.PublicKeyHex, error) {
+
+	retired, err := sk.db.GetRetiredBlsKeyStore(blockHash, canAddr)
+	if nil != err {
+		return bls.PublicKeyHex{}, err
+	}
+
+	// The retired keys are appended in order of rotation
+	for _, key := range retired {
+		if targetBlockNumber <= key.RetiredBlock {
+			return key.BlsPubKey, nil
+		}
+	}
+	return currKey, nil
+}
+
 func (sk *StakingPlugin) GetVerifierCandidateInfo(blockHash common.Hash, blockNumber uint64) ([]*staking.Candidate, error) {
 	verifierList, err := sk.getVerifierList(blockHash, blockNumber, false)
 	if nil != err {
@@ -1616,6 +2082,9 @@ func (sk *StakingPlugin) GetCandidateList(blockHash common.Hash, blockNumber uin
 
 		lazyCalcStakeAmount(epoch, can.CandidateMutable)
 		canHex := buildCanHex(can)
+		if canHex.Operator, err = sk.db.GetOperatorStore(blockHash, can.NodeId, can.StakingBlockNum); nil != err {
+			return nil, err
+		}
 		queue = append(queue, canHex)
 	}
 
@@ -2172,7 +2641,7 @@ func (sk *StakingPlugin) SlashCandidates(state xcom.StateDB, blockHash common.Ha
 		}
 		if needRemove {
 			invalidNodeIdMap[slashItem.NodeId] = struct{}{}
-			if slashItem.SlashType != staking.LowRatio {
+			if slashItem.SlashType != staking.LowRatio && slashItem.SlashType != staking.MissedVote {
 				invalidRemoveGovNodeIdMap[slashItem.NodeId] = struct{}{}
 			}
 		}
@@ -2207,6 +2676,7 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	// check slash type is right
 	slashTypeIsWrong := func() bool {
 		return !slashItem.SlashType.IsLowRatio() &&
+			!slashItem.SlashType.IsMissedVote() &&
 			!slashItem.SlashType.IsLowRatioDel() &&
 			!slashItem.SlashType.IsDuplicateSign()
 	}
@@ -2254,7 +2724,7 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	// it will not punish the behavior of low block rate again
 	// If the penalty is imposed again,
 	// the deposit may be lower than the minimum deposit and may be forced to release the staking during the lock-in period
-	if can.IsLowRatio() && slashItem.SlashType.IsLowRatio() {
+	if can.IsLowRatio() && (slashItem.SlashType.IsLowRatio() || slashItem.SlashType.IsMissedVote()) {
 		log.Info("Call SlashCandidates: node has already been punished", "nodeId", slashItem.NodeId.String(), "nodeStatus", can.Status,
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "slashType", slashItem.SlashType, "slashAmount", slashItem.Amount)
 	} else {
@@ -2289,6 +2759,15 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 			return needRemove, staking.ErrWrongSlashVonCalc
 		}
 
+		if err := UpdateEpochEconomics(blockHash, state, sk.db.GetDB(), epoch, func(ledger *reward.EpochEconomics) {
+			node := ledger.Node(slashItem.NodeId)
+			node.Slashed.Add(node.Slashed, slashItem.Amount)
+		}); nil != err {
+			log.Error("Failed to SlashCandidates: Store epoch economics is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "nodeId", slashItem.NodeId.String(), "err", err)
+			return needRemove, err
+		}
+
 		sharesHaveBeenClean := func() bool {
 			return (can.IsInvalidLowRatioNotEnough() ||
 				can.IsInvalidLowRatioDel() ||
@@ -2453,7 +2932,12 @@ func handleSlashTypeFn(blockNumber uint64, blockHash common.Hash, slashType stak
 	var changeStatus staking.CandidateStatus        // need to add this status
 
 	switch slashType {
-	case staking.LowRatio:
+	case staking.LowRatio, staking.MissedVote:
+		// The missed votes are punished like the low package ratio,
+		// the candidate is frozen with the lowRatio status
+		if slashType == staking.MissedVote {
+			changeStatus |= staking.LowRatio
+		}
 		if ok, _ := CheckStakeThreshold(blockNumber, blockHash, remain); !ok {
 			changeStatus |= staking.NotEnough
 			needReturnHes = true
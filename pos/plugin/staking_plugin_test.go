@@ -1801,6 +1801,273 @@ func TestStakingPlugin_WithdrewDelegate(t *testing.T) {
 	t.Log("Get Candidate Info is:", can)
 }
 
+func TestStakingPlugin_Redelegate(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index, targetIndex := 1, 2
+
+	for _, i := range []int{index, targetIndex} {
+		if err := create_staking(state, blockNumber, blockHash, i, 0, t); nil != err {
+			t.Error("Failed to Create Staking", err)
+			return
+		}
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+
+	if _, err := delegate(state, blockHash, blockNumber, can, 0, index, t); !assert.Nil(t, err, fmt.Sprintf("Failed to delegate: %v", err)) {
+		return
+	}
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Error("Commit 1 err", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock 2 err", err)
+		return
+	}
+
+	delAddr := addrArr[index+1]
+	amount := common.Big257
+	targetAddr, _ := xutil.NodeId2Addr(nodeIdArr[targetIndex])
+
+	redelegate := func(from, to int) (*staking.Delegation, error) {
+		del := getDelegate(blockHash2, blockNumber.Uint64(), from, t)
+		target, err := getCandidate(blockHash2, to)
+		if nil != err {
+			return nil, err
+		}
+		targetDel, err := StakingInstance().GetDelegateInfo(blockHash2, delAddr, nodeIdArr[to], target.StakingBlockNum)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return nil, err
+		}
+		if targetDel.IsEmpty() {
+			targetDel = &staking.Delegation{
+				Released:           new(big.Int),
+				ReleasedHes:        new(big.Int),
+				RestrictingPlan:    new(big.Int),
+				RestrictingPlanHes: new(big.Int),
+				CumulativeIncome:   new(big.Int),
+			}
+		}
+		_, err = StakingInstance().Redelegate(state, blockHash2, blockNumber2, amount, delAddr, nodeIdArr[from],
+			blockNumber.Uint64(), del, make([]*reward.DelegateRewardPer, 0), targetAddr, target, targetDel, make([]*reward.DelegateRewardPer, 0))
+		return del, err
+	}
+
+	balance := state.GetBalance(delAddr)
+	del, err := redelegate(index, targetIndex)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to Redelegate: %v", err)) {
+		return
+	}
+	// the von stays in the staking contract
+	assert.True(t, balance.Cmp(state.GetBalance(delAddr)) == 0)
+
+	delegateTotalHes := new(big.Int).Add(del.ReleasedHes, amount)
+	can, err = getCandidate(blockHash2, index)
+	assert.Nil(t, err)
+	assert.True(t, del.ReleasedHes.Cmp(can.DelegateTotalHes) == 0)
+
+	target, err := getCandidate(blockHash2, targetIndex)
+	assert.Nil(t, err)
+	assert.True(t, amount.Cmp(target.DelegateTotalHes) == 0)
+
+	targetDel, err := StakingInstance().GetDelegateInfo(blockHash2, delAddr, nodeIdArr[targetIndex], target.StakingBlockNum)
+	assert.Nil(t, err)
+	assert.True(t, amount.Cmp(targetDel.ReleasedHes) == 0)
+	assert.True(t, new(big.Int).Add(del.ReleasedHes, targetDel.ReleasedHes).Cmp(delegateTotalHes) == 0)
+
+	// the same candidate
+	_, err = StakingInstance().Redelegate(state, blockHash2, blockNumber2, amount, delAddr, nodeIdArr[targetIndex],
+		target.StakingBlockNum, targetDel, make([]*reward.DelegateRewardPer, 0), targetAddr, target, targetDel, make([]*reward.DelegateRewardPer, 0))
+	assert.Equal(t, staking.ErrRedelegateSameCandidate, err)
+
+	// the limit of the epoch
+	for i := uint64(1); i < xcom.MaxRedelegations(); i++ {
+		_, err := redelegate(index, targetIndex)
+		if !assert.Nil(t, err, fmt.Sprintf("Failed to Redelegate %d: %v", i, err)) {
+			return
+		}
+	}
+	_, err = redelegate(index, targetIndex)
+	assert.Equal(t, staking.ErrRedelegateTooFrequent, err)
+
+	if err := sndb.Commit(blockHash2); nil != err {
+		t.Error("Commit 2 err", err)
+		return
+	}
+
+	// the limit is reset in the next epoch
+	count, err := StakingInstance().db.GetRedelegateCountStore(blockHash2, delAddr, xutil.CalculateEpoch(blockNumber2.Uint64())+1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), count)
+}
+
+func TestStakingPlugin_RotateBlsKey(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	index := 1
+	if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+	oldKey := can.BlsPubKey
+
+	val := &staking.Validator{
+		NodeAddress:     canAddr,
+		NodeId:          can.NodeId,
+		BlsPubKey:       can.BlsPubKey,
+		ProgramVersion:  can.ProgramVersion,
+		Shares:          can.Shares,
+		StakingBlockNum: can.StakingBlockNum,
+		StakingTxIndex:  can.StakingTxIndex,
+	}
+
+	epochEnd := xutil.EpochSize() * xutil.ConsensusSize()
+	if err := setVerifierList(blockHash, &staking.ValidatorArray{
+		Start: 1,
+		End:   epochEnd,
+		Arr:   staking.ValidatorQueue{val},
+	}); nil != err {
+		t.Error("Failed to setVerifierList", err)
+		return
+	}
+	if err := setRoundValList(blockHash, &staking.ValidatorArray{
+		Start: epochEnd - xutil.ConsensusSize() + 1,
+		End:   epochEnd,
+		Arr:   staking.ValidatorQueue{val},
+	}); nil != err {
+		t.Error("Failed to set current round validators", err)
+		return
+	}
+	if err := setRoundValList(blockHash, &staking.ValidatorArray{
+		Start: epochEnd + 1,
+		End:   epochEnd + xutil.ConsensusSize(),
+		Arr:   staking.ValidatorQueue{val},
+	}); nil != err {
+		t.Error("Failed to set next round validators", err)
+		return
+	}
+
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	var newKey bls.PublicKeyHex
+	b, _ := blsKey.GetPublicKey().MarshalText()
+	if err := newKey.UnmarshalText(b); nil != err {
+		t.Error("Failed to blsKeyHex.UnmarshalText", err)
+		return
+	}
+
+	err = StakingInstance().RotateBlsKey(blockHash, blockNumber.Uint64(), canAddr, newKey)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to RotateBlsKey: %v", err)) {
+		return
+	}
+
+	// the current and the queued key can't be used by another candidate
+	otherAddr, _ := xutil.NodeId2Addr(nodeIdArr[index+1])
+	for _, key := range []bls.PublicKeyHex{oldKey, newKey} {
+		used, err := StakingInstance().IsBlsKeyUsed(blockHash, blockNumber.Uint64(), otherAddr, key)
+		assert.Nil(t, err)
+		assert.True(t, used)
+		used, err = StakingInstance().IsBlsKeyUsed(blockHash, blockNumber.Uint64(), canAddr, key)
+		assert.Nil(t, err)
+		assert.False(t, used)
+	}
+
+	// the key is not replaced before the epoch boundary
+	can, err = getCandidate(blockHash, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.Equal(t, oldKey, can.BlsPubKey)
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Error("Commit 1 err", err)
+		return
+	}
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock 2 err", err)
+		return
+	}
+
+	err = StakingInstance().ElectNextVerifierList(blockHash2, epochEnd, state)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to ElectNextVerifierList: %v", err)) {
+		return
+	}
+
+	can, err = getCandidate(blockHash2, index)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getCandidate: %v", err)) {
+		return
+	}
+	assert.Equal(t, newKey, can.BlsPubKey)
+
+	verifiers, err := StakingInstance().getVerifierList(blockHash2, epochEnd+1, QueryStartNotIrr)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getVerifierList: %v", err)) {
+		return
+	}
+	assert.Equal(t, newKey, verifiers.Arr[0].BlsPubKey)
+
+	next, err := StakingInstance().getNextValList(blockHash2, epochEnd, QueryStartNotIrr)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to getNextValList: %v", err)) {
+		return
+	}
+	assert.Equal(t, newKey, next.Arr[0].BlsPubKey)
+
+	// the evidences before the epoch boundary are verified by the old key
+	key, err := StakingInstance().getBlsPubKeyAt(blockHash2, canAddr, can.BlsPubKey, epochEnd)
+	assert.Nil(t, err)
+	assert.Equal(t, oldKey, key)
+
+	key, err = StakingInstance().getBlsPubKeyAt(blockHash2, canAddr, can.BlsPubKey, epochEnd+1)
+	assert.Nil(t, err)
+	assert.Equal(t, newKey, key)
+}
+
 func TestStakingPlugin_GetDelegateInfo(t *testing.T) {
 
 	state, genesis, err := newChainState()
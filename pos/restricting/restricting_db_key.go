@@ -8,6 +8,7 @@ var (
 	RestrictingKeyPrefix         = []byte("RestrictInfo")
 	RestrictRecordKeyPrefix      = []byte("RestrictRecord")
 	InitialFoundationRestricting = []byte("InitialFoundationRestricting")
+	VestingKeyPrefix             = []byte("RestrictVesting")
 )
 
 // RestrictingKey used for search restricting info. key: prefix + account
@@ -32,3 +33,8 @@ func GetReleaseAccountKey(epoch uint64, index uint32) []byte {
 	releaseIndex := append(common.Uint64ToBytes(epoch), common.Uint32ToBytes(index)...)
 	return append(RestrictRecordKeyPrefix, releaseIndex...)
 }
+
+// VestingKey used for search the unreleased vesting schedules of the account. key: prefix + account
+func GetVestingKey(account common.Address) []byte {
+	return append(VestingKeyPrefix, account.Bytes()...)
+}
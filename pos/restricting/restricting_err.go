@@ -8,6 +8,10 @@ import (
 
 const (
 	RestrictTxPlanSize = 36
+	// RestrictVestingPeriods is the max linear releases of a vesting plan
+	RestrictVestingPeriods = 1200
+	// RestrictRevokerSize is the max revokers of an account
+	RestrictRevokerSize = 8
 )
 
 var (
@@ -26,4 +30,9 @@ var (
 	ErrRestrictBalanceNotEnough             = common.NewBizError(304013, "The user restricting balance is not enough for staking lock funds")
 	ErrCreatePlanAmountLessThanMiniAmount   = common.NewBizError(304014, "Create plan each amount should greater than mini amount")
 	ErrRestrictBalanceAndFreeNotEnough      = common.NewBizError(304015, "The user restricting  and free balance is not enough for staking lock funds")
+	ErrVestingPlanInvalid                   = common.NewBizError(304016, fmt.Sprintf("The vesting plan is invalid, the periods cannot be more than %d", RestrictVestingPeriods))
+	ErrRevocablePlanNotFound                = common.NewBizError(304017, "The revocable plan is not found")
+	ErrRevokeAmountEmpty                    = common.NewBizError(304018, "The unreleased amount of the revocable plan is staked or empty")
+	ErrRevokerInvalid                       = common.NewBizError(304019, "The revoker of the vesting plan must be the sender")
+	ErrCountRevokersInvalid                 = common.NewBizError(304020, fmt.Sprintf("The number of the revokers of the account cannot be more than %d", RestrictRevokerSize))
 )
@@ -1,8 +1,10 @@
 package restricting
 
 import (
+	"math"
 	"math/big"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common/hexutil"
 )
 
@@ -29,6 +31,111 @@ type RestrictingPlan struct {
 	Amount *big.Int `json:"amount"` // amount representation of the released amount
 }
 
+// VestingPlan releases the CliffAmount at the Cliff epoch, then releases the rest of the Amount
+// equally every Interval epochs for Periods times, the last period takes the remainder.
+// The unreleased amount can be reclaimed by the Revoker, the plan is not revocable if the Revoker is empty.
+type VestingPlan struct {
+	Cliff       uint64         `json:"cliff"`       // the epoch of the first release, the same as the Epoch of RestrictingPlan
+	CliffAmount *big.Int       `json:"cliffAmount"` // the amount released at the cliff epoch
+	Interval    uint64         `json:"interval"`    // the epochs between two linear releases
+	Periods     uint64         `json:"periods"`     // the number of the linear releases after the cliff
+	Amount      *big.Int       `json:"amount"`      // the total amount of the plan
+	Revoker     common.Address `json:"revoker"`     // the account can revoke the unreleased amount
+}
+
+// Revocable returns whether the plan can be revoked
+func (p *VestingPlan) Revocable() bool {
+	return p.Revoker != (common.Address{})
+}
+
+// Validate checks the amounts and the schedule of the plan
+func (p *VestingPlan) Validate() error {
+	if p.Cliff == 0 {
+		return ErrParamEpochInvalid
+	}
+	if p.Amount == nil || p.Amount.Sign() <= 0 || p.CliffAmount == nil || p.CliffAmount.Sign() < 0 || p.CliffAmount.Cmp(p.Amount) > 0 {
+		return ErrVestingPlanInvalid
+	}
+	linear := new(big.Int).Sub(p.Amount, p.CliffAmount)
+	if linear.Sign() == 0 {
+		if p.Periods != 0 {
+			return ErrVestingPlanInvalid
+		}
+		return nil
+	}
+	if p.Periods == 0 || p.Periods > RestrictVestingPeriods || p.Interval == 0 ||
+		p.Interval > (math.MaxUint64-p.Cliff)/p.Periods || linear.Cmp(new(big.Int).SetUint64(p.Periods)) < 0 {
+		return ErrVestingPlanInvalid
+	}
+	return nil
+}
+
+// Each returns the amount of every linear release, the last one takes the remainder
+func (p *VestingPlan) Each() *big.Int {
+	if p.Periods == 0 {
+		return new(big.Int)
+	}
+	linear := new(big.Int).Sub(p.Amount, p.CliffAmount)
+	return linear.Div(linear, new(big.Int).SetUint64(p.Periods))
+}
+
+// Schedule returns the schedule of the valid plan with its first release recorded
+func (p *VestingPlan) Schedule() VestingSchedule {
+	s := VestingSchedule{
+		Epoch:    p.Cliff,
+		Pending:  new(big.Int).Set(p.CliffAmount),
+		Interval: p.Interval,
+		Each:     p.Each(),
+		Remain:   new(big.Int).Sub(p.Amount, p.CliffAmount),
+		Revoker:  p.Revoker,
+	}
+	if s.Pending.Sign() == 0 {
+		s.Next()
+	}
+	return s
+}
+
+// VestingSchedule is the unreleased part of a vesting plan. Only the Pending release is recorded
+// in the release list of the account, the next one is recorded when it is released.
+type VestingSchedule struct {
+	Epoch    uint64         // the epoch of the recorded release
+	Pending  *big.Int       // the amount of the recorded release
+	Interval uint64         // the epochs between two linear releases
+	Each     *big.Int       // the amount of every linear release
+	Remain   *big.Int       // the amount of the linear releases after the recorded one
+	Revoker  common.Address // the account can revoke the unreleased amount
+}
+
+// Revocable returns whether the schedule can be revoked
+func (s *VestingSchedule) Revocable() bool {
+	return s.Revoker != (common.Address{})
+}
+
+// Next moves the schedule to its next release, it returns false if there is nothing left to release.
+// The remainder is released with the last period once it is less than two periods.
+func (s *VestingSchedule) Next() bool {
+	if s.Remain.Sign() == 0 {
+		return false
+	}
+	amount := new(big.Int).Set(s.Each)
+	if s.Remain.Cmp(new(big.Int).Lsh(s.Each, 1)) < 0 {
+		amount.Set(s.Remain)
+	}
+	s.Epoch += s.Interval
+	s.Pending = amount
+	s.Remain = new(big.Int).Sub(s.Remain, amount)
+	return true
+}
+
+// Releases returns all unreleased plans of the schedule, starting with the recorded one
+func (s VestingSchedule) Releases() []RestrictingPlan {
+	plans := []RestrictingPlan{{s.Epoch, new(big.Int).Set(s.Pending)}}
+	for s.Next() {
+		plans = append(plans, RestrictingPlan{s.Epoch, s.Pending})
+	}
+	return plans
+}
+
 // for plugin test
 type ReleaseAmountInfo struct {
 	Height uint64       `json:"blockNumber"` // blockNumber representation of the block number at the released epoch
@@ -37,8 +144,15 @@ type ReleaseAmountInfo struct {
 
 // for plugin test
 type Result struct {
-	Balance *hexutil.Big        `json:"balance"`
-	Debt    *hexutil.Big        `json:"debt"`
+	Balance   *hexutil.Big        `json:"balance"`
+	Debt      *hexutil.Big        `json:"debt"`
+	Entry     []ReleaseAmountInfo `json:"plans"`
+	Pledge    *hexutil.Big        `json:"Pledge"`
+	Revocable []RevocableInfo     `json:"revocable,omitempty"`
+}
+
+// RevocableInfo is the unreleased amount of the revocable plans which can be revoked by the Revoker
+type RevocableInfo struct {
+	Revoker common.Address      `json:"revoker"`
 	Entry   []ReleaseAmountInfo `json:"plans"`
-	Pledge  *hexutil.Big        `json:"Pledge"`
 }
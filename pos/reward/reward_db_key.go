@@ -20,6 +20,7 @@ var (
 	StakingRewardKey         = []byte("StakingRewardKey")
 	ChainYearNumberKey       = []byte("ChainYearNumberKey")
 	delegateRewardPerKey     = []byte("DelegateRewardPerKey")
+	EpochEconomicsPrefix     = []byte("EpochEconomics")
 )
 
 // GetHistoryIncreaseKey used for search the balance of reward pool at last year
@@ -32,6 +33,11 @@ func HistoryBalancePrefix(year uint32) []byte {
 	return append(LastYearEndBalancePrefix, common.Uint32ToBytes(year)...)
 }
 
+// GetEpochEconomicsKey used for search the economics ledger of the epoch
+func GetEpochEconomicsKey(epoch uint64) []byte {
+	return append(EpochEconomicsPrefix, common.Uint64ToBytes(epoch)...)
+}
+
 func DelegateRewardPerKey(nodeID discover.NodeID, stakingNum, epoch uint64) []byte {
 	index := uint32(epoch / DelegateRewardPerLength)
 	add, err := xutil.NodeId2Addr(nodeID)
@@ -3,5 +3,6 @@ package reward
 import "github.com/PhoenixGlobal/Phoenix-Chain-Core/libs/common"
 
 var (
-	ErrDelegationNotFound = common.NewBizError(305001, "Delegation info not found")
+	ErrDelegationNotFound     = common.NewBizError(305001, "Delegation info not found")
+	ErrEpochEconomicsNotFound = common.NewBizError(305002, "Epoch economics not found")
 )
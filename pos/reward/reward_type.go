@@ -106,3 +106,122 @@ type DelegateRewardReceipt struct {
 	Delegate *big.Int
 	Epoch    uint64
 }
+
+// NodeEconomics is the funds received or lost by a node in an epoch
+type NodeEconomics struct {
+	NodeID discover.NodeID
+	// the number of blocks packaged by the node
+	Blocks uint64
+	// the block reward and the staking reward received by the benefit address of the node
+	PackageReward *big.Int
+	StakingReward *big.Int
+	// the share of the block reward and the staking reward allocated to the delegators of the node
+	DelegateReward *big.Int
+	Slashed        *big.Int
+}
+
+func NewNodeEconomics(nodeID discover.NodeID) *NodeEconomics {
+	return &NodeEconomics{
+		NodeID:         nodeID,
+		PackageReward:  new(big.Int),
+		StakingReward:  new(big.Int),
+		DelegateReward: new(big.Int),
+		Slashed:        new(big.Int),
+	}
+}
+
+// EpochEconomics is the ledger of the funds issued and distributed in an epoch
+type EpochEconomics struct {
+	Epoch uint64
+	// the reward of every block and the total staking reward of the epoch
+	NewBlockReward *big.Int
+	StakingReward  *big.Int
+	// the issuance increased at the end of the year, and the allocations of it
+	IncreaseIssuance       *big.Int
+	RewardPoolIssuance     *big.Int
+	DeveloperFoundation    *big.Int
+	PhoenixChainFoundation *big.Int
+	Nodes                  []*NodeEconomics
+}
+
+func NewEpochEconomics(epoch uint64) *EpochEconomics {
+	return &EpochEconomics{
+		Epoch:                  epoch,
+		NewBlockReward:         new(big.Int),
+		StakingReward:          new(big.Int),
+		IncreaseIssuance:       new(big.Int),
+		RewardPoolIssuance:     new(big.Int),
+		DeveloperFoundation:    new(big.Int),
+		PhoenixChainFoundation: new(big.Int),
+		Nodes:                  make([]*NodeEconomics, 0),
+	}
+}
+
+// Node returns the economics of the node, it is appended if the node is not recorded yet
+func (e *EpochEconomics) Node(nodeID discover.NodeID) *NodeEconomics {
+	for _, node := range e.Nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+	node := NewNodeEconomics(nodeID)
+	e.Nodes = append(e.Nodes, node)
+	return node
+}
+
+func (e *EpochEconomics) Presenter() *EpochEconomicsPresenter {
+	p := &EpochEconomicsPresenter{
+		Epoch:                  e.Epoch,
+		NewBlockReward:         (*hexutil.Big)(e.NewBlockReward),
+		StakingReward:          (*hexutil.Big)(e.StakingReward),
+		IncreaseIssuance:       (*hexutil.Big)(e.IncreaseIssuance),
+		RewardPoolIssuance:     (*hexutil.Big)(e.RewardPoolIssuance),
+		DeveloperFoundation:    (*hexutil.Big)(e.DeveloperFoundation),
+		PhoenixChainFoundation: (*hexutil.Big)(e.PhoenixChainFoundation),
+		Nodes:                  make([]*NodeEconomicsPresenter, 0, len(e.Nodes)),
+	}
+	totalPackage, totalStaking, totalDelegate, totalSlashed := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	for _, node := range e.Nodes {
+		totalPackage.Add(totalPackage, node.PackageReward)
+		totalStaking.Add(totalStaking, node.StakingReward)
+		totalDelegate.Add(totalDelegate, node.DelegateReward)
+		totalSlashed.Add(totalSlashed, node.Slashed)
+		p.Nodes = append(p.Nodes, &NodeEconomicsPresenter{
+			NodeID:         node.NodeID,
+			Blocks:         node.Blocks,
+			PackageReward:  (*hexutil.Big)(node.PackageReward),
+			StakingReward:  (*hexutil.Big)(node.StakingReward),
+			DelegateReward: (*hexutil.Big)(node.DelegateReward),
+			Slashed:        (*hexutil.Big)(node.Slashed),
+		})
+	}
+	p.TotalPackageReward = (*hexutil.Big)(totalPackage)
+	p.TotalStakingReward = (*hexutil.Big)(totalStaking)
+	p.TotalDelegateReward = (*hexutil.Big)(totalDelegate)
+	p.TotalSlashed = (*hexutil.Big)(totalSlashed)
+	return p
+}
+
+type NodeEconomicsPresenter struct {
+	NodeID         discover.NodeID `json:"nodeID"`
+	Blocks         uint64          `json:"blocks"`
+	PackageReward  *hexutil.Big    `json:"packageReward"`
+	StakingReward  *hexutil.Big    `json:"stakingReward"`
+	DelegateReward *hexutil.Big    `json:"delegateReward"`
+	Slashed        *hexutil.Big    `json:"slashed"`
+}
+
+type EpochEconomicsPresenter struct {
+	Epoch                  uint64                    `json:"epoch"`
+	NewBlockReward         *hexutil.Big              `json:"newBlockReward"`
+	StakingReward          *hexutil.Big              `json:"stakingReward"`
+	IncreaseIssuance       *hexutil.Big              `json:"increaseIssuance"`
+	RewardPoolIssuance     *hexutil.Big              `json:"rewardPoolIssuance"`
+	DeveloperFoundation    *hexutil.Big              `json:"developerFoundation"`
+	PhoenixChainFoundation *hexutil.Big              `json:"phoenixChainFoundation"`
+	TotalPackageReward     *hexutil.Big              `json:"totalPackageReward"`
+	TotalStakingReward     *hexutil.Big              `json:"totalStakingReward"`
+	TotalDelegateReward    *hexutil.Big              `json:"totalDelegateReward"`
+	TotalSlashed           *hexutil.Big              `json:"totalSlashed"`
+	Nodes                  []*NodeEconomicsPresenter `json:"nodes"`
+}
@@ -515,6 +515,108 @@ func (db *StakingDB) DelDelegateStoreBySuffix(blockHash common.Hash, suffix []by
 	return db.del(blockHash, key)
 }
 
+// about auto-compounding delegations ...
+
+func (db *StakingDB) GetAutoCompoundStore(blockHash common.Hash, delAddr common.Address, nodeId discover.NodeID, stakeBlockNumber uint64) (bool, error) {
+	_, err := db.get(blockHash, GetAutoCompoundKey(delAddr, nodeId, stakeBlockNumber))
+	switch {
+	case snapshotdb.NonDbNotFoundErr(err):
+		return false, err
+	case snapshotdb.IsDbNotFoundErr(err):
+		return false, nil
+	}
+	return true, nil
+}
+
+func (db *StakingDB) SetAutoCompoundStore(blockHash common.Hash, delAddr common.Address, nodeId discover.NodeID, stakeBlockNumber uint64, autoCompound bool) error {
+	key := GetAutoCompoundKey(delAddr, nodeId, stakeBlockNumber)
+	if !autoCompound {
+		return db.del(blockHash, key)
+	}
+	return db.put(blockHash, key, []byte{1})
+}
+
+// about BLS key rotation ...
+
+func (db *StakingDB) GetBlsRotationStore(blockHash common.Hash, epoch uint64) ([]*BlsKeyRotation, error) {
+	val, err := db.get(blockHash, GetBlsRotationKey(epoch))
+	switch {
+	case snapshotdb.NonDbNotFoundErr(err):
+		return nil, err
+	case snapshotdb.IsDbNotFoundErr(err):
+		return nil, nil
+	}
+
+	var queue []*BlsKeyRotation
+	if err := rlp.DecodeBytes(val, &queue); nil != err {
+		return nil, err
+	}
+	return queue, nil
+}
+
+func (db *StakingDB) SetBlsRotationStore(blockHash common.Hash, epoch uint64, queue []*BlsKeyRotation) error {
+	key := GetBlsRotationKey(epoch)
+	if len(queue) == 0 {
+		return db.del(blockHash, key)
+	}
+
+	val, err := rlp.EncodeToBytes(queue)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, key, val)
+}
+
+func (db *StakingDB) GetRetiredBlsKeyStore(blockHash common.Hash, addr common.NodeAddress) ([]*RetiredBlsKey, error) {
+	val, err := db.get(blockHash, GetRetiredBlsKeyKey(addr))
+	switch {
+	case snapshotdb.NonDbNotFoundErr(err):
+		return nil, err
+	case snapshotdb.IsDbNotFoundErr(err):
+		return nil, nil
+	}
+
+	var keys []*RetiredBlsKey
+	if err := rlp.DecodeBytes(val, &keys); nil != err {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (db *StakingDB) SetRetiredBlsKeyStore(blockHash common.Hash, addr common.NodeAddress, keys []*RetiredBlsKey) error {
+	key := GetRetiredBlsKeyKey(addr)
+	if len(keys) == 0 {
+		return db.del(blockHash, key)
+	}
+
+	val, err := rlp.EncodeToBytes(keys)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, key, val)
+}
+
+// about candidate operator ...
+
+func (db *StakingDB) GetOperatorStore(blockHash common.Hash, nodeId discover.NodeID, stakeBlockNumber uint64) (common.Address, error) {
+	val, err := db.get(blockHash, GetOperatorKey(nodeId, stakeBlockNumber))
+	switch {
+	case snapshotdb.NonDbNotFoundErr(err):
+		return common.ZeroAddr, err
+	case snapshotdb.IsDbNotFoundErr(err):
+		return common.ZeroAddr, nil
+	}
+	return common.BytesToAddress(val), nil
+}
+
+func (db *StakingDB) SetOperatorStore(blockHash common.Hash, nodeId discover.NodeID, stakeBlockNumber uint64, operator common.Address) error {
+	key := GetOperatorKey(nodeId, stakeBlockNumber)
+	if operator == common.ZeroAddr {
+		return db.del(blockHash, key)
+	}
+	return db.put(blockHash, key, operator.Bytes())
+}
+
 // about epoch validates ...
 
 func (db *StakingDB) SetEpochValIndex(blockHash common.Hash, indexArr ValArrIndexQueue) error {
@@ -673,6 +775,10 @@ func (db *StakingDB) IteratorCandidatePowerByBlockHash(blockHash common.Hash, ra
 	return db.ranking(blockHash, CanPowerKeyPrefix, ranges)
 }
 
+func (db *StakingDB) IteratorAutoCompoundByBlockHash(blockHash common.Hash, nodeId discover.NodeID, stakeBlockNumber uint64, ranges int) iterator.Iterator {
+	return db.ranking(blockHash, GetAutoCompoundPrefix(nodeId, stakeBlockNumber), ranges)
+}
+
 func (db *StakingDB) IteratorDelegateByBlockHashWithAddr(blockHash common.Hash, addr common.Address, ranges int) iterator.Iterator {
 	prefix := append(DelegateKeyPrefix, addr.Bytes()...)
 	return db.ranking(blockHash, prefix, ranges)
@@ -745,6 +851,36 @@ func (db *StakingDB) HasAccountStakeRc(blockHash common.Hash, addr common.Addres
 	}
 }
 
+// about delegator's redelegation count ...
+
+// The count is stored with the epoch it belongs to, it is zero in any other epoch
+func (db *StakingDB) GetRedelegateCountStore(blockHash common.Hash, delAddr common.Address, epoch uint64) (uint64, error) {
+	val, err := db.get(blockHash, GetRedelegateCountKey(delAddr))
+	switch {
+	case snapshotdb.NonDbNotFoundErr(err):
+		return 0, err
+	case snapshotdb.IsDbNotFoundErr(err) || len(val) != 16:
+		return 0, nil
+	}
+
+	if common.BytesToUint64(val[:8]) != epoch {
+		return 0, nil
+	}
+	return common.BytesToUint64(val[8:]), nil
+}
+
+func (db *StakingDB) AddRedelegateCountStore(blockHash common.Hash, delAddr common.Address, epoch uint64) error {
+	count, err := db.GetRedelegateCountStore(blockHash, delAddr, epoch)
+	if nil != err {
+		return err
+	}
+
+	count++
+
+	val := append(common.Uint64ToBytes(epoch), common.Uint64ToBytes(count)...)
+	return db.put(blockHash, GetRedelegateCountKey(delAddr), val)
+}
+
 // about round validator's addrs ...
 
 func (db *StakingDB) StoreRoundValidatorAddrs(blockHash common.Hash, key []byte, arry []common.NodeAddress) error {
@@ -25,6 +25,11 @@ const (
 	DPOSHASHStr                = "DPOSHASH"
 	RoundValAddrArrPrefixStr   = "RoundValAddrArr"
 	RoundAddrBoundaryPrefixStr = "RoundAddrBoundary"
+	RedelegateCountPrefixStr   = "RedelCount"
+	AutoCompoundPrefixStr      = "AutoCompound"
+	BlsRotationPrefixStr       = "BlsRotation"
+	RetiredBlsKeyPrefixStr     = "RetiredBls"
+	OperatorPrefixStr          = "Operator"
 )
 
 var (
@@ -42,6 +47,11 @@ var (
 	DPOSHASHKey             = []byte(DPOSHASHStr)
 	RoundValAddrArrPrefix   = []byte(RoundValAddrArrPrefixStr)
 	RoundAddrBoundaryPrefix = []byte(RoundAddrBoundaryPrefixStr)
+	RedelegateCountPrefix   = []byte(RedelegateCountPrefixStr)
+	AutoCompoundPrefix      = []byte(AutoCompoundPrefixStr)
+	BlsRotationPrefix       = []byte(BlsRotationPrefixStr)
+	RetiredBlsKeyPrefix     = []byte(RetiredBlsKeyPrefixStr)
+	OperatorPrefix          = []byte(OperatorPrefixStr)
 
 	b104Len = len(math.MaxBig104.Bytes())
 )
@@ -210,3 +220,37 @@ func GetRoundValAddrArrKey(round uint64) []byte {
 func GetRoundAddrBoundaryKey() []byte {
 	return RoundAddrBoundaryPrefix
 }
+
+func GetRedelegateCountKey(delAddr common.Address) []byte {
+	return append(RedelegateCountPrefix, delAddr.Bytes()...)
+}
+
+// GetAutoCompoundPrefix returns the prefix of the auto-compounding flags of the delegations on the candidate
+func GetAutoCompoundPrefix(nodeId discover.NodeID, stakeBlockNumber uint64) []byte {
+	key := make([]byte, 0, len(AutoCompoundPrefix)+len(nodeId)+8)
+	key = append(key, AutoCompoundPrefix...)
+	key = append(key, nodeId.Bytes()...)
+	return append(key, common.Uint64ToBytes(stakeBlockNumber)...)
+}
+
+func GetAutoCompoundKey(delAddr common.Address, nodeId discover.NodeID, stakeBlockNumber uint64) []byte {
+	return append(GetAutoCompoundPrefix(nodeId, stakeBlockNumber), delAddr.Bytes()...)
+}
+
+//notice this assume key must right
+func DecodeAutoCompoundKey(key []byte) common.Address {
+	return common.BytesToAddress(key[len(key)-common.AddressLength:])
+}
+
+func GetBlsRotationKey(epoch uint64) []byte {
+	return append(BlsRotationPrefix, common.Uint64ToBytes(epoch)...)
+}
+
+func GetRetiredBlsKeyKey(addr common.NodeAddress) []byte {
+	return append(RetiredBlsKeyPrefix, addr.Bytes()...)
+}
+
+func GetOperatorKey(nodeId discover.NodeID, stakeBlockNumber uint64) []byte {
+	key := append(OperatorPrefix, nodeId.Bytes()...)
+	return append(key, common.Uint64ToBytes(stakeBlockNumber)...)
+}
@@ -33,6 +33,12 @@ var (
 	ErrWrongSlashType              = common.NewBizError(301117, "The slash type is illegal")
 	ErrSlashVonOverflow            = common.NewBizError(301118, "The amount of slash is overflowed")
 	ErrWrongSlashVonCalc           = common.NewBizError(301119, "The amount of slash for decreasing staking is incorrect")
+	ErrRedelegateSameCandidate     = common.NewBizError(301120, "The source and target candidate of redelegation are the same")
+	ErrRedelegateTooFrequent       = common.NewBizError(301121, "Redelegate too frequently in the current epoch")
+	ErrSameBlsPubKey               = common.NewBizError(301122, "The new BLS public key is the same as the current one")
+	ErrOperatorNotAllowed          = common.NewBizError(301123, "The operator is not allowed to change the benefit address or the reward ratio")
+	ErrCanSharesNoEnough           = common.NewBizError(301124, "The shares of the candidate are insufficient")
+	ErrBlsPubKeyUsed               = common.NewBizError(301125, "The BLS public key is already used by another candidate")
 	ErrGetVerifierList             = common.NewBizError(301200, "Retreiving verifier list failed")
 	ErrGetValidatorList            = common.NewBizError(301201, "Retreiving validator list failed")
 	ErrGetCandidateList            = common.NewBizError(301202, "Retreiving candidate list failed")
@@ -26,6 +26,7 @@ const (
 	DuplicateSign                             // 1000: The Duplicate package or Duplicate sign
 	LowRatioDel                               // 0001,0000: The lowRatio AND must delete
 	Withdrew                                  // 0010,0000: The Active withdrew
+	MissedVote                                // 0100,0000: The candidate missed votes, it is frozen like the lowRatio
 	Valided       = 0                         // 0000: The current candidate is in force
 	NotExist      = 1 << 31                   // 1000,xxxx,... : The candidate is not exist
 )
@@ -84,6 +85,10 @@ func (status CandidateStatus) IsInvalidDuplicateSign() bool {
 	return status&(DuplicateSign|Invalided) == (DuplicateSign | Invalided)
 }
 
+func (status CandidateStatus) IsMissedVote() bool {
+	return status&MissedVote == MissedVote
+}
+
 func (status CandidateStatus) IsLowRatioDel() bool {
 	return status&LowRatioDel == LowRatioDel
 }
@@ -279,7 +284,7 @@ func (can *CandidateMutable) AppendStatus(status CandidateStatus) {
 }
 
 func (can *CandidateMutable) CleanLowRatioStatus() {
-	can.Status &^= LowRatio
+	can.Status &^= LowRatio | MissedVote
 }
 
 func (can *CandidateMutable) CleanShares() {
@@ -405,11 +410,13 @@ type CandidateHex struct {
 	DelegateTotal        *hexutil.Big
 	DelegateTotalHes     *hexutil.Big
 	DelegateRewardTotal  *hexutil.Big
+	// The operator allowed to manage the candidate besides the staking address
+	Operator common.Address
 	Description
 }
 
 func (can *CandidateHex) String() string {
-	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","RewardPer": "%d","NextRewardPer": "%d","RewardPerChangeEpoch": "%d","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": "%s","Released": "%s","ReleasedHes": "%s","RestrictingPlan": "%s","RestrictingPlanHes": "%s","DelegateEpoch": "%d","DelegateTotal": "%s","DelegateTotalHes": "%s","ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s","DelegateRewardTotal": "%s"}`,
+	return fmt.Sprintf(`{"NodeId": "%s","BlsPubKey": "%s","StakingAddress": "%s","BenefitAddress": "%s","RewardPer": "%d","NextRewardPer": "%d","RewardPerChangeEpoch": "%d","StakingTxIndex": %d,"ProgramVersion": %d,"Status": %d,"StakingEpoch": %d,"StakingBlockNum": %d,"Shares": "%s","Released": "%s","ReleasedHes": "%s","RestrictingPlan": "%s","RestrictingPlanHes": "%s","DelegateEpoch": "%d","DelegateTotal": "%s","DelegateTotalHes": "%s","ExternalId": "%s","NodeName": "%s","Website": "%s","Details": "%s","DelegateRewardTotal": "%s","Operator": "%s"}`,
 		fmt.Sprintf("%x", can.NodeId.Bytes()),
 		fmt.Sprintf("%x", can.BlsPubKey.Bytes()),
 		fmt.Sprintf("%x", can.StakingAddress.Bytes()),
@@ -434,7 +441,8 @@ func (can *CandidateHex) String() string {
 		can.NodeName,
 		can.Website,
 		can.Details,
-		can.DelegateRewardTotal)
+		can.DelegateRewardTotal,
+		fmt.Sprintf("%x", can.Operator.Bytes()))
 }
 
 func (can *CandidateHex) IsNotEmpty() bool {
@@ -1081,16 +1089,19 @@ type DelegationHex struct {
 	RestrictingPlanHes *hexutil.Big
 	// Cumulative delegate income (Waiting for withdrawal)
 	CumulativeIncome *hexutil.Big
+	// The delegate income is added to the delegation at each epoch settlement
+	AutoCompound bool
 }
 
 func (delHex *DelegationHex) String() string {
-	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s}`,
+	return fmt.Sprintf(`{"DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s,"AutoCompound": %t}`,
 		delHex.DelegateEpoch,
 		delHex.Released,
 		delHex.ReleasedHes,
 		delHex.RestrictingPlan,
 		delHex.RestrictingPlanHes,
-		delHex.CumulativeIncome)
+		delHex.CumulativeIncome,
+		delHex.AutoCompound)
 }
 
 func (del *DelegationHex) IsNotEmpty() bool {
@@ -1109,7 +1120,7 @@ type DelegationEx struct {
 }
 
 func (dex *DelegationEx) String() string {
-	return fmt.Sprintf(`{"Addr": "%s","NodeId": "%s","StakingBlockNum": "%d","DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s}`,
+	return fmt.Sprintf(`{"Addr": "%s","NodeId": "%s","StakingBlockNum": "%d","DelegateEpoch": "%d","Released": "%s","ReleasedHes": %s,"RestrictingPlan": %s,"RestrictingPlanHes": %s,"CumulativeIncome": %s,"AutoCompound": %t}`,
 		dex.Addr.String(),
 		fmt.Sprintf("%x", dex.NodeId.Bytes()),
 		dex.StakingBlockNum,
@@ -1118,7 +1129,8 @@ func (dex *DelegationEx) String() string {
 		dex.ReleasedHes,
 		dex.RestrictingPlan,
 		dex.RestrictingPlanHes,
-		dex.CumulativeIncome)
+		dex.CumulativeIncome,
+		dex.AutoCompound)
 }
 
 func (dex *DelegationEx) IsNotEmpty() bool {
@@ -1176,6 +1188,21 @@ type UnStakeItem struct {
 	Recovery bool
 }
 
+// A BLS key rotation of candidate, it takes effect at the epoch boundary
+type BlsKeyRotation struct {
+	// this is the nodeAddress
+	NodeAddress common.NodeAddress
+	BlsPubKey   bls.PublicKeyHex
+}
+
+// A BLS key that was replaced by rotation,
+// it is still used to verify the evidences of earlier blocks
+type RetiredBlsKey struct {
+	BlsPubKey bls.PublicKeyHex
+	// the last blockNumber signed with the key
+	RetiredBlock uint64
+}
+
 //type UnDelegateItem struct {
 //	// this is the `delegateAddress` + `nodeAddress` + `stakeBlockNumber`
 //	KeySuffix []byte
@@ -85,22 +85,25 @@ type commonConfig struct {
 }
 
 type stakingConfig struct {
-	StakeThreshold          *big.Int `json:"stakeThreshold"`          // The Staking minimum threshold allowed
-	OperatingThreshold      *big.Int `json:"operatingThreshold"`      // The (incr, decr) delegate or incr staking minimum threshold allowed
-	MaxValidators           uint64   `json:"maxValidators"`           // The epoch (billing cycle) validators count
-	UnStakeFreezeDuration   uint64   `json:"unStakeFreezeDuration"`   // The freeze period of the withdrew Staking (unit is  epochs)
-	RewardPerMaxChangeRange uint16   `json:"rewardPerMaxChangeRange"` // The maximum amount of commission reward ratio that can be modified each time
-	RewardPerChangeInterval uint16   `json:"rewardPerChangeInterval"` // The interval for each modification of the commission reward ratio (unit: epoch)
+	StakeThreshold          *big.Int `json:"stakeThreshold"`           // The Staking minimum threshold allowed
+	OperatingThreshold      *big.Int `json:"operatingThreshold"`       // The (incr, decr) delegate or incr staking minimum threshold allowed
+	MaxValidators           uint64   `json:"maxValidators"`            // The epoch (billing cycle) validators count
+	UnStakeFreezeDuration   uint64   `json:"unStakeFreezeDuration"`    // The freeze period of the withdrew Staking (unit is  epochs)
+	RewardPerMaxChangeRange uint16   `json:"rewardPerMaxChangeRange"`  // The maximum amount of commission reward ratio that can be modified each time
+	RewardPerChangeInterval uint16   `json:"rewardPerChangeInterval"`  // The interval for each modification of the commission reward ratio (unit: epoch)
+	MaxRedelegations        uint64   `json:"maxRedelegations" rlp:"-"` // The maximum number of redelegations of a delegator in an epoch, 0 disables the redelegation (not part of the genesis config hash)
 }
 
 type slashingConfig struct {
-	SlashFractionDuplicateSign uint32 `json:"slashFractionDuplicateSign"` // Proportion of fines when double signing occurs
-	DuplicateSignReportReward  uint32 `json:"duplicateSignReportReward"`  // The percentage of rewards for whistleblowers, calculated from the penalty
-	MaxEvidenceAge             uint32 `json:"maxEvidenceAge"`             // Validity period of evidence (unit is  epochs)
-	SlashBlocksReward          uint32 `json:"slashBlocksReward"`          // the number of blockReward to slashing per round
-	ZeroProduceCumulativeTime  uint16 `json:"zeroProduceCumulativeTime"`  // Count the number of zero-production blocks in this time range and check it. If it reaches a certain number of times, it can be punished (unit is consensus round)
-	ZeroProduceNumberThreshold uint16 `json:"zeroProduceNumberThreshold"` // Threshold for the number of zero production blocks. punishment is reached within the specified time range
-	ZeroProduceFreezeDuration  uint64 `json:"zeroProduceFreezeDuration"`  // Number of settlement cycles frozen after zero block penalty (unit is epochs)
+	SlashFractionDuplicateSign  uint32 `json:"slashFractionDuplicateSign"`          // Proportion of fines when double signing occurs
+	DuplicateSignReportReward   uint32 `json:"duplicateSignReportReward"`           // The percentage of rewards for whistleblowers, calculated from the penalty
+	MaxEvidenceAge              uint32 `json:"maxEvidenceAge"`                      // Validity period of evidence (unit is  epochs)
+	SlashBlocksReward           uint32 `json:"slashBlocksReward"`                   // the number of blockReward to slashing per round
+	ZeroProduceCumulativeTime   uint16 `json:"zeroProduceCumulativeTime"`           // Count the number of zero-production blocks in this time range and check it. If it reaches a certain number of times, it can be punished (unit is consensus round)
+	ZeroProduceNumberThreshold  uint16 `json:"zeroProduceNumberThreshold"`          // Threshold for the number of zero production blocks. punishment is reached within the specified time range
+	ZeroProduceFreezeDuration   uint64 `json:"zeroProduceFreezeDuration"`           // Number of settlement cycles frozen after zero block penalty (unit is epochs)
+	VoteParticipationThreshold  uint16 `json:"voteParticipationThreshold" rlp:"-"`  // The percentage of the quorum certificates of a round that a validator must have signed, 0 disables the missed vote slashing (not part of the genesis config hash)
+	VoteParticipationMinSamples uint32 `json:"voteParticipationMinSamples" rlp:"-"` // The minimum number of the quorum certificates counted in a round before its vote participation is judged (not part of the genesis config hash)
 }
 
 type governanceConfig struct {
@@ -201,15 +204,18 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				UnStakeFreezeDuration:   uint64(168), // freezing 168 epoch
 				RewardPerMaxChangeRange: uint16(500),
 				RewardPerChangeInterval: uint16(10),
+				MaxRedelegations:        uint64(5),
 			},
 			Slashing: slashingConfig{
-				SlashFractionDuplicateSign: uint32(10),
-				DuplicateSignReportReward:  uint32(50),
-				MaxEvidenceAge:             uint32(27),
-				SlashBlocksReward:          uint32(250),
-				ZeroProduceCumulativeTime:  uint16(20),
-				ZeroProduceNumberThreshold: uint16(1),
-				ZeroProduceFreezeDuration:  uint64(56),
+				SlashFractionDuplicateSign:  uint32(10),
+				DuplicateSignReportReward:   uint32(50),
+				MaxEvidenceAge:              uint32(27),
+				SlashBlocksReward:           uint32(250),
+				ZeroProduceCumulativeTime:   uint16(20),
+				ZeroProduceNumberThreshold:  uint16(1),
+				ZeroProduceFreezeDuration:   uint64(56),
+				VoteParticipationThreshold:  uint16(0),
+				VoteParticipationMinSamples: uint32(10),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(14 * 24 * 360),
@@ -257,15 +263,18 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				UnStakeFreezeDuration:   uint64(2), // freezing 2 epoch
 				RewardPerMaxChangeRange: uint16(500),
 				RewardPerChangeInterval: uint16(10),
+				MaxRedelegations:        uint64(5),
 			},
 			Slashing: slashingConfig{
-				SlashFractionDuplicateSign: uint32(10),
-				DuplicateSignReportReward:  uint32(50),
-				MaxEvidenceAge:             uint32(1),
-				SlashBlocksReward:          uint32(250),
-				ZeroProduceCumulativeTime:  uint16(30),
-				ZeroProduceNumberThreshold: uint16(1),
-				ZeroProduceFreezeDuration:  uint64(1),
+				SlashFractionDuplicateSign:  uint32(10),
+				DuplicateSignReportReward:   uint32(50),
+				MaxEvidenceAge:              uint32(1),
+				SlashBlocksReward:           uint32(250),
+				ZeroProduceCumulativeTime:   uint16(30),
+				ZeroProduceNumberThreshold:  uint16(1),
+				ZeroProduceFreezeDuration:   uint64(1),
+				VoteParticipationThreshold:  uint16(0),
+				VoteParticipationMinSamples: uint32(10),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(14 * 24 * 3600),
@@ -313,15 +322,18 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				UnStakeFreezeDuration:   uint64(2),
 				RewardPerMaxChangeRange: uint16(500),
 				RewardPerChangeInterval: uint16(10),
+				MaxRedelegations:        uint64(5),
 			},
 			Slashing: slashingConfig{
-				SlashFractionDuplicateSign: uint32(10),
-				DuplicateSignReportReward:  uint32(50),
-				MaxEvidenceAge:             uint32(1),
-				SlashBlocksReward:          uint32(0),
-				ZeroProduceCumulativeTime:  uint16(3),
-				ZeroProduceNumberThreshold: uint16(2),
-				ZeroProduceFreezeDuration:  uint64(1),
+				SlashFractionDuplicateSign:  uint32(10),
+				DuplicateSignReportReward:   uint32(50),
+				MaxEvidenceAge:              uint32(1),
+				SlashBlocksReward:           uint32(0),
+				ZeroProduceCumulativeTime:   uint16(3),
+				ZeroProduceNumberThreshold:  uint16(2),
+				ZeroProduceFreezeDuration:   uint64(1),
+				VoteParticipationThreshold:  uint16(0),
+				VoteParticipationMinSamples: uint32(10),
 			},
 			Gov: governanceConfig{
 				VersionProposalVoteDurationSeconds: uint64(160),
@@ -422,6 +434,13 @@ func CheckSlashBlocksReward(rewards int) error {
 	return nil
 }
 
+func CheckVoteParticipationThreshold(threshold uint16) error {
+	if threshold > Hundred {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The VoteParticipationThreshold must be [%d, %d]", Zero, Hundred))
+	}
+	return nil
+}
+
 func CheckZeroProduceCumulativeTime(zeroProduceCumulativeTime uint16, zeroProduceNumberThreshold uint16) error {
 	if zeroProduceCumulativeTime < zeroProduceNumberThreshold || zeroProduceCumulativeTime > MaxZeroProduceCumulativeTime {
 		return common.InvalidParameter.Wrap(fmt.Sprintf("The ZeroProduceCumulativeTime must be [%d, %d]", zeroProduceNumberThreshold, MaxZeroProduceCumulativeTime))
@@ -562,6 +581,10 @@ func CheckEconomicModel() error {
 		return err
 	}
 
+	if err := CheckVoteParticipationThreshold(ec.Slashing.VoteParticipationThreshold); nil != err {
+		return err
+	}
+
 	if err := CheckRewardPerMaxChangeRange(ec.Staking.RewardPerMaxChangeRange); nil != err {
 		return err
 	}
@@ -658,6 +681,16 @@ func HesitateRatio() uint64 {
 	return 1
 }
 
+// The maximum number of redelegations of a delegator in an epoch
+func MaxRedelegations() uint64 {
+	return ec.Staking.MaxRedelegations
+}
+
+// The maximum number of the parameter changes in a parameter batch proposal
+func MaxParamBatchChanges() uint64 {
+	return 10
+}
+
 func ElectionDistance() uint64 {
 	// min need two view
 	return 2 * ec.Common.PerRoundBlocks
@@ -714,6 +747,18 @@ func ZeroProduceFreezeDuration() uint64 {
 	return ec.Slashing.ZeroProduceFreezeDuration
 }
 
+// The percentage of the quorum certificates of a round that a validator
+// must have signed, 0 disables the missed vote slashing
+func VoteParticipationThreshold() uint16 {
+	return ec.Slashing.VoteParticipationThreshold
+}
+
+// The minimum number of the quorum certificates recorded in a round
+// before the vote participation of the round is judged
+func VoteParticipationMinSamples() uint32 {
+	return ec.Slashing.VoteParticipationMinSamples
+}
+
 /******
  * Reward config
  ******/